@@ -19,5 +19,8 @@ func NewContainerCmd(flags *flags.GlobalFlags) *cobra.Command {
 	containerCmd.AddCommand(NewCredentialsServerCmd(flags))
 	containerCmd.AddCommand(NewSetupLoftPlatformAccessCmd(flags))
 	containerCmd.AddCommand(NewSSHServerCmd(flags))
+	containerCmd.AddCommand(NewSyncIDESettingsCmd(flags))
+	containerCmd.AddCommand(NewInstallIDECmd(flags))
+	containerCmd.AddCommand(NewIDEStatusCmd(flags))
 	return containerCmd
 }
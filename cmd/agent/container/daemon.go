@@ -208,6 +208,7 @@ func runTimeoutMonitor(ctx context.Context, duration time.Duration, errChan chan
 				continue
 			}
 			if !stat.ModTime().Add(duration).After(time.Now()) {
+				stopContainer()
 				errChan <- errors.New("timeout reached, terminating daemon")
 				return
 			}
@@ -215,6 +216,19 @@ func runTimeoutMonitor(ctx context.Context, duration time.Duration, errChan chan
 	}
 }
 
+// stopContainer asks the main process of the container to terminate, as the container only stops with it.
+// If the daemon is the main process itself, the container stops as soon as it exits.
+func stopContainer() {
+	if os.Getpid() == 1 {
+		return
+	}
+
+	process, err := os.FindProcess(1)
+	if err == nil {
+		_ = process.Signal(syscall.SIGTERM)
+	}
+}
+
 // runNetworkServer starts the network server.
 func runNetworkServer(ctx context.Context, cmd *DaemonCmd, errChan chan<- error, wg *sync.WaitGroup) {
 	defer wg.Done()
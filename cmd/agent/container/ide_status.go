@@ -0,0 +1,44 @@
+package container
+
+import (
+	"encoding/json"
+	"os"
+
+	"dev.khulnasoft.com/cmd/flags"
+	"dev.khulnasoft.com/pkg/ide"
+	"github.com/spf13/cobra"
+)
+
+// IDEStatusCmd holds the cmd flags
+type IDEStatusCmd struct {
+	*flags.GlobalFlags
+
+	CustomIDEs []string
+}
+
+// NewIDEStatusCmd creates a new command
+func NewIDEStatusCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &IDEStatusCmd{
+		GlobalFlags: flags,
+	}
+	ideStatusCmd := &cobra.Command{
+		Use:   "ide-status",
+		Short: "Prints the IDE servers running in the container as json",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return cmd.Run()
+		},
+	}
+	ideStatusCmd.Flags().StringArrayVar(&cmd.CustomIDEs, "custom-ide", []string{}, "The name of an IDE added with devspace ide add to look for")
+	return ideStatusCmd
+}
+
+// Run runs the command logic
+func (cmd *IDEStatusCmd) Run() error {
+	servers, err := ide.FindRunningServers("/proc", os.TempDir(), cmd.CustomIDEs)
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(os.Stdout).Encode(servers)
+}
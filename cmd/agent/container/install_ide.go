@@ -0,0 +1,83 @@
+//go:build !windows
+
+package container
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"dev.khulnasoft.com/cmd/flags"
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/compress"
+	provider2 "dev.khulnasoft.com/pkg/provider"
+	"github.com/spf13/cobra"
+)
+
+// InstallIDECmd holds the cmd flags
+type InstallIDECmd struct {
+	*flags.GlobalFlags
+
+	IDEConfig string
+}
+
+// NewInstallIDECmd creates a new command
+func NewInstallIDECmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &InstallIDECmd{
+		GlobalFlags: flags,
+	}
+	installIDECmd := &cobra.Command{
+		Use:   "install-ide",
+		Short: "Installs and starts an IDE in an already set up container",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return cmd.Run()
+		},
+	}
+	installIDECmd.Flags().StringVar(&cmd.IDEConfig, "ide-config", "", "The compressed configuration of the IDE to install")
+	_ = installIDECmd.MarkFlagRequired("ide-config")
+	return installIDECmd
+}
+
+// Run runs the command logic. It prints the container result, so the caller knows the remote user and workspace
+// folder to open the IDE with.
+func (cmd *InstallIDECmd) Run() error {
+	decompressed, err := compress.Decompress(cmd.IDEConfig)
+	if err != nil {
+		return err
+	}
+
+	ideConfig := &provider2.WorkspaceIDEConfig{}
+	err = json.Unmarshal([]byte(decompressed), ideConfig)
+	if err != nil {
+		return fmt.Errorf("parse ide config: %w", err)
+	}
+
+	result, err := readContainerResult()
+	if err != nil {
+		return err
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	// the background extension installs expect the setup info in the same form as during setup
+	setupInfo, err := compress.Compress(string(out))
+	if err != nil {
+		return err
+	}
+
+	setupCmd := &SetupContainerCmd{
+		GlobalFlags: cmd.GlobalFlags,
+		SetupInfo:   setupInfo,
+	}
+	err = setupCmd.installIDE(result, ideConfig, log.Default.ErrorStreamOnly())
+	if err != nil {
+		return fmt.Errorf("install %s: %w", ideConfig.Name, err)
+	}
+
+	_, err = os.Stdout.Write(out)
+	return err
+}
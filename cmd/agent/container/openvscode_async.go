@@ -5,8 +5,10 @@ import (
 
 	"dev.khulnasoft.com/cmd/flags"
 	"dev.khulnasoft.com/pkg/compress"
+	config2 "dev.khulnasoft.com/pkg/config"
 	"dev.khulnasoft.com/pkg/devcontainer/config"
 	"dev.khulnasoft.com/pkg/ide/openvscode"
+	"dev.khulnasoft.com/pkg/ide/vscode"
 	"dev.khulnasoft.com/log"
 	"github.com/spf13/cobra"
 )
@@ -15,7 +17,8 @@ import (
 type OpenVSCodeAsyncCmd struct {
 	*flags.GlobalFlags
 
-	SetupInfo string
+	SetupInfo         string
+	ExtensionsGallery string
 }
 
 // NewOpenVSCodeAsyncCmd creates a new command
@@ -29,6 +32,7 @@ func NewOpenVSCodeAsyncCmd() *cobra.Command {
 	}
 	vsCodeAsyncCmd.Flags().StringVar(&cmd.SetupInfo, "setup-info", "", "The container setup info")
 	_ = vsCodeAsyncCmd.MarkFlagRequired("setup-info")
+	vsCodeAsyncCmd.Flags().StringVar(&cmd.ExtensionsGallery, "extensions-gallery", "", "The Open VSX compatible registry to install extensions from")
 	return vsCodeAsyncCmd
 }
 
@@ -47,7 +51,7 @@ func (cmd *OpenVSCodeAsyncCmd) Run(_ *cobra.Command, _ []string) error {
 	}
 
 	// install IDE
-	err = setupOpenVSCodeExtensions(setupInfo, log.Default)
+	err = setupOpenVSCodeExtensions(setupInfo, cmd.ExtensionsGallery, log.Default)
 	if err != nil {
 		return err
 	}
@@ -55,8 +59,11 @@ func (cmd *OpenVSCodeAsyncCmd) Run(_ *cobra.Command, _ []string) error {
 	return nil
 }
 
-func setupOpenVSCodeExtensions(setupInfo *config.Result, log log.Logger) error {
+func setupOpenVSCodeExtensions(setupInfo *config.Result, extensionsGallery string, log log.Logger) error {
 	vsCodeConfiguration := config.GetVSCodeConfiguration(setupInfo.MergedConfig)
 	user := config.GetRemoteUser(setupInfo)
-	return openvscode.NewOpenVSCodeServer(vsCodeConfiguration.Extensions, "", user, "", "", nil, log).InstallExtensions()
+	values := map[string]config2.OptionValue{
+		vscode.ExtensionsGalleryOption: {Value: extensionsGallery},
+	}
+	return openvscode.NewOpenVSCodeServer(vsCodeConfiguration.Extensions, "", user, "", "", values, log).InstallExtensions()
 }
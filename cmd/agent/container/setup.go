@@ -35,11 +35,14 @@ import (
 	"dev.khulnasoft.com/pkg/envfile"
 	"dev.khulnasoft.com/pkg/extract"
 	"dev.khulnasoft.com/pkg/git"
+	"dev.khulnasoft.com/pkg/ide/codeserver"
+	"dev.khulnasoft.com/pkg/ide/custom"
 	"dev.khulnasoft.com/pkg/ide/fleet"
 	"dev.khulnasoft.com/pkg/ide/jetbrains"
 	"dev.khulnasoft.com/pkg/ide/jupyter"
 	"dev.khulnasoft.com/pkg/ide/openvscode"
 	"dev.khulnasoft.com/pkg/ide/rstudio"
+	"dev.khulnasoft.com/pkg/ide/terminal"
 	"dev.khulnasoft.com/pkg/ide/vscode"
 	provider2 "dev.khulnasoft.com/pkg/provider"
 	"dev.khulnasoft.com/pkg/single"
@@ -64,6 +67,7 @@ type SetupContainerCmd struct {
 	AccessKey              string
 	PlatformHost           string
 	WorkspaceHost          string
+	StreamVSIXFolder       string
 }
 
 // NewSetupContainerCmd creates a new command
@@ -87,6 +91,7 @@ func NewSetupContainerCmd(flags *flags.GlobalFlags) *cobra.Command {
 	setupContainerCmd.Flags().StringVar(&cmd.AccessKey, "access-key", "", "Access Key to use")
 	setupContainerCmd.Flags().StringVar(&cmd.WorkspaceHost, "workspace-host", "", "Workspace hostname to use")
 	setupContainerCmd.Flags().StringVar(&cmd.PlatformHost, "platform-host", "", "Platform host")
+	setupContainerCmd.Flags().StringVar(&cmd.StreamVSIXFolder, "stream-vsix-folder", "", "The local VSIX folder to copy into the container for offline extension installation")
 	_ = setupContainerCmd.MarkFlagRequired("setup-info")
 	return setupContainerCmd
 }
@@ -200,22 +205,39 @@ func (cmd *SetupContainerCmd) Run(ctx context.Context) error {
 		return err
 	}
 
+	// copy the local VSIX folder, so extensions can be installed without the marketplace
+	if cmd.StreamVSIXFolder != "" {
+		err = streamMount(ctx, workspaceInfo, vscode.VSIXMount(cmd.StreamVSIXFolder), tunnelClient, logger)
+		if err != nil {
+			logger.Warnf("Error copying VSIX folder: %v", err)
+		}
+	}
+
 	// install IDE
 	err = cmd.installIDE(setupInfo, &workspaceInfo.IDE, logger)
 	if err != nil {
 		return err
 	}
 
+	// keep the agent for the next container start if the state folder is persisted
+	if os.Getenv(config.WorkspacePersistentStateExtraEnvVar) == "true" {
+		err = backupAgent()
+		if err != nil {
+			logger.Errorf("Error backing up agent: %v", err)
+		}
+	}
+
 	// start container daemon if necessary
-	if !workspaceInfo.CLIOptions.Platform.Enabled && !workspaceInfo.CLIOptions.DisableDaemon && workspaceInfo.ContainerTimeout != "" {
+	containerTimeout := getContainerTimeout(workspaceInfo)
+	if !workspaceInfo.CLIOptions.Platform.Enabled && !workspaceInfo.CLIOptions.DisableDaemon && containerTimeout != "" {
 		err = single.Single("devspace.daemon.pid", func() (*exec.Cmd, error) {
-			logger.Debugf("Start DevSpace Container Daemon with Inactivity Timeout %s", workspaceInfo.ContainerTimeout)
+			logger.Debugf("Start DevSpace Container Daemon with Inactivity Timeout %s", containerTimeout)
 			binaryPath, err := os.Executable()
 			if err != nil {
 				return nil, err
 			}
 
-			return exec.Command(binaryPath, "agent", "container", "daemon", "--timeout", workspaceInfo.ContainerTimeout), nil
+			return exec.Command(binaryPath, "agent", "container", "daemon", "--timeout", containerTimeout), nil
 		})
 		if err != nil {
 			return err
@@ -235,6 +257,43 @@ func (cmd *SetupContainerCmd) Run(ctx context.Context) error {
 	return nil
 }
 
+// getContainerTimeout returns after how much inactivity the container should be stopped. The Kubernetes driver
+// puts idle workspaces to sleep after its inactivity timeout. Containers not created by DevSpace are never stopped.
+func getContainerTimeout(workspaceInfo *provider2.ContainerWorkspaceInfo) string {
+	if workspaceInfo.Source.Container != "" || workspaceInfo.Source.KubernetesWorkload != "" {
+		return ""
+	} else if workspaceInfo.ContainerTimeout == "" && workspaceInfo.Agent.Driver == provider2.KubernetesDriver {
+		return workspaceInfo.Agent.Kubernetes.InactivityTimeout
+	}
+
+	return workspaceInfo.ContainerTimeout
+}
+
+// backupAgent copies the running agent into the persisted state folder
+func backupAgent() error {
+	binaryPath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	// skip if the backup is already up to date
+	binaryStat, err := os.Stat(binaryPath)
+	if err != nil {
+		return err
+	}
+	backupStat, err := os.Stat(config.ContainerAgentBackupLocation)
+	if err == nil && backupStat.Size() == binaryStat.Size() && !backupStat.ModTime().Before(binaryStat.ModTime()) {
+		return nil
+	}
+
+	err = os.MkdirAll(filepath.Dir(config.ContainerAgentBackupLocation), 0755)
+	if err != nil {
+		return err
+	}
+
+	return copy.File(binaryPath, config.ContainerAgentBackupLocation, 0755)
+}
+
 func fillContainerEnv(setupInfo *config.Result) error {
 	// set remote-env
 	if setupInfo.MergedConfig.RemoteEnv == nil {
@@ -461,11 +520,21 @@ func (cmd *SetupContainerCmd) installIDE(setupInfo *config.Result, ide *provider
 		return fleet.NewFleetServer(config.GetRemoteUser(setupInfo), ide.Options, log).Install(setupInfo.SubstitutionContext.ContainerWorkspaceFolder)
 	case string(config2.IDEJupyterNotebook):
 		return jupyter.NewJupyterNotebookServer(setupInfo.SubstitutionContext.ContainerWorkspaceFolder, config.GetRemoteUser(setupInfo), ide.Options, log).Install()
+	case string(config2.IDEJupyterLab):
+		return jupyter.NewJupyterLabServer(setupInfo.SubstitutionContext.ContainerWorkspaceFolder, config.GetRemoteUser(setupInfo), ide.Options, log).Install()
+	case string(config2.IDECodeServer):
+		return cmd.setupCodeServer(setupInfo, ide.Options, log)
 	case string(config2.IDERStudio):
 		err := rstudio.NewRStudioServer(setupInfo.SubstitutionContext.ContainerWorkspaceFolder, config.GetRemoteUser(setupInfo), ide.Options, log).Install()
 		if err != nil {
 			log.Errorf("could not install rstudio with error: %w", err)
 		}
+	case string(config2.IDETerminal):
+		return terminal.NewTerminalServer(config.GetRemoteUser(setupInfo), ide.Options, log).Install()
+	default:
+		if ide.Custom != nil {
+			return custom.NewCustomIDEServer(ide.Custom, setupInfo.SubstitutionContext.ContainerWorkspaceFolder, config.GetRemoteUser(setupInfo), ide.Options, log).Install()
+		}
 	}
 
 	return nil
@@ -511,11 +580,31 @@ func (cmd *SetupContainerCmd) setupVSCode(setupInfo *config.Result, ideOptions m
 			"--setup-info", cmd.SetupInfo,
 			"--release-channel", string(flavor),
 		}
+		if gallery := vscode.Options.GetValue(ideOptions, vscode.ExtensionsGalleryOption); gallery != "" {
+			args = append(args, "--extensions-gallery", gallery)
+		}
 
 		return exec.Command(binaryPath, args...), nil
 	})
 }
 
+func (cmd *SetupContainerCmd) setupCodeServer(setupInfo *config.Result, ideOptions map[string]config2.OptionValue, log log.Logger) error {
+	log.Debugf("Setup code-server...")
+	vsCodeConfiguration := config.GetVSCodeConfiguration(setupInfo.MergedConfig)
+	settings := ""
+	if len(vsCodeConfiguration.Settings) > 0 {
+		out, err := json.Marshal(vsCodeConfiguration.Settings)
+		if err != nil {
+			return err
+		}
+
+		settings = string(out)
+	}
+
+	user := config.GetRemoteUser(setupInfo)
+	return codeserver.NewCodeServer(vsCodeConfiguration.Extensions, settings, setupInfo.SubstitutionContext.ContainerWorkspaceFolder, user, ideOptions, log).Install()
+}
+
 func (cmd *SetupContainerCmd) setupOpenVSCode(setupInfo *config.Result, ideOptions map[string]config2.OptionValue, log log.Logger) error {
 	log.Debugf("Setup openvscode...")
 	vsCodeConfiguration := config.GetVSCodeConfiguration(setupInfo.MergedConfig)
@@ -547,7 +636,12 @@ func (cmd *SetupContainerCmd) setupOpenVSCode(setupInfo *config.Result, ideOptio
 				return nil, err
 			}
 
-			return exec.Command(binaryPath, "agent", "container", "openvscode-async", "--setup-info", cmd.SetupInfo), nil
+			args := []string{"agent", "container", "openvscode-async", "--setup-info", cmd.SetupInfo}
+			if gallery := openvscode.Options.GetValue(ideOptions, vscode.ExtensionsGalleryOption); gallery != "" {
+				args = append(args, "--extensions-gallery", gallery)
+			}
+
+			return exec.Command(binaryPath, args...), nil
 		})
 		if err != nil {
 			return errors.Wrap(err, "install extensions")
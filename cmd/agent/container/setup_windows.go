@@ -17,3 +17,14 @@ func NewSetupContainerCmd(flags *flags.GlobalFlags) *cobra.Command {
 		},
 	}
 }
+
+func NewInstallIDECmd(flags *flags.GlobalFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "install-ide",
+		Short: "Installs and starts an IDE in an already set up container",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			panic("Windows Containers are not supported")
+		},
+	}
+}
@@ -0,0 +1,82 @@
+package container
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"dev.khulnasoft.com/cmd/flags"
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/devcontainer/config"
+	"dev.khulnasoft.com/pkg/devcontainer/setup"
+	"dev.khulnasoft.com/pkg/ide/settingssync"
+	"github.com/spf13/cobra"
+)
+
+// SyncIDESettingsCmd holds the cmd flags
+type SyncIDESettingsCmd struct {
+	*flags.GlobalFlags
+
+	WorkspaceFolder string
+}
+
+// NewSyncIDESettingsCmd creates a new command
+func NewSyncIDESettingsCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &SyncIDESettingsCmd{
+		GlobalFlags: flags,
+	}
+	syncIDESettingsCmd := &cobra.Command{
+		Use:   "sync-ide-settings",
+		Short: "Applies the local IDE settings read from stdin in the container",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return cmd.Run()
+		},
+	}
+	syncIDESettingsCmd.Flags().StringVar(&cmd.WorkspaceFolder, "workspace-folder", "", "The folder the IDE opens, defaults to the workspace folder of the container")
+	return syncIDESettingsCmd
+}
+
+// Run runs the command logic
+func (cmd *SyncIDESettingsCmd) Run() error {
+	payload := &settingssync.Payload{}
+	err := json.NewDecoder(os.Stdin).Decode(payload)
+	if err != nil {
+		return fmt.Errorf("parse settings: %w", err)
+	}
+
+	// the result holds the remote user and the settings of the devcontainer.json, which take precedence
+	result, err := readContainerResult()
+	if err != nil {
+		return err
+	}
+
+	workspaceFolder := cmd.WorkspaceFolder
+	if workspaceFolder == "" {
+		workspaceFolder = result.SubstitutionContext.ContainerWorkspaceFolder
+	}
+
+	return settingssync.Apply(
+		payload,
+		config.GetRemoteUser(result),
+		workspaceFolder,
+		config.GetVSCodeConfiguration(result.MergedConfig).Settings,
+		log.Default,
+	)
+}
+
+// readContainerResult reads the result the container was set up with
+func readContainerResult() (*config.Result, error) {
+	rawResult, err := os.ReadFile(setup.ResultLocation)
+	if err != nil {
+		return nil, fmt.Errorf("read container result: %w", err)
+	}
+
+	result := &config.Result{}
+	err = json.Unmarshal(rawResult, result)
+	if err != nil {
+		return nil, fmt.Errorf("parse container result: %w", err)
+	}
+
+	return result, nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"dev.khulnasoft.com/cmd/flags"
 	"dev.khulnasoft.com/pkg/compress"
+	config2 "dev.khulnasoft.com/pkg/config"
 	"dev.khulnasoft.com/pkg/devcontainer/config"
 	"dev.khulnasoft.com/pkg/ide/vscode"
 	"dev.khulnasoft.com/log"
@@ -16,8 +17,9 @@ import (
 type VSCodeAsyncCmd struct {
 	*flags.GlobalFlags
 
-	SetupInfo string
-	Flavor    string
+	SetupInfo         string
+	Flavor            string
+	ExtensionsGallery string
 }
 
 // NewVSCodeAsyncCmd creates a new command
@@ -32,6 +34,7 @@ func NewVSCodeAsyncCmd() *cobra.Command {
 	vsCodeAsyncCmd.Flags().StringVar(&cmd.SetupInfo, "setup-info", "", "The container setup info")
 	_ = vsCodeAsyncCmd.MarkFlagRequired("setup-info")
 
+	vsCodeAsyncCmd.Flags().StringVar(&cmd.ExtensionsGallery, "extensions-gallery", "", "The Open VSX compatible registry to install extensions from")
 	vsCodeAsyncCmd.Flags().StringVar(&cmd.Flavor, "flavor", string(vscode.FlavorStable), "The flavor of the VSCode distribution")
 	vsCodeAsyncCmd.Flags().StringVar(&cmd.Flavor, "release-channel", string(vscode.FlavorStable), "The release channel to use for vscode")
 	_ = vsCodeAsyncCmd.Flags().MarkDeprecated("release-channel", "prefer the --flavor flag")
@@ -63,7 +66,7 @@ func (cmd *VSCodeAsyncCmd) Run(_ *cobra.Command, _ []string) error {
 	}
 
 	// install IDE
-	err = setupVSCodeExtensions(setupInfo, vscode.Flavor(cmd.Flavor), log.Default)
+	err = setupVSCodeExtensions(setupInfo, vscode.Flavor(cmd.Flavor), cmd.ExtensionsGallery, log.Default)
 	if err != nil {
 		return err
 	}
@@ -71,8 +74,11 @@ func (cmd *VSCodeAsyncCmd) Run(_ *cobra.Command, _ []string) error {
 	return nil
 }
 
-func setupVSCodeExtensions(setupInfo *config.Result, flavor vscode.Flavor, log log.Logger) error {
+func setupVSCodeExtensions(setupInfo *config.Result, flavor vscode.Flavor, extensionsGallery string, log log.Logger) error {
 	vsCodeConfiguration := config.GetVSCodeConfiguration(setupInfo.MergedConfig)
 	user := config.GetRemoteUser(setupInfo)
-	return vscode.NewVSCodeServer(vsCodeConfiguration.Extensions, "", user, nil, flavor, log).InstallExtensions()
+	values := map[string]config2.OptionValue{
+		vscode.ExtensionsGalleryOption: {Value: extensionsGallery},
+	}
+	return vscode.NewVSCodeServer(vsCodeConfiguration.Extensions, "", user, values, flavor, log).InstallExtensions()
 }
@@ -71,26 +71,19 @@ func (cmd *BuildCmd) Run(ctx context.Context) error {
 	}
 
 	// if there is no platform specified, we use empty to let
-	// the builder find out itself.
-	platforms := workspaceInfo.CLIOptions.Platforms
-	if len(platforms) == 0 {
-		platforms = []string{""}
+	// the builder find out itself. If multiple platforms are specified
+	// the pushed images are combined into a single image index.
+	imageNames, err := runner.BuildPlatforms(ctx, provider2.BuildOptions{
+		CLIOptions:    workspaceInfo.CLIOptions,
+		RegistryCache: workspaceInfo.RegistryCache,
+		ExportCache:   true,
+	}, workspaceInfo.CLIOptions.Platforms)
+	if err != nil {
+		logger.Errorf("Error building image: %v", err)
+		return errors.Wrap(err, "build")
 	}
 
-	// build and push images
-	for _, platform := range platforms {
-		// build the image
-		imageName, err := runner.Build(ctx, provider2.BuildOptions{
-			CLIOptions:    workspaceInfo.CLIOptions,
-			RegistryCache: workspaceInfo.RegistryCache,
-			Platform:      platform,
-			ExportCache:   true,
-		})
-		if err != nil {
-			logger.Errorf("Error building image: %v", err)
-			return errors.Wrap(err, "build")
-		}
-
+	for _, imageName := range imageNames {
 		if workspaceInfo.CLIOptions.SkipPush {
 			logger.Donef("Successfully build image %s", imageName)
 		} else {
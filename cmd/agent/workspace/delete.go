@@ -82,7 +82,7 @@ func removeContainer(ctx context.Context, workspaceInfo *provider2.AgentWorkspac
 		return err
 	}
 
-	if workspaceInfo.Workspace.Source.Container != "" {
+	if workspaceInfo.Workspace.Source.Container != "" || workspaceInfo.Workspace.Source.KubernetesWorkload != "" {
 		log.Infof("Skipping container deletion, since it was not created by DevSpace")
 	} else {
 		err = runner.Delete(ctx)
@@ -16,6 +16,7 @@ import (
 	"dev.khulnasoft.com/pkg/binaries"
 	"dev.khulnasoft.com/pkg/client/clientimplementation"
 	"dev.khulnasoft.com/pkg/command"
+	"dev.khulnasoft.com/pkg/config"
 	"dev.khulnasoft.com/pkg/credentials"
 	agentdaemon "dev.khulnasoft.com/pkg/daemon/agent"
 	"dev.khulnasoft.com/pkg/devcontainer"
@@ -23,6 +24,7 @@ import (
 	"dev.khulnasoft.com/pkg/devcontainer/crane"
 	"dev.khulnasoft.com/pkg/dockercredentials"
 	"dev.khulnasoft.com/pkg/extract"
+	"dev.khulnasoft.com/pkg/ide/vscode"
 	provider2 "dev.khulnasoft.com/pkg/provider"
 	"dev.khulnasoft.com/pkg/util"
 	"dev.khulnasoft.com/scripts"
@@ -225,6 +227,12 @@ func initWorkspace(ctx context.Context, cancel context.CancelFunc, workspaceInfo
 		return nil, logger, "", err
 	}
 
+	// copy the VSIX folder of the client, the runner can't read it on this machine
+	err = downloadVSIXFolder(ctx, workspaceInfo, tunnelClient, logger)
+	if err != nil {
+		logger.Warnf("Error copying VSIX folder: %v", err)
+	}
+
 	// install daemon
 	if shouldInstallDaemon {
 		err = installDaemon(workspaceInfo, logger)
@@ -326,7 +334,12 @@ func prepareWorkspace(ctx context.Context, workspaceInfo *provider2.AgentWorkspa
 		return nil
 	}
 
-	return fmt.Errorf("either workspace repository, image, container or local-folder is required")
+	if workspaceInfo.Workspace.Source.KubernetesWorkload != "" {
+		log.Debugf("Workspace is a Kubernetes workload, nothing to do")
+		return nil
+	}
+
+	return fmt.Errorf("either workspace repository, image, container, Kubernetes workload or local-folder is required")
 }
 
 func ensureLastDevContainerJson(workspaceInfo *provider2.AgentWorkspaceInfo) error {
@@ -419,6 +432,42 @@ func downloadLocalFolder(ctx context.Context, workspaceDir string, client tunnel
 	return nil
 }
 
+// downloadVSIXFolder copies the VSIX folder of the client next to the workspace config and points the ide options to
+// the copy, so the runner streams it into the container
+func downloadVSIXFolder(ctx context.Context, workspaceInfo *provider2.AgentWorkspaceInfo, client tunnel.TunnelClient, log log.Logger) error {
+	if workspaceInfo.CLIOptions.VSIXFolder == "" {
+		return nil
+	}
+
+	// start from an empty folder, so extensions removed on the client are gone as well
+	vsixFolder := filepath.Join(workspaceInfo.Origin, "vsix")
+	err := os.RemoveAll(vsixFolder)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(vsixFolder, 0o755)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Upload VSIX folder to server")
+	stream, err := client.StreamMount(ctx, &tunnel.StreamMountRequest{Mount: vscode.VSIXMount(workspaceInfo.CLIOptions.VSIXFolder).String()})
+	if err != nil {
+		return errors.Wrap(err, "read VSIX folder")
+	}
+
+	err = extract.Extract(tunnelserver.NewStreamReader(stream, log), vsixFolder)
+	if err != nil {
+		return errors.Wrap(err, "extract VSIX folder")
+	}
+
+	if workspaceInfo.Workspace.IDE.Options == nil {
+		workspaceInfo.Workspace.IDE.Options = map[string]config.OptionValue{}
+	}
+	workspaceInfo.Workspace.IDE.Options[vscode.ExtensionsVSIXFolderOption] = config.OptionValue{Value: vsixFolder}
+	return nil
+}
+
 func prepareImage(workspaceDir, image string) error {
 	// create a .devcontainer.json with the image
 	err := os.WriteFile(filepath.Join(workspaceDir, ".devcontainer.json"), []byte(`{
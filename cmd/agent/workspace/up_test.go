@@ -0,0 +1,83 @@
+package workspace
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/agent/tunnelserver"
+	"dev.khulnasoft.com/pkg/devcontainer/config"
+	"dev.khulnasoft.com/pkg/ide/vscode"
+	provider2 "dev.khulnasoft.com/pkg/provider"
+)
+
+func TestDownloadVSIXFolder(t *testing.T) {
+	clientFolder := t.TempDir()
+	err := os.WriteFile(filepath.Join(clientFolder, "ms-python.python-1.0.0.vsix"), []byte("vsix"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the up server of the client only streams the mounts it allows
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	serverReader, clientWriter := io.Pipe()
+	clientReader, serverWriter := io.Pipe()
+	go func() {
+		_ = tunnelserver.New(log.Discard, tunnelserver.WithMounts([]*config.Mount{vscode.VSIXMount(clientFolder)})).Run(ctx, serverReader, serverWriter)
+	}()
+	tunnelClient, err := tunnelserver.NewTunnelClient(clientReader, clientWriter, false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// extensions removed on the client don't survive on the agent
+	origin := t.TempDir()
+	err = os.MkdirAll(filepath.Join(origin, "vsix"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(origin, "vsix", "stale.vsix"), []byte("stale"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	workspaceInfo := &provider2.AgentWorkspaceInfo{
+		Workspace:  &provider2.Workspace{},
+		CLIOptions: provider2.CLIOptions{VSIXFolder: clientFolder},
+		Origin:     origin,
+	}
+	err = downloadVSIXFolder(ctx, workspaceInfo, tunnelClient, log.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vsixFolder := filepath.Join(origin, "vsix")
+	out, err := os.ReadFile(filepath.Join(vsixFolder, "ms-python.python-1.0.0.vsix"))
+	if err != nil {
+		t.Fatal(err)
+	} else if string(out) != "vsix" {
+		t.Fatalf("unexpected VSIX content %q", string(out))
+	}
+	_, err = os.Stat(filepath.Join(vsixFolder, "stale.vsix"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("expected the stale VSIX file to be removed, got %v", err)
+	}
+
+	folder, err := vscode.GetLocalVSIXFolder(workspaceInfo.Workspace.IDE.Options)
+	if err != nil {
+		t.Fatal(err)
+	} else if folder != vsixFolder {
+		t.Fatalf("expected the ide options to point to %s, got %s", vsixFolder, folder)
+	}
+
+	// other folders of the client can't be read
+	workspaceInfo.CLIOptions.VSIXFolder = t.TempDir()
+	err = downloadVSIXFolder(ctx, workspaceInfo, tunnelClient, log.Discard)
+	if err == nil {
+		t.Fatal("expected streaming a folder the client didn't allow to fail")
+	}
+}
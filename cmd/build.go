@@ -48,7 +48,7 @@ func NewBuildCmd(flags *flags.GlobalFlags) *cobra.Command {
 			}
 
 			// check permissions
-			if !cmd.SkipPush && cmd.Repository != "" {
+			if !cmd.SkipPush && !cmd.Explain && cmd.Repository != "" {
 				err = image.CheckPushPermissions(cmd.Repository)
 				if err != nil {
 					return fmt.Errorf("cannot push to %s, please make sure you have push permissions to repository %s", cmd.Repository, cmd.Repository)
@@ -62,6 +62,15 @@ func NewBuildCmd(flags *flags.GlobalFlags) *cobra.Command {
 				}
 			}
 
+			// validate attestations
+			if cmd.SBOM != "" && cmd.SBOM != "spdx" && cmd.SBOM != "cyclonedx" {
+				return fmt.Errorf("unsupported sbom format %s, please use spdx or cyclonedx", cmd.SBOM)
+			} else if cmd.SBOM == "cyclonedx" && cmd.SBOMGenerator == "" {
+				return fmt.Errorf("cyclonedx sboms require a --sbom-generator image that produces CycloneDX")
+			} else if (cmd.SBOM != "" || cmd.Provenance) && cmd.SkipPush {
+				return fmt.Errorf("attestations can only be attached to pushed images, please remove --skip-push")
+			}
+
 			if devSpaceConfig.ContextOption(config.ContextOptionSSHStrictHostKeyChecking) == "true" {
 				cmd.StrictHostKeyChecking = true
 			}
@@ -128,6 +137,10 @@ func NewBuildCmd(flags *flags.GlobalFlags) *cobra.Command {
 	buildCmd.Flags().StringSliceVar(&cmd.Tag, "tag", []string{}, "Image Tag(s) in the form of a comma separated list --tag latest,arm64 or multiple flags --tag latest --tag arm64")
 	buildCmd.Flags().StringSliceVar(&cmd.Platforms, "platform", []string{}, "Set target platform for build")
 	buildCmd.Flags().BoolVar(&cmd.SkipPush, "skip-push", false, "If true will not push the image to the repository, useful for testing")
+	buildCmd.Flags().BoolVar(&cmd.Explain, "explain", false, "If true will print the inputs of the prebuild hash and whether a prebuild exists instead of building the image")
+	buildCmd.Flags().StringVar(&cmd.SBOM, "sbom", "", "Generate a software bill of materials in the given format (spdx or cyclonedx) and push it as OCI referrer of the image")
+	buildCmd.Flags().StringVar(&cmd.SBOMGenerator, "sbom-generator", "", "The BuildKit scanner image used to generate the SBOM")
+	buildCmd.Flags().BoolVar(&cmd.Provenance, "provenance", false, "If true will push SLSA provenance recording the source repository, commit, devcontainer.json and features as OCI referrer of the image")
 	buildCmd.Flags().Var(&cmd.GitCloneStrategy, "git-clone-strategy", "The git clone strategy DevSpace uses to checkout git based workspaces. Can be full (default), blobless, treeless or shallow")
 	buildCmd.Flags().BoolVar(&cmd.GitCloneRecursiveSubmodules, "git-clone-recursive-submodules", false, "If true will clone git submodule repositories recursively")
 
@@ -136,6 +149,9 @@ func NewBuildCmd(flags *flags.GlobalFlags) *cobra.Command {
 	buildCmd.Flags().BoolVar(&cmd.ForceInternalBuildKit, "force-internal-buildkit", false, "TESTING ONLY")
 	_ = buildCmd.Flags().MarkHidden("force-build")
 	_ = buildCmd.Flags().MarkHidden("force-internal-buildkit")
+
+	buildCmd.AddCommand(NewBuildDiffCmd(flags))
+	buildCmd.AddCommand(NewBuildPruneCmd(flags))
 	return buildCmd
 }
 
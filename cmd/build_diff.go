@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"dev.khulnasoft.com/cmd/flags"
+	"dev.khulnasoft.com/log"
+	config2 "dev.khulnasoft.com/pkg/devcontainer/config"
+	"dev.khulnasoft.com/pkg/image"
+	"github.com/spf13/cobra"
+)
+
+// BuildDiffCmd holds the cmd flags
+type BuildDiffCmd struct {
+	*flags.GlobalFlags
+
+	Arch string
+}
+
+// NewBuildDiffCmd creates a new command
+func NewBuildDiffCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &BuildDiffCmd{
+		GlobalFlags: flags,
+	}
+	diffCmd := &cobra.Command{
+		Use:   "diff [flags] imageA imageB",
+		Short: "Compares the prebuild hash inputs of two images",
+		Long: `Compares the prebuild hash inputs of two prebuilt images.
+
+DevSpace pushes the checksums of the prebuild hash inputs together with a prebuild
+and stores their aggregate hashes as an image label. This command shows which of
+them differ, e.g. to find out why a prebuild was not used.
+
+Example:
+devspace build diff ghcr.io/my-org/my-repo:devspace-abc ghcr.io/my-org/my-repo:devspace-def`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args[0], args[1])
+		},
+	}
+
+	diffCmd.Flags().StringVar(&cmd.Arch, "arch", "", "The architecture to compare if the images are multi-platform image indexes")
+	return diffCmd
+}
+
+// Run runs the command logic
+func (cmd *BuildDiffCmd) Run(ctx context.Context, imageA, imageB string) error {
+	manifestA, err := cmd.getPrebuildManifest(ctx, imageA)
+	if err != nil {
+		return err
+	}
+	manifestB, err := cmd.getPrebuildManifest(ctx, imageB)
+	if err != nil {
+		return err
+	}
+
+	differences := config2.DiffPrebuildHashManifests(manifestA, manifestB)
+	if len(differences) == 0 {
+		if manifestA.Hash == manifestB.Hash {
+			log.Default.Infof("Prebuild hash inputs of %s and %s are identical", imageA, imageB)
+		} else {
+			log.Default.Infof("Prebuild hash inputs of %s and %s are identical, but the hashes differ (%s != %s), the images were probably built by different DevSpace versions", imageA, imageB, manifestA.Hash, manifestB.Hash)
+		}
+		return nil
+	}
+
+	fmt.Printf("Prebuild hash: %s != %s\n", manifestA.Hash, manifestB.Hash)
+	for _, difference := range differences {
+		fmt.Println(difference)
+	}
+
+	return nil
+}
+
+// getPrebuildManifest returns the prebuild hash manifest pushed as referrer of the image, with the checksums of all
+// context files, and falls back to the aggregate hashes within the image label
+func (cmd *BuildDiffCmd) getPrebuildManifest(ctx context.Context, imageName string) (*config2.PrebuildHashManifest, error) {
+	rawManifest, err := image.GetReferrer(ctx, imageName, cmd.Arch, config2.PrebuildManifestArtifactType)
+	if err != nil {
+		log.Default.Debugf("Error retrieving prebuild hash manifest referrer of %s: %v", imageName, err)
+	} else if rawManifest != nil {
+		manifest := &config2.PrebuildHashManifest{}
+		err = json.Unmarshal(rawManifest, manifest)
+		if err != nil {
+			return nil, fmt.Errorf("parse prebuild hash manifest of %s: %w", imageName, err)
+		}
+
+		return manifest, nil
+	}
+
+	var labels map[string]string
+	if cmd.Arch != "" {
+		img, err := image.GetImageForArch(ctx, imageName, cmd.Arch)
+		if err != nil {
+			return nil, err
+		}
+
+		configFile, err := img.ConfigFile()
+		if err != nil {
+			return nil, fmt.Errorf("get image config %s: %w", imageName, err)
+		}
+		labels = configFile.Config.Labels
+	} else {
+		configFile, _, err := image.GetImageConfig(ctx, imageName, log.Default)
+		if err != nil {
+			return nil, err
+		}
+		labels = configFile.Config.Labels
+	}
+
+	rawLabel, ok := labels[config2.PrebuildManifestLabel]
+	if !ok {
+		return nil, fmt.Errorf("image %s has no prebuild hash manifest, it was probably built by an older DevSpace version", imageName)
+	}
+
+	manifest := &config2.PrebuildHashManifest{}
+	err = json.Unmarshal([]byte(rawLabel), manifest)
+	if err != nil {
+		return nil, fmt.Errorf("parse prebuild hash manifest of %s: %w", imageName, err)
+	}
+
+	return manifest, nil
+}
@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"dev.khulnasoft.com/cmd/flags"
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/log/table"
+	"dev.khulnasoft.com/pkg/config"
+	config2 "dev.khulnasoft.com/pkg/devcontainer/config"
+	"dev.khulnasoft.com/pkg/docker"
+	"dev.khulnasoft.com/pkg/image"
+	"dev.khulnasoft.com/pkg/workspace"
+	"github.com/spf13/cobra"
+)
+
+// BuildPruneCmd holds the cmd flags
+type BuildPruneCmd struct {
+	*flags.GlobalFlags
+
+	Repository     []string
+	Local          bool
+	KeepLast       int
+	KeepReferenced bool
+	DryRun         bool
+}
+
+// NewBuildPruneCmd creates a new command
+func NewBuildPruneCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &BuildPruneCmd{
+		GlobalFlags: flags,
+	}
+	pruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Deletes old prebuild images",
+		Long: `Deletes old prebuild images from prebuild repositories and the local docker daemon.
+
+Every change to the dev container configuration produces a new devspace-HASH prebuild image.
+This command deletes all but the most recent prebuilds of each git branch. Prebuilds that
+existing workspaces were created from and images with tags not managed by DevSpace are kept.
+
+Example:
+devspace build prune --repository ghcr.io/my-org/my-repo --keep-last 3 --dry-run
+devspace build prune --local`,
+		Args: cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context())
+		},
+	}
+
+	pruneCmd.Flags().StringSliceVar(&cmd.Repository, "repository", []string{}, "The prebuild repositories to prune")
+	pruneCmd.Flags().BoolVar(&cmd.Local, "local", false, "If true will prune prebuild images within the local docker daemon")
+	pruneCmd.Flags().IntVar(&cmd.KeepLast, "keep-last", 3, "The amount of most recent prebuilds to keep per git branch")
+	pruneCmd.Flags().BoolVar(&cmd.KeepReferenced, "keep-referenced", true, "If true will keep prebuilds existing workspaces were created from")
+	pruneCmd.Flags().BoolVar(&cmd.DryRun, "dry-run", false, "If true will only print the prebuilds that would be deleted")
+	return pruneCmd
+}
+
+// Run runs the command logic
+func (cmd *BuildPruneCmd) Run(ctx context.Context) error {
+	if len(cmd.Repository) == 0 && !cmd.Local {
+		return fmt.Errorf("please specify a prebuild repository via --repository or use --local")
+	} else if cmd.KeepLast < 0 {
+		return fmt.Errorf("--keep-last cannot be negative")
+	}
+
+	options := image.PruneOptions{KeepLast: cmd.KeepLast}
+	if cmd.KeepReferenced {
+		keepTags, err := cmd.getReferencedPrebuilds()
+		if err != nil {
+			return err
+		}
+		options.KeepTags = keepTags
+	}
+
+	// registry prebuilds
+	prunable := []*image.Prebuild{}
+	for _, repository := range cmd.Repository {
+		log.Default.Infof("List prebuilds in %s...", repository)
+		prebuilds, err := image.ListPrebuilds(ctx, repository)
+		if err != nil {
+			return err
+		}
+
+		prunable = append(prunable, image.SelectPrunable(prebuilds, options)...)
+	}
+
+	// local prebuilds
+	dockerHelper := &docker.DockerHelper{DockerCommand: "docker", Log: log.Default}
+	localPrunable := []*image.Prebuild{}
+	if cmd.Local {
+		prebuilds, err := listLocalPrebuilds(ctx, dockerHelper)
+		if err != nil {
+			return err
+		}
+
+		localPrunable = image.SelectPrunable(prebuilds, options)
+	}
+
+	printPrebuilds(prunable, localPrunable)
+	if len(prunable) == 0 && len(localPrunable) == 0 {
+		log.Default.Infof("No prebuilds to prune")
+		return nil
+	} else if cmd.DryRun {
+		log.Default.Infof("Would delete %d prebuild(s), run without --dry-run to delete them", len(prunable)+len(localPrunable))
+		return nil
+	}
+
+	for _, prebuild := range prunable {
+		log.Default.Infof("Delete prebuild %s:%s", prebuild.Repository, strings.Join(prebuild.Tags, ","))
+		err := image.DeletePrebuild(ctx, prebuild)
+		if err != nil {
+			return err
+		}
+	}
+	for _, prebuild := range localPrunable {
+		for _, tag := range prebuild.Tags {
+			log.Default.Infof("Delete local image %s:%s", prebuild.Repository, tag)
+			err := dockerHelper.Run(ctx, []string{"image", "rm", prebuild.Repository + ":" + tag}, nil, nil, nil)
+			if err != nil {
+				// the image is probably still used by a container
+				log.Default.Warnf("Couldn't delete local image %s:%s: %v", prebuild.Repository, tag, err)
+			}
+		}
+	}
+
+	log.Default.Donef("Pruned %d prebuild(s)", len(prunable)+len(localPrunable))
+	return nil
+}
+
+// getReferencedPrebuilds returns the prebuild tags of all workspaces in all contexts
+func (cmd *BuildPruneCmd) getReferencedPrebuilds() (map[string]bool, error) {
+	devSpaceConfig, err := config.LoadConfig(cmd.Context, cmd.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	keepTags := map[string]bool{}
+	for contextName := range devSpaceConfig.Contexts {
+		workspaces, err := workspace.ListLocalWorkspaces(contextName, false, log.Default)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, workspaceConfig := range workspaces {
+			if workspaceConfig.PrebuildHash != "" {
+				keepTags[workspaceConfig.PrebuildHash] = true
+			}
+		}
+	}
+
+	return keepTags, nil
+}
+
+// listLocalPrebuilds returns the prebuild images within the local docker daemon grouped by image id and repository
+func listLocalPrebuilds(ctx context.Context, dockerHelper *docker.DockerHelper) ([]*image.Prebuild, error) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	err := dockerHelper.Run(ctx, []string{"image", "ls", "--format", "{{json .}}"}, nil, stdout, stderr)
+	if err != nil {
+		return nil, fmt.Errorf("list local images: %w: %s", err, stderr.String())
+	}
+
+	prebuilds := map[string]*image.Prebuild{}
+	ids := []string{}
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		localImage := struct {
+			ID         string `json:"ID"`
+			Repository string `json:"Repository"`
+			Tag        string `json:"Tag"`
+		}{}
+		err := json.Unmarshal(scanner.Bytes(), &localImage)
+		if err != nil {
+			return nil, fmt.Errorf("parse local image: %w", err)
+		} else if !strings.HasPrefix(localImage.Tag, image.PrebuildTagPrefix) {
+			continue
+		}
+
+		key := localImage.ID + "@" + localImage.Repository
+		if prebuilds[key] == nil {
+			prebuilds[key] = &image.Prebuild{
+				Repository: localImage.Repository,
+				Digests:    []string{localImage.ID},
+			}
+			ids = append(ids, localImage.ID)
+		}
+		prebuilds[key].Tags = append(prebuilds[key].Tags, localImage.Tag)
+	}
+	if len(prebuilds) == 0 {
+		return nil, nil
+	}
+
+	// get branch and creation time
+	details := []struct {
+		ID      string    `json:"Id"`
+		Created time.Time `json:"Created"`
+		Config  struct {
+			Labels map[string]string `json:"Labels"`
+		} `json:"Config"`
+	}{}
+	err = dockerHelper.Inspect(ctx, ids, "image", &details)
+	if err != nil {
+		return nil, err
+	}
+
+	retPrebuilds := []*image.Prebuild{}
+	for _, prebuild := range prebuilds {
+		for _, detail := range details {
+			if strings.HasPrefix(strings.TrimPrefix(detail.ID, "sha256:"), prebuild.Digests[0]) {
+				prebuild.Created = detail.Created
+				prebuild.Branch = detail.Config.Labels[config2.PrebuildBranchLabel]
+				break
+			}
+		}
+
+		retPrebuilds = append(retPrebuilds, prebuild)
+	}
+
+	return retPrebuilds, nil
+}
+
+func printPrebuilds(prebuilds, localPrebuilds []*image.Prebuild) {
+	tableEntries := [][]string{}
+	for _, prebuild := range prebuilds {
+		tableEntries = append(tableEntries, prebuildTableEntry(prebuild, "registry"))
+	}
+	for _, prebuild := range localPrebuilds {
+		tableEntries = append(tableEntries, prebuildTableEntry(prebuild, "local"))
+	}
+	if len(tableEntries) == 0 {
+		return
+	}
+
+	table.PrintTable(log.Default, []string{
+		"Location",
+		"Repository",
+		"Tags",
+		"Branch",
+		"Created",
+	}, tableEntries)
+}
+
+func prebuildTableEntry(prebuild *image.Prebuild, location string) []string {
+	created := ""
+	if !prebuild.Created.IsZero() {
+		created = prebuild.Created.Local().Format(time.DateTime)
+	}
+
+	return []string{
+		location,
+		prebuild.Repository,
+		strings.Join(prebuild.Tags, ","),
+		prebuild.Branch,
+		created,
+	}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"dev.khulnasoft.com/cmd/flags"
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/agent"
+	client2 "dev.khulnasoft.com/pkg/client"
+	"dev.khulnasoft.com/pkg/config"
+	"dev.khulnasoft.com/pkg/daemon/local"
+	"dev.khulnasoft.com/pkg/provider"
+	workspace2 "dev.khulnasoft.com/pkg/workspace"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+)
+
+// DaemonCmd holds the daemon cmd flags
+type DaemonCmd struct {
+	*flags.GlobalFlags
+}
+
+// NewDaemonCmd creates a new daemon command
+func NewDaemonCmd(f *flags.GlobalFlags) *cobra.Command {
+	cmd := &DaemonCmd{
+		GlobalFlags: f,
+	}
+	return &cobra.Command{
+		Use:    "daemon",
+		Short:  "Keeps connections to workspaces open that devspace ssh sessions attach to",
+		Args:   cobra.NoArgs,
+		Hidden: true,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			ctx, cancel := WithSignals(cobraCmd.Context())
+			defer cancel()
+
+			return cmd.Run(ctx)
+		},
+	}
+}
+
+// Run runs the command logic
+func (cmd *DaemonCmd) Run(ctx context.Context) error {
+	devSpaceConfig, err := config.LoadConfig(cmd.Context, cmd.Provider)
+	if err != nil {
+		return err
+	}
+
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return err
+	}
+	logLevel := logrus.InfoLevel
+	if cmd.Debug {
+		logLevel = logrus.DebugLevel
+	}
+	logger := log.NewFileLogger(filepath.Join(configDir, "daemon", devSpaceConfig.DefaultContext+".log"), logLevel)
+
+	idleTimeout, err := strconv.Atoi(devSpaceConfig.ContextOption(config.ContextOptionSSHMultiplexingIdleTimeout))
+	if err != nil || idleTimeout <= 0 {
+		idleTimeout = 600
+	}
+
+	command := fmt.Sprintf("'%s' helper ssh-server --stdio", agent.ContainerDevSpaceHelperLocation)
+	if cmd.Debug {
+		command += " --debug"
+	}
+
+	d, err := local.Init(local.InitConfig{
+		Context:     devSpaceConfig.DefaultContext,
+		Command:     command,
+		IdleTimeout: time.Duration(idleTimeout) * time.Second,
+		Connect: func(ctx context.Context, workspaceID string, handler func(ctx context.Context, containerClient *ssh.Client) error) error {
+			return cmd.connect(ctx, devSpaceConfig.DefaultContext, workspaceID, handler, logger)
+		},
+		CheckUpdates: func(ctx context.Context, workspaceID string) error {
+			return checkImageUpdates(ctx, devSpaceConfig.DefaultContext, workspaceID)
+		},
+		// the cache of the image update check decides when the registries are queried again
+		UpdateCheckInterval: 5 * time.Minute,
+	}, logger)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Serving ssh sessions of context %s", devSpaceConfig.DefaultContext)
+	return d.Start(ctx)
+}
+
+// connect resolves the workspace and connects to its container like devspace ssh does, but never starts or creates
+// the workspace
+func (cmd *DaemonCmd) connect(ctx context.Context, devSpaceContext, workspaceID string, handler func(ctx context.Context, containerClient *ssh.Client) error, log log.Logger) error {
+	// the config is loaded for every connection, as it may have changed since the daemon started
+	devSpaceConfig, err := config.LoadConfig(devSpaceContext, "")
+	if err != nil {
+		return err
+	}
+
+	client, err := workspace2.Get(ctx, devSpaceConfig, []string{workspaceID}, false, cmd.Owner, true, log)
+	if err != nil {
+		return err
+	}
+
+	workspaceClient, ok := client.(client2.WorkspaceClient)
+	if !ok {
+		return fmt.Errorf("workspace %s doesn't support ssh multiplexing", workspaceID)
+	}
+
+	sshCmd := &SSHCmd{GlobalFlags: cmd.GlobalFlags}
+	return sshCmd.jumpContainer(ctx, devSpaceConfig, workspaceClient, handler, log)
+}
+
+// checkImageUpdates refreshes the cached image updates of the workspace that devspace status reports
+func checkImageUpdates(ctx context.Context, devSpaceContext, workspaceID string) error {
+	workspace, err := provider.LoadWorkspaceConfig(devSpaceContext, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	_, err = workspace2.CheckImageUpdates(ctx, workspace, workspace2.DefaultUpdateCheckInterval)
+	return err
+}
@@ -5,6 +5,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
 	"github.com/blang/semver"
@@ -23,7 +25,8 @@ var (
 
 type CheckProviderUpdateCmd struct {
 	*flags.GlobalFlags
-	log log.Logger
+	log    log.Logger
+	stdout io.Writer
 }
 
 type providerVersionCheck struct {
@@ -36,6 +39,7 @@ func NewCheckProviderUpdateCmd(flags *flags.GlobalFlags) *cobra.Command {
 	cmd := &CheckProviderUpdateCmd{
 		GlobalFlags: flags,
 		log:         log.Default,
+		stdout:      os.Stdout,
 	}
 	shellCmd := &cobra.Command{
 		Use:   "check-provider-update",
@@ -58,13 +62,15 @@ func (cmd *CheckProviderUpdateCmd) Run(ctx context.Context, devSpaceConfig *conf
 	}
 	providerName := args[0]
 
-	providerSourceRaw, err := workspace.ResolveProviderSource(devSpaceConfig, providerName, cmd.log)
+	// the desktop app parses the output as json, so nothing else may be written to stdout
+	logger := cmd.log.ErrorStreamOnly()
+	providerSourceRaw, err := workspace.ResolveProviderSource(devSpaceConfig, providerName, logger)
 	if err != nil {
 		return fmt.Errorf("provider %s doesn't exist", providerName)
 	}
 
 	// retrieve current config for provider
-	allProviders, err := workspace.LoadAllProviders(devSpaceConfig, cmd.log)
+	allProviders, err := workspace.LoadAllProviders(devSpaceConfig, logger)
 	if err != nil {
 		return err
 	}
@@ -73,7 +79,7 @@ func (cmd *CheckProviderUpdateCmd) Run(ctx context.Context, devSpaceConfig *conf
 		return errProviderNotFound
 	}
 
-	latestProviderConfig, err := loadLatestProvider(providerSourceRaw, cmd.log)
+	latestProviderConfig, err := loadLatestProvider(devSpaceConfig, providerName, providerSourceRaw, logger)
 	if err != nil {
 		return err
 	}
@@ -96,16 +102,20 @@ func (cmd *CheckProviderUpdateCmd) Run(ctx context.Context, devSpaceConfig *conf
 	if err != nil {
 		return err
 	}
-	fmt.Println(string(out))
+	fmt.Fprintln(cmd.stdout, string(out))
 
 	return nil
 }
 
-func loadLatestProvider(providerSourceRaw string, log log.Logger) (*provider.ProviderConfig, error) {
-	providerRaw, _, err := workspace.ResolveProvider(providerSourceRaw, log)
+func loadLatestProvider(devSpaceConfig *config.Config, providerName, providerSourceRaw string, log log.Logger) (*provider.ProviderConfig, error) {
+	providerRaw, providerSource, err := workspace.ResolveProvider(devSpaceConfig, providerSourceRaw, log)
 	if err != nil {
 		return nil, errors.Wrap(err, "resolve provider")
 	}
+	_, err = workspace.VerifyProviderSignature(devSpaceConfig, providerName, providerRaw, providerSource, log)
+	if err != nil {
+		return nil, errors.Wrap(err, "verify provider")
+	}
 	providerConfig, err := provider.ParseProvider(bytes.NewReader(providerRaw))
 	if err != nil {
 		return nil, errors.Wrap(err, "parse provider")
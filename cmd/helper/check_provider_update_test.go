@@ -0,0 +1,107 @@
+package helper
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/config"
+	"dev.khulnasoft.com/pkg/provider"
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+const testProvider = `name: test
+version: %s
+exec:
+  command: echo
+`
+
+func TestCheckProviderUpdateOutput(t *testing.T) {
+	t.Setenv(config.DEVSPACE_HOME, t.TempDir())
+
+	// the latest provider is signed, its publisher key is trusted on first use
+	sourceDir := t.TempDir()
+	sourceFile := filepath.Join(sourceDir, "provider.yaml")
+	rawProvider := []byte(fmt.Sprintf(testProvider, "v0.0.2"))
+	publicKey, rawSignature := signMinisign(t, rawProvider)
+	for file, content := range map[string][]byte{
+		sourceFile:                               rawProvider,
+		sourceFile + ".minisig":                  rawSignature,
+		filepath.Join(sourceDir, "minisign.pub"): []byte(publicKey),
+	} {
+		err := os.WriteFile(file, content, 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	currentProvider, err := provider.ParseProvider(bytes.NewReader([]byte(fmt.Sprintf(testProvider, "v0.0.1"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	currentProvider.Source.File = sourceFile
+	err = provider.SaveProviderConfig("default", currentProvider)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// logs and the json are written to the same stdout, like in a terminal
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd := &CheckProviderUpdateCmd{
+		log:    log.NewStdoutLogger(nil, stdout, stderr, logrus.InfoLevel),
+		stdout: stdout,
+	}
+	devSpaceConfig := &config.Config{
+		DefaultContext: "default",
+		Contexts: map[string]*config.ContextConfig{
+			"default": {Providers: map[string]*config.ProviderConfig{"test": {}}},
+		},
+	}
+	err = cmd.Run(context.Background(), devSpaceConfig, []string{"test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	versionCheck := providerVersionCheck{}
+	err = json.Unmarshal(stdout.Bytes(), &versionCheck)
+	if err != nil {
+		t.Fatalf("stdout is not json: %v\n%s", err, stdout.String())
+	}
+	if diff := cmp.Diff(providerVersionCheck{UpdateAvailable: true, LatestVersion: "v0.0.2"}, versionCheck); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+	if !bytes.Contains(stderr.Bytes(), []byte("Verified signature")) {
+		t.Errorf("expected the signature verification to be logged to stderr, got %q", stderr.String())
+	}
+}
+
+// signMinisign returns a minisign public key and the detached signature of the message
+func signMinisign(t *testing.T, message []byte) (string, []byte) {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyID := binary.LittleEndian.AppendUint64(nil, 42)
+	rawKey := append(append([]byte("Ed"), keyID...), public...)
+	signature := ed25519.Sign(private, message)
+	trustedComment := "timestamp:1700000000\tfile:provider.yaml"
+	globalSignature := ed25519.Sign(private, append(append([]byte{}, signature...), trustedComment...))
+	rawSignature := append(append([]byte("Ed"), keyID...), signature...)
+
+	return "untrusted comment: minisign public key\n" + base64.StdEncoding.EncodeToString(rawKey) + "\n",
+		[]byte(fmt.Sprintf("untrusted comment: signature from minisign secret key\n%s\ntrusted comment: %s\n%s\n",
+			base64.StdEncoding.EncodeToString(rawSignature),
+			trustedComment,
+			base64.StdEncoding.EncodeToString(globalSignature),
+		))
+}
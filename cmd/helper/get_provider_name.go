@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"dev.khulnasoft.com/cmd/flags"
+	"dev.khulnasoft.com/pkg/config"
 	"dev.khulnasoft.com/pkg/provider"
 	"dev.khulnasoft.com/pkg/workspace"
 	"dev.khulnasoft.com/log"
@@ -38,7 +39,12 @@ func (cmd *GetProviderNameCmd) Run(ctx context.Context, args []string) error {
 		return fmt.Errorf("provider is missing")
 	}
 
-	providerRaw, _, err := workspace.ResolveProvider(args[0], log.Default.ErrorStreamOnly())
+	devSpaceConfig, err := config.LoadConfig(cmd.Context, cmd.Provider)
+	if err != nil {
+		return err
+	}
+
+	providerRaw, _, err := workspace.ResolveProvider(devSpaceConfig, args[0], log.Default.ErrorStreamOnly())
 	if err != nil {
 		return errors.Wrap(err, "resolve provider")
 	}
@@ -0,0 +1,88 @@
+package ide
+
+import (
+	"context"
+	"fmt"
+
+	"dev.khulnasoft.com/cmd/flags"
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/config"
+	"dev.khulnasoft.com/pkg/ide/custom"
+	"dev.khulnasoft.com/pkg/ide/ideparse"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// AddCmd holds the add cmd flags
+type AddCmd struct {
+	flags.GlobalFlags
+
+	Name string
+	Use  bool
+}
+
+// NewAddCmd creates a new command
+func NewAddCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &AddCmd{
+		GlobalFlags: *flags,
+	}
+	addCmd := &cobra.Command{
+		Use:   "add [source]",
+		Short: "Add an IDE from an ide.yaml manifest",
+		Long: `Add an IDE from an ide.yaml manifest
+
+The source can be a path to a local ide.yaml or a URL. Adding an IDE with
+the same name again updates it.`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("please specify the ide manifest to add")
+			}
+
+			return cmd.Run(context.Background(), args[0])
+		},
+	}
+
+	addCmd.Flags().StringVar(&cmd.Name, "name", "", "The name to use for this IDE. If empty will use the name within the manifest")
+	addCmd.Flags().BoolVar(&cmd.Use, "use", false, "If enabled will use the IDE as default IDE")
+	return addCmd
+}
+
+// Run runs the command logic
+func (cmd *AddCmd) Run(ctx context.Context, source string) error {
+	devSpaceConfig, err := config.LoadConfig(cmd.Context, cmd.Provider)
+	if err != nil {
+		return err
+	}
+
+	ideConfig, err := custom.ResolveIDE(source, log.Default)
+	if err != nil {
+		return err
+	}
+	if cmd.Name != "" {
+		err = custom.ValidateName(cmd.Name)
+		if err != nil {
+			return errors.Wrap(err, "validate --name")
+		}
+
+		ideConfig.Name = cmd.Name
+	}
+	if ideparse.IsBuiltinIDE(ideConfig.Name) {
+		return fmt.Errorf("ide '%s' is built into DevSpace, please choose a different name with --name", ideConfig.Name)
+	}
+
+	err = custom.SaveIDEConfig(devSpaceConfig.DefaultContext, ideConfig)
+	if err != nil {
+		return errors.Wrap(err, "save ide")
+	}
+
+	if cmd.Use {
+		devSpaceConfig.Current().DefaultIDE = ideConfig.Name
+		err = config.SaveConfig(devSpaceConfig)
+		if err != nil {
+			return errors.Wrap(err, "save config")
+		}
+	}
+
+	log.Default.Donef("Successfully added ide '%s', use it with 'devspace up --ide %s'", ideConfig.Name, ideConfig.Name)
+	return nil
+}
@@ -0,0 +1,74 @@
+package ide
+
+import (
+	"context"
+	"fmt"
+
+	"dev.khulnasoft.com/cmd/flags"
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/config"
+	"dev.khulnasoft.com/pkg/ide/custom"
+	"dev.khulnasoft.com/pkg/ide/ideparse"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// DeleteCmd holds the delete cmd flags
+type DeleteCmd struct {
+	flags.GlobalFlags
+}
+
+// NewDeleteCmd creates a new command
+func NewDeleteCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &DeleteCmd{
+		GlobalFlags: *flags,
+	}
+	deleteCmd := &cobra.Command{
+		Use:   "delete [name]",
+		Short: "Delete an IDE added with 'devspace ide add'",
+		RunE: func(_ *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("please specify the ide to delete")
+			}
+
+			return cmd.Run(context.Background(), args[0])
+		},
+	}
+
+	return deleteCmd
+}
+
+// Run runs the command logic
+func (cmd *DeleteCmd) Run(ctx context.Context, ide string) error {
+	devSpaceConfig, err := config.LoadConfig(cmd.Context, cmd.Provider)
+	if err != nil {
+		return err
+	}
+
+	if ideparse.IsBuiltinIDE(ide) {
+		return fmt.Errorf("ide '%s' is built into DevSpace and cannot be deleted", ide)
+	}
+
+	ideConfig, err := custom.LoadIDEConfig(devSpaceConfig.DefaultContext, ide)
+	if err != nil {
+		return err
+	} else if ideConfig == nil {
+		return fmt.Errorf("ide '%s' not found", ide)
+	}
+
+	err = custom.DeleteIDEConfig(devSpaceConfig.DefaultContext, ide)
+	if err != nil {
+		return errors.Wrap(err, "delete ide")
+	}
+
+	if devSpaceConfig.Current().DefaultIDE == ide {
+		devSpaceConfig.Current().DefaultIDE = ""
+		err = config.SaveConfig(devSpaceConfig)
+		if err != nil {
+			return errors.Wrap(err, "save config")
+		}
+	}
+
+	log.Default.Donef("Successfully deleted ide '%s'", ide)
+	return nil
+}
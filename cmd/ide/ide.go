@@ -16,5 +16,8 @@ func NewIDECmd(flags *flags.GlobalFlags) *cobra.Command {
 	ideCmd.AddCommand(NewSetOptionsCmd(flags))
 	ideCmd.AddCommand(NewOptionsCmd(flags))
 	ideCmd.AddCommand(NewListCmd(flags))
+	ideCmd.AddCommand(NewAddCmd(flags))
+	ideCmd.AddCommand(NewDeleteCmd(flags))
+	ideCmd.AddCommand(NewStatusCmd(flags))
 	return ideCmd
 }
@@ -53,9 +53,14 @@ func (cmd *ListCmd) Run(ctx context.Context) error {
 		return err
 	}
 
+	allowedIDEs, err := ideparse.GetAllowedIDEs(devSpaceConfig)
+	if err != nil {
+		return err
+	}
+
 	if cmd.Output == "plain" {
 		tableEntries := [][]string{}
-		for _, entry := range ideparse.AllowedIDEs {
+		for _, entry := range allowedIDEs {
 			tableEntries = append(tableEntries, []string{
 				string(entry.Name),
 				strconv.FormatBool(devSpaceConfig.Current().DefaultIDE == string(entry.Name)),
@@ -71,7 +76,7 @@ func (cmd *ListCmd) Run(ctx context.Context) error {
 		}, tableEntries)
 	} else if cmd.Output == "json" {
 		ides := []IDEWithDefault{}
-		for _, entry := range ideparse.AllowedIDEs {
+		for _, entry := range allowedIDEs {
 			ides = append(ides, IDEWithDefault{
 				AllowedIDE: entry,
 				Default:    devSpaceConfig.Current().DefaultIDE == string(entry.Name),
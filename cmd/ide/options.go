@@ -57,7 +57,7 @@ func (cmd *OptionsCmd) Run(ctx context.Context, ide string) error {
 	}
 
 	values := devSpaceConfig.IDEOptions(ide)
-	ideOptions, err := ideparse.GetIDEOptions(ide)
+	ideOptions, err := ideparse.GetIDEOptions(devSpaceConfig, ide)
 	if err != nil {
 		return err
 	}
@@ -48,7 +48,7 @@ func (cmd *SetOptionsCmd) Run(ctx context.Context, ide string) error {
 	}
 
 	ide = strings.ToLower(ide)
-	ideOptions, err := ideparse.GetIDEOptions(ide)
+	ideOptions, err := ideparse.GetIDEOptions(devSpaceConfig, ide)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,165 @@
+package ide
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"dev.khulnasoft.com/cmd/completion"
+	"dev.khulnasoft.com/cmd/flags"
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/log/table"
+	"dev.khulnasoft.com/pkg/agent"
+	client2 "dev.khulnasoft.com/pkg/client"
+	"dev.khulnasoft.com/pkg/config"
+	"dev.khulnasoft.com/pkg/ide"
+	"dev.khulnasoft.com/pkg/ide/ideparse"
+	workspace2 "dev.khulnasoft.com/pkg/workspace"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// StatusCmd holds the status cmd flags
+type StatusCmd struct {
+	flags.GlobalFlags
+
+	Output string
+}
+
+// NewStatusCmd creates a new command
+func NewStatusCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &StatusCmd{
+		GlobalFlags: *flags,
+	}
+	statusCmd := &cobra.Command{
+		Use:   "status [flags] [workspace-path|workspace-name]",
+		Short: "List the IDE servers running in a workspace",
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+		ValidArgsFunction: func(rootCmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GetWorkspaceSuggestions(rootCmd, cmd.Context, cmd.Provider, args, toComplete, cmd.Owner, log.Default)
+		},
+	}
+
+	statusCmd.Flags().StringVar(&cmd.Output, "output", "plain", "The output format to use. Can be json or plain")
+	return statusCmd
+}
+
+// Run runs the command logic
+func (cmd *StatusCmd) Run(ctx context.Context, args []string) error {
+	devSpaceConfig, err := config.LoadConfig(cmd.Context, cmd.Provider)
+	if err != nil {
+		return err
+	}
+
+	logger := log.Default.ErrorStreamOnly()
+	client, err := workspace2.Get(ctx, devSpaceConfig, args, false, cmd.Owner, false, logger)
+	if err != nil {
+		return err
+	}
+
+	instanceStatus, err := client.Status(ctx, client2.StatusOptions{})
+	if err != nil {
+		return err
+	} else if instanceStatus != client2.StatusRunning {
+		return fmt.Errorf("workspace '%s' is '%s', you can start it via 'devspace up %s'", client.Workspace(), instanceStatus, client.Workspace())
+	}
+
+	servers, err := findRunningServers(devSpaceConfig, client, logger)
+	if err != nil {
+		return err
+	}
+
+	return printServers(log.Default, os.Stdout, servers, client.WorkspaceConfig().IDE.Name, cmd.Output)
+}
+
+// printServers prints the running servers in the given output format and marks the server of the default IDE of the
+// workspace. Plain output is printed as table to the logger.
+func printServers(logger log.Logger, stdout io.Writer, servers []ide.RunningServer, defaultIDE string, output string) error {
+	if output == "plain" {
+		tableEntries := [][]string{}
+		for _, server := range servers {
+			tableEntries = append(tableEntries, []string{
+				server.IDE,
+				strconv.Itoa(server.PID),
+				strconv.FormatBool(defaultIDE == server.IDE),
+			})
+		}
+
+		table.PrintTable(logger, []string{
+			"IDE",
+			"PID",
+			"Default",
+		}, tableEntries)
+	} else if output == "json" {
+		out, err := json.MarshalIndent(servers, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprint(stdout, string(out))
+		if err != nil {
+			return err
+		}
+	} else {
+		return fmt.Errorf("unexpected output format, choose either json or plain. Got %s", output)
+	}
+
+	return nil
+}
+
+func findRunningServers(devSpaceConfig *config.Config, client client2.BaseWorkspaceClient, log log.Logger) ([]ide.RunningServer, error) {
+	allowedIDEs, err := ideparse.GetAllowedIDEs(devSpaceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	agentCommand := fmt.Sprintf("'%s' agent container ide-status", agent.ContainerDevSpaceHelperLocation)
+	for _, allowedIDE := range allowedIDEs {
+		if !ideparse.IsBuiltinIDE(string(allowedIDE.Name)) {
+			agentCommand += fmt.Sprintf(" --custom-ide '%s'", allowedIDE.Name)
+		}
+	}
+
+	execPath, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	writer := log.Writer(logrus.DebugLevel, false)
+	defer writer.Close()
+
+	stdout := &bytes.Buffer{}
+	statusCmd := exec.Command(
+		execPath,
+		"ssh",
+		"--start-services=false",
+		"--user",
+		"root",
+		"--context",
+		client.Context(),
+		client.Workspace(),
+		"--log-output=raw",
+		"--command",
+		agentCommand,
+	)
+	statusCmd.Stdout = stdout
+	statusCmd.Stderr = writer
+	err = statusCmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("retrieve running IDEs: %w", err)
+	}
+
+	servers := []ide.RunningServer{}
+	err = json.Unmarshal(stdout.Bytes(), &servers)
+	if err != nil {
+		return nil, fmt.Errorf("parse running IDEs: %w", err)
+	}
+
+	return servers, nil
+}
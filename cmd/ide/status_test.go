@@ -0,0 +1,70 @@
+package ide
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/ide"
+	"github.com/sirupsen/logrus"
+)
+
+func TestPrintServers(t *testing.T) {
+	servers := []ide.RunningServer{
+		{IDE: "vscode", PID: 42},
+		{IDE: "openvscode", PID: 1234},
+	}
+
+	// plain output is a table with the default ide marked
+	logs := &bytes.Buffer{}
+	stdout := &bytes.Buffer{}
+	err := printServers(log.NewStreamLogger(logs, logs, logrus.InfoLevel), stdout, servers, "openvscode", "plain")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout.Len() > 0 {
+		t.Errorf("printServers() wrote %q to stdout, want the table in the logs", stdout.String())
+	}
+
+	rows := map[string][]string{}
+	for _, line := range strings.Split(logs.String(), "\n") {
+		fields := strings.Fields(strings.ReplaceAll(line, "|", " "))
+		if len(fields) == 3 {
+			rows[fields[0]] = fields[1:]
+		}
+	}
+	for ideName, want := range map[string][]string{"vscode": {"42", "false"}, "openvscode": {"1234", "true"}} {
+		if got := strings.Join(rows[ideName], " "); got != strings.Join(want, " ") {
+			t.Errorf("printServers() row of %s = %q, want %q\n%s", ideName, got, strings.Join(want, " "), logs.String())
+		}
+	}
+
+	// json output is printed to stdout, so it can be parsed
+	logs.Reset()
+	err = printServers(log.NewStreamLogger(logs, logs, logrus.InfoLevel), stdout, servers, "openvscode", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `[
+  {
+    "ide": "vscode",
+    "pid": 42
+  },
+  {
+    "ide": "openvscode",
+    "pid": 1234
+  }
+]`
+	if stdout.String() != want {
+		t.Errorf("printServers() = %q, want %q", stdout.String(), want)
+	}
+	if logs.Len() > 0 {
+		t.Errorf("printServers() logged %q, want no logs for json output", logs.String())
+	}
+
+	err = printServers(log.Discard, stdout, servers, "openvscode", "yaml")
+	if err == nil {
+		t.Errorf("printServers() expected an error for an unknown output format")
+	}
+}
@@ -52,7 +52,7 @@ func (cmd *UseCmd) Run(ctx context.Context, ide string) error {
 	}
 
 	ide = strings.ToLower(ide)
-	ideOptions, err := ideparse.GetIDEOptions(ide)
+	ideOptions, err := ideparse.GetIDEOptions(devSpaceConfig, ide)
 	if err != nil {
 		return err
 	}
@@ -70,6 +70,7 @@ func (cmd *ListCmd) Run(ctx context.Context) error {
 			tableEntries = append(tableEntries, []string{
 				machineConfig.ID,
 				machineConfig.Provider.Name,
+				machineConfig.Pool,
 				time.Since(machineConfig.CreationTimestamp.Time).Round(1 * time.Second).String(),
 			})
 		}
@@ -80,6 +81,7 @@ func (cmd *ListCmd) Run(ctx context.Context) error {
 		table.PrintTable(log.Default, []string{
 			"Name",
 			"Provider",
+			"Pool",
 			"Age",
 		}, tableEntries)
 	} else if cmd.Output == "json" {
@@ -2,6 +2,7 @@ package machine
 
 import (
 	"dev.khulnasoft.com/cmd/flags"
+	"dev.khulnasoft.com/cmd/machine/pool"
 	"github.com/spf13/cobra"
 )
 
@@ -20,5 +21,6 @@ func NewMachineCmd(flags *flags.GlobalFlags) *cobra.Command {
 	machineCmd.AddCommand(NewDeleteCmd(flags))
 	machineCmd.AddCommand(NewCreateCmd(flags))
 	machineCmd.AddCommand(NewInspectCmd(flags))
+	machineCmd.AddCommand(pool.NewPoolCmd(flags))
 	return machineCmd
 }
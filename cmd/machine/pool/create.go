@@ -0,0 +1,87 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+
+	"dev.khulnasoft.com/cmd/flags"
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/config"
+	"dev.khulnasoft.com/pkg/provider"
+	"dev.khulnasoft.com/pkg/workspace"
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+)
+
+// CreateCmd holds the configuration
+type CreateCmd struct {
+	*flags.GlobalFlags
+
+	Size            int
+	State           string
+	CPUs            int
+	Memory          string
+	MaxWorkspaces   int
+	ProviderOptions []string
+}
+
+// NewCreateCmd creates a new command
+func NewCreateCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &CreateCmd{
+		GlobalFlags: flags,
+	}
+	createCmd := &cobra.Command{
+		Use:   "create [name]",
+		Short: "Creates a new machine pool",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return cmd.Run(context.Background(), args[0])
+		},
+	}
+
+	createCmd.Flags().IntVar(&cmd.Size, "size", 1, "The amount of unused machines the pool keeps ready")
+	createCmd.Flags().StringVar(&cmd.State, "state", provider.MachinePoolStateStopped, "The state unused machines are kept in. Can be either running or stopped")
+	createCmd.Flags().IntVar(&cmd.CPUs, "cpus", 0, "The amount of cpus a pool machine offers to workspaces. 0 means unlimited")
+	createCmd.Flags().StringVar(&cmd.Memory, "memory", "", "The amount of memory a pool machine offers to workspaces, e.g. 16gb. Empty means unlimited")
+	createCmd.Flags().IntVar(&cmd.MaxWorkspaces, "max-workspaces", 1, "The maximum amount of workspaces that share a pool machine. 0 means unlimited")
+	createCmd.Flags().StringSliceVar(&cmd.ProviderOptions, "provider-option", []string{}, "Provider option in the form KEY=VALUE")
+	return createCmd
+}
+
+// Run runs the command logic
+func (cmd *CreateCmd) Run(ctx context.Context, name string) error {
+	devSpaceConfig, err := config.LoadConfig(cmd.Context, cmd.Provider)
+	if err != nil {
+		return err
+	} else if devSpaceConfig.Current().DefaultProvider == "" {
+		return fmt.Errorf("no provider selected, please specify a provider via --provider")
+	}
+
+	pool := &provider.MachinePool{
+		Name: name,
+		Provider: provider.MachineProviderConfig{
+			Name: devSpaceConfig.Current().DefaultProvider,
+		},
+		ProviderOptions: cmd.ProviderOptions,
+		Size:            cmd.Size,
+		State:           cmd.State,
+		Capacity: provider.MachineResources{
+			CPUs: cmd.CPUs,
+		},
+		MaxWorkspaces: cmd.MaxWorkspaces,
+	}
+	if cmd.Memory != "" {
+		pool.Capacity.Memory, err = units.RAMInBytes(cmd.Memory)
+		if err != nil {
+			return fmt.Errorf("parse memory %s: %w", cmd.Memory, err)
+		}
+	}
+
+	err = workspace.CreateMachinePool(ctx, devSpaceConfig, pool, log.Default)
+	if err != nil {
+		return err
+	}
+
+	log.Default.Donef("Successfully created machine pool %s", name)
+	return nil
+}
@@ -0,0 +1,49 @@
+package pool
+
+import (
+	"context"
+
+	"dev.khulnasoft.com/cmd/flags"
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/config"
+	"dev.khulnasoft.com/pkg/workspace"
+	"github.com/spf13/cobra"
+)
+
+// DeleteCmd holds the configuration
+type DeleteCmd struct {
+	*flags.GlobalFlags
+}
+
+// NewDeleteCmd creates a new command
+func NewDeleteCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &DeleteCmd{
+		GlobalFlags: flags,
+	}
+	deleteCmd := &cobra.Command{
+		Use:   "delete [name]",
+		Short: "Deletes a machine pool and its unused machines",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return cmd.Run(context.Background(), args[0])
+		},
+	}
+
+	return deleteCmd
+}
+
+// Run runs the command logic
+func (cmd *DeleteCmd) Run(ctx context.Context, name string) error {
+	devSpaceConfig, err := config.LoadConfig(cmd.Context, cmd.Provider)
+	if err != nil {
+		return err
+	}
+
+	err = workspace.DeleteMachinePool(ctx, devSpaceConfig, name, log.Default)
+	if err != nil {
+		return err
+	}
+
+	log.Default.Donef("Successfully deleted machine pool %s", name)
+	return nil
+}
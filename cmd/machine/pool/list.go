@@ -0,0 +1,121 @@
+package pool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"dev.khulnasoft.com/cmd/flags"
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/log/table"
+	"dev.khulnasoft.com/pkg/config"
+	"dev.khulnasoft.com/pkg/provider"
+	"dev.khulnasoft.com/pkg/workspace"
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+)
+
+// ListCmd holds the configuration
+type ListCmd struct {
+	*flags.GlobalFlags
+
+	Output string
+}
+
+type poolWithUsage struct {
+	*provider.MachinePool
+
+	Usage *workspace.MachinePoolUsage `json:"usage,omitempty"`
+}
+
+// NewListCmd creates a new command
+func NewListCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ListCmd{
+		GlobalFlags: flags,
+	}
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "Lists machine pools",
+		Args:    cobra.NoArgs,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return cmd.Run(context.Background())
+		},
+	}
+
+	listCmd.Flags().StringVar(&cmd.Output, "output", "plain", "The output format to use. Can be json or plain")
+	return listCmd
+}
+
+// Run runs the command logic
+func (cmd *ListCmd) Run(ctx context.Context) error {
+	devSpaceConfig, err := config.LoadConfig(cmd.Context, cmd.Provider)
+	if err != nil {
+		return err
+	}
+
+	pools, err := workspace.ListMachinePools(devSpaceConfig, log.Default)
+	if err != nil {
+		return err
+	}
+
+	retPools := []*poolWithUsage{}
+	for _, pool := range pools {
+		usage, err := workspace.GetMachinePoolUsage(devSpaceConfig, pool, log.Default)
+		if err != nil {
+			return err
+		}
+
+		retPools = append(retPools, &poolWithUsage{MachinePool: pool, Usage: usage})
+	}
+
+	if cmd.Output == "json" {
+		out, err := json.Marshal(retPools)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(out))
+		return nil
+	} else if cmd.Output != "plain" {
+		return fmt.Errorf("unexpected output format, choose either json or plain. Got %s", cmd.Output)
+	}
+
+	tableEntries := [][]string{}
+	for _, pool := range retPools {
+		capacity := []string{}
+		if pool.Capacity.CPUs > 0 {
+			capacity = append(capacity, fmt.Sprintf("%d cpus", pool.Capacity.CPUs))
+		}
+		if pool.Capacity.Memory > 0 {
+			capacity = append(capacity, units.BytesSize(float64(pool.Capacity.Memory)))
+		}
+		if len(capacity) == 0 {
+			capacity = append(capacity, "unlimited")
+		}
+
+		tableEntries = append(tableEntries, []string{
+			pool.Name,
+			pool.Provider.Name,
+			strconv.Itoa(pool.Size),
+			pool.State,
+			strconv.Itoa(pool.Usage.Machines),
+			strconv.Itoa(pool.Usage.Idle),
+			strconv.Itoa(pool.Usage.Workspaces),
+			strings.Join(capacity, ", "),
+		})
+	}
+	table.PrintTable(log.Default, []string{
+		"Name",
+		"Provider",
+		"Size",
+		"State",
+		"Machines",
+		"Idle",
+		"Workspaces",
+		"Capacity",
+	}, tableEntries)
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package pool
+
+import (
+	"dev.khulnasoft.com/cmd/flags"
+	"github.com/spf13/cobra"
+)
+
+// NewPoolCmd returns a new command
+func NewPoolCmd(flags *flags.GlobalFlags) *cobra.Command {
+	poolCmd := &cobra.Command{
+		Use:   "pool",
+		Short: "DevSpace Machine Pool commands",
+		Long: `Machine pools keep a number of pre-warmed machines ready for new workspaces.
+When running 'devspace up' with a provider that has a pool, the workspace is
+scheduled onto a pool machine and the pool is replenished in the background.`,
+	}
+
+	poolCmd.AddCommand(NewCreateCmd(flags))
+	poolCmd.AddCommand(NewListCmd(flags))
+	poolCmd.AddCommand(NewDeleteCmd(flags))
+	poolCmd.AddCommand(NewReplenishCmd(flags))
+	return poolCmd
+}
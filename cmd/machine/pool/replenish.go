@@ -0,0 +1,43 @@
+package pool
+
+import (
+	"context"
+
+	"dev.khulnasoft.com/cmd/flags"
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/config"
+	"dev.khulnasoft.com/pkg/workspace"
+	"github.com/spf13/cobra"
+)
+
+// ReplenishCmd holds the configuration
+type ReplenishCmd struct {
+	*flags.GlobalFlags
+}
+
+// NewReplenishCmd creates a new command
+func NewReplenishCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ReplenishCmd{
+		GlobalFlags: flags,
+	}
+	replenishCmd := &cobra.Command{
+		Use:   "replenish [name]",
+		Short: "Creates or removes unused machines until the pool has the desired size",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return cmd.Run(context.Background(), args[0])
+		},
+	}
+
+	return replenishCmd
+}
+
+// Run runs the command logic
+func (cmd *ReplenishCmd) Run(ctx context.Context, name string) error {
+	devSpaceConfig, err := config.LoadConfig(cmd.Context, cmd.Provider)
+	if err != nil {
+		return err
+	}
+
+	return workspace.ReplenishMachinePool(ctx, devSpaceConfig, name, log.Default)
+}
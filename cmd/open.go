@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"dev.khulnasoft.com/cmd/completion"
+	"dev.khulnasoft.com/cmd/flags"
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/agent"
+	client2 "dev.khulnasoft.com/pkg/client"
+	"dev.khulnasoft.com/pkg/compress"
+	"dev.khulnasoft.com/pkg/config"
+	config2 "dev.khulnasoft.com/pkg/devcontainer/config"
+	"dev.khulnasoft.com/pkg/ide"
+	"dev.khulnasoft.com/pkg/ide/ideparse"
+	provider2 "dev.khulnasoft.com/pkg/provider"
+	devssh "dev.khulnasoft.com/pkg/ssh"
+	devsshagent "dev.khulnasoft.com/pkg/ssh/agent"
+	"dev.khulnasoft.com/pkg/tunnel"
+	"dev.khulnasoft.com/pkg/util"
+	workspace2 "dev.khulnasoft.com/pkg/workspace"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+)
+
+// OpenCmd holds the open cmd flags
+type OpenCmd struct {
+	*flags.GlobalFlags
+
+	IDE                string
+	IDEOptions         []string
+	GPGAgentForwarding bool
+}
+
+// NewOpenCmd creates a new open command
+func NewOpenCmd(f *flags.GlobalFlags) *cobra.Command {
+	cmd := &OpenCmd{
+		GlobalFlags: f,
+	}
+	openCmd := &cobra.Command{
+		Use:   "open [flags] [workspace-path|workspace-name]",
+		Short: "Opens a running workspace in an IDE",
+		Long: `Opens a running workspace in an IDE without running up again
+
+The IDE backend is installed into the workspace if it is missing. The workspace keeps its default IDE, so
+multiple IDEs can be used side by side. List the IDEs running in a workspace with 'devspace ide status'.`,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			devSpaceConfig, err := config.LoadConfig(cmd.Context, cmd.Provider)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := WithSignals(cobraCmd.Context())
+			defer cancel()
+
+			client, err := workspace2.Get(ctx, devSpaceConfig, args, true, cmd.Owner, false, log.Default)
+			if err != nil {
+				return err
+			}
+
+			return cmd.Run(ctx, devSpaceConfig, client, log.Default)
+		},
+		ValidArgsFunction: func(rootCmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GetWorkspaceSuggestions(rootCmd, cmd.Context, cmd.Provider, args, toComplete, cmd.Owner, log.Default)
+		},
+	}
+
+	openCmd.Flags().StringVar(&cmd.IDE, "ide", "", "The IDE to open the workspace in. If empty will use the IDE of the workspace")
+	openCmd.Flags().StringArrayVar(&cmd.IDEOptions, "ide-option", []string{}, "IDE option in the form KEY=VALUE")
+	openCmd.Flags().BoolVar(&cmd.GPGAgentForwarding, "gpg-agent-forwarding", false, "If true forward the local gpg-agent to the DevSpace workspace")
+	return openCmd
+}
+
+// Run runs the command logic
+func (cmd *OpenCmd) Run(ctx context.Context, devSpaceConfig *config.Config, client client2.BaseWorkspaceClient, log log.Logger) error {
+	ideConfig, err := ideparse.ResolveIDE(devSpaceConfig, client.WorkspaceConfig(), cmd.IDE, cmd.IDEOptions)
+	if err != nil {
+		return err
+	} else if ideConfig.Name == string(config.IDENone) {
+		return fmt.Errorf("please specify the IDE to open the workspace in with --ide")
+	}
+
+	instanceStatus, err := client.Status(ctx, client2.StatusOptions{})
+	if err != nil {
+		return err
+	} else if instanceStatus != client2.StatusRunning {
+		return fmt.Errorf("workspace '%s' is '%s', you can start it via 'devspace up %s --ide %s'", client.Workspace(), instanceStatus, client.Workspace(), ideConfig.Name)
+	}
+
+	// browser IDEs reuse the SSH_AUTH_SOCK of the connection that starts them
+	authSockID := ""
+	if ide.ReusesAuthSock(ideConfig.Name) {
+		authSockID = util.RandStringBytes(10)
+		log.Debug("Reusing SSH_AUTH_SOCK", authSockID)
+	}
+
+	// install the IDE backend and sync the settings over a single connection
+	var (
+		result          *config2.Result
+		workspaceFolder string
+	)
+	err = tunnel.NewTunnel(
+		ctx,
+		func(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+			writer := log.Writer(logrus.DebugLevel, false)
+			defer writer.Close()
+
+			args := []string{"--log-output=raw", "--stdio"}
+			if authSockID != "" {
+				args = append(args, fmt.Sprintf("--reuse-ssh-auth-sock=%s", authSockID))
+			}
+
+			cmd, err := createSSHCommand(ctx, client, log, args)
+			if err != nil {
+				return err
+			}
+			cmd.Stdout = stdout
+			cmd.Stdin = stdin
+			cmd.Stderr = writer
+			return cmd.Run()
+		},
+		func(ctx context.Context, sshClient *ssh.Client) error {
+			var err error
+			result, err = installIDE(sshClient, ideConfig, authSockID != "", log)
+			if err != nil {
+				return err
+			}
+
+			workspaceFolder = result.SubstitutionContext.ContainerWorkspaceFolder
+			if client.WorkspaceConfig().Source.GitSubPath != "" {
+				workspaceFolder = filepath.Join(workspaceFolder, client.WorkspaceConfig().Source.GitSubPath)
+			}
+
+			// sync the local ide settings into the workspace
+			if devSpaceConfig.ContextOption(config.ContextOptionSyncIDESettings) == "true" {
+				err = syncIDESettingsOverSSH(ctx, sshClient, ideConfig.Name, workspaceFolder, log)
+				if err != nil {
+					log.Warnf("Error syncing IDE settings: %v", err)
+				}
+			}
+
+			return nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	user := config2.GetRemoteUser(result)
+	return openIDE(
+		ctx,
+		devSpaceConfig,
+		client,
+		*ideConfig,
+		user,
+		workspaceFolder,
+		cmd.GPGAgentForwarding,
+		authSockID,
+		log,
+	)
+}
+
+// installIDE installs the IDE backend in the running container and returns the result the container was set up with.
+// Browser IDEs reuse the SSH_AUTH_SOCK of the forwarded agent.
+func installIDE(sshClient *ssh.Client, ideConfig *provider2.WorkspaceIDEConfig, agentForwarding bool, log log.Logger) (*config2.Result, error) {
+	rawIDEConfig, err := json.Marshal(ideConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	compressed, err := compress.Compress(string(rawIDEConfig))
+	if err != nil {
+		return nil, err
+	}
+
+	agentCommand := fmt.Sprintf("'%s' agent container install-ide --ide-config '%s'", agent.ContainerDevSpaceHelperLocation, compressed)
+	if log.GetLevel() == logrus.DebugLevel {
+		agentCommand += " --debug"
+	}
+
+	session, err := sshClient.NewSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	authSock := devsshagent.GetSSHAuthSocket()
+	if agentForwarding && authSock != "" {
+		err = devsshagent.ForwardToRemote(sshClient, authSock)
+		if err != nil {
+			return nil, fmt.Errorf("forward agent: %w", err)
+		}
+
+		err = devsshagent.RequestAgentForwarding(session)
+		if err != nil {
+			return nil, fmt.Errorf("request agent forwarding: %w", err)
+		}
+	}
+
+	writer := log.Writer(logrus.InfoLevel, false)
+	defer writer.Close()
+
+	log.Infof("Installing %s in the workspace", ideConfig.Name)
+	stdout := &bytes.Buffer{}
+	session.Stdout = stdout
+	session.Stderr = writer
+	err = session.Run(agentCommand)
+	if err != nil {
+		return nil, fmt.Errorf("install %s: %w", ideConfig.Name, err)
+	}
+
+	result := &config2.Result{}
+	err = json.Unmarshal(stdout.Bytes(), result)
+	if err != nil {
+		return nil, fmt.Errorf("parse container result: %w", err)
+	}
+
+	return result, nil
+}
+
+// syncIDESettingsOverSSH syncs the local ide settings into the workspace over the ssh connection
+func syncIDESettingsOverSSH(ctx context.Context, sshClient *ssh.Client, ideName, workspaceFolder string, log log.Logger) error {
+	rawPayload, agentCommand, err := ideSettingsSync(ideName, workspaceFolder, log)
+	if err != nil || rawPayload == nil {
+		return err
+	}
+
+	writer := log.Writer(logrus.InfoLevel, false)
+	defer writer.Close()
+
+	log.Infof("Syncing %s settings into the workspace", ideName)
+	return devssh.Run(ctx, sshClient, agentCommand, bytes.NewReader(rawPayload), writer, writer, nil)
+}
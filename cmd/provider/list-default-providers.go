@@ -9,7 +9,10 @@ import (
 	"strings"
 
 	"dev.khulnasoft.com/cmd/flags"
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/config"
 	devspacehttp "dev.khulnasoft.com/pkg/http"
+	"dev.khulnasoft.com/pkg/provider/catalog"
 	"github.com/spf13/cobra"
 )
 
@@ -70,5 +73,23 @@ func NewListAvailableCmd(flags *flags.GlobalFlags) *cobra.Command {
 
 // Run runs the command logic
 func (cmd *ListAvailableCmd) Run(ctx context.Context) error {
+	devSpaceConfig, err := config.LoadConfig(cmd.Context, cmd.Provider)
+	if err != nil {
+		return err
+	}
+
+	// the default providers are listed even if no catalog can be reached
+	catalogs, err := catalog.LoadAll(devSpaceConfig, false, log.Default.ErrorStreamOnly())
+	if err != nil {
+		log.Default.Warnf("Error loading provider catalogs: %v", err)
+	}
+
+	for _, c := range catalogs {
+		fmt.Printf("List of available providers from catalog %s:\n", c.Origin)
+		for _, provider := range c.Providers {
+			fmt.Println("\t", provider.Name)
+		}
+	}
+
 	return getDevspaceProviderList()
 }
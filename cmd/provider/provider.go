@@ -20,5 +20,7 @@ func NewProviderCmd(flags *flags.GlobalFlags) *cobra.Command {
 	providerCmd.AddCommand(NewAddCmd(flags))
 	providerCmd.AddCommand(NewUpdateCmd(flags))
 	providerCmd.AddCommand(NewSetOptionsCmd(flags))
+	providerCmd.AddCommand(NewTestCmd(flags))
+	providerCmd.AddCommand(NewSearchCmd(flags))
 	return providerCmd
 }
@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"dev.khulnasoft.com/cmd/flags"
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/log/table"
+	"dev.khulnasoft.com/pkg/config"
+	"dev.khulnasoft.com/pkg/provider/catalog"
+	"github.com/spf13/cobra"
+)
+
+// SearchCmd holds the cmd flags
+type SearchCmd struct {
+	*flags.GlobalFlags
+
+	Output  string
+	Refresh bool
+}
+
+// NewSearchCmd creates a new command
+func NewSearchCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &SearchCmd{
+		GlobalFlags: flags,
+	}
+	searchCmd := &cobra.Command{
+		Use:   "search [query]",
+		Short: "Searches the configured provider catalogs",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			query := ""
+			if len(args) == 1 {
+				query = args[0]
+			}
+
+			return cmd.Run(context.Background(), query)
+		},
+	}
+
+	searchCmd.Flags().StringVar(&cmd.Output, "output", "plain", "The output format to use. Can be json or plain")
+	searchCmd.Flags().BoolVar(&cmd.Refresh, "refresh", false, "If enabled, will download the catalogs again instead of using the cache")
+	return searchCmd
+}
+
+// Run runs the command logic
+func (cmd *SearchCmd) Run(ctx context.Context, query string) error {
+	devSpaceConfig, err := config.LoadConfig(cmd.Context, cmd.Provider)
+	if err != nil {
+		return err
+	}
+
+	if len(catalog.Locations(devSpaceConfig)) == 0 {
+		return fmt.Errorf("no provider catalog configured. Please run 'devspace context set-options -o %s=https://my-catalog/index.yaml'", config.ContextOptionProviderCatalogs)
+	}
+
+	catalogs, err := catalog.LoadAll(devSpaceConfig, cmd.Refresh, log.Default.ErrorStreamOnly())
+	if err != nil {
+		return err
+	}
+
+	found := []*catalog.Provider{}
+	for _, c := range catalogs {
+		for _, provider := range c.Providers {
+			if provider.Matches(query) {
+				found = append(found, provider)
+			}
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].Name < found[j].Name
+	})
+
+	if cmd.Output == "json" {
+		out, err := json.MarshalIndent(found, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(out))
+		return nil
+	} else if cmd.Output != "plain" {
+		return fmt.Errorf("unexpected output format, choose either json or plain. Got %s", cmd.Output)
+	}
+
+	tableEntries := [][]string{}
+	for _, provider := range found {
+		latest := ""
+		if provider.Latest() != nil {
+			latest = provider.Latest().Version
+		}
+
+		requiredOptions := []string{}
+		for _, option := range provider.Options {
+			if option.Required {
+				requiredOptions = append(requiredOptions, option.Name)
+			}
+		}
+
+		tableEntries = append(tableEntries, []string{
+			provider.Name,
+			latest,
+			strings.Join(requiredOptions, ", "),
+			provider.Description,
+		})
+	}
+	table.PrintTable(log.Default, []string{
+		"Name",
+		"Latest",
+		"Required Options",
+		"Description",
+	}, tableEntries)
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"dev.khulnasoft.com/cmd/flags"
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/log/table"
+	"dev.khulnasoft.com/pkg/config"
+	"dev.khulnasoft.com/pkg/provider/conformance"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// TestCmd holds the cmd flags
+type TestCmd struct {
+	*flags.GlobalFlags
+
+	Name          string
+	Options       []string
+	Skip          []string
+	JUnit         string
+	StatusTimeout time.Duration
+	Keep          bool
+}
+
+// NewTestCmd creates a new command
+func NewTestCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &TestCmd{
+		GlobalFlags: flags,
+	}
+	testCmd := &cobra.Command{
+		Use:   "test [URL or path]",
+		Short: "Runs a conformance test suite against a provider",
+		Long: `Runs a conformance test suite against a provider.
+
+The provider is installed into a temporary DevSpace home and goes through
+parsing, option resolution, init, machine creation, status transitions,
+command execution, agent injection, stop / start and deletion.
+
+Example:
+devspace provider test ./provider.yaml -o REGION=eu-west-1 --junit report.xml
+devspace provider test docker`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("please specify either a local file, url or git repository. E.g. devspace provider test ./provider.yaml")
+			}
+
+			return cmd.Run(context.Background(), args[0])
+		},
+	}
+
+	testCmd.Flags().StringVar(&cmd.Name, "name", "", "The name to use for this provider. If empty will use the name within the loaded config")
+	testCmd.Flags().StringArrayVarP(&cmd.Options, "option", "o", []string{}, "Provider option in the form KEY=VALUE")
+	testCmd.Flags().StringSliceVar(&cmd.Skip, "skip", []string{}, fmt.Sprintf("Steps to skip. Can be any of %v except %v", conformance.Steps, conformance.SetupSteps))
+	testCmd.Flags().StringVar(&cmd.JUnit, "junit", "", "If defined, writes a JUnit report to the given path")
+	testCmd.Flags().DurationVar(&cmd.StatusTimeout, "status-timeout", 5*time.Minute, "The time to wait for a machine to reach an expected status")
+	testCmd.Flags().BoolVar(&cmd.Keep, "keep", false, "If enabled, will not delete the temporary DevSpace home after the run")
+	return testCmd
+}
+
+// Run runs the command logic
+func (cmd *TestCmd) Run(ctx context.Context, source string) error {
+	for _, step := range cmd.Skip {
+		if !slices.Contains(conformance.Steps, step) {
+			return fmt.Errorf("unknown step %s, needs to be one of %v", step, conformance.Steps)
+		} else if slices.Contains(conformance.SetupSteps, step) {
+			return fmt.Errorf("step %s can't be skipped, all other steps depend on it", step)
+		}
+	}
+
+	// run the suite within an isolated devspace home, so we don't touch the user config
+	devSpaceHome, err := os.MkdirTemp("", "devspace-provider-test-")
+	if err != nil {
+		return err
+	}
+	if cmd.Keep {
+		log.Default.Infof("Using temporary DevSpace home %s", devSpaceHome)
+	} else {
+		defer os.RemoveAll(devSpaceHome)
+	}
+	_ = os.Setenv(config.DEVSPACE_HOME, devSpaceHome)
+	_ = os.Setenv(config.DEVSPACE_CONFIG, filepath.Join(devSpaceHome, config.ConfigFile))
+
+	devSpaceConfig, err := config.LoadConfig("", "")
+	if err != nil {
+		return err
+	}
+
+	report := conformance.Run(ctx, devSpaceConfig, conformance.Options{
+		Source:          source,
+		Name:            cmd.Name,
+		ProviderOptions: cmd.Options,
+		Skip:            cmd.Skip,
+		StatusTimeout:   cmd.StatusTimeout,
+	}, log.Default)
+
+	tableEntries := [][]string{}
+	for _, result := range report.Results {
+		tableEntries = append(tableEntries, []string{
+			result.Name,
+			string(result.Status),
+			result.Duration.Round(time.Millisecond).String(),
+			result.Message,
+		})
+	}
+	table.PrintTable(log.Default, []string{
+		"Step",
+		"Status",
+		"Duration",
+		"Message",
+	}, tableEntries)
+
+	if cmd.JUnit != "" {
+		file, err := os.Create(cmd.JUnit)
+		if err != nil {
+			return errors.Wrap(err, "create junit report")
+		}
+		defer file.Close()
+
+		err = report.WriteJUnit(file)
+		if err != nil {
+			return errors.Wrap(err, "write junit report")
+		}
+	}
+
+	if report.Failed() > 0 {
+		return fmt.Errorf("provider %s failed %d of %d conformance steps", report.Provider, report.Failed(), len(report.Results))
+	}
+
+	log.Default.Donef("Provider %s passed %d conformance steps (%d skipped)", report.Provider, report.Passed(), report.Skipped())
+	return nil
+}
@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"dev.khulnasoft.com/cmd/flags"
 	"dev.khulnasoft.com/pkg/config"
@@ -18,6 +19,7 @@ type UpdateCmd struct {
 
 	Use     bool
 	Options []string
+	To      string
 }
 
 // NewUpdateCmd creates a new command
@@ -28,6 +30,14 @@ func NewUpdateCmd(flags *flags.GlobalFlags) *cobra.Command {
 	updateCmd := &cobra.Command{
 		Use:   "update [name] [URL or path]",
 		Short: "Updates a provider in DevSpace",
+		Long: `Updates a provider in DevSpace.
+
+Providers installed from a catalog can be pinned to a version via --to.
+Use --to latest to follow the newest version again.
+
+Example:
+devspace provider update my-provider
+devspace provider update aws --to v0.0.16`,
 		RunE: func(_ *cobra.Command, args []string) error {
 			ctx := context.Background()
 			devSpaceConfig, err := config.LoadConfig(cmd.Context, cmd.Provider)
@@ -41,6 +51,7 @@ func NewUpdateCmd(flags *flags.GlobalFlags) *cobra.Command {
 
 	updateCmd.Flags().BoolVar(&cmd.Use, "use", true, "If enabled will automatically activate the provider")
 	updateCmd.Flags().StringArrayVarP(&cmd.Options, "option", "o", []string{}, "Provider option in the form KEY=VALUE")
+	updateCmd.Flags().StringVar(&cmd.To, "to", "", "The catalog version to update to and pin the provider at. Use latest to unpin")
 	return updateCmd
 }
 
@@ -53,6 +64,17 @@ func (cmd *UpdateCmd) Run(ctx context.Context, devSpaceConfig *config.Config, ar
 	if len(args) == 2 {
 		providerSource = args[1]
 	}
+	if cmd.To != "" {
+		if providerSource != "" {
+			return fmt.Errorf("--to cannot be used together with a provider source")
+		}
+
+		s, err := pinnedProviderSource(devSpaceConfig, args[0], cmd.To)
+		if err != nil {
+			return err
+		}
+		providerSource = s
+	}
 
 	providerConfig, err := workspace.UpdateProvider(devSpaceConfig, args[0], providerSource, log.Default)
 	if err != nil {
@@ -74,3 +96,23 @@ func (cmd *UpdateCmd) Run(ctx context.Context, devSpaceConfig *config.Config, ar
 	log.Default.Infof("devspace provider use %s", providerConfig.Name)
 	return nil
 }
+
+// pinnedProviderSource returns the catalog reference of the provider pinned at the given version
+func pinnedProviderSource(devSpaceConfig *config.Config, providerName, version string) (string, error) {
+	providerWithOptions, err := workspace.FindProvider(devSpaceConfig, providerName, log.Default)
+	if err != nil {
+		return "", err
+	}
+
+	name := providerWithOptions.Config.Source.Catalog
+	if name == "" {
+		return "", fmt.Errorf("provider %s was not installed from a catalog, please specify the source to update from instead", providerName)
+	}
+
+	name, _, _ = strings.Cut(name, "@")
+	if version == "latest" {
+		return name, nil
+	}
+
+	return name + "@" + version, nil
+}
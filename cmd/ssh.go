@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -17,14 +18,18 @@ import (
 	"dev.khulnasoft.com/pkg/agent"
 	client2 "dev.khulnasoft.com/pkg/client"
 	"dev.khulnasoft.com/pkg/config"
+	"dev.khulnasoft.com/pkg/daemon/local"
 	daemon "dev.khulnasoft.com/pkg/daemon/platform"
 	"dev.khulnasoft.com/pkg/gpg"
+	"dev.khulnasoft.com/pkg/ide/terminal"
 	"dev.khulnasoft.com/pkg/port"
 	"dev.khulnasoft.com/pkg/provider"
 	devssh "dev.khulnasoft.com/pkg/ssh"
+	"dev.khulnasoft.com/pkg/token"
 	"dev.khulnasoft.com/pkg/tunnel"
 	workspace2 "dev.khulnasoft.com/pkg/workspace"
 	"dev.khulnasoft.com/log"
+	"github.com/mattn/go-isatty"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -56,10 +61,15 @@ type SSHCmd struct {
 	SSHKeepAliveInterval time.Duration `json:"sshKeepAliveInterval,omitempty"`
 
 	StartServices bool
+	AttachSession bool
+	NoMux         bool
 
 	Command string
 	User    string
 	WorkDir string
+
+	// hostKeyToken is passed to the ssh server in the container when connecting through the wildcard ssh config
+	hostKeyToken string
 }
 
 // NewSSHCmd creates a new ssh command
@@ -79,9 +89,24 @@ func NewSSHCmd(f *flags.GlobalFlags) *cobra.Command {
 			localOnly := false
 			if cmd.Stdio {
 				localOnly = true
+
+				// the wildcard ssh config passes the host name, e.g. my-workspace.devspace
+				if len(args) == 1 && strings.HasSuffix(args[0], ".devspace") {
+					devSpaceConfig, args, err = cmd.resolveWildcardHost(devSpaceConfig, args[0])
+					if err != nil {
+						return err
+					}
+				}
 			}
 
 			ctx := cobraCmd.Context()
+			if len(args) == 1 {
+				attached, err := cmd.attachToMux(ctx, devSpaceConfig, args[0], log.Default.ErrorStreamOnly())
+				if attached || err != nil {
+					return err
+				}
+			}
+
 			client, err := workspace2.Get(ctx, devSpaceConfig, args, true, cmd.Owner, localOnly, log.Default.ErrorStreamOnly())
 			if err != nil {
 				return err
@@ -108,17 +133,142 @@ func NewSSHCmd(f *flags.GlobalFlags) *cobra.Command {
 	sshCmd.Flags().BoolVar(&cmd.GPGAgentForwarding, "gpg-agent-forwarding", false, "If true forward the local gpg-agent to the remote machine")
 	sshCmd.Flags().BoolVar(&cmd.Stdio, "stdio", false, "If true will tunnel connection through stdout and stdin")
 	sshCmd.Flags().BoolVar(&cmd.StartServices, "start-services", true, "If false will not start any port-forwarding or git / docker credentials helper")
+	sshCmd.Flags().BoolVar(&cmd.AttachSession, "attach-session", true, "If false will not attach to the session of the terminal IDE")
+	sshCmd.Flags().BoolVar(&cmd.NoMux, "no-mux", false, "If true will connect to the workspace directly instead of through the background connection of the workspace")
 	sshCmd.Flags().DurationVar(&cmd.SSHKeepAliveInterval, "ssh-keepalive-interval", 55*time.Second, "How often should keepalive request be made (55s)")
 
 	return sshCmd
 }
 
+// resolveWildcardHost finds the context of the workspace behind the host name and loads the host key the
+// ssh client verifies the connection with. Host names that don't belong to a workspace are left untouched.
+func (cmd *SSHCmd) resolveWildcardHost(devSpaceConfig *config.Config, host string) (*config.Config, []string, error) {
+	workspaceID := strings.TrimSuffix(host, ".devspace")
+	workspaceContext := cmd.Context
+	if workspaceContext == "" {
+		contexts := []string{devSpaceConfig.DefaultContext}
+		for name := range devSpaceConfig.Contexts {
+			if name != devSpaceConfig.DefaultContext {
+				contexts = append(contexts, name)
+			}
+		}
+		sort.Strings(contexts[1:])
+
+		for _, name := range contexts {
+			if provider.WorkspaceExists(name, workspaceID) {
+				workspaceContext = name
+				break
+			}
+		}
+	}
+	if workspaceContext == "" || !provider.WorkspaceExists(workspaceContext, workspaceID) {
+		return devSpaceConfig, []string{host}, nil
+	}
+
+	if workspaceContext != devSpaceConfig.DefaultContext {
+		var err error
+		devSpaceConfig, err = config.LoadConfig(workspaceContext, cmd.Provider)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	cmd.Context = workspaceContext
+
+	hostKeyToken, err := token.GetWorkspaceToken(workspaceContext, workspaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	cmd.hostKeyToken = hostKeyToken
+
+	// there is no host entry with a workdir, so use the workspace folder the container was set up with
+	if cmd.WorkDir == "" {
+		result, err := provider.LoadWorkspaceResult(workspaceContext, workspaceID)
+		if err == nil && result != nil && result.MergedConfig != nil && result.MergedConfig.WorkspaceFolder != "" {
+			cmd.WorkDir = result.MergedConfig.WorkspaceFolder
+
+			workspace, err := provider.LoadWorkspaceConfig(workspaceContext, workspaceID)
+			if err == nil && workspace.Source.GitSubPath != "" {
+				cmd.WorkDir = filepath.Join(cmd.WorkDir, workspace.Source.GitSubPath)
+			}
+		}
+	}
+
+	return devSpaceConfig, []string{workspaceID}, nil
+}
+
+// attachToMux starts the session over the connection the local daemon holds for the workspace if there is one. It
+// returns false if the caller needs to connect to the workspace itself.
+func (cmd *SSHCmd) attachToMux(ctx context.Context, devSpaceConfig *config.Config, workspaceID string, log log.Logger) (bool, error) {
+	workspace, err := provider.LoadWorkspaceConfig(devSpaceConfig.DefaultContext, workspaceID)
+	if err != nil || !cmd.useMux(devSpaceConfig, workspace) {
+		return false, nil
+	}
+
+	containerClient, err := local.Dial(workspace.Context, workspace.ID)
+	if err != nil {
+		log.Debugf("Connect directly, because the daemon has no connection to the workspace: %v", err)
+		return false, nil
+	}
+	defer containerClient.Close()
+
+	log.Debugf("Attach to the connection of the daemon")
+	err = cmd.prepare(ctx, devSpaceConfig, workspace, log)
+	if err != nil {
+		return true, err
+	}
+
+	return true, cmd.startTunnel(ctx, devSpaceConfig, containerClient, workspace, log)
+}
+
+// useMux returns if sessions of the workspace go through the connection of the local daemon. Pro workspaces are
+// reached through the daemon of their pro instance instead.
+func (cmd *SSHCmd) useMux(devSpaceConfig *config.Config, workspace *provider.Workspace) bool {
+	return !cmd.NoMux && !workspace.IsPro() && devSpaceConfig.ContextOption(config.ContextOptionSSHMultiplexing) == "true"
+}
+
 // Run runs the command logic
 func (cmd *SSHCmd) Run(
 	ctx context.Context,
 	devSpaceConfig *config.Config,
 	client client2.BaseWorkspaceClient,
 	log log.Logger) error {
+	err := cmd.prepare(ctx, devSpaceConfig, client.WorkspaceConfig(), log)
+	if err != nil {
+		return err
+	}
+
+	startTunnel := func(ctx context.Context, containerClient *ssh.Client) error {
+		return cmd.startTunnel(ctx, devSpaceConfig, containerClient, client.WorkspaceConfig(), log)
+	}
+
+	workspaceClient, ok := client.(client2.WorkspaceClient)
+	if ok {
+		// let the daemon connect to the workspace for the next sessions
+		if cmd.useMux(devSpaceConfig, client.WorkspaceConfig()) {
+			go func() {
+				err := local.Connect(client.Context(), client.Workspace(), log)
+				if err != nil {
+					log.Debugf("Error connecting the daemon to workspace %s: %v", client.Workspace(), err)
+				}
+			}()
+		}
+
+		return cmd.jumpContainer(ctx, devSpaceConfig, workspaceClient, startTunnel, log)
+	}
+	proxyClient, ok := client.(client2.ProxyClient)
+	if ok {
+		return cmd.startProxyTunnel(ctx, proxyClient, startTunnel, log)
+	}
+	daemonClient, ok := client.(client2.DaemonClient)
+	if ok {
+		return cmd.jumpContainerTailscale(ctx, devSpaceConfig, daemonClient, log)
+	}
+
+	return nil
+}
+
+// prepare resolves the options of the session that depend on the workspace
+func (cmd *SSHCmd) prepare(ctx context.Context, devSpaceConfig *config.Config, workspace *provider.Workspace, log log.Logger) error {
 	// add ssh keys to agent
 	if devSpaceConfig.ContextOption(config.ContextOptionSSHAgentForwarding) == "true" && devSpaceConfig.ContextOption(config.ContextOptionSSHAddPrivateKeys) == "true" {
 		log.Debug("Adding ssh keys to agent, disable via 'devspace context set-options -o SSH_ADD_PRIVATE_KEYS=false'")
@@ -131,7 +281,7 @@ func (cmd *SSHCmd) Run(
 	// get user
 	if cmd.User == "" {
 		var err error
-		cmd.User, err = devssh.GetUser(client.WorkspaceConfig().ID, client.WorkspaceConfig().SSHConfigPath)
+		cmd.User, err = devssh.GetWorkspaceUser(workspace)
 		if err != nil {
 			return err
 		}
@@ -142,17 +292,11 @@ func (cmd *SSHCmd) Run(
 		cmd.Context = devSpaceConfig.DefaultContext
 	}
 
-	workspaceClient, ok := client.(client2.WorkspaceClient)
-	if ok {
-		return cmd.jumpContainer(ctx, devSpaceConfig, workspaceClient, log)
-	}
-	proxyClient, ok := client.(client2.ProxyClient)
-	if ok {
-		return cmd.startProxyTunnel(ctx, devSpaceConfig, proxyClient, log)
-	}
-	daemonClient, ok := client.(client2.DaemonClient)
-	if ok {
-		return cmd.jumpContainerTailscale(ctx, devSpaceConfig, daemonClient, log)
+	// attach to the persistent session of the terminal ide
+	ideConfig := workspace.IDE
+	if cmd.AttachSession && cmd.Command == "" && !cmd.Stdio && ideConfig.Name == string(config.IDETerminal) && isatty.IsTerminal(os.Stdin.Fd()) {
+		log.Debugf("Attach to terminal session")
+		cmd.Command = terminal.AttachCommand(ideConfig.Options)
 	}
 
 	return nil
@@ -206,7 +350,7 @@ func (cmd *SSHCmd) jumpContainerTailscale(
 	}
 
 	// Handle GPG agent forwarding
-	if cmd.GPGAgentForwarding || devSpaceConfig.ContextOption(config.ContextOptionGPGAgentForwarding) == "true" {
+	if cmd.gpgAgentForwarding(devSpaceConfig, client.WorkspaceConfig()) {
 		if gpg.IsGpgTunnelRunning(cmd.User, ctx, toolSSHClient, log) {
 			log.Debugf("[GPG] exporting already running, skipping")
 		} else if err := cmd.setupGPGAgent(ctx, toolSSHClient, log); err != nil {
@@ -220,6 +364,14 @@ func (cmd *SSHCmd) jumpContainerTailscale(
 			go startSSHKeepAlive(ctx, toolSSHClient, cmd.SSHKeepAliveInterval, log)
 		}
 
+		// the ssh server of the daemon doesn't present the host key in the known_hosts file
+		if cmd.hostKeyToken != "" {
+			writer := log.ErrorStreamOnly().Writer(logrus.InfoLevel, false)
+			defer writer.Close()
+
+			return devssh.Run(ctx, toolSSHClient, cmd.sshServerCommand(client.Workspace(), log), os.Stdin, os.Stdout, writer, nil)
+		}
+
 		return client.DirectTunnel(ctx, os.Stdin, os.Stdout)
 	}
 
@@ -235,8 +387,8 @@ func (cmd *SSHCmd) jumpContainerTailscale(
 
 func (cmd *SSHCmd) startProxyTunnel(
 	ctx context.Context,
-	devSpaceConfig *config.Config,
 	client client2.ProxyClient,
+	handler tunnel.Handler,
 	log log.Logger,
 ) error {
 	log.Debugf("Start proxy tunnel")
@@ -249,9 +401,7 @@ func (cmd *SSHCmd) startProxyTunnel(
 				Stdout: stdout,
 			})
 		},
-		func(ctx context.Context, containerClient *ssh.Client) error {
-			return cmd.startTunnel(ctx, devSpaceConfig, containerClient, client, log)
-		},
+		handler,
 	)
 }
 
@@ -324,6 +474,7 @@ func (cmd *SSHCmd) jumpContainer(
 	ctx context.Context,
 	devSpaceConfig *config.Config,
 	client client2.WorkspaceClient,
+	handler tunnel.Handler,
 	log log.Logger,
 ) error {
 	// lock the workspace as long as we init the connection
@@ -351,7 +502,7 @@ func (cmd *SSHCmd) jumpContainer(
 			client.Unlock()
 
 			// start ssh tunnel
-			return cmd.startTunnel(ctx, devSpaceConfig, containerClient, client, log)
+			return handler(ctx, containerClient)
 		}, devSpaceConfig, envVars)
 }
 
@@ -459,7 +610,13 @@ func (cmd *SSHCmd) forwardPorts(
 	return <-errChan
 }
 
-func (cmd *SSHCmd) startTunnel(ctx context.Context, devSpaceConfig *config.Config, containerClient *ssh.Client, workspaceClient client2.BaseWorkspaceClient, log log.Logger) error {
+// gpgAgentForwarding returns if the gpg agent should be forwarded because of the flag, the context option or because
+// the workspace was started with it
+func (cmd *SSHCmd) gpgAgentForwarding(devSpaceConfig *config.Config, workspace *provider.Workspace) bool {
+	return cmd.GPGAgentForwarding || devSpaceConfig.ContextOption(config.ContextOptionGPGAgentForwarding) == "true" || (workspace != nil && workspace.GPGAgentForwarding)
+}
+
+func (cmd *SSHCmd) startTunnel(ctx context.Context, devSpaceConfig *config.Config, containerClient *ssh.Client, workspace *provider.Workspace, log log.Logger) error {
 	// check if we should forward ports
 	if len(cmd.ForwardPorts) > 0 {
 		return cmd.forwardPorts(ctx, containerClient, log)
@@ -475,14 +632,14 @@ func (cmd *SSHCmd) startTunnel(ctx context.Context, devSpaceConfig *config.Confi
 		configureGitCredentials := devSpaceConfig.ContextOption(config.ContextOptionSSHInjectGitCredentials) == "true"
 		configureGitSSHSignatureHelper := devSpaceConfig.ContextOption(config.ContextOptionGitSSHSignatureForwarding) == "true"
 
-		go cmd.startServices(ctx, devSpaceConfig, containerClient, workspaceClient.WorkspaceConfig(), configureDockerCredentials, configureGitCredentials, configureGitSSHSignatureHelper, log)
+		go cmd.startServices(ctx, devSpaceConfig, containerClient, workspace, configureDockerCredentials, configureGitCredentials, configureGitSSHSignatureHelper, log)
 	}
 	// start ssh
 	writer := log.ErrorStreamOnly().Writer(logrus.InfoLevel, false)
 	defer writer.Close()
 
 	// check if we should do gpg agent forwarding
-	if cmd.GPGAgentForwarding || devSpaceConfig.ContextOption(config.ContextOptionGPGAgentForwarding) == "true" {
+	if cmd.gpgAgentForwarding(devSpaceConfig, workspace) {
 		// Check if a forwarding is already enabled and running, in that case
 		// we skip the forwarding and keep using the original one
 		if gpg.IsGpgTunnelRunning(cmd.User, ctx, containerClient, log) {
@@ -495,24 +652,8 @@ func (cmd *SSHCmd) startTunnel(ctx context.Context, devSpaceConfig *config.Confi
 		}
 	}
 
-	workdir := filepath.Join("/workspaces", workspaceClient.Workspace())
-	if cmd.WorkDir != "" {
-		workdir = cmd.WorkDir
-	}
-
 	log.Debugf("Run outer container tunnel")
-	command := fmt.Sprintf("'%s' helper ssh-server --track-activity --stdio --workdir '%s'", agent.ContainerDevSpaceHelperLocation, workdir)
-	if cmd.ReuseSSHAuthSock != "" {
-		log.Debug("Reusing SSH_AUTH_SOCK")
-		command += fmt.Sprintf(" --reuse-ssh-auth-sock=%s", cmd.ReuseSSHAuthSock)
-	}
-	if cmd.Debug {
-		command += " --debug"
-	}
-	if cmd.User != "" && cmd.User != "root" {
-		command = fmt.Sprintf("su -c \"%s\" '%s'", command, cmd.User)
-	}
-
+	command := cmd.sshServerCommand(workspace.ID, log)
 	envVars, err := cmd.retrieveEnVars()
 	if err != nil {
 		return err
@@ -538,6 +679,31 @@ func (cmd *SSHCmd) startTunnel(ctx context.Context, devSpaceConfig *config.Confi
 	)
 }
 
+// sshServerCommand returns the command that starts the ssh server in the container on stdin and stdout
+func (cmd *SSHCmd) sshServerCommand(workspace string, log log.Logger) string {
+	workdir := filepath.Join("/workspaces", workspace)
+	if cmd.WorkDir != "" {
+		workdir = cmd.WorkDir
+	}
+
+	command := fmt.Sprintf("'%s' helper ssh-server --track-activity --stdio --workdir '%s'", agent.ContainerDevSpaceHelperLocation, workdir)
+	if cmd.hostKeyToken != "" {
+		command += fmt.Sprintf(" --token '%s'", cmd.hostKeyToken)
+	}
+	if cmd.ReuseSSHAuthSock != "" {
+		log.Debug("Reusing SSH_AUTH_SOCK")
+		command += fmt.Sprintf(" --reuse-ssh-auth-sock=%s", cmd.ReuseSSHAuthSock)
+	}
+	if cmd.Debug {
+		command += " --debug"
+	}
+	if cmd.User != "" && cmd.User != "root" {
+		command = fmt.Sprintf("su -c \"%s\" '%s'", command, cmd.User)
+	}
+
+	return command
+}
+
 func (cmd *SSHCmd) startServices(
 	ctx context.Context,
 	devSpaceConfig *config.Config,
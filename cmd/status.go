@@ -11,6 +11,7 @@ import (
 	client2 "dev.khulnasoft.com/pkg/client"
 	"dev.khulnasoft.com/pkg/client/clientimplementation"
 	"dev.khulnasoft.com/pkg/config"
+	config2 "dev.khulnasoft.com/pkg/devcontainer/config"
 	workspace2 "dev.khulnasoft.com/pkg/workspace"
 	"dev.khulnasoft.com/log"
 	"github.com/pkg/errors"
@@ -22,8 +23,9 @@ type StatusCmd struct {
 	*flags.GlobalFlags
 	client2.StatusOptions
 
-	Output  string
-	Timeout string
+	Output       string
+	Timeout      string
+	CheckUpdates bool
 }
 
 // NewStatusCmd creates a new command
@@ -40,6 +42,11 @@ func NewStatusCmd(flags *flags.GlobalFlags) *cobra.Command {
 				return fmt.Errorf("decode status options: %w", err)
 			}
 
+			// the desktop app polls the json status, so it reports the updates found by the daemon instead
+			if !cobraCmd.Flags().Changed("check-updates") {
+				cmd.CheckUpdates = cmd.Output != "json"
+			}
+
 			ctx := cobraCmd.Context()
 			devSpaceConfig, err := config.LoadConfig(cmd.Context, cmd.Provider)
 			if err != nil {
@@ -62,6 +69,7 @@ func NewStatusCmd(flags *flags.GlobalFlags) *cobra.Command {
 	statusCmd.Flags().BoolVar(&cmd.ContainerStatus, "container-status", true, "If enabled shows the workspace container status as well")
 	statusCmd.Flags().StringVar(&cmd.Output, "output", "plain", "Status shows the workspace status")
 	statusCmd.Flags().StringVar(&cmd.Timeout, "timeout", "30s", "The timeout to wait until the status can be retrieved")
+	statusCmd.Flags().BoolVar(&cmd.CheckUpdates, "check-updates", true, "If enabled checks if the images and features the workspace was built from have newer versions. Defaults to false for json output, which reports the last check instead")
 	return statusCmd
 }
 
@@ -85,6 +93,19 @@ func (cmd *StatusCmd) Run(ctx context.Context, client client2.BaseWorkspaceClien
 		return err
 	}
 
+	// check for newer base images, prebuilds and features
+	var updates []*config2.ImageUpdate
+	if instanceStatus != client2.StatusNotFound {
+		if cmd.CheckUpdates {
+			updates, err = workspace2.CheckImageUpdates(ctx, client.WorkspaceConfig(), workspace2.DefaultUpdateCheckInterval)
+			if err != nil {
+				log.Warnf("Error checking for workspace image updates: %v", err)
+			}
+		} else {
+			updates = workspace2.CachedImageUpdates(client.WorkspaceConfig())
+		}
+	}
+
 	if cmd.Output == "plain" {
 		if instanceStatus == client2.StatusStopped {
 			log.Infof("Workspace '%s' is '%s', you can start it via 'devspace up %s'", client.Workspace(), instanceStatus, client.Workspace())
@@ -95,12 +116,20 @@ func (cmd *StatusCmd) Run(ctx context.Context, client client2.BaseWorkspaceClien
 		} else {
 			log.Infof("Workspace '%s' is '%s'", client.Workspace(), instanceStatus)
 		}
+
+		if len(updates) > 0 {
+			for _, update := range updates {
+				log.Warnf("Update available for %s", update.String())
+			}
+			log.Infof("Run 'devspace up %s --rebase' to recreate the workspace on the latest versions", client.Workspace())
+		}
 	} else if cmd.Output == "json" {
 		out, err := json.Marshal(&client2.WorkspaceStatus{
 			ID:       client.Workspace(),
 			Context:  client.Context(),
 			Provider: client.Provider(),
 			State:    string(instanceStatus),
+			Updates:  updates,
 		})
 		if err != nil {
 			return err
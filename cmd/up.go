@@ -3,9 +3,11 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net"
+	"net/url"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -14,36 +16,45 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/blang/semver"
 	"dev.khulnasoft.com/cmd/flags"
 	"dev.khulnasoft.com/pkg/agent"
 	"dev.khulnasoft.com/pkg/agent/tunnelserver"
+	"dev.khulnasoft.com/pkg/browserproxy"
 	client2 "dev.khulnasoft.com/pkg/client"
 	"dev.khulnasoft.com/pkg/client/clientimplementation"
 	"dev.khulnasoft.com/pkg/command"
 	"dev.khulnasoft.com/pkg/config"
+	"dev.khulnasoft.com/pkg/daemon/local"
 	config2 "dev.khulnasoft.com/pkg/devcontainer/config"
 	"dev.khulnasoft.com/pkg/devcontainer/sshtunnel"
 	"dev.khulnasoft.com/pkg/ide"
+	"dev.khulnasoft.com/pkg/ide/codeserver"
+	"dev.khulnasoft.com/pkg/ide/custom"
 	"dev.khulnasoft.com/pkg/ide/fleet"
 	"dev.khulnasoft.com/pkg/ide/jetbrains"
 	"dev.khulnasoft.com/pkg/ide/jupyter"
 	"dev.khulnasoft.com/pkg/ide/openvscode"
 	"dev.khulnasoft.com/pkg/ide/rstudio"
+	"dev.khulnasoft.com/pkg/ide/settingssync"
 	"dev.khulnasoft.com/pkg/ide/vscode"
 	"dev.khulnasoft.com/pkg/ide/zed"
 	open2 "dev.khulnasoft.com/pkg/open"
 	"dev.khulnasoft.com/pkg/platform"
 	"dev.khulnasoft.com/pkg/port"
 	provider2 "dev.khulnasoft.com/pkg/provider"
+	"dev.khulnasoft.com/pkg/shell"
 	devssh "dev.khulnasoft.com/pkg/ssh"
 	"dev.khulnasoft.com/pkg/telemetry"
 	"dev.khulnasoft.com/pkg/tunnel"
+	"dev.khulnasoft.com/pkg/usage"
 	"dev.khulnasoft.com/pkg/util"
 	"dev.khulnasoft.com/pkg/version"
 	workspace2 "dev.khulnasoft.com/pkg/workspace"
 	"dev.khulnasoft.com/log"
+	"github.com/mattn/go-isatty"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/skratchdot/open-golang/open"
@@ -117,6 +128,7 @@ func NewUpCmd(f *flags.GlobalFlags) *cobra.Command {
 	upCmd.Flags().BoolVar(&cmd.Reconfigure, "reconfigure", false, "Reconfigure the options for this workspace. Only supported in DevSpace Pro right now.")
 	upCmd.Flags().BoolVar(&cmd.Recreate, "recreate", false, "If true will remove any existing containers and recreate them")
 	upCmd.Flags().BoolVar(&cmd.Reset, "reset", false, "If true will remove any existing containers including sources, and recreate them")
+	upCmd.Flags().BoolVar(&cmd.Rebase, "rebase", false, "If true will recreate the workspace on the latest base image and features, keeping volumes and sources")
 	upCmd.Flags().StringSliceVar(&cmd.PrebuildRepositories, "prebuild-repository", []string{}, "Docker repository that hosts devspace prebuilds for this workspace")
 	upCmd.Flags().StringArrayVar(&cmd.WorkspaceEnv, "workspace-env", []string{}, "Extra env variables to put into the workspace. E.g. MY_ENV_VAR=MY_VALUE")
 	upCmd.Flags().StringSliceVar(&cmd.WorkspaceEnvFile, "workspace-env-file", []string{}, "The path to files containing a list of extra env variables to put into the workspace. E.g. MY_ENV_VAR=MY_VALUE")
@@ -130,7 +142,7 @@ func NewUpCmd(f *flags.GlobalFlags) *cobra.Command {
 	upCmd.Flags().StringVar(&cmd.GitSSHSigningKey, "git-ssh-signing-key", "", "The ssh key to use when signing git commits. Used to explicitly setup DevSpace's ssh signature forwarding with given key. Should be same format as value of `git config user.signingkey`")
 	upCmd.Flags().StringVar(&cmd.FallbackImage, "fallback-image", "", "The fallback image to use if no devcontainer configuration has been detected")
 	upCmd.Flags().BoolVar(&cmd.DisableDaemon, "disable-daemon", false, "If enabled, will not install a daemon into the target machine to track activity")
-	upCmd.Flags().StringVar(&cmd.Source, "source", "", "Optional source for the workspace. E.g. git:https://github.com/my-org/my-repo")
+	upCmd.Flags().StringVar(&cmd.Source, "source", "", "Optional source for the workspace. E.g. git:https://github.com/my-org/my-repo or k8s:<namespace>/[<kind>/]<name>[/<container>] to attach to a running Kubernetes workload")
 
 	// testing
 	upCmd.Flags().StringVar(&cmd.DaemonInterval, "daemon-interval", "", "TESTING ONLY")
@@ -148,8 +160,8 @@ func (cmd *UpCmd) Run(
 	args []string,
 	log log.Logger,
 ) error {
-	// a reset implies a recreate
-	if cmd.Reset {
+	// a reset or rebase implies a recreate
+	if cmd.Reset || cmd.Rebase {
 		cmd.Recreate = true
 	}
 
@@ -176,6 +188,11 @@ func (cmd *UpCmd) Run(
 		return nil
 	}
 
+	// remember what the workspace was created from, so build prune keeps it and status can check for updates
+	if result.ContainerDetails != nil {
+		saveImageSources(client.WorkspaceConfig(), result.ContainerDetails.Config.Labels, log)
+	}
+
 	// get user from result
 	user := config2.GetRemoteUser(result)
 
@@ -197,7 +214,9 @@ func (cmd *UpCmd) Run(
 		}
 		setupGPGAgentForwarding := cmd.GPGAgentForwarding || devSpaceConfig.ContextOption(config.ContextOptionGPGAgentForwarding) == "true"
 
-		err = configureSSH(client, cmd.SSHConfigPath, user, workdir, setupGPGAgentForwarding, devSpaceHome)
+		wildcard := devSpaceConfig.ContextOption(config.ContextOptionSSHWildcardConfig) == "true"
+
+		err = configureSSH(client, cmd.SSHConfigPath, user, workdir, setupGPGAgentForwarding, wildcard, devSpaceHome)
 		if err != nil {
 			return err
 		}
@@ -205,6 +224,14 @@ func (cmd *UpCmd) Run(
 		log.Infof("Run 'ssh %s.devspace' to ssh into the devcontainer", client.Workspace())
 	}
 
+	// let the daemon connect to the workspace, so the ssh sessions of the ide attach to a warm connection
+	if _, ok := client.(client2.WorkspaceClient); ok && !client.WorkspaceConfig().IsPro() && devSpaceConfig.ContextOption(config.ContextOptionSSHMultiplexing) == "true" {
+		err = local.Connect(client.Context(), client.Workspace(), log)
+		if err != nil {
+			log.Debugf("Error connecting the daemon to workspace %s: %v", client.Workspace(), err)
+		}
+	}
+
 	// setup git ssh signature
 	if cmd.GitSSHSigningKey != "" {
 		err = setupGitSSHSignature(cmd.GitSSHSigningKey, client, log)
@@ -219,120 +246,206 @@ func (cmd *UpCmd) Run(
 		return err
 	}
 
+	// sync the local ide settings into the workspace
+	if devSpaceConfig.ContextOption(config.ContextOptionSyncIDESettings) == "true" {
+		err = syncIDESettings(client.WorkspaceConfig().IDE.Name, result.SubstitutionContext.ContainerWorkspaceFolder, client, log)
+		if err != nil {
+			log.Warnf("Error syncing IDE settings: %v", err)
+		}
+	}
+
 	// open ide
 	if cmd.OpenIDE {
-		ideConfig := client.WorkspaceConfig().IDE
-		switch ideConfig.Name {
-		case string(config.IDEVSCode):
-			return vscode.Open(
-				ctx,
-				client.Workspace(),
-				result.SubstitutionContext.ContainerWorkspaceFolder,
-				vscode.Options.GetValue(ideConfig.Options, vscode.OpenNewWindow) == "true",
-				vscode.FlavorStable,
-				log,
-			)
-		case string(config.IDEVSCodeInsiders):
-			return vscode.Open(
-				ctx,
-				client.Workspace(),
-				result.SubstitutionContext.ContainerWorkspaceFolder,
-				vscode.Options.GetValue(ideConfig.Options, vscode.OpenNewWindow) == "true",
-				vscode.FlavorInsiders,
-				log,
-			)
-		case string(config.IDECursor):
-			return vscode.Open(
-				ctx,
-				client.Workspace(),
-				result.SubstitutionContext.ContainerWorkspaceFolder,
-				vscode.Options.GetValue(ideConfig.Options, vscode.OpenNewWindow) == "true",
-				vscode.FlavorCursor,
-				log,
-			)
-		case string(config.IDECodium):
-			return vscode.Open(
-				ctx,
-				client.Workspace(),
-				result.SubstitutionContext.ContainerWorkspaceFolder,
-				vscode.Options.GetValue(ideConfig.Options, vscode.OpenNewWindow) == "true",
-				vscode.FlavorCodium,
-				log,
-			)
-		case string(config.IDEPositron):
-			return vscode.Open(
-				ctx,
-				client.Workspace(),
-				result.SubstitutionContext.ContainerWorkspaceFolder,
-				vscode.Options.GetValue(ideConfig.Options, vscode.OpenNewWindow) == "true",
-				vscode.FlavorPositron,
-				log,
-			)
-		case string(config.IDEWindsurf):
-			return vscode.Open(
-				ctx,
-				client.Workspace(),
-				result.SubstitutionContext.ContainerWorkspaceFolder,
-				vscode.Options.GetValue(ideConfig.Options, vscode.OpenNewWindow) == "true",
-				vscode.FlavorWindsurf,
-				log,
-			)
-		case string(config.IDEOpenVSCode):
-			return startVSCodeInBrowser(
-				cmd.GPGAgentForwarding,
-				ctx,
-				devSpaceConfig,
-				client,
-				result.SubstitutionContext.ContainerWorkspaceFolder,
-				user,
-				ideConfig.Options,
-				cmd.SSHAuthSockID,
-				log,
-			)
-		case string(config.IDERustRover):
-			return jetbrains.NewRustRoverServer(config2.GetRemoteUser(result), ideConfig.Options, log).OpenGateway(result.SubstitutionContext.ContainerWorkspaceFolder, client.Workspace())
-		case string(config.IDEGoland):
-			return jetbrains.NewGolandServer(config2.GetRemoteUser(result), ideConfig.Options, log).OpenGateway(result.SubstitutionContext.ContainerWorkspaceFolder, client.Workspace())
-		case string(config.IDEPyCharm):
-			return jetbrains.NewPyCharmServer(config2.GetRemoteUser(result), ideConfig.Options, log).OpenGateway(result.SubstitutionContext.ContainerWorkspaceFolder, client.Workspace())
-		case string(config.IDEPhpStorm):
-			return jetbrains.NewPhpStorm(config2.GetRemoteUser(result), ideConfig.Options, log).OpenGateway(result.SubstitutionContext.ContainerWorkspaceFolder, client.Workspace())
-		case string(config.IDEIntellij):
-			return jetbrains.NewIntellij(config2.GetRemoteUser(result), ideConfig.Options, log).OpenGateway(result.SubstitutionContext.ContainerWorkspaceFolder, client.Workspace())
-		case string(config.IDECLion):
-			return jetbrains.NewCLionServer(config2.GetRemoteUser(result), ideConfig.Options, log).OpenGateway(result.SubstitutionContext.ContainerWorkspaceFolder, client.Workspace())
-		case string(config.IDERider):
-			return jetbrains.NewRiderServer(config2.GetRemoteUser(result), ideConfig.Options, log).OpenGateway(result.SubstitutionContext.ContainerWorkspaceFolder, client.Workspace())
-		case string(config.IDERubyMine):
-			return jetbrains.NewRubyMineServer(config2.GetRemoteUser(result), ideConfig.Options, log).OpenGateway(result.SubstitutionContext.ContainerWorkspaceFolder, client.Workspace())
-		case string(config.IDEWebStorm):
-			return jetbrains.NewWebStormServer(config2.GetRemoteUser(result), ideConfig.Options, log).OpenGateway(result.SubstitutionContext.ContainerWorkspaceFolder, client.Workspace())
-		case string(config.IDEDataSpell):
-			return jetbrains.NewDataSpellServer(config2.GetRemoteUser(result), ideConfig.Options, log).OpenGateway(result.SubstitutionContext.ContainerWorkspaceFolder, client.Workspace())
-		case string(config.IDEFleet):
-			return startFleet(ctx, client, log)
-		case string(config.IDEZed):
-			return zed.Open(ctx, ideConfig.Options, config2.GetRemoteUser(result), result.SubstitutionContext.ContainerWorkspaceFolder, client.Workspace(), log)
-		case string(config.IDEJupyterNotebook):
-			return startJupyterNotebookInBrowser(
-				cmd.GPGAgentForwarding,
-				ctx,
-				devSpaceConfig,
-				client,
-				user,
-				ideConfig.Options,
-				cmd.SSHAuthSockID,
-				log,
-			)
-		case string(config.IDERStudio):
-			return startRStudioInBrowser(
-				cmd.GPGAgentForwarding,
+		return openIDE(
+			ctx,
+			devSpaceConfig,
+			client,
+			client.WorkspaceConfig().IDE,
+			user,
+			result.SubstitutionContext.ContainerWorkspaceFolder,
+			cmd.GPGAgentForwarding,
+			cmd.SSHAuthSockID,
+			log,
+		)
+	}
+
+	return nil
+}
+
+// openIDE opens the IDE on the local machine, for browser based IDEs it keeps the tunnel open until the context is
+// canceled
+func openIDE(
+	ctx context.Context,
+	devSpaceConfig *config.Config,
+	client client2.BaseWorkspaceClient,
+	ideConfig provider2.WorkspaceIDEConfig,
+	user string,
+	workspaceFolder string,
+	forwardGpg bool,
+	authSockID string,
+	log log.Logger,
+) error {
+	switch ideConfig.Name {
+	case string(config.IDEVSCode):
+		return vscode.Open(
+			ctx,
+			client.Workspace(),
+			workspaceFolder,
+			vscode.Options.GetValue(ideConfig.Options, vscode.OpenNewWindow) == "true",
+			vscode.FlavorStable,
+			log,
+		)
+	case string(config.IDEVSCodeInsiders):
+		return vscode.Open(
+			ctx,
+			client.Workspace(),
+			workspaceFolder,
+			vscode.Options.GetValue(ideConfig.Options, vscode.OpenNewWindow) == "true",
+			vscode.FlavorInsiders,
+			log,
+		)
+	case string(config.IDECursor):
+		return vscode.Open(
+			ctx,
+			client.Workspace(),
+			workspaceFolder,
+			vscode.Options.GetValue(ideConfig.Options, vscode.OpenNewWindow) == "true",
+			vscode.FlavorCursor,
+			log,
+		)
+	case string(config.IDECodium):
+		return vscode.Open(
+			ctx,
+			client.Workspace(),
+			workspaceFolder,
+			vscode.Options.GetValue(ideConfig.Options, vscode.OpenNewWindow) == "true",
+			vscode.FlavorCodium,
+			log,
+		)
+	case string(config.IDEPositron):
+		return vscode.Open(
+			ctx,
+			client.Workspace(),
+			workspaceFolder,
+			vscode.Options.GetValue(ideConfig.Options, vscode.OpenNewWindow) == "true",
+			vscode.FlavorPositron,
+			log,
+		)
+	case string(config.IDEWindsurf):
+		return vscode.Open(
+			ctx,
+			client.Workspace(),
+			workspaceFolder,
+			vscode.Options.GetValue(ideConfig.Options, vscode.OpenNewWindow) == "true",
+			vscode.FlavorWindsurf,
+			log,
+		)
+	case string(config.IDEOpenVSCode):
+		return startVSCodeInBrowser(
+			forwardGpg,
+			ctx,
+			devSpaceConfig,
+			client,
+			workspaceFolder,
+			user,
+			ideConfig.Options,
+			authSockID,
+			log,
+		)
+	case string(config.IDERustRover):
+		return jetbrains.NewRustRoverServer(user, ideConfig.Options, log).OpenGateway(workspaceFolder, client.Workspace())
+	case string(config.IDEGoland):
+		return jetbrains.NewGolandServer(user, ideConfig.Options, log).OpenGateway(workspaceFolder, client.Workspace())
+	case string(config.IDEPyCharm):
+		return jetbrains.NewPyCharmServer(user, ideConfig.Options, log).OpenGateway(workspaceFolder, client.Workspace())
+	case string(config.IDEPhpStorm):
+		return jetbrains.NewPhpStorm(user, ideConfig.Options, log).OpenGateway(workspaceFolder, client.Workspace())
+	case string(config.IDEIntellij):
+		return jetbrains.NewIntellij(user, ideConfig.Options, log).OpenGateway(workspaceFolder, client.Workspace())
+	case string(config.IDECLion):
+		return jetbrains.NewCLionServer(user, ideConfig.Options, log).OpenGateway(workspaceFolder, client.Workspace())
+	case string(config.IDERider):
+		return jetbrains.NewRiderServer(user, ideConfig.Options, log).OpenGateway(workspaceFolder, client.Workspace())
+	case string(config.IDERubyMine):
+		return jetbrains.NewRubyMineServer(user, ideConfig.Options, log).OpenGateway(workspaceFolder, client.Workspace())
+	case string(config.IDEWebStorm):
+		return jetbrains.NewWebStormServer(user, ideConfig.Options, log).OpenGateway(workspaceFolder, client.Workspace())
+	case string(config.IDEDataSpell):
+		return jetbrains.NewDataSpellServer(user, ideConfig.Options, log).OpenGateway(workspaceFolder, client.Workspace())
+	case string(config.IDEFleet):
+		return startFleet(ctx, client, log)
+	case string(config.IDEZed):
+		return zed.Open(ctx, ideConfig.Options, user, workspaceFolder, client.Workspace(), log)
+	case string(config.IDEJupyterNotebook):
+		return startWebIDEInBrowser(
+			forwardGpg,
+			ctx,
+			devSpaceConfig,
+			client,
+			user,
+			"Jupyter Notebook",
+			jupyter.DefaultServerPort,
+			"/lab",
+			ideConfig.Options,
+			authSockID,
+			log,
+		)
+	case string(config.IDEJupyterLab):
+		return startWebIDEInBrowser(
+			forwardGpg,
+			ctx,
+			devSpaceConfig,
+			client,
+			user,
+			"JupyterLab",
+			jupyter.DefaultLabServerPort,
+			"/lab",
+			ideConfig.Options,
+			authSockID,
+			log,
+		)
+	case string(config.IDECodeServer):
+		return startWebIDEInBrowser(
+			forwardGpg,
+			ctx,
+			devSpaceConfig,
+			client,
+			user,
+			"code-server",
+			codeserver.DefaultServerPort,
+			"/",
+			ideConfig.Options,
+			authSockID,
+			log,
+		)
+	case string(config.IDERStudio):
+		return startWebIDEInBrowser(
+			forwardGpg,
+			ctx,
+			devSpaceConfig,
+			client,
+			user,
+			"RStudio Server",
+			rstudio.DefaultServerPort,
+			"/",
+			ideConfig.Options,
+			authSockID,
+			log,
+		)
+	case string(config.IDETerminal):
+		return startTerminal(ctx, client, log)
+	default:
+		if ideConfig.Custom != nil {
+			return startCustomIDE(
+				forwardGpg,
 				ctx,
 				devSpaceConfig,
 				client,
+				workspaceFolder,
 				user,
+				ideConfig.Custom,
 				ideConfig.Options,
-				cmd.SSHAuthSockID,
+				authSockID,
 				log,
 			)
 		}
@@ -367,6 +480,7 @@ func (cmd *UpCmd) devSpaceUp(
 		if err != nil {
 			return nil, err
 		}
+		usage.RecordWorkspace(devSpaceConfig, client.WorkspaceConfig(), usage.EventStarted, log)
 	case client2.ProxyClient:
 		result, err = cmd.devSpaceUpProxy(ctx, client, log)
 		if err != nil {
@@ -504,8 +618,19 @@ func (cmd *UpCmd) devSpaceUpMachine(
 		return nil, err
 	}
 
+	// a remote agent streams the local VSIX folder through the tunnel
+	cliOptions := cmd.CLIOptions
+	upServerOptions := []tunnelserver.Option{}
+	if !client.AgentLocal() {
+		vsixFolder := localVSIXFolder(client.WorkspaceConfig(), log)
+		if vsixFolder != "" {
+			cliOptions.VSIXFolder = vsixFolder
+			upServerOptions = append(upServerOptions, tunnelserver.WithMounts([]*config2.Mount{vscode.VSIXMount(vsixFolder)}))
+		}
+	}
+
 	// compress info
-	workspaceInfo, wInfo, err := client.AgentInfo(cmd.CLIOptions)
+	workspaceInfo, wInfo, err := client.AgentInfo(cliOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -577,17 +702,42 @@ func (cmd *UpCmd) devSpaceUpMachine(
 				client.AgentInjectDockerCredentials(cmd.CLIOptions),
 				client.WorkspaceConfig(),
 				log,
+				upServerOptions...,
 			)
 		},
 	)
 }
 
-func startJupyterNotebookInBrowser(
+// localVSIXFolder returns the VSIX folder of the workspace if it exists on this machine
+func localVSIXFolder(workspace *provider2.Workspace, log log.Logger) string {
+	folder, err := vscode.GetLocalVSIXFolder(workspace.IDE.Options)
+	if err != nil {
+		log.Warnf("Error resolving VSIX folder: %v", err)
+		return ""
+	} else if folder == "" {
+		return ""
+	}
+
+	_, err = os.Stat(folder)
+	if err != nil {
+		log.Debugf("Skip streaming VSIX folder %s: %v", folder, err)
+		return ""
+	}
+
+	return folder
+}
+
+// startWebIDEInBrowser forwards the port of a web IDE server that was started by the agent and opens the given
+// path in the browser, either through the browser proxy or the forwarded local port
+func startWebIDEInBrowser(
 	forwardGpg bool,
 	ctx context.Context,
 	devSpaceConfig *config.Config,
 	client client2.BaseWorkspaceClient,
 	user string,
+	displayName string,
+	remotePort int,
+	path string,
 	ideOptions map[string]config.OptionValue,
 	authSockID string,
 	logger log.Logger,
@@ -600,32 +750,44 @@ func startJupyterNotebookInBrowser(
 	}
 
 	// determine port
-	jupyterAddress, jupyterPort, err := parseAddressAndPort(
-		jupyter.Options.GetValue(ideOptions, jupyter.BindAddressOption),
-		jupyter.DefaultServerPort,
+	webOptions := ide.WithWebOptions(nil)
+	addr, port, err := parseAddressAndPort(
+		webOptions.GetValue(ideOptions, ide.BindAddressOption),
+		remotePort,
 	)
 	if err != nil {
 		return err
 	}
 
+	// serve through the browser proxy if enabled
+	proxy, err := browserproxy.New(devSpaceConfig, client.Workspace(), remotePort)
+	if err != nil {
+		return err
+	}
+
 	// wait until reachable then open browser
-	targetURL := fmt.Sprintf("http://localhost:%d/lab", jupyterPort)
-	if jupyter.Options.GetValue(ideOptions, jupyter.OpenOption) == "true" {
+	targetURL := fmt.Sprintf("http://localhost:%d%s", port, path)
+	extraPorts := []string{fmt.Sprintf("%s:%d", addr, remotePort)}
+	if proxy != nil {
+		targetURL = proxy.URL(path)
+		extraPorts = nil
+	}
+	if webOptions.GetValue(ideOptions, ide.OpenOption) == "true" {
 		go func() {
-			err = open2.Open(ctx, targetURL, logger)
+			err = openBrowser(ctx, proxy, targetURL, path, logger)
 			if err != nil {
-				logger.Errorf("error opening jupyter notebook: %v", err)
+				logger.Errorf("error opening %s: %v", displayName, err)
 			}
 
 			logger.Infof(
-				"Successfully started jupyter notebook in browser mode. Please keep this terminal open as long as you use Jupyter Notebook",
+				"Successfully started %s in browser mode. Please keep this terminal open as long as you use it",
+				displayName,
 			)
 		}()
 	}
 
 	// start in browser
-	logger.Infof("Starting jupyter notebook in browser mode at %s", targetURL)
-	extraPorts := []string{fmt.Sprintf("%s:%d", jupyterAddress, jupyter.DefaultServerPort)}
+	logger.Infof("Starting %s in browser mode at %s", displayName, targetURL)
 	return startBrowserTunnel(
 		ctx,
 		devSpaceConfig,
@@ -634,55 +796,100 @@ func startJupyterNotebookInBrowser(
 		targetURL,
 		false,
 		extraPorts,
+		proxy,
 		authSockID,
 		logger,
 	)
 }
 
-func startRStudioInBrowser(
+// startCustomIDE opens an IDE added with devspace ide add. Its ports are forwarded until the command is
+// interrupted, the first local port is available as LOCAL_PORT to the open url and command.
+func startCustomIDE(
 	forwardGpg bool,
 	ctx context.Context,
 	devSpaceConfig *config.Config,
 	client client2.BaseWorkspaceClient,
+	workspaceFolder string,
 	user string,
+	ideConfig *custom.IDEConfig,
 	ideOptions map[string]config.OptionValue,
 	authSockID string,
 	logger log.Logger,
 ) error {
-	if forwardGpg {
-		err := performGpgForwarding(client, logger)
+	vars := ideConfig.Environ(ideOptions, map[string]string{
+		"WORKSPACE_ID":     client.Workspace(),
+		"SSH_HOST":         client.Workspace() + ".devspace",
+		"WORKSPACE_FOLDER": workspaceFolder,
+		"REMOTE_USER":      user,
+	})
+
+	// determine ports
+	extraPorts := []string{}
+	for _, rawPort := range ideConfig.Ports {
+		remotePort, err := strconv.Atoi(custom.Expand(rawPort, vars))
+		if err != nil {
+			return fmt.Errorf("parse port '%s' of ide %s: %w", rawPort, ideConfig.Name, err)
+		}
+
+		addr, localPort, err := parseAddressAndPort("", remotePort)
 		if err != nil {
 			return err
 		}
-	}
+		if len(extraPorts) == 0 {
+			vars["LOCAL_PORT"] = strconv.Itoa(localPort)
+		}
 
-	// determine port
-	addr, port, err := parseAddressAndPort(
-		rstudio.Options.GetValue(ideOptions, rstudio.BindAddressOption),
-		rstudio.DefaultServerPort,
-	)
-	if err != nil {
-		return err
+		extraPorts = append(extraPorts, fmt.Sprintf("%s:%d", addr, remotePort))
 	}
 
-	// wait until reachable then open browser
-	targetURL := fmt.Sprintf("http://localhost:%d", port)
-	if rstudio.Options.GetValue(ideOptions, rstudio.OpenOption) == "true" {
-		go func() {
-			err = open2.Open(ctx, targetURL, logger)
+	openIDE := func() error {
+		if ideConfig.Open.URL != "" {
+			err := open2.Open(ctx, custom.Expand(ideConfig.Open.URL, vars), logger)
 			if err != nil {
-				logger.Errorf("error opening rstudio: %v", err)
+				return err
 			}
+		}
 
-			logger.Infof(
-				"Successfully started RStudio Server in browser mode. Please keep this terminal open as long as you use it",
-			)
-		}()
+		if ideConfig.Open.Command != "" {
+			env := os.Environ()
+			for k, v := range vars {
+				env = append(env, k+"="+v)
+			}
+
+			writer := logger.Writer(logrus.InfoLevel, false)
+			defer writer.Close()
+
+			err := shell.RunEmulatedShell(ctx, ideConfig.Open.Command, nil, writer, writer, env)
+			if err != nil {
+				return fmt.Errorf("run open command: %w", err)
+			}
+		}
+
+		return nil
 	}
 
-	// start in browser
-	logger.Infof("Starting RStudio server in browser mode at %s", targetURL)
-	extraPorts := []string{fmt.Sprintf("%s:%d", addr, rstudio.DefaultServerPort)}
+	// nothing to forward
+	if len(extraPorts) == 0 {
+		return openIDE()
+	}
+
+	if forwardGpg {
+		err := performGpgForwarding(client, logger)
+		if err != nil {
+			return err
+		}
+	}
+
+	go func() {
+		err := openIDE()
+		if err != nil {
+			logger.Errorf("error opening %s: %v", ideConfig.Name, err)
+		}
+	}()
+
+	// start tunnel
+	targetURL := custom.Expand(ideConfig.Open.URL, vars)
+	logger.Infof("Forwarding ports of %s, please keep this terminal open as long as you use it", ideConfig.Name)
 	return startBrowserTunnel(
 		ctx,
 		devSpaceConfig,
@@ -691,11 +898,32 @@ func startRStudioInBrowser(
 		targetURL,
 		false,
 		extraPorts,
+		nil,
 		authSockID,
 		logger,
 	)
 }
 
+// startTerminal attaches the current terminal to the session of the terminal IDE
+func startTerminal(ctx context.Context, client client2.BaseWorkspaceClient, logger log.Logger) error {
+	if !isatty.IsTerminal(os.Stdin.Fd()) {
+		logger.Infof("Run 'devspace ssh %s' to attach to the terminal session", client.Workspace())
+		return nil
+	}
+
+	execPath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Attaching to the terminal session, run 'devspace ssh %s' to reattach later", client.Workspace())
+	cmd := exec.CommandContext(ctx, execPath, "ssh", "--context", client.Context(), client.Workspace())
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
 func startFleet(ctx context.Context, client client2.BaseWorkspaceClient, logger log.Logger) error {
 	// create ssh command
 	stdout := &bytes.Buffer{}
@@ -757,11 +985,28 @@ func startVSCodeInBrowser(
 		return err
 	}
 
+	// serve through the browser proxy if enabled
+	proxy, err := browserproxy.New(devSpaceConfig, client.Workspace(), openvscode.DefaultVSCodePort)
+	if err != nil {
+		return err
+	}
+
 	// wait until reachable then open browser
 	targetURL := fmt.Sprintf("http://localhost:%d/?folder=%s", vscodePort, workspaceFolder)
+	extraPorts := []string{fmt.Sprintf("%s:%d", vscodeAddress, openvscode.DefaultVSCodePort)}
+	forwardPorts := openvscode.Options.GetValue(ideOptions, openvscode.ForwardPortsOption) == "true"
+	if proxy != nil {
+		targetURL = proxy.URL("/?folder=" + url.QueryEscape(workspaceFolder))
+		extraPorts = nil
+		if forwardPorts {
+			// ports are reachable through the proxy instead
+			logger.Infof("Ports of the workspace are reachable at %s", strings.Replace(proxy.PortURL(0), "://0.", "://<port>.", 1))
+			forwardPorts = false
+		}
+	}
 	if openvscode.Options.GetValue(ideOptions, openvscode.OpenOption) == "true" {
 		go func() {
-			err = open2.Open(ctx, targetURL, logger)
+			err = openBrowser(ctx, proxy, targetURL, "/?folder="+url.QueryEscape(workspaceFolder), logger)
 			if err != nil {
 				logger.Errorf("error opening vscode: %v", err)
 			}
@@ -774,8 +1019,6 @@ func startVSCodeInBrowser(
 
 	// start in browser
 	logger.Infof("Starting vscode in browser mode at %s", targetURL)
-	forwardPorts := openvscode.Options.GetValue(ideOptions, openvscode.ForwardPortsOption) == "true"
-	extraPorts := []string{fmt.Sprintf("%s:%d", vscodeAddress, openvscode.DefaultVSCodePort)}
 	return startBrowserTunnel(
 		ctx,
 		devSpaceConfig,
@@ -784,6 +1027,7 @@ func startVSCodeInBrowser(
 		targetURL,
 		forwardPorts,
 		extraPorts,
+		proxy,
 		authSockID,
 		logger,
 	)
@@ -827,7 +1071,7 @@ func setupBackhaul(client client2.BaseWorkspaceClient, authSockId string, log lo
 		return err
 	}
 
-	remoteUser, err := devssh.GetUser(client.WorkspaceConfig().ID, client.WorkspaceConfig().SSHConfigPath)
+	remoteUser, err := devssh.GetWorkspaceUser(client.WorkspaceConfig())
 	if err != nil {
 		remoteUser = "root"
 	}
@@ -876,6 +1120,7 @@ func startBrowserTunnel(
 	user, targetURL string,
 	forwardPorts bool,
 	extraPorts []string,
+	proxy *browserproxy.Proxy,
 	authSockID string,
 	logger log.Logger,
 ) error {
@@ -898,6 +1143,7 @@ func startBrowserTunnel(
 			return err
 		}
 		defer toolClient.Close()
+		serveBrowserProxy(ctx, devSpaceConfig, proxy, toolClient, logger)
 
 		err = startServicesDaemon(ctx,
 			devSpaceConfig,
@@ -945,6 +1191,8 @@ func startBrowserTunnel(
 				})
 			}
 
+			serveBrowserProxy(ctx, devSpaceConfig, proxy, containerClient, logger)
+
 			configureDockerCredentials := devSpaceConfig.ContextOption(config.ContextOptionSSHInjectDockerCredentials) == "true"
 			configureGitCredentials := devSpaceConfig.ContextOption(config.ContextOptionSSHInjectGitCredentials) == "true"
 			configureGitSSHSignatureHelper := devSpaceConfig.ContextOption(config.ContextOptionGitSSHSignatureForwarding) == "true"
@@ -979,13 +1227,62 @@ func startBrowserTunnel(
 	return nil
 }
 
-func configureSSH(client client2.BaseWorkspaceClient, sshConfigPath, user, workdir string, gpgagent bool, devSpaceHome string) error {
+// serveBrowserProxy serves the browser IDE through the browser proxy in the background if it is enabled
+func serveBrowserProxy(ctx context.Context, devSpaceConfig *config.Config, proxy *browserproxy.Proxy, sshClient *ssh.Client, logger log.Logger) {
+	if proxy == nil {
+		return
+	}
+
+	exitAfterTimeout := time.Duration(0)
+	if devSpaceConfig.ContextOption(config.ContextOptionExitAfterTimeout) == "true" {
+		exitAfterTimeout = time.Second * 5
+	}
+
+	go func() {
+		err := proxy.Serve(ctx, sshClient, exitAfterTimeout, logger)
+		if err != nil {
+			logger.Errorf("Error serving browser proxy: %v", err)
+		}
+	}()
+}
+
+// openBrowser opens the url once it is reachable, through the browser proxy if it is enabled
+func openBrowser(ctx context.Context, proxy *browserproxy.Proxy, targetURL, path string, logger log.Logger) error {
+	if proxy != nil {
+		return proxy.Open(ctx, path, logger)
+	}
+
+	return open2.Open(ctx, targetURL, logger)
+}
+
+func configureSSH(client client2.BaseWorkspaceClient, sshConfigPath, user, workdir string, gpgagent, wildcard bool, devSpaceHome string) error {
 	path, err := devssh.ResolveSSHConfigPath(sshConfigPath)
 	if err != nil {
 		return errors.Wrap(err, "Invalid ssh config path")
 	}
 	sshConfigPath = path
 
+	// user and workdir are resolved by devspace ssh when connecting, gpg agent forwarding is remembered in the
+	// workspace because it can't be part of the shared host entry
+	if wildcard {
+		workspace := client.WorkspaceConfig()
+		if workspace.GPGAgentForwarding != gpgagent {
+			workspace.GPGAgentForwarding = gpgagent
+			err = provider2.SaveWorkspaceConfig(workspace)
+			if err != nil {
+				return errors.Wrap(err, "save workspace config")
+			}
+		}
+
+		return devssh.ConfigureWildcardSSHConfig(
+			sshConfigPath,
+			client.Context(),
+			client.Workspace(),
+			devSpaceHome,
+			log.Default,
+		)
+	}
+
 	err = devssh.ConfigureSSHConfig(
 		sshConfigPath,
 		client.Context(),
@@ -1163,7 +1460,7 @@ func buildDotCmd(devSpaceConfig *config.Config, dotfilesRepo, dotfilesScript str
 		sshCmd = append(sshCmd, "--send-env", envKey)
 	}
 
-	remoteUser, err := devssh.GetUser(client.WorkspaceConfig().ID, client.WorkspaceConfig().SSHConfigPath)
+	remoteUser, err := devssh.GetWorkspaceUser(client.WorkspaceConfig())
 	if err != nil {
 		remoteUser = "root"
 	}
@@ -1216,13 +1513,71 @@ func collectDotfilesScriptEnvKeyvaluePairs(envFiles []string) ([]string, error)
 	return keyValues, nil
 }
 
+// syncIDESettings copies the local settings of the IDE into the workspace, where the agent applies them as machine
+// settings. It runs as root, because the agent needs the container result to merge them with the devcontainer.json.
+func syncIDESettings(ideName, workspaceFolder string, client client2.BaseWorkspaceClient, log log.Logger) error {
+	rawPayload, agentCommand, err := ideSettingsSync(ideName, workspaceFolder, log)
+	if err != nil || rawPayload == nil {
+		return err
+	}
+
+	execPath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	writer := log.Writer(logrus.InfoLevel, false)
+	defer writer.Close()
+
+	log.Infof("Syncing %s settings into the workspace", ideName)
+	syncCmd := exec.Command(
+		execPath,
+		"ssh",
+		"--user",
+		"root",
+		"--context",
+		client.Context(),
+		client.Workspace(),
+		"--log-output=raw",
+		"--command",
+		agentCommand,
+	)
+	syncCmd.Stdin = bytes.NewReader(rawPayload)
+	syncCmd.Stdout = writer
+	syncCmd.Stderr = writer
+	return syncCmd.Run()
+}
+
+// ideSettingsSync collects the local settings of the ide and returns them together with the agent command that syncs
+// them into the workspace. The settings are nil if there is nothing to sync.
+func ideSettingsSync(ideName, workspaceFolder string, log log.Logger) ([]byte, string, error) {
+	payload, err := settingssync.Collect(ideName, log)
+	if err != nil {
+		return nil, "", fmt.Errorf("collect %s settings: %w", ideName, err)
+	} else if payload == nil {
+		return nil, "", nil
+	}
+
+	rawPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, "", err
+	}
+
+	agentCommand := fmt.Sprintf("'%s' agent container sync-ide-settings --workspace-folder '%s'", agent.ContainerDevSpaceHelperLocation, workspaceFolder)
+	if log.GetLevel() == logrus.DebugLevel {
+		agentCommand += " --debug"
+	}
+
+	return rawPayload, agentCommand, nil
+}
+
 func setupGitSSHSignature(signingKey string, client client2.BaseWorkspaceClient, log log.Logger) error {
 	execPath, err := os.Executable()
 	if err != nil {
 		return err
 	}
 
-	remoteUser, err := devssh.GetUser(client.WorkspaceConfig().ID, client.WorkspaceConfig().SSHConfigPath)
+	remoteUser, err := devssh.GetWorkspaceUser(client.WorkspaceConfig())
 	if err != nil {
 		remoteUser = "root"
 	}
@@ -1256,7 +1611,7 @@ func performGpgForwarding(
 		return err
 	}
 
-	remoteUser, err := devssh.GetUser(client.WorkspaceConfig().ID, client.WorkspaceConfig().SSHConfigPath)
+	remoteUser, err := devssh.GetWorkspaceUser(client.WorkspaceConfig())
 	if err != nil {
 		remoteUser = "root"
 	}
@@ -1350,6 +1705,30 @@ func checkProviderUpdate(devSpaceConfig *config.Config, proInstance *provider2.P
 	return nil
 }
 
+// saveImageSources stores the prebuild hash and image sources of the workspace container within the workspace config
+func saveImageSources(workspaceConfig *provider2.Workspace, labels map[string]string, log log.Logger) {
+	imageSources, err := config2.ParseImageSources(labels)
+	if err != nil {
+		log.Debugf("Error parsing image sources of workspace: %v", err)
+	}
+
+	prebuildHash := labels[config2.PrebuildHashLabel]
+	if (prebuildHash == "" || prebuildHash == workspaceConfig.PrebuildHash) && (imageSources == nil || slices.Equal(imageSources, workspaceConfig.ImageSources)) {
+		return
+	}
+
+	if prebuildHash != "" {
+		workspaceConfig.PrebuildHash = prebuildHash
+	}
+	if imageSources != nil {
+		workspaceConfig.ImageSources = imageSources
+	}
+	err = provider2.SaveWorkspaceConfig(workspaceConfig)
+	if err != nil {
+		log.Debugf("Error saving image sources of workspace: %v", err)
+	}
+}
+
 func getProInstance(devSpaceConfig *config.Config, providerName string, log log.Logger) *provider2.ProInstance {
 	proInstances, err := workspace2.ListProInstances(devSpaceConfig, log)
 	if err != nil {
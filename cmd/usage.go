@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"dev.khulnasoft.com/cmd/flags"
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/log/table"
+	"dev.khulnasoft.com/pkg/config"
+	"dev.khulnasoft.com/pkg/usage"
+	"github.com/spf13/cobra"
+)
+
+// UsageCmd holds the configuration
+type UsageCmd struct {
+	*flags.GlobalFlags
+
+	Output  string
+	Since   string
+	Until   string
+	Month   string
+	GroupBy []string
+}
+
+// NewUsageCmd creates a new command
+func NewUsageCmd(flags *flags.GlobalFlags) *cobra.Command {
+	cmd := &UsageCmd{
+		GlobalFlags: flags,
+	}
+	usageCmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Shows how long workspaces and machines have been running and what they cost",
+		Long: `Shows how long workspaces and machines have been running and what they cost.
+
+DevSpace records when workspaces and machines are created, started, stopped and
+deleted. Providers can declare an hourly price through the ` + usage.HourlyPriceOption + ` option.
+
+Example:
+devspace usage --month 2026-09 --group-by provider,machine-type --output csv`,
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return cmd.Run(context.Background())
+		},
+	}
+
+	usageCmd.Flags().StringVar(&cmd.Output, "output", "plain", "The output format to use. Can be plain, csv or json")
+	usageCmd.Flags().StringVar(&cmd.Since, "since", "", "Only include usage after this date, e.g. 2026-09-01")
+	usageCmd.Flags().StringVar(&cmd.Until, "until", "", "Only include usage before this date, e.g. 2026-10-01")
+	usageCmd.Flags().StringVar(&cmd.Month, "month", "", "Only include usage of this month, e.g. 2026-09")
+	usageCmd.Flags().StringSliceVar(&cmd.GroupBy, "group-by", []string{usage.GroupByProvider, usage.GroupByMachineType, usage.GroupByWorkspace}, "The dimensions to group by. Can be provider, machine-type and workspace")
+	return usageCmd
+}
+
+// Run runs the command logic
+func (cmd *UsageCmd) Run(ctx context.Context) error {
+	devSpaceConfig, err := config.LoadConfig(cmd.Context, cmd.Provider)
+	if err != nil {
+		return err
+	}
+
+	options := usage.ReportOptions{GroupBy: cmd.GroupBy}
+	if cmd.Month != "" {
+		if cmd.Since != "" || cmd.Until != "" {
+			return fmt.Errorf("--month cannot be used together with --since or --until")
+		}
+
+		options.Since, err = time.ParseInLocation("2006-01", cmd.Month, time.Local)
+		if err != nil {
+			return fmt.Errorf("parse month %s: %w", cmd.Month, err)
+		}
+		options.Until = options.Since.AddDate(0, 1, 0)
+	}
+	if cmd.Since != "" {
+		options.Since, err = parseUsageTime(cmd.Since)
+		if err != nil {
+			return err
+		}
+	}
+	if cmd.Until != "" {
+		options.Until, err = parseUsageTime(cmd.Until)
+		if err != nil {
+			return err
+		}
+	}
+
+	events, err := usage.LoadEvents(devSpaceConfig.DefaultContext)
+	if err != nil {
+		return err
+	}
+
+	entries, err := usage.Report(events, options, time.Now())
+	if err != nil {
+		return err
+	}
+
+	switch cmd.Output {
+	case "json":
+		out, err := json.Marshal(entries)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(out))
+	case "csv":
+		return usage.WriteCSV(os.Stdout, entries)
+	case "plain":
+		tableEntries := [][]string{}
+		for _, entry := range entries {
+			tableEntries = append(tableEntries, []string{
+				string(entry.Kind),
+				entry.Provider,
+				entry.MachineType,
+				entry.Workspace,
+				strconv.FormatFloat(entry.Hours, 'f', 2, 64),
+				strconv.FormatFloat(entry.Cost, 'f', 2, 64),
+			})
+		}
+		table.PrintTable(log.Default, []string{
+			"Kind",
+			"Provider",
+			"Machine Type",
+			"Workspace",
+			"Hours",
+			"Cost",
+		}, tableEntries)
+	default:
+		return fmt.Errorf("unexpected output format, choose either plain, csv or json. Got %s", cmd.Output)
+	}
+
+	return nil
+}
+
+func parseUsageTime(value string) (time.Time, error) {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return parsed, nil
+	}
+
+	parsed, err = time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse date %s, expected a format like 2026-09-01: %w", value, err)
+	}
+
+	return parsed, nil
+}
@@ -46,6 +46,8 @@ pub struct Settings {
     experimental_positron: bool,
     #[serde(rename = "experimental_rstudio")]
     experimental_rstudio: bool,
+    #[serde(rename = "experimental_codeServer")]
+    experimental_code_server: bool,
     #[serde(rename = "experimental_devSpacePro")]
     experimental_devspace_pro: bool,
     #[serde(rename = "experimental_colorMode")]
@@ -68,6 +68,9 @@ const IDE_ICONS: Record<string, string> = {
   fleet: FleetSvg,
   jupyternotebook: JupyterNotebookSvg,
   jupyternotebook_dark: JupyterNotebookDarkSvg,
+  jupyterlab: JupyterNotebookSvg,
+  jupyterlab_dark: JupyterNotebookDarkSvg,
+  codeserver: VSCodeBrowser,
   cursor: CursorSvg,
   positron: PositronSvg,
   codium: CodiumSvg,
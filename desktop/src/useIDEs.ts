@@ -8,6 +8,8 @@ import { TIDE, TIDEs } from "@/types"
 // See pkg/config/ide.go for names
 const FLEET_IDE_NAME = "fleet"
 const JUPYTER_IDE_NAME = "jupyternotebook"
+const JUPYTER_LAB_IDE_NAME = "jupyterlab"
+const CODE_SERVER = "codeserver"
 const VSCODE_INSIDERS = "vscode-insiders"
 const CURSOR = "cursor"
 const POSITRON = "positron"
@@ -30,6 +32,8 @@ export function useIDEs() {
 
         if (ide.name === FLEET_IDE_NAME && settings.experimental_fleet) return true
         if (ide.name === JUPYTER_IDE_NAME && settings.experimental_jupyterNotebooks) return true
+        if (ide.name === JUPYTER_LAB_IDE_NAME && settings.experimental_jupyterNotebooks) return true
+        if (ide.name === CODE_SERVER && settings.experimental_codeServer) return true
         if (ide.name === VSCODE_INSIDERS && settings.experimental_vscodeInsiders) return true
         if (ide.name === CURSOR && settings.experimental_cursor) return true
         if (ide.name === POSITRON && settings.experimental_positron) return true
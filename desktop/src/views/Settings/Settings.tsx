@@ -474,6 +474,16 @@ function ExperimentalSettings() {
           </FormLabel>
         </HStack>
 
+        <HStack width="full" align="center">
+          <Switch
+            isChecked={settings.experimental_codeServer}
+            onChange={(e) => set("experimental_codeServer", e.target.checked)}
+          />
+          <FormLabel marginBottom="0" whiteSpace="nowrap" fontSize="sm">
+            code-server
+          </FormLabel>
+        </HStack>
+
         <HStack width="full" align="center">
           <Switch
             isChecked={settings.experimental_windsurf}
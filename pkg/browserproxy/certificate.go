@@ -0,0 +1,191 @@
+package browserproxy
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	caCertFile = "ca.crt"
+	caKeyFile  = "ca.key"
+)
+
+// loadTLSConfig returns the tls config of the proxy. If no certificate is provided, certificates for the requested
+// hosts are issued by a local certificate authority that is created on first use and can be trusted by the user.
+func loadTLSConfig(dir, certFile, keyFile string) (*tls.Config, error) {
+	if certFile != "" || keyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load certificate: %w", err)
+		}
+
+		return &tls.Config{
+			Certificates: []tls.Certificate{certificate},
+			MinVersion:   tls.VersionTLS12,
+		}, nil
+	}
+
+	ca, err := loadOrCreateCA(dir)
+	if err != nil {
+		return nil, fmt.Errorf("load certificate authority: %w", err)
+	}
+
+	issuer := &issuer{
+		ca:           ca,
+		certificates: map[string]*tls.Certificate{},
+	}
+	return &tls.Config{
+		GetCertificate: issuer.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}, nil
+}
+
+type issuer struct {
+	ca *tls.Certificate
+
+	m            sync.Mutex
+	certificates map[string]*tls.Certificate
+}
+
+func (i *issuer) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	serverName := hello.ServerName
+	if serverName == "" {
+		serverName = Domain
+	}
+
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if certificate, ok := i.certificates[serverName]; ok {
+		return certificate, nil
+	}
+
+	certificate, err := issueCertificate(i.ca, serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	i.certificates[serverName] = certificate
+	return certificate, nil
+}
+
+func issueCertificate(ca *tls.Certificate, serverName string) (*tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	serialNumber, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: serverName},
+		DNSNames:     []string{serverName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, ca.Leaf, &key.PublicKey, ca.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Certificate{
+		Certificate: [][]byte{raw, ca.Leaf.Raw},
+		PrivateKey:  key,
+	}, nil
+}
+
+func loadOrCreateCA(dir string) (*tls.Certificate, error) {
+	certPath := filepath.Join(dir, caCertFile)
+	keyPath := filepath.Join(dir, caKeyFile)
+	ca, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err == nil {
+		ca.Leaf, err = x509.ParseCertificate(ca.Certificate[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return &ca, nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	serialNumber, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: "DevSpace Browser Proxy CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+		// the certificate authority can only issue certificates for devspace.localhost
+		PermittedDNSDomainsCritical: true,
+		PermittedDNSDomains:         []string{Domain},
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+
+	rawKey, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	err = os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: rawKey}), 0600)
+	if err != nil {
+		return nil, err
+	}
+	err = os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: raw}), 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	leaf, err := x509.ParseCertificate(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Certificate{
+		Certificate: [][]byte{raw},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}, nil
+}
+
+func newSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
@@ -0,0 +1,521 @@
+package browserproxy
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/config"
+	"github.com/skratchdot/open-golang/open"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// Domain is the domain workspaces are served under, browsers resolve all subdomains of localhost to loopback
+	Domain = "devspace.localhost"
+
+	// TokenParameter is the query parameter that holds the session token in the url opened by DevSpace
+	TokenParameter = "devspace-token"
+
+	tokenCookie = "devspace-token"
+	portHeader  = "X-Devspace-Port"
+
+	idleConnTimeout = time.Second * 30
+)
+
+// Proxy serves the browser IDE and the web ports of a workspace at https://<workspace>.devspace.localhost and
+// https://<port>.<workspace>.devspace.localhost. All devspace processes of a user share the same listener, the process
+// that started first serves the requests of all others and another process takes over once it stops. Requests are
+// passed to the workspace through a unix socket that is only accessible by the user, so no unauthenticated port is
+// exposed on the machine.
+type Proxy struct {
+	workspace string
+	idePort   int
+	address   string
+	certFile  string
+	keyFile   string
+	token     string
+	dir       string
+}
+
+// New creates a new proxy for the workspace if the browser proxy is enabled in the context options, otherwise it
+// returns nil
+func New(devSpaceConfig *config.Config, workspace string, idePort int) (*Proxy, error) {
+	if devSpaceConfig.ContextOption(config.ContextOptionBrowserProxy) != "true" {
+		return nil, nil
+	}
+
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Proxy{
+		workspace: workspace,
+		idePort:   idePort,
+		address:   devSpaceConfig.ContextOption(config.ContextOptionBrowserProxyAddress),
+		certFile:  devSpaceConfig.ContextOption(config.ContextOptionBrowserProxyCert),
+		keyFile:   devSpaceConfig.ContextOption(config.ContextOptionBrowserProxyKey),
+		token:     token,
+		dir:       filepath.Join(configDir, "browser-proxy"),
+	}, nil
+}
+
+// URL returns the url of the given path of the IDE including the session token
+func (p *Proxy) URL(path string) string {
+	return p.url(p.workspace, path, p.token)
+}
+
+// PortURL returns the url a port of the workspace is reachable at
+func (p *Proxy) PortURL(port int) string {
+	return p.url(strconv.Itoa(port)+"."+p.workspace, "/", "")
+}
+
+func (p *Proxy) url(subdomain, path, token string) string {
+	host := subdomain + "." + Domain
+	_, port, err := net.SplitHostPort(p.address)
+	if err == nil && port != "443" {
+		host = net.JoinHostPort(host, port)
+	}
+
+	target, err := url.Parse(path)
+	if err != nil {
+		target = &url.URL{}
+	}
+	target.Scheme = "https"
+	target.Host = host
+	if target.Path == "" {
+		target.Path = "/"
+	}
+	if token != "" {
+		query := target.Query()
+		query.Set(TokenParameter, token)
+		target.RawQuery = query.Encode()
+	}
+
+	return target.String()
+}
+
+// Serve serves the workspace through the proxy until the context is done. If exitAfterTimeout is set, the process
+// exits after the browser has been disconnected for the given duration.
+func (p *Proxy) Serve(ctx context.Context, sshClient *ssh.Client, exitAfterTimeout time.Duration, log log.Logger) error {
+	tlsConfig, err := loadTLSConfig(p.dir, p.certFile, p.keyFile)
+	if err != nil {
+		return err
+	}
+
+	// serve the workspace on a unix socket
+	routesDir := filepath.Join(p.dir, "routes")
+	err = os.MkdirAll(routesDir, 0700)
+	if err != nil {
+		return err
+	}
+
+	socketPath := filepath.Join(routesDir, shortHash(p.workspace)+".sock")
+	_ = os.Remove(socketPath)
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", socketPath, err)
+	}
+	defer os.Remove(socketPath)
+	err = os.Chmod(socketPath, 0600)
+	if err != nil {
+		_ = listener.Close()
+		return err
+	}
+
+	counter := newConnectionCounter(ctx, exitAfterTimeout, func() {
+		log.Fatal("Stopping devspace up, because it stayed idle for a while. You can disable this via 'devspace context set-options -o EXIT_AFTER_TIMEOUT=false'")
+	})
+	workspaceServer := &http.Server{Handler: p.workspaceHandler(sshClient, log)}
+	go func() {
+		err := workspaceServer.Serve(&countingListener{Listener: listener, counter: counter})
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Debugf("Error serving workspace %s: %v", p.workspace, err)
+		}
+	}()
+	defer workspaceServer.Close()
+
+	// register the workspace for the proxy
+	err = writeRoute(routesDir, p.workspace, &route{Token: p.token, Socket: socketPath})
+	if err != nil {
+		return err
+	}
+	defer removeRoute(routesDir, p.workspace, p.token)
+
+	// serve the proxy or wait until the process serving it stopped
+	handler := &proxyHandler{routesDir: routesDir, log: log}
+	for {
+		proxyListener, err := tls.Listen("tcp", p.address, tlsConfig)
+		if err == nil {
+			log.Debugf("Serving browser proxy on %s", p.address)
+			proxyServer := &http.Server{Handler: handler}
+			go func() {
+				<-ctx.Done()
+				_ = proxyServer.Close()
+			}()
+
+			err = proxyServer.Serve(proxyListener)
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Debugf("Error serving browser proxy: %v", err)
+			}
+		} else {
+			log.Debugf("Browser proxy is served by another process: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Second * 5):
+		}
+	}
+}
+
+// Open opens the url of the IDE in the browser once the IDE is reachable
+func (p *Proxy) Open(ctx context.Context, path string, log log.Logger) error {
+	routesDir := filepath.Join(p.dir, "routes")
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", filepath.Join(routesDir, shortHash(p.workspace)+".sock"))
+			},
+			DisableKeepAlives: true,
+		},
+		Timeout: time.Second,
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Second):
+			resp, err := client.Get("http://localhost" + path)
+			if err != nil {
+				continue
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode == http.StatusBadGateway || resp.StatusCode == http.StatusServiceUnavailable {
+				continue
+			}
+
+			_ = open.Start(p.URL(path))
+			log.Donef("Successfully opened %s", p.url(p.workspace, path, ""))
+			return nil
+		}
+	}
+}
+
+// workspaceHandler passes requests to the given port of the workspace or the IDE port
+func (p *Proxy) workspaceHandler(sshClient *ssh.Client, log log.Logger) http.Handler {
+	return &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			port := req.Header.Get(portHeader)
+			if port == "" {
+				port = strconv.Itoa(p.idePort)
+			}
+			req.Header.Del(portHeader)
+
+			req.URL.Scheme = "http"
+			req.URL.Host = "localhost:" + port
+		},
+		Transport: &http.Transport{
+			DialContext: func(_ context.Context, network, addr string) (net.Conn, error) {
+				return sshClient.Dial(network, addr)
+			},
+			IdleConnTimeout: idleConnTimeout,
+		},
+		ErrorHandler: func(w http.ResponseWriter, req *http.Request, err error) {
+			log.Debugf("Error proxying %s: %v", req.URL.String(), err)
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	}
+}
+
+type proxyHandler struct {
+	routesDir string
+	log       log.Logger
+
+	m          sync.Mutex
+	transports map[string]*http.Transport
+}
+
+// transport returns the transport to the unix socket of a workspace
+func (h *proxyHandler) transport(socketPath string) *http.Transport {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	if h.transports == nil {
+		h.transports = map[string]*http.Transport{}
+	}
+	if transport, ok := h.transports[socketPath]; ok {
+		return transport
+	}
+
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+		},
+		// close idle connections, so the workspace process notices when the browser is gone
+		IdleConnTimeout: idleConnTimeout,
+	}
+	h.transports[socketPath] = transport
+	return transport
+}
+
+func (h *proxyHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	workspace, port, ok := parseHost(req.Host)
+	if !ok {
+		http.Error(w, "unknown host "+req.Host, http.StatusNotFound)
+		return
+	}
+
+	route, err := readRoute(h.routesDir, workspace)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("workspace %s is not served, please run 'devspace up %s'", workspace, workspace), http.StatusBadGateway)
+		return
+	}
+
+	// exchange the token of the url opened by devspace for a cookie
+	query := req.URL.Query()
+	if token := query.Get(TokenParameter); token != "" {
+		if !tokenEquals(token, route.Token) {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     tokenCookie,
+			Value:    token,
+			Domain:   workspace + "." + Domain,
+			Path:     "/",
+			Secure:   true,
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+		query.Del(TokenParameter)
+		redirectURL := *req.URL
+		redirectURL.RawQuery = query.Encode()
+		http.Redirect(w, req, redirectURL.RequestURI(), http.StatusFound)
+		return
+	}
+
+	cookie, err := req.Cookie(tokenCookie)
+	if err != nil || !tokenEquals(cookie.Value, route.Token) {
+		http.Error(w, "missing or invalid token, please open the url printed by 'devspace up'", http.StatusUnauthorized)
+		return
+	}
+
+	proxy := &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			removeCookie(req, tokenCookie)
+			req.Header.Del(portHeader)
+			if port != "" {
+				req.Header.Set(portHeader, port)
+			}
+			req.Header.Set("X-Forwarded-Proto", "https")
+
+			req.URL.Scheme = "http"
+			req.URL.Host = "localhost"
+		},
+		Transport: h.transport(route.Socket),
+		ErrorHandler: func(w http.ResponseWriter, req *http.Request, err error) {
+			h.log.Debugf("Error proxying to workspace %s: %v", workspace, err)
+			http.Error(w, fmt.Sprintf("workspace %s is not reachable", workspace), http.StatusBadGateway)
+		},
+	}
+	proxy.ServeHTTP(w, req)
+}
+
+// parseHost returns the workspace and port of hosts in the form <workspace>.devspace.localhost or
+// <port>.<workspace>.devspace.localhost
+func parseHost(host string) (string, string, bool) {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+
+	subdomain, ok := strings.CutSuffix(strings.ToLower(host), "."+Domain)
+	if !ok || subdomain == "" {
+		return "", "", false
+	}
+
+	labels := strings.Split(subdomain, ".")
+	switch len(labels) {
+	case 1:
+		return labels[0], "", true
+	case 2:
+		port, err := strconv.Atoi(labels[0])
+		if err != nil || port <= 0 || port > 65535 {
+			return "", "", false
+		}
+
+		return labels[1], labels[0], true
+	}
+
+	return "", "", false
+}
+
+func removeCookie(req *http.Request, name string) {
+	cookies := req.Cookies()
+	req.Header.Del("Cookie")
+	for _, cookie := range cookies {
+		if cookie.Name != name {
+			req.AddCookie(cookie)
+		}
+	}
+}
+
+func tokenEquals(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+func newToken() (string, error) {
+	raw := make([]byte, 32)
+	_, err := rand.Read(raw)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(raw), nil
+}
+
+// shortHash keeps unix socket paths below the length limit of some operating systems
+func shortHash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+type route struct {
+	Token  string `json:"token"`
+	Socket string `json:"socket"`
+}
+
+func readRoute(routesDir, workspace string) (*route, error) {
+	raw, err := os.ReadFile(filepath.Join(routesDir, workspace+".json"))
+	if err != nil {
+		return nil, err
+	}
+
+	r := &route{}
+	err = json.Unmarshal(raw, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func writeRoute(routesDir, workspace string, r *route) error {
+	raw, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(routesDir, workspace+".json"), raw, 0600)
+}
+
+// removeRoute removes the route of the workspace unless another process took it over
+func removeRoute(routesDir, workspace, token string) {
+	r, err := readRoute(routesDir, workspace)
+	if err == nil && r.Token == token {
+		_ = os.Remove(filepath.Join(routesDir, workspace+".json"))
+	}
+}
+
+type countingListener struct {
+	net.Listener
+
+	counter *connectionCounter
+}
+
+func (l *countingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	l.counter.Add()
+	return &countingConn{Conn: conn, counter: l.counter}, nil
+}
+
+type countingConn struct {
+	net.Conn
+
+	once    sync.Once
+	counter *connectionCounter
+}
+
+func (c *countingConn) Close() error {
+	c.once.Do(c.counter.Dec)
+	return c.Conn.Close()
+}
+
+func newConnectionCounter(ctx context.Context, timeout time.Duration, onTimeout func()) *connectionCounter {
+	return &connectionCounter{
+		ctx:       ctx,
+		timeout:   timeout,
+		onTimeout: onTimeout,
+	}
+}
+
+type connectionCounter struct {
+	ctx       context.Context
+	timeout   time.Duration
+	onTimeout func()
+
+	m           sync.Mutex
+	connections int
+	generation  int
+}
+
+func (c *connectionCounter) Add() {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.connections++
+}
+
+func (c *connectionCounter) Dec() {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.connections--
+	if c.connections <= 0 && c.timeout > 0 {
+		c.generation++
+
+		go func(generation int) {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-time.After(c.timeout):
+				c.m.Lock()
+				defer c.m.Unlock()
+
+				if c.generation == generation && c.connections <= 0 {
+					c.onTimeout()
+				}
+			}
+		}(c.generation)
+	}
+}
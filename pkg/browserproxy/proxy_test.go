@@ -0,0 +1,117 @@
+package browserproxy
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"dev.khulnasoft.com/log"
+)
+
+func TestParseHost(t *testing.T) {
+	tests := []struct {
+		host          string
+		wantWorkspace string
+		wantPort      string
+		wantOK        bool
+	}{
+		{host: "my-workspace.devspace.localhost:8443", wantWorkspace: "my-workspace", wantOK: true},
+		{host: "my-workspace.devspace.localhost", wantWorkspace: "my-workspace", wantOK: true},
+		{host: "3000.my-workspace.devspace.localhost:8443", wantWorkspace: "my-workspace", wantPort: "3000", wantOK: true},
+		{host: "abc.my-workspace.devspace.localhost:8443"},
+		{host: "70000.my-workspace.devspace.localhost:8443"},
+		{host: "devspace.localhost:8443"},
+		{host: "localhost:8443"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			workspace, port, ok := parseHost(tt.host)
+			if workspace != tt.wantWorkspace || port != tt.wantPort || ok != tt.wantOK {
+				t.Errorf("parseHost() = %q, %q, %v, want %q, %q, %v", workspace, port, ok, tt.wantWorkspace, tt.wantPort, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestProxyHandler(t *testing.T) {
+	// unix socket paths are limited in length, so we don't use t.TempDir()
+	routesDir, err := os.MkdirTemp("", "bp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(routesDir)
+
+	socketPath := filepath.Join(routesDir, "ws.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	workspace := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if _, err := req.Cookie(tokenCookie); err == nil {
+			t.Errorf("token cookie was passed to the workspace")
+		}
+		_, _ = w.Write([]byte(req.Header.Get(portHeader)))
+	})}
+	go func() { _ = workspace.Serve(listener) }()
+	defer workspace.Close()
+
+	err = writeRoute(routesDir, "ws", &route{Token: "secret", Socket: socketPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := &proxyHandler{routesDir: routesDir, log: log.Discard}
+
+	tests := []struct {
+		name       string
+		host       string
+		path       string
+		cookie     string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "no token", host: "ws.devspace.localhost", path: "/", wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", host: "ws.devspace.localhost", path: "/?devspace-token=wrong", wantStatus: http.StatusUnauthorized},
+		{name: "token", host: "ws.devspace.localhost", path: "/?devspace-token=secret", wantStatus: http.StatusFound},
+		{name: "wrong cookie", host: "ws.devspace.localhost", path: "/", cookie: "wrong", wantStatus: http.StatusUnauthorized},
+		{name: "cookie", host: "ws.devspace.localhost", path: "/", cookie: "secret", wantStatus: http.StatusOK},
+		{name: "port", host: "3000.ws.devspace.localhost", path: "/", cookie: "secret", wantStatus: http.StatusOK, wantBody: "3000"},
+		{name: "unknown workspace", host: "other.devspace.localhost", path: "/", cookie: "secret", wantStatus: http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "https://"+tt.host+tt.path, nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: tokenCookie, Value: tt.cookie})
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("ServeHTTP() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK && recorder.Body.String() != tt.wantBody {
+				t.Errorf("ServeHTTP() body = %q, want %q", recorder.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestURL(t *testing.T) {
+	p := &Proxy{workspace: "ws", address: "127.0.0.1:8443", token: "secret"}
+	if got, want := p.URL("/?folder=/workspaces/ws"), "https://ws.devspace.localhost:8443/?devspace-token=secret&folder=%2Fworkspaces%2Fws"; got != want {
+		t.Errorf("URL() = %q, want %q", got, want)
+	}
+	if got, want := p.PortURL(3000), "https://3000.ws.devspace.localhost:8443/"; got != want {
+		t.Errorf("PortURL() = %q, want %q", got, want)
+	}
+
+	p.address = "127.0.0.1:443"
+	if got, want := p.URL("/lab"), "https://ws.devspace.localhost/lab?devspace-token=secret"; got != want {
+		t.Errorf("URL() = %q, want %q", got, want)
+	}
+}
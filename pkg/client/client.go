@@ -211,6 +211,9 @@ type WorkspaceStatus struct {
 	Context  string `json:"context,omitempty"`
 	Provider string `json:"provider,omitempty"`
 	State    string `json:"state,omitempty"`
+
+	// Updates are newer versions of the images and features the workspace was built from
+	Updates []*config.ImageUpdate `json:"updates,omitempty"`
 }
 
 type User struct {
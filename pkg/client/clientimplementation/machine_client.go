@@ -13,6 +13,7 @@ import (
 	"dev.khulnasoft.com/pkg/options"
 	"dev.khulnasoft.com/pkg/provider"
 	"dev.khulnasoft.com/pkg/types"
+	"dev.khulnasoft.com/pkg/usage"
 	"dev.khulnasoft.com/log"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -112,6 +113,8 @@ func (s *machineClient) Create(ctx context.Context, options client.CreateOptions
 	}
 
 	s.log.Donef("Successfully created machine '%s' with provider '%s'", s.machine.ID, s.config.Name)
+	usage.RecordMachine(s.devSpaceConfig, s.machine, usage.EventCreated, s.log)
+	usage.RecordMachine(s.devSpaceConfig, s.machine, usage.EventStarted, s.log)
 	return nil
 }
 
@@ -142,6 +145,7 @@ func (s *machineClient) Start(ctx context.Context, options client.StartOptions)
 		return err
 	}
 	s.log.Donef("Successfully started '%s'", s.machine.ID)
+	usage.RecordMachine(s.devSpaceConfig, s.machine, usage.EventStarted, s.log)
 
 	return nil
 }
@@ -173,6 +177,7 @@ func (s *machineClient) Stop(ctx context.Context, options client.StopOptions) er
 		return err
 	}
 	s.log.Donef("Successfully stopped '%s'", s.machine.ID)
+	usage.RecordMachine(s.devSpaceConfig, s.machine, usage.EventStopped, s.log)
 
 	return nil
 }
@@ -225,6 +230,11 @@ func (s *machineClient) Status(ctx context.Context, options client.StatusOptions
 		return client.StatusNotFound, err
 	}
 
+	// the machine might have stopped itself
+	if parsedStatus == client.StatusStopped || parsedStatus == client.StatusNotFound {
+		usage.RecordMachineNotRunning(s.devSpaceConfig, s.machine, s.log)
+	}
+
 	return parsedStatus, nil
 }
 
@@ -274,6 +284,7 @@ func (s *machineClient) Delete(ctx context.Context, options client.DeleteOptions
 		s.log.Errorf("Error deleting machine '%s': %v", s.machine.ID, err)
 	}
 	s.log.Donef("Successfully deleted machine '%s'", s.machine.ID)
+	usage.RecordMachine(s.devSpaceConfig, s.machine, usage.EventDeleted, s.log)
 
 	// delete machine folder
 	err = DeleteMachineFolder(s.machine.Context, s.machine.ID)
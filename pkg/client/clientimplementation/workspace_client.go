@@ -24,6 +24,7 @@ import (
 	"dev.khulnasoft.com/pkg/shell"
 	"dev.khulnasoft.com/pkg/ssh"
 	"dev.khulnasoft.com/pkg/types"
+	"dev.khulnasoft.com/pkg/usage"
 	"dev.khulnasoft.com/log"
 	perrors "github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -401,6 +402,7 @@ func (s *workspaceClient) Delete(ctx context.Context, opt client.DeleteOptions)
 		}
 	}
 
+	usage.RecordWorkspace(s.devSpaceConfig, s.workspace, usage.EventDeleted, s.log)
 	return DeleteWorkspaceFolder(s.workspace.Context, s.workspace.ID, s.workspace.SSHConfigPath, s.log)
 }
 
@@ -477,6 +479,7 @@ func (s *workspaceClient) Stop(ctx context.Context, opt client.StopOptions) erro
 			return err
 		}
 		s.log.Infof("Successfully stopped container...")
+		usage.RecordWorkspace(s.devSpaceConfig, s.workspace, usage.EventStopped, s.log)
 
 		return nil
 	}
@@ -486,7 +489,13 @@ func (s *workspaceClient) Stop(ctx context.Context, opt client.StopOptions) erro
 		return err
 	}
 
-	return machineClient.Stop(ctx, opt)
+	err = machineClient.Stop(ctx, opt)
+	if err != nil {
+		return err
+	}
+
+	usage.RecordWorkspace(s.devSpaceConfig, s.workspace, usage.EventStopped, s.log)
+	return nil
 }
 
 func (s *workspaceClient) Command(ctx context.Context, commandOptions client.CommandOptions) (err error) {
@@ -508,6 +517,16 @@ func (s *workspaceClient) Status(ctx context.Context, options client.StatusOptio
 	s.m.Lock()
 	defer s.m.Unlock()
 
+	status, err := s.status(ctx, options)
+	if err == nil && (status == client.StatusStopped || status == client.StatusNotFound) {
+		// the workspace might have been stopped on the machine
+		usage.RecordWorkspaceNotRunning(s.devSpaceConfig, s.workspace, s.log)
+	}
+
+	return status, err
+}
+
+func (s *workspaceClient) status(ctx context.Context, options client.StatusOptions) (client.Status, error) {
 	// check if provider has status command
 	if s.isMachineProvider() && len(s.config.Exec.Status) > 0 {
 		if s.machine == nil {
@@ -79,6 +79,10 @@ type ProviderConfig struct {
 	// DynamicOptions are the unresolved dynamic provider options
 	DynamicOptions OptionDefinitions `json:"dynamicOptions,omitempty"`
 
+	// PublisherKey is the minisign public key the provider manifest was signed with.
+	// It is pinned on first use and every update needs to be signed by the same key.
+	PublisherKey string `json:"publisherKey,omitempty"`
+
 	// CreationTimestamp is the timestamp when this provider was added
 	CreationTimestamp types.Time `json:"creationTimestamp,omitempty"`
 }
@@ -22,6 +22,23 @@ const (
 	ContextOptionAgentInjectTimeout         = "AGENT_INJECT_TIMEOUT"
 	ContextOptionRegistryCache              = "REGISTRY_CACHE"
 	ContextOptionSSHStrictHostKeyChecking   = "SSH_STRICT_HOST_KEY_CHECKING"
+	ContextOptionProviderSignatureMode      = "PROVIDER_SIGNATURE_MODE"
+	ContextOptionProviderTrustedKeys        = "PROVIDER_TRUSTED_KEYS"
+	ContextOptionProviderCatalogs           = "PROVIDER_CATALOGS"
+	ContextOptionBrowserProxy               = "BROWSER_PROXY"
+	ContextOptionBrowserProxyAddress        = "BROWSER_PROXY_ADDRESS"
+	ContextOptionBrowserProxyCert           = "BROWSER_PROXY_CERT"
+	ContextOptionBrowserProxyKey            = "BROWSER_PROXY_KEY"
+	ContextOptionSyncIDESettings            = "SYNC_IDE_SETTINGS"
+	ContextOptionSSHWildcardConfig          = "SSH_WILDCARD_CONFIG"
+	ContextOptionSSHMultiplexing            = "SSH_MULTIPLEXING"
+	ContextOptionSSHMultiplexingIdleTimeout = "SSH_MULTIPLEXING_IDLE_TIMEOUT"
+)
+
+const (
+	ProviderSignatureModeOff      = "off"
+	ProviderSignatureModeOptional = "optional"
+	ProviderSignatureModeRequired = "required"
 )
 
 var ContextOptions = []ContextOption{
@@ -105,6 +122,62 @@ var ContextOptions = []ContextOption{
 		Default:     "false",
 		Enum:        []string{"true", "false"},
 	},
+	{
+		Name:        ContextOptionProviderSignatureMode,
+		Description: "Specifies if provider manifests need a valid minisign signature. Optional verifies signatures if they exist",
+		Default:     ProviderSignatureModeOptional,
+		Enum:        []string{ProviderSignatureModeOff, ProviderSignatureModeOptional, ProviderSignatureModeRequired},
+	},
+	{
+		Name:        ContextOptionProviderTrustedKeys,
+		Description: "Specifies a comma separated list of minisign public keys that are trusted to sign provider manifests",
+	},
+	{
+		Name:        ContextOptionProviderCatalogs,
+		Description: "Specifies a comma separated list of provider catalog urls or paths to search providers in",
+	},
+	{
+		Name:        ContextOptionBrowserProxy,
+		Description: "Specifies if DevSpace should serve browser IDEs and web ports over HTTPS with a session token at https://WORKSPACE.devspace.localhost instead of forwarding plain local ports",
+		Default:     "false",
+		Enum:        []string{"true", "false"},
+	},
+	{
+		Name:        ContextOptionBrowserProxyAddress,
+		Description: "Specifies the address the browser proxy listens on",
+		Default:     "127.0.0.1:8443",
+	},
+	{
+		Name:        ContextOptionBrowserProxyCert,
+		Description: "Specifies the path to a certificate for *.devspace.localhost the browser proxy should use. If empty, DevSpace issues certificates with a local certificate authority",
+	},
+	{
+		Name:        ContextOptionBrowserProxyKey,
+		Description: "Specifies the path to the private key of the browser proxy certificate",
+	},
+	{
+		Name:        ContextOptionSyncIDESettings,
+		Description: "Specifies if DevSpace should copy the local settings, keybindings and snippets of the IDE into the workspace on every up",
+		Default:     "false",
+		Enum:        []string{"true", "false"},
+	},
+	{
+		Name:        ContextOptionSSHWildcardConfig,
+		Description: "Specifies if DevSpace should add a single Host *.devspace entry to the ssh config instead of an entry per workspace",
+		Default:     "false",
+		Enum:        []string{"true", "false"},
+	},
+	{
+		Name:        ContextOptionSSHMultiplexing,
+		Description: "Specifies if devspace ssh should keep a connection to the workspace open in the background that new sessions attach to",
+		Default:     "true",
+		Enum:        []string{"true", "false"},
+	},
+	{
+		Name:        ContextOptionSSHMultiplexingIdleTimeout,
+		Description: "Specifies the seconds after which the background connection of a workspace is closed if no session is attached",
+		Default:     "600",
+	},
 }
 
 func MergeContextOptions(contextConfig *ContextConfig, environ []string) {
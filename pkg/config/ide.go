@@ -19,12 +19,15 @@ const (
 	IDEDataSpell       IDE = "dataspell"
 	IDEFleet           IDE = "fleet"
 	IDEJupyterNotebook IDE = "jupyternotebook"
+	IDEJupyterLab      IDE = "jupyterlab"
+	IDECodeServer      IDE = "codeserver"
 	IDECursor          IDE = "cursor"
 	IDEPositron        IDE = "positron"
 	IDECodium          IDE = "codium"
 	IDEZed             IDE = "zed"
 	IDERStudio         IDE = "rstudio"
 	IDEWindsurf        IDE = "windsurf"
+	IDETerminal        IDE = "terminal"
 )
 
 type IDEGroup string
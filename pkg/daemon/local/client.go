@@ -0,0 +1,152 @@
+package local
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"time"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/single"
+	devssh "dev.khulnasoft.com/pkg/ssh"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// handshakeTimeout is how long Dial waits for the container, before the caller falls back to a new connection
+	handshakeTimeout = 5 * time.Second
+
+	// startTimeout is how long Connect waits for a daemon that was just started to listen
+	startTimeout = 5 * time.Second
+)
+
+// Dial attaches a session to the connection the daemon of the context holds for the workspace and returns a client
+// for the container. It fails fast if no daemon is running or it isn't connected to the workspace, so the caller can
+// connect to the workspace itself.
+func Dial(context, workspaceID string) (*ssh.Client, error) {
+	conn, err := sendRequest(context, request{Workspace: workspaceID}, waitForConnectionTimeout+handshakeTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	clientConfig, err := devssh.ConfigFromKeyBytes(nil)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(handshakeTimeout))
+	c, chans, reqs, err := ssh.NewClientConn(conn, "daemon", clientConfig)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("connect to workspace %s through the daemon: %w", workspaceID, err)
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
+// Connect makes the daemon of the context connect to the workspace, so the next sessions can attach to the
+// connection. The daemon is started if it isn't running yet.
+func Connect(context, workspaceID string, log log.Logger) error {
+	StartInBackground(context, log)
+
+	var err error
+	deadline := time.Now().Add(startTimeout)
+	for time.Now().Before(deadline) {
+		var conn net.Conn
+		conn, err = sendRequest(context, request{Workspace: workspaceID, Connect: true}, handshakeTimeout)
+		if err == nil {
+			_ = conn.Close()
+			return nil
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return err
+}
+
+// StartInBackground starts a detached daemon for the context, unless one is running already
+func StartInBackground(context string, log log.Logger) {
+	err := single.Single("devspace-daemon-"+socketName(context)+".pid", func() (*exec.Cmd, error) {
+		executable, err := os.Executable()
+		if err != nil {
+			return nil, err
+		}
+
+		cmd := exec.Command(executable, "daemon", "--context", context)
+		cmd.Env = os.Environ()
+		detach(cmd)
+		return cmd, nil
+	})
+	if err != nil {
+		log.Debugf("Error starting the daemon for context %s: %v", context, err)
+	}
+}
+
+// sendRequest sends the request to the daemon of the context and returns the connection if the daemon accepted it
+func sendRequest(context string, req request, timeout time.Duration) (net.Conn, error) {
+	addr, err := GetSocketAddr(context)
+	if err != nil {
+		return nil, err
+	}
+
+	rawConn, err := dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	conn := newBufferedConn(rawConn)
+
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	out, err := json.Marshal(req)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	_, err = conn.Write(append(out, '\n'))
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+
+	line, err := conn.br.ReadBytes('\n')
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	resp := &response{}
+	err = json.Unmarshal(line, resp)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("parse response: %w", err)
+	} else if resp.Error != "" {
+		_ = conn.Close()
+		return nil, errors.New(resp.Error)
+	}
+
+	return conn, nil
+}
+
+// bufferedConn reads from the buffered reader the response was read with, it may hold the start of the session
+type bufferedConn struct {
+	net.Conn
+	br *bufio.Reader
+}
+
+func newBufferedConn(conn net.Conn) *bufferedConn {
+	return &bufferedConn{
+		Conn: conn,
+		br:   bufio.NewReader(conn),
+	}
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.br.Read(b)
+}
@@ -0,0 +1,265 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"dev.khulnasoft.com/log"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// waitForConnectionTimeout is how long a new session waits for the daemon to reconnect
+	waitForConnectionTimeout = 10 * time.Second
+
+	keepAliveInterval = 15 * time.Second
+	keepAliveTimeout  = 10 * time.Second
+
+	maxReconnectAttempts = 5
+)
+
+// errIdle is returned by connection.run if no session was attached for the idle timeout
+var errIdle = errors.New("no sessions attached")
+
+// ConnectFunc connects to the container of the workspace and calls handler with the connected client. It returns
+// once the handler returned.
+type ConnectFunc func(ctx context.Context, workspaceID string, handler func(ctx context.Context, containerClient *ssh.Client) error) error
+
+// connection keeps a connection to the container of a workspace open. Sessions attach to it as channels, so they
+// don't need to resolve the workspace and connect to it again.
+type connection struct {
+	workspaceID string
+	idleTimeout time.Duration
+	log         log.Logger
+
+	m            sync.Mutex
+	client       *ssh.Client
+	connected    chan struct{}
+	sessions     int
+	lastActivity time.Time
+}
+
+func newConnection(workspaceID string, idleTimeout time.Duration, log log.Logger) *connection {
+	return &connection{
+		workspaceID:  workspaceID,
+		idleTimeout:  idleTimeout,
+		log:          log,
+		connected:    make(chan struct{}),
+		lastActivity: time.Now(),
+	}
+}
+
+// run connects to the container until no session was attached for the idle timeout or the container can't be
+// reached anymore. Lost connections, for example after a network change, are reconnected.
+func (c *connection) run(ctx context.Context, connect ConnectFunc) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	idle := make(chan struct{})
+	go func() {
+		c.waitForIdle(ctx)
+		close(idle)
+		cancel()
+	}()
+
+	failures := 0
+	for {
+		connectedAt := time.Time{}
+		err := connect(ctx, c.workspaceID, func(ctx context.Context, containerClient *ssh.Client) error {
+			connectedAt = time.Now()
+			c.log.Infof("Connected to workspace %s", c.workspaceID)
+			c.setClient(containerClient)
+			defer c.setClient(nil)
+
+			return keepAlive(ctx, containerClient)
+		})
+
+		select {
+		case <-idle:
+			return errIdle
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if !connectedAt.IsZero() {
+			failures = 0
+			c.log.Infof("Lost connection to workspace %s, reconnecting", c.workspaceID)
+		} else {
+			failures++
+			if failures >= maxReconnectAttempts {
+				return fmt.Errorf("connect to workspace %s: %w", c.workspaceID, err)
+			}
+			c.log.Infof("Error connecting to workspace %s (%d/%d): %v", c.workspaceID, failures, maxReconnectAttempts, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(failures) * time.Second):
+		}
+	}
+}
+
+// newSession opens a session on the connection to the container. It waits for the daemon to reconnect if the
+// connection was lost.
+func (c *connection) newSession(ctx context.Context) (*ssh.Session, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		containerClient := c.waitForClient(ctx)
+		if containerClient == nil {
+			break
+		}
+
+		session, err := containerClient.NewSession()
+		if err != nil {
+			// the connection broke before the keepalive noticed, wait for the next one
+			c.log.Debugf("Error opening session on workspace %s: %v", c.workspaceID, err)
+			c.dropClient(containerClient)
+			continue
+		}
+
+		return session, nil
+	}
+
+	return nil, fmt.Errorf("not connected to workspace %s", c.workspaceID)
+}
+
+func runSession(ctx context.Context, session *ssh.Session, command string, stdin io.Reader, stdout, stderr io.Writer) error {
+	defer session.Close()
+
+	exit := make(chan struct{})
+	defer close(exit)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = session.Close()
+		case <-exit:
+		}
+	}()
+
+	session.Stdin = stdin
+	session.Stdout = stdout
+	session.Stderr = stderr
+	return session.Run(command)
+}
+
+// waitForClient returns the current connection to the container or waits for the daemon to reconnect
+func (c *connection) waitForClient(ctx context.Context) *ssh.Client {
+	timeout := time.After(waitForConnectionTimeout)
+	for {
+		c.m.Lock()
+		client, connected := c.client, c.connected
+		c.m.Unlock()
+		if client != nil {
+			return client
+		}
+
+		select {
+		case <-connected:
+		case <-timeout:
+			return nil
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// setClient replaces the connection to the container. The connected channel is closed as long as there is one.
+func (c *connection) setClient(client *ssh.Client) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.client == nil && client != nil {
+		close(c.connected)
+	} else if c.client != nil && client == nil {
+		c.connected = make(chan struct{})
+	}
+	c.client = client
+}
+
+// dropClient closes the connection to the container, so keepAlive returns and run reconnects
+func (c *connection) dropClient(client *ssh.Client) {
+	c.m.Lock()
+	if c.client == client {
+		c.client = nil
+		c.connected = make(chan struct{})
+	}
+	c.m.Unlock()
+
+	_ = client.Close()
+}
+
+// touch counts as activity, so the connection isn't closed for the idle timeout
+func (c *connection) touch() {
+	c.trackSession(0)
+}
+
+func (c *connection) trackSession(delta int) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.sessions += delta
+	c.lastActivity = time.Now()
+}
+
+func (c *connection) waitForIdle(ctx context.Context) {
+	ticker := time.NewTicker(time.Second * 5)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.m.Lock()
+			idle := c.sessions == 0 && time.Since(c.lastActivity) > c.idleTimeout
+			c.m.Unlock()
+			if idle {
+				c.log.Infof("No sessions attached to workspace %s for %s, disconnecting", c.workspaceID, c.idleTimeout)
+				return
+			}
+		}
+	}
+}
+
+// keepAlive returns once the connection to the container is closed or doesn't answer anymore. Requests can block
+// forever on a dead connection after a network change, so they time out.
+func keepAlive(ctx context.Context, client *ssh.Client) error {
+	closed := make(chan error, 1)
+	go func() {
+		closed <- client.Wait()
+	}()
+
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-closed:
+			return err
+		case <-ticker.C:
+			answered := make(chan error, 1)
+			go func() {
+				_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+				answered <- err
+			}()
+
+			select {
+			case err := <-answered:
+				if err != nil {
+					_ = client.Close()
+					return fmt.Errorf("send keepalive: %w", err)
+				}
+			case <-time.After(keepAliveTimeout):
+				_ = client.Close()
+				return fmt.Errorf("keepalive timed out")
+			}
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package local
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"dev.khulnasoft.com/log"
+	"github.com/sirupsen/logrus"
+)
+
+// Daemon keeps connections to the containers of the workspaces of a DevSpace context open. For every session that
+// attaches to a workspace it runs the ssh server command on the connection of the workspace, so devspace ssh doesn't
+// need to resolve the workspace, check its status and open a tunnel again.
+type Daemon struct {
+	listener    net.Listener
+	command     string
+	idleTimeout time.Duration
+	connect     ConnectFunc
+	log         log.Logger
+
+	checkUpdates        func(ctx context.Context, workspaceID string) error
+	updateCheckInterval time.Duration
+
+	m           sync.Mutex
+	connections map[string]*connection
+	lastActive  time.Time
+}
+
+// InitConfig configures the daemon of a DevSpace context
+type InitConfig struct {
+	// Context is the DevSpace context the daemon connects to workspaces of
+	Context string
+
+	// Command is the ssh server command that is run in the container for every session
+	Command string
+
+	// IdleTimeout is how long a workspace stays connected without sessions. The daemon stops once no workspace was
+	// connected for the idle timeout.
+	IdleTimeout time.Duration
+
+	// Connect connects to the container of a workspace
+	Connect ConnectFunc
+
+	// CheckUpdates checks a workspace for newer images and features. It's called for every connected workspace
+	// each UpdateCheckInterval, so the status of the workspace can report them without querying the registries.
+	CheckUpdates        func(ctx context.Context, workspaceID string) error
+	UpdateCheckInterval time.Duration
+}
+
+// request is sent by clients as a single json line before the session starts
+type request struct {
+	Workspace string `json:"workspace"`
+
+	// Connect asks the daemon to connect to the workspace without starting a session
+	Connect bool `json:"connect,omitempty"`
+}
+
+// response answers a request as a single json line. If there is no error, the session starts right after.
+type response struct {
+	Error string `json:"error,omitempty"`
+}
+
+// GetSocketAddr returns the address of the socket the daemon of the context listens on
+func GetSocketAddr(context string) (string, error) {
+	return getSocketAddr(socketName(context))
+}
+
+// socketName keeps the socket path short, unix socket paths are limited to about 100 characters
+func socketName(context string) string {
+	hash := sha256.Sum256([]byte(context))
+	return hex.EncodeToString(hash[:])[:16]
+}
+
+// Init listens on the socket of the context. It fails if another daemon listens on it already.
+func Init(config InitConfig, log log.Logger) (*Daemon, error) {
+	addr, err := GetSocketAddr(config.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := listen(addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen on %s: %w", addr, err)
+	}
+
+	return &Daemon{
+		listener:    listener,
+		command:     config.Command,
+		idleTimeout: config.IdleTimeout,
+		connect:     config.Connect,
+		log:         log,
+		connections: map[string]*connection{},
+		lastActive:  time.Now(),
+
+		checkUpdates:        config.CheckUpdates,
+		updateCheckInterval: config.UpdateCheckInterval,
+	}, nil
+}
+
+// Start serves requests until no workspace was connected for the idle timeout or the context is canceled
+func (d *Daemon) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer d.listener.Close()
+
+	go d.accept(ctx)
+	if d.checkUpdates != nil && d.updateCheckInterval > 0 {
+		go d.checkForUpdates(ctx)
+	}
+
+	ticker := time.NewTicker(time.Second * 5)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			d.m.Lock()
+			idle := len(d.connections) == 0 && time.Since(d.lastActive) > d.idleTimeout
+			d.m.Unlock()
+			if idle {
+				d.log.Infof("No workspace connected for %s, stopping", d.idleTimeout)
+				return nil
+			}
+		}
+	}
+}
+
+func (d *Daemon) accept(ctx context.Context) {
+	for {
+		conn, err := d.listener.Accept()
+		if err != nil {
+			if ctx.Err() == nil {
+				d.log.Debugf("Error accepting connection: %v", err)
+			}
+			return
+		}
+
+		go d.handle(ctx, conn)
+	}
+}
+
+// checkForUpdates periodically checks the connected workspaces for image updates
+func (d *Daemon) checkForUpdates(ctx context.Context) {
+	ticker := time.NewTicker(d.updateCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.m.Lock()
+			workspaceIDs := slices.Sorted(maps.Keys(d.connections))
+			d.m.Unlock()
+
+			for _, workspaceID := range workspaceIDs {
+				err := d.checkUpdates(ctx, workspaceID)
+				if err != nil && ctx.Err() == nil {
+					d.log.Debugf("Error checking workspace %s for image updates: %v", workspaceID, err)
+				}
+			}
+		}
+	}
+}
+
+func (d *Daemon) handle(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		d.log.Debugf("Error reading request: %v", err)
+		return
+	}
+
+	req := &request{}
+	err = json.Unmarshal(line, req)
+	if err != nil || req.Workspace == "" {
+		writeResponse(conn, fmt.Errorf("invalid request"))
+		return
+	}
+
+	// connect to the workspace in the background
+	if req.Connect {
+		d.ensureConnection(ctx, req.Workspace).touch()
+		writeResponse(conn, nil)
+		return
+	}
+
+	d.m.Lock()
+	workspaceConnection := d.connections[req.Workspace]
+	d.m.Unlock()
+	if workspaceConnection == nil {
+		writeResponse(conn, fmt.Errorf("not connected to workspace %s", req.Workspace))
+		return
+	}
+
+	workspaceConnection.trackSession(1)
+	defer workspaceConnection.trackSession(-1)
+
+	session, err := workspaceConnection.newSession(ctx)
+	if err != nil {
+		writeResponse(conn, err)
+		return
+	}
+	writeResponse(conn, nil)
+
+	writer := d.log.Writer(logrus.DebugLevel, false)
+	defer writer.Close()
+
+	// the client only starts the ssh handshake after the response, so the reader holds no buffered session data
+	err = runSession(ctx, session, d.command, reader, conn, writer)
+	if err != nil {
+		d.log.Debugf("Session of workspace %s closed: %v", req.Workspace, err)
+	}
+}
+
+// ensureConnection returns the connection of the workspace and starts connecting if there is none
+func (d *Daemon) ensureConnection(ctx context.Context, workspaceID string) *connection {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	workspaceConnection := d.connections[workspaceID]
+	if workspaceConnection != nil {
+		return workspaceConnection
+	}
+
+	workspaceConnection = newConnection(workspaceID, d.idleTimeout, d.log)
+	d.connections[workspaceID] = workspaceConnection
+	go func() {
+		err := workspaceConnection.run(ctx, d.connect)
+		if err != nil && !errors.Is(err, errIdle) && !errors.Is(err, context.Canceled) {
+			d.log.Infof("Disconnected from workspace %s: %v", workspaceID, err)
+		}
+
+		d.m.Lock()
+		delete(d.connections, workspaceID)
+		d.lastActive = time.Now()
+		d.m.Unlock()
+	}()
+
+	return workspaceConnection
+}
+
+func writeResponse(conn net.Conn, err error) {
+	resp := response{}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+
+	out, _ := json.Marshal(resp)
+	_, _ = conn.Write(append(out, '\n'))
+}
@@ -0,0 +1,191 @@
+package local
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/stdio"
+	gliderssh "dev.khulnasoft.com/ssh"
+	"golang.org/x/crypto/ssh"
+)
+
+// startContainer starts an ssh server that serves an inner ssh server on every session, like the helper ssh-server
+// does in the container
+func startContainer(t *testing.T) string {
+	inner := &gliderssh.Server{
+		Handler: func(s gliderssh.Session) {
+			_, _ = io.WriteString(s, "hello from "+s.RawCommand())
+			_ = s.Exit(0)
+		},
+		ChannelHandlers: map[string]gliderssh.ChannelHandler{
+			"session": gliderssh.DefaultSessionHandler,
+		},
+	}
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(hostKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inner.AddHostKey(signer)
+
+	outer := &gliderssh.Server{
+		Handler: func(s gliderssh.Session) {
+			inner.HandleConn(stdio.NewStdioStream(s, s, false, 0))
+		},
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = outer.Close()
+	})
+	go func() {
+		_ = outer.Serve(listener)
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestDaemon(t *testing.T) {
+	t.Setenv("DEVSPACE_HOME", t.TempDir())
+	addr := startContainer(t)
+
+	connections := make(chan *ssh.Client, 2)
+	d, err := Init(InitConfig{
+		Context:     "default",
+		Command:     "ssh-server",
+		IdleTimeout: time.Minute,
+		Connect: func(ctx context.Context, workspaceID string, handler func(ctx context.Context, containerClient *ssh.Client) error) error {
+			if workspaceID != "test" {
+				return fmt.Errorf("workspace %s not found", workspaceID)
+			}
+
+			containerClient, err := ssh.Dial("tcp", addr, &ssh.ClientConfig{HostKeyCallback: ssh.InsecureIgnoreHostKey()})
+			if err != nil {
+				return err
+			}
+			defer containerClient.Close()
+
+			connections <- containerClient
+			return handler(ctx, containerClient)
+		},
+	}, log.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Init(InitConfig{Context: "default", Command: "ssh-server", IdleTimeout: time.Minute}, log.Discard)
+	if err == nil {
+		t.Fatal("expected a second daemon for the context to fail")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = d.Start(ctx)
+	}()
+
+	_, err = Dial("default", "test")
+	if err == nil {
+		t.Fatal("expected dial to a workspace the daemon isn't connected to to fail")
+	}
+
+	conn, err := sendRequest("default", request{Workspace: "test", Connect: true}, handshakeTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.Close()
+
+	runSession := func() {
+		t.Helper()
+
+		client, err := Dial("default", "test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer client.Close()
+
+		session, err := client.NewSession()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer session.Close()
+
+		out, err := session.Output("echo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != "hello from echo" {
+			t.Fatalf("unexpected output %q", string(out))
+		}
+	}
+
+	// the first session waits until the daemon is connected
+	runSession()
+	runSession()
+
+	// the next session waits until the lost connection is replaced
+	_ = (<-connections).Close()
+	runSession()
+
+	_, err = Dial("default", "other")
+	if err == nil {
+		t.Fatal("expected dial to another workspace to fail")
+	}
+}
+
+func TestDaemonCheckUpdates(t *testing.T) {
+	t.Setenv("DEVSPACE_HOME", t.TempDir())
+
+	checked := make(chan string, 10)
+	d, err := Init(InitConfig{
+		Context:     "default",
+		Command:     "ssh-server",
+		IdleTimeout: time.Minute,
+		Connect: func(ctx context.Context, workspaceID string, handler func(ctx context.Context, containerClient *ssh.Client) error) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+		CheckUpdates: func(ctx context.Context, workspaceID string) error {
+			checked <- workspaceID
+			return nil
+		},
+		UpdateCheckInterval: 50 * time.Millisecond,
+	}, log.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = d.Start(ctx)
+	}()
+
+	conn, err := sendRequest("default", request{Workspace: "test", Connect: true}, handshakeTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.Close()
+
+	select {
+	case workspaceID := <-checked:
+		if workspaceID != "test" {
+			t.Fatalf("expected workspace test to be checked, got %s", workspaceID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the connected workspace to be checked for updates")
+	}
+}
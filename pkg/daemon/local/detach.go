@@ -0,0 +1,14 @@
+//go:build !windows
+
+package local
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detach starts the process in its own session, so it keeps running when the terminal sends signals to the
+// devspace ssh that started it
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
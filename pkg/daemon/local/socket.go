@@ -0,0 +1,48 @@
+//go:build !windows
+
+package local
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"dev.khulnasoft.com/pkg/config"
+)
+
+func getSocketAddr(name string) (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "daemon", name+".sock"), nil
+}
+
+func dial(addr string) (net.Conn, error) {
+	return net.DialTimeout("unix", addr, 2*time.Second)
+}
+
+func listen(addr string) (net.Listener, error) {
+	conn, err := net.Dial("unix", addr)
+	if err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("%s: address already in use", addr)
+	}
+	_ = os.Remove(addr)
+
+	// everyone who can connect to the socket gets a shell in the workspace
+	err = os.MkdirAll(filepath.Dir(addr), 0o700)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("unix", addr)
+	if err != nil {
+		return nil, err
+	}
+	_ = os.Chmod(addr, 0o600)
+	return listener, nil
+}
@@ -0,0 +1,23 @@
+//go:build windows
+
+package local
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"gopkg.in/natefinch/npipe.v2"
+)
+
+func getSocketAddr(name string) (string, error) {
+	return fmt.Sprintf("\\\\.\\pipe\\devspace.daemon.%s", name), nil
+}
+
+func dial(addr string) (net.Conn, error) {
+	return npipe.DialTimeout(addr, 2*time.Second)
+}
+
+func listen(addr string) (net.Listener, error) {
+	return npipe.Listen(addr)
+}
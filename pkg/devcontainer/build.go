@@ -2,6 +2,7 @@ package devcontainer
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path"
@@ -18,6 +19,7 @@ import (
 	"dev.khulnasoft.com/pkg/driver"
 	"dev.khulnasoft.com/pkg/image"
 	"dev.khulnasoft.com/pkg/provider"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/pkg/errors"
 )
 
@@ -43,6 +45,13 @@ func (r *runner) extendImage(
 	options provider.BuildOptions,
 ) (*config.BuildInfo, error) {
 	imageBase := parsedConfig.Config.Image
+	if options.Rebase {
+		err := r.pullImage(ctx, imageBase)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	imageBuildInfo, err := r.getImageBuildInfoFromImage(ctx, substitutionContext, imageBase)
 	if err != nil {
 		return nil, errors.Wrap(err, "get image build info")
@@ -62,6 +71,7 @@ func (r *runner) extendImage(
 			ImageName:     imageBase,
 			RegistryCache: options.RegistryCache,
 			Tags:          options.Tag,
+			Sources:       r.getImageSources(ctx, imageBuildInfo, nil, options),
 		}, nil
 	}
 
@@ -106,6 +116,19 @@ func (r *runner) buildAndExtendImage(
 		return nil, errors.Wrap(err, "get image build info")
 	}
 
+	// pull the latest base image, so the image metadata is up to date as well
+	if options.Rebase {
+		err = r.pullImage(ctx, imageBuildInfo.BaseImage)
+		if err != nil {
+			return nil, err
+		}
+
+		imageBuildInfo, err = r.getImageBuildInfoFromDockerfile(substitutionContext, string(dockerFileContent), parsedConfig.Config.GetArgs(), parsedConfig.Config.GetTarget())
+		if err != nil {
+			return nil, errors.Wrap(err, "get image build info")
+		}
+	}
+
 	// get extend image build info
 	extendedBuildInfo, err := feature.GetExtendedBuildInfo(substitutionContext, imageBuildInfo, imageBase, parsedConfig, r.Log, options.ForceBuild)
 	if err != nil {
@@ -152,6 +175,7 @@ func (r *runner) getImageBuildInfoFromImage(ctx context.Context, substitutionCon
 		ImageDetails: imageDetails,
 		User:         user,
 		Metadata:     imageMetadata,
+		BaseImage:    imageName,
 	}, nil
 }
 
@@ -198,6 +222,7 @@ func (r *runner) getImageBuildInfoFromDockerfile(substitutionContext *config.Sub
 		Dockerfile: parsedDockerfile,
 		User:       user,
 		Metadata:   imageMetadataConfig,
+		BaseImage:  baseImage,
 	}, nil
 }
 
@@ -216,10 +241,11 @@ func (r *runner) buildImage(
 		return nil, err
 	}
 
-	prebuildHash, err := config.CalculatePrebuildHash(parsedConfig.Config, options.Platform, targetArch, config.GetContextPath(parsedConfig.Config), dockerfilePath, dockerfileContent, buildInfo, r.Log)
+	prebuildManifest, err := config.CalculatePrebuildHashManifest(parsedConfig.Config, options.Platform, targetArch, config.GetContextPath(parsedConfig.Config), dockerfilePath, dockerfileContent, buildInfo, r.Log)
 	if err != nil {
 		return nil, err
 	}
+	prebuildHash, prebuildIndexHash := prebuildManifest.Hash, prebuildManifest.IndexHash
 
 	// check if there is a prebuild image
 	if !options.ForceDockerless && !options.ForceBuild {
@@ -229,11 +255,24 @@ func (r *runner) buildImage(
 		}
 		options.PrebuildRepositories = append(options.PrebuildRepositories, devSpaceCustomizations.PrebuildRepository...)
 
-		r.Log.Debugf("Try to find prebuild image %s in repositories %s", prebuildHash, strings.Join(options.PrebuildRepositories, ","))
+		// look for the architecture we are building for
+		lookupArch := targetArch
+		if platform, err := v1.ParsePlatform(options.Platform); err == nil && options.Platform != "" && platform.Architecture != "" {
+			lookupArch = platform.Architecture
+		}
+
+		r.Log.Debugf("Try to find prebuild image %s or %s in repositories %s", prebuildIndexHash, prebuildHash, strings.Join(options.PrebuildRepositories, ","))
 		for _, prebuildRepo := range options.PrebuildRepositories {
-			prebuildImage := prebuildRepo + ":" + prebuildHash
-			img, err := image.GetImageForArch(ctx, prebuildImage, targetArch)
-			if err == nil && img != nil {
+			// prefer the multi-platform image index and fall back to the architecture specific image
+			for _, prebuildImage := range []string{prebuildRepo + ":" + prebuildIndexHash, prebuildRepo + ":" + prebuildHash} {
+				img, err := image.GetImageForArch(ctx, prebuildImage, lookupArch)
+				if err != nil {
+					r.Log.Debugf("Error trying to find prebuild image %s: %v", prebuildImage, err)
+					continue
+				} else if img == nil {
+					continue
+				}
+
 				// prebuild image found
 				r.Log.Infof("Found existing prebuilt image %s", prebuildImage)
 
@@ -243,41 +282,100 @@ func (r *runner) buildImage(
 					return nil, errors.Wrap(err, "get image details")
 				}
 
+				// the prebuild is checked for updates together with what it was built from
+				sources := r.getImageSources(ctx, buildInfo, extendedBuildInfo.Features, options)
+				if !options.Prebuild {
+					if prebuildSource := r.getImageSource(ctx, config.ImageSourceTypePrebuild, prebuildImage); prebuildSource != nil {
+						sources = append([]config.ImageSource{*prebuildSource}, sources...)
+					}
+				}
+
 				return &config.BuildInfo{
-					ImageDetails:  imageDetails,
-					ImageMetadata: extendedBuildInfo.MetadataConfig,
-					ImageName:     prebuildImage,
-					PrebuildHash:  prebuildHash,
-					RegistryCache: options.RegistryCache,
-					Tags:          options.Tag,
+					ImageDetails:      imageDetails,
+					ImageMetadata:     extendedBuildInfo.MetadataConfig,
+					ImageName:         prebuildImage,
+					PrebuildHash:      prebuildHash,
+					PrebuildIndexHash: prebuildIndexHash,
+					PrebuildManifest:  prebuildManifest,
+					RegistryCache:     options.RegistryCache,
+					Tags:              options.Tag,
+					Sources:           sources,
 				}, nil
-			} else if err != nil {
-				r.Log.Debugf("Error trying to find prebuild image %s: %v", prebuildImage, err)
 			}
 		}
 	}
 
+	// only explain the prebuild hash without building
+	if options.Explain {
+		return &config.BuildInfo{
+			ImageMetadata:     extendedBuildInfo.MetadataConfig,
+			PrebuildHash:      prebuildHash,
+			PrebuildIndexHash: prebuildIndexHash,
+			PrebuildManifest:  prebuildManifest,
+		}, nil
+	}
+
+	// store the aggregate hashes of the hash inputs within prebuilds, so they can be compared later on. The checksums
+	// of all context files are pushed as referrer after the prebuild is pushed, they could exceed the argument size
+	// limit of the build command.
+	labels := map[string]string{}
+	if options.Prebuild {
+		rawPrebuildManifest, err := json.Marshal(prebuildManifest.Summary())
+		if err != nil {
+			return nil, err
+		}
+		labels[config.PrebuildManifestLabel] = string(rawPrebuildManifest)
+	}
+	if branch := r.getGitSource(ctx).Branch; branch != "" {
+		labels[config.PrebuildBranchLabel] = branch
+	}
+	for k, v := range options.Labels {
+		labels[k] = v
+	}
+	options.Labels = labels
+
 	if options.CLIOptions.Platform.Enabled {
-		buildInfo, err := buildkit.BuildRemote(ctx, prebuildHash, parsedConfig, extendedBuildInfo, dockerfilePath, dockerfileContent, r.LocalWorkspaceFolder, options, targetArch, r.Log)
+		remoteBuildInfo, err := buildkit.BuildRemote(ctx, prebuildHash, parsedConfig, extendedBuildInfo, dockerfilePath, dockerfileContent, r.LocalWorkspaceFolder, options, targetArch, r.Log)
 		if err != nil {
 			return nil, fmt.Errorf("(remote) %w", err)
 		}
 
-		return buildInfo, nil
+		remoteBuildInfo.PrebuildIndexHash = prebuildIndexHash
+		remoteBuildInfo.PrebuildManifest = prebuildManifest
+		remoteBuildInfo.Features = extendedBuildInfo.Features
+		remoteBuildInfo.Sources = r.getImageSources(ctx, buildInfo, extendedBuildInfo.Features, options)
+		return remoteBuildInfo, nil
 	}
 
 	// check if we should fallback to dockerless.
 	// This should only be OSS kubernetes as of March 06, 2025.
-	dockerDriver, ok := r.Driver.(driver.DockerDriver)
-	if options.ForceDockerless || !ok {
+	// Non-docker drivers that can build themselves are only used for prebuilds.
+	buildDriver, ok := r.Driver.(driver.BuildDriver)
+	_, isDockerDriver := r.Driver.(driver.DockerDriver)
+	if options.ForceDockerless || !ok || (!isDockerDriver && !options.Prebuild) {
 		if r.WorkspaceConfig.Agent.Dockerless.Disabled == "true" {
 			return nil, fmt.Errorf("cannot build devcontainer because driver is non-docker and dockerless fallback is disabled")
 		}
 
-		return dockerlessFallback(r.LocalWorkspaceFolder, substitutionContext.ContainerWorkspaceFolder, parsedConfig, buildInfo, extendedBuildInfo, dockerfileContent, options)
+		dockerlessBuildInfo, err := dockerlessFallback(r.LocalWorkspaceFolder, substitutionContext.ContainerWorkspaceFolder, parsedConfig, buildInfo, extendedBuildInfo, dockerfileContent, options)
+		if err != nil {
+			return nil, err
+		}
+
+		dockerlessBuildInfo.Sources = r.getImageSources(ctx, buildInfo, extendedBuildInfo.Features, options)
+		return dockerlessBuildInfo, nil
+	}
+
+	builtInfo, err := buildDriver.BuildDevContainer(ctx, prebuildHash, parsedConfig, extendedBuildInfo, dockerfilePath, dockerfileContent, r.LocalWorkspaceFolder, options)
+	if err != nil {
+		return nil, err
 	}
 
-	return dockerDriver.BuildDevContainer(ctx, prebuildHash, parsedConfig, extendedBuildInfo, dockerfilePath, dockerfileContent, r.LocalWorkspaceFolder, options)
+	builtInfo.PrebuildIndexHash = prebuildIndexHash
+	builtInfo.PrebuildManifest = prebuildManifest
+	builtInfo.Features = extendedBuildInfo.Features
+	builtInfo.Sources = r.getImageSources(ctx, buildInfo, extendedBuildInfo.Features, options)
+	return builtInfo, nil
 }
 
 func (r *runner) buildDevImageCompose(
@@ -16,6 +16,9 @@ import (
 	"github.com/pkg/errors"
 )
 
+// DefaultSBOMGenerator is the BuildKit scanner image used to generate SPDX SBOMs
+const DefaultSBOMGenerator = "docker/buildkit-syft-scanner:stable-1"
+
 type BuildOptions struct {
 	BuildArgs map[string]string
 	Labels    map[string]string
@@ -32,6 +35,15 @@ type BuildOptions struct {
 
 	Target string
 
+	// SBOM are the BuildKit sbom attestation parameters, attestations are only kept if the image is pushed by BuildKit
+	SBOM string
+
+	// PushImages are the images BuildKit pushes to the registry directly
+	PushImages []string
+
+	// Pull always pulls the latest version of the base images
+	Pull bool
+
 	Load   bool
 	Push   bool
 	Upload bool
@@ -68,12 +80,21 @@ func NewOptions(
 		buildOptions.Dockerfile = dockerfilePath
 	}
 
-	// add label
+	// add labels
+	for k, v := range options.Labels {
+		buildOptions.Labels[k] = v
+	}
 	if extendedBuildInfo != nil && extendedBuildInfo.MetadataLabel != "" {
 		buildOptions.Labels[metadata.ImageMetadataLabel] = extendedBuildInfo.MetadataLabel
 	}
 
+	// sbom attestation
+	if options.SBOM != "" {
+		buildOptions.SBOM = GetSBOMAttestation(options.SBOMGenerator)
+	}
+
 	// other options
+	buildOptions.Pull = options.Rebase
 	if imageName != "" {
 		buildOptions.Images = append(buildOptions.Images, imageName)
 	}
@@ -161,6 +182,38 @@ func RewriteDockerfile(
 	return "", nil
 }
 
+// GetSBOMAttestation returns the BuildKit sbom attestation parameters. The default scanner produces
+// SPDX, other formats require a generator image that implements the BuildKit SBOM scanning protocol.
+func GetSBOMAttestation(generator string) string {
+	if generator == "" {
+		generator = DefaultSBOMGenerator
+	}
+
+	return "generator=" + generator
+}
+
+// GetPrebuildImages returns the prebuild image and the user defined tags within the prebuild repository,
+// or nil if no prebuild repository is configured.
+func GetPrebuildImages(parsedConfig *config.SubstitutedConfig, options provider.BuildOptions, prebuildHash string) []string {
+	repository := options.Repository
+	if repository == "" {
+		prebuildRepositories := config.GetDevSpaceCustomizations(parsedConfig.Config).PrebuildRepository
+		if len(prebuildRepositories) > 0 {
+			repository = prebuildRepositories[0]
+		}
+	}
+	if repository == "" {
+		return nil
+	}
+
+	imageNames := []string{repository + ":" + prebuildHash}
+	for _, tag := range options.Tag {
+		imageNames = append(imageNames, repository+":"+tag)
+	}
+
+	return imageNames
+}
+
 func GetImageName(localWorkspaceFolder, prebuildHash string) string {
 	imageHash := hash.String(localWorkspaceFolder)[:5]
 	return id.ToDockerImageName(filepath.Base(localWorkspaceFolder)) + "-" + imageHash + ":" + prebuildHash
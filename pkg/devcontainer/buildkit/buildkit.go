@@ -59,6 +59,11 @@ func Build(ctx context.Context, client *buildkit.Client, writer io.Writer, platf
 		solveOptions.FrontendAttrs["platform"] = platform
 	}
 
+	// always pull base images
+	if options.Pull {
+		solveOptions.FrontendAttrs["image-resolve-mode"] = "pull"
+	}
+
 	// add context and dockerfile to local dirs
 	solveOptions.LocalDirs = map[string]string{}
 	solveOptions.LocalDirs["context"] = options.Context
@@ -81,6 +86,15 @@ func Build(ctx context.Context, client *buildkit.Client, writer io.Writer, platf
 		solveOptions.FrontendAttrs["context:"+k] = "local:" + localName
 	}
 
+	// attestations
+	if options.SBOM != "" {
+		if options.Load {
+			return fmt.Errorf("sbom attestations cannot be loaded into the docker daemon, please use docker buildx")
+		}
+
+		solveOptions.FrontendAttrs["attest:sbom"] = options.SBOM
+	}
+
 	// load?
 	if options.Load {
 		solveOptions.Exports = append(solveOptions.Exports, buildkit.ExportEntry{
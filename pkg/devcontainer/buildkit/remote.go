@@ -226,6 +226,11 @@ func BuildRemote(
 		solveOptions.FrontendAttrs["context:"+k] = "local:" + localName
 	}
 
+	// attestations are pushed together with the image
+	if buildOptions.SBOM != "" {
+		solveOptions.FrontendAttrs["attest:sbom"] = buildOptions.SBOM
+	}
+
 	push := "true"
 	if options.SkipPush {
 		push = "false"
@@ -19,6 +19,7 @@ import (
 	"dev.khulnasoft.com/pkg/devcontainer/metadata"
 	"dev.khulnasoft.com/pkg/dockerfile"
 	"dev.khulnasoft.com/pkg/driver"
+	"dev.khulnasoft.com/pkg/ide"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v2"
@@ -301,7 +302,11 @@ func (r *runner) startContainer(
 		}
 	}
 
-	var didRestoreFromPersistedShare bool
+	var (
+		didRestoreFromPersistedShare bool
+		ideCacheImage                string
+		ideCacheExtensions           []string
+	)
 	if container != nil {
 		labels := container.Config.Labels
 		if labels[ConfigFilesLabel] != "" {
@@ -360,6 +365,14 @@ func (r *runner) startContainer(
 			metadata.ImageMetadataLabel: metadataLabel,
 			config.UserLabel:            imageDetails.Config.User,
 		}
+
+		// compose builds have no prebuild hash, the image tag is used instead like for compose prebuilds
+		imageTag, err := r.getImageTag(ctx, imageDetails.ID)
+		if err != nil {
+			r.Log.Debugf("Error getting image tag of %s: %v", currentImageName, err)
+		} else if imageTag != "" {
+			additionalLabels[config.PrebuildHashLabel] = imageTag
+		}
 		overrideComposeUpFilePath, err := r.extendedDockerComposeUp(parsedConfig, mergedConfig, composeHelper, &composeService, originalImageName, overrideBuildImageName, imageDetails, additionalLabels)
 		if err != nil {
 			return nil, errors.Wrap(err, "extend docker-compose up")
@@ -368,6 +381,9 @@ func (r *runner) startContainer(
 		if overrideComposeUpFilePath != "" {
 			composeGlobalArgs = append(composeGlobalArgs, "-f", overrideComposeUpFilePath)
 		}
+
+		ideCacheImage = currentImageName
+		ideCacheExtensions = config.GetVSCodeConfiguration(mergedConfig).Extensions
 	}
 
 	if container != nil && options.Recreate {
@@ -398,6 +414,11 @@ func (r *runner) startContainer(
 		return nil, errors.Wrapf(err, "docker-compose run")
 	}
 
+	// containers restored from their persisted compose files were filled when they were created
+	if ideCacheImage != "" {
+		r.fillIDECache(ctx, ideCacheImage, ideCacheExtensions)
+	}
+
 	// TODO wait for started event?
 	containerDetails, err := composeHelper.FindDevContainer(ctx, project.Name, composeService.Name)
 	if err != nil {
@@ -771,6 +792,17 @@ exec "$$@"
 		})
 	}
 
+	// share the ide cache with the other workspaces on this machine
+	ideCache := r.WorkspaceConfig.Agent.Docker.IDECacheEnabled()
+	if ideCache {
+		overrideService.Volumes = append(overrideService.Volumes, composetypes.ServiceVolumeConfig{
+			Type:   "volume",
+			Source:   ide.CacheVolume,
+			Target:   ide.CacheFolder,
+			ReadOnly: true,
+		})
+	}
+
 	project := &composetypes.Project{}
 	project.Services = map[string]composetypes.ServiceConfig{
 		overrideService.Name: *overrideService,
@@ -787,6 +819,12 @@ exec "$$@"
 		}
 	}
 
+	if ideCache {
+		volumeMounts = append(volumeMounts, composetypes.VolumeConfig{
+			Name: ide.CacheVolume,
+		})
+	}
+
 	if len(volumeMounts) > 0 {
 		project.Volumes = map[string]composetypes.VolumeConfig{}
 	}
@@ -0,0 +1,308 @@
+package devcontainer
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/compose"
+	"dev.khulnasoft.com/pkg/devcontainer/config"
+	"dev.khulnasoft.com/pkg/driver"
+	composetypes "github.com/compose-spec/compose-go/v2/types"
+	"github.com/pkg/errors"
+)
+
+// canRunComposeSidecars returns true if the driver cannot run docker compose itself, but is able to run
+// the compose services as sidecars next to the devcontainer
+func (r *runner) canRunComposeSidecars() bool {
+	if _, ok := r.Driver.(driver.DockerDriver); ok {
+		return false
+	}
+
+	sidecarDriver, ok := r.Driver.(driver.SidecarDriver)
+	return ok && sidecarDriver.CanRunSidecars()
+}
+
+// runComposeSidecars runs a docker compose devcontainer without docker compose. The main service becomes
+// the devcontainer and the other services are started as sidecars that share its network.
+func (r *runner) runComposeSidecars(
+	ctx context.Context,
+	parsedConfig *config.SubstitutedConfig,
+	substitutionContext *config.SubstitutionContext,
+	options UpOptions,
+	timeout time.Duration,
+) (*config.Result, error) {
+	composeFiles, envFiles, _, err := r.dockerComposeProjectFiles(parsedConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "get compose/env files")
+	}
+
+	r.Log.Debugf("Loading docker compose project %+v", composeFiles)
+	project, err := compose.LoadDockerComposeProject(ctx, composeFiles, envFiles)
+	if err != nil {
+		return nil, errors.Wrap(err, "load docker compose project")
+	}
+
+	sidecars, err := getComposeSidecars(parsedConfig.Config, project, r.Log)
+	if err != nil {
+		return nil, err
+	}
+
+	err = applyComposeService(parsedConfig.Config, project, r.Log)
+	if err != nil {
+		return nil, err
+	}
+
+	options.Sidecars = sidecars
+	return r.runSingleContainer(ctx, parsedConfig, substitutionContext, options, timeout)
+}
+
+// applyComposeService turns the compose devcontainer config into a single container config
+// by taking over the image, build, environment and volumes of the main service
+func applyComposeService(devContainerConfig *config.DevContainerConfig, project *composetypes.Project, log log.Logger) error {
+	service, err := project.GetService(devContainerConfig.Service)
+	if err != nil {
+		return err
+	}
+
+	if service.Build != nil {
+		buildOptions, err := getComposeBuildOptions(devContainerConfig, service.Build)
+		if err != nil {
+			return err
+		}
+
+		devContainerConfig.DockerfileContainer = config.DockerfileContainer{Build: buildOptions}
+	} else if service.Image != "" {
+		devContainerConfig.ImageContainer = config.ImageContainer{Image: service.Image}
+	} else {
+		return fmt.Errorf("service '%s' has neither an image nor a build configured", service.Name)
+	}
+
+	env := getComposeEnvironment(service.Environment)
+	for k, v := range devContainerConfig.ContainerEnv {
+		env[k] = v
+	}
+	devContainerConfig.ContainerEnv = env
+
+	for _, volume := range service.Volumes {
+		switch volume.Type {
+		case composetypes.VolumeTypeVolume:
+			devContainerConfig.Mounts = append(devContainerConfig.Mounts, &config.Mount{
+				Type:   "volume",
+				Source: volume.Source,
+				Target: volume.Target,
+			})
+		case composetypes.VolumeTypeBind:
+			// the workspace itself is mounted at the workspace folder
+			if rel, err := filepath.Rel(volume.Target, devContainerConfig.WorkspaceFolder); err == nil && !strings.HasPrefix(rel, "..") {
+				continue
+			}
+
+			log.Warnf("Skipping bind mount %s:%s of service '%s', bind mounts are not supported for this provider", volume.Source, volume.Target, service.Name)
+		}
+	}
+
+	if devContainerConfig.ContainerUser == "" {
+		devContainerConfig.ContainerUser = service.User
+	}
+	devContainerConfig.CapAdd = append(devContainerConfig.CapAdd, service.CapAdd...)
+	if devContainerConfig.Privileged == nil && service.Privileged {
+		devContainerConfig.Privileged = &service.Privileged
+	}
+
+	devContainerConfig.ComposeContainer = config.ComposeContainer{}
+	return nil
+}
+
+func getComposeBuildOptions(devContainerConfig *config.DevContainerConfig, build *composetypes.BuildConfig) (*config.ConfigBuildOptions, error) {
+	if build.DockerfileInline != "" {
+		return nil, fmt.Errorf("inline dockerfiles are not supported for this provider")
+	}
+
+	dockerfile := build.Dockerfile
+	if dockerfile == "" {
+		dockerfile = "Dockerfile"
+	}
+	if !filepath.IsAbs(dockerfile) {
+		dockerfile = filepath.Join(build.Context, dockerfile)
+	}
+
+	// paths of the devcontainer.json are relative to its folder
+	configDir := filepath.Dir(devContainerConfig.Origin)
+	relDockerfile, err := filepath.Rel(configDir, dockerfile)
+	if err != nil {
+		return nil, errors.Wrap(err, "resolve dockerfile")
+	}
+	relContext, err := filepath.Rel(configDir, build.Context)
+	if err != nil {
+		return nil, errors.Wrap(err, "resolve build context")
+	}
+
+	return &config.ConfigBuildOptions{
+		Dockerfile: relDockerfile,
+		Context:    relContext,
+		Target:     build.Target,
+		Args:       getComposeEnvironment(build.Args),
+	}, nil
+}
+
+// getComposeSidecars returns the services that should run next to the main service ordered by their dependencies
+func getComposeSidecars(devContainerConfig *config.DevContainerConfig, project *composetypes.Project, log log.Logger) ([]*driver.Sidecar, error) {
+	mainService, err := project.GetService(devContainerConfig.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	// start the run services or all services and their dependencies
+	serviceNames := slices.Clone(devContainerConfig.RunServices)
+	if len(serviceNames) == 0 {
+		serviceNames = project.ServiceNames()
+	}
+	for name := range mainService.DependsOn {
+		serviceNames = append(serviceNames, name)
+	}
+
+	orderedNames, err := sortComposeServices(project, serviceNames, mainService.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	sidecars := []*driver.Sidecar{}
+	for _, name := range orderedNames {
+		service := project.Services[name]
+		if service.Image == "" {
+			return nil, fmt.Errorf("service '%s' has no image, building compose services is not supported for this provider", name)
+		} else if service.Build != nil {
+			log.Debugf("Using image %s of service '%s' without building it", service.Image, name)
+		}
+
+		mounts := []*config.Mount{}
+		for _, volume := range service.Volumes {
+			mounts = append(mounts, &config.Mount{
+				Type:   volume.Type,
+				Source: volume.Source,
+				Target: volume.Target,
+			})
+		}
+
+		sidecars = append(sidecars, &driver.Sidecar{
+			Name:        name,
+			Image:       service.Image,
+			Entrypoint:  service.Entrypoint,
+			Cmd:         service.Command,
+			Env:         getComposeEnvironment(service.Environment),
+			User:        service.User,
+			WorkingDir:  service.WorkingDir,
+			CapAdd:      service.CapAdd,
+			Mounts:      mounts,
+			Healthcheck: getComposeHealthcheck(service.HealthCheck),
+		})
+	}
+
+	return sidecars, nil
+}
+
+// sortComposeServices returns the given services and their dependencies without the main service, so that
+// each service comes after the services it depends on
+func sortComposeServices(project *composetypes.Project, serviceNames []string, mainService string) ([]string, error) {
+	sort.Strings(serviceNames)
+
+	ordered := []string{}
+	visited := map[string]bool{}
+	visiting := map[string]bool{}
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		if name == mainService || visited[name] {
+			return nil
+		} else if visiting[name] {
+			return fmt.Errorf("dependency cycle between services: %s", strings.Join(append(path, name), " -> "))
+		}
+
+		service, ok := project.Services[name]
+		if !ok {
+			return fmt.Errorf("service '%s' not found in docker compose project", name)
+		}
+
+		visiting[name] = true
+		dependencies := []string{}
+		for dependency := range service.DependsOn {
+			dependencies = append(dependencies, dependency)
+		}
+		sort.Strings(dependencies)
+		for _, dependency := range dependencies {
+			err := visit(dependency, append(path, name))
+			if err != nil {
+				return err
+			}
+		}
+		visiting[name] = false
+		visited[name] = true
+
+		ordered = append(ordered, name)
+		return nil
+	}
+
+	for _, name := range serviceNames {
+		err := visit(name, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
+
+func getComposeHealthcheck(healthcheck *composetypes.HealthCheckConfig) *driver.Healthcheck {
+	if healthcheck == nil || healthcheck.Disable || len(healthcheck.Test) == 0 {
+		return nil
+	}
+
+	var test []string
+	switch healthcheck.Test[0] {
+	case "NONE":
+		return nil
+	case "CMD":
+		test = healthcheck.Test[1:]
+	case "CMD-SHELL":
+		test = []string{"/bin/sh", "-c", strings.Join(healthcheck.Test[1:], " ")}
+	default:
+		test = healthcheck.Test
+	}
+
+	retHealthcheck := &driver.Healthcheck{
+		Test:               test,
+		IntervalSeconds:    durationSeconds(healthcheck.Interval),
+		TimeoutSeconds:     durationSeconds(healthcheck.Timeout),
+		StartPeriodSeconds: durationSeconds(healthcheck.StartPeriod),
+	}
+	if healthcheck.Retries != nil {
+		retHealthcheck.Retries = int32(*healthcheck.Retries)
+	}
+
+	return retHealthcheck
+}
+
+func getComposeEnvironment(environment composetypes.MappingWithEquals) map[string]string {
+	env := map[string]string{}
+	for k, v := range environment {
+		// unresolved variables are not set at all
+		if v != nil {
+			env[k] = *v
+		}
+	}
+
+	return env
+}
+
+func durationSeconds(duration *composetypes.Duration) int32 {
+	if duration == nil {
+		return 0
+	}
+
+	return int32(time.Duration(*duration).Seconds())
+}
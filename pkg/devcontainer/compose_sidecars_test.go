@@ -0,0 +1,120 @@
+package devcontainer
+
+import (
+	"testing"
+	"time"
+
+	"dev.khulnasoft.com/pkg/driver"
+	composetypes "github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/assert"
+)
+
+func TestSortComposeServices(t *testing.T) {
+	dependsOn := func(names ...string) composetypes.DependsOnConfig {
+		config := composetypes.DependsOnConfig{}
+		for _, name := range names {
+			config[name] = composetypes.ServiceDependency{Condition: composetypes.ServiceConditionHealthy}
+		}
+		return config
+	}
+
+	tests := []struct {
+		name         string
+		services     composetypes.Services
+		serviceNames []string
+		want         []string
+		wantErr      string
+	}{
+		{
+			name: "dependencies first",
+			services: composetypes.Services{
+				"app":    {Name: "app", DependsOn: dependsOn("api")},
+				"api":    {Name: "api", DependsOn: dependsOn("db", "redis")},
+				"db":     {Name: "db"},
+				"redis":  {Name: "redis"},
+				"worker": {Name: "worker", DependsOn: dependsOn("redis")},
+			},
+			serviceNames: []string{"worker", "api", "app"},
+			want:         []string{"db", "redis", "api", "worker"},
+		},
+		{
+			name: "dependency on main service",
+			services: composetypes.Services{
+				"app":   {Name: "app"},
+				"proxy": {Name: "proxy", DependsOn: dependsOn("app")},
+			},
+			serviceNames: []string{"proxy"},
+			want:         []string{"proxy"},
+		},
+		{
+			name: "cycle",
+			services: composetypes.Services{
+				"app": {Name: "app"},
+				"a":   {Name: "a", DependsOn: dependsOn("b")},
+				"b":   {Name: "b", DependsOn: dependsOn("a")},
+			},
+			serviceNames: []string{"a"},
+			wantErr:      "dependency cycle between services: a -> b -> a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sortComposeServices(&composetypes.Project{Services: tt.services}, tt.serviceNames, "app")
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestGetComposeHealthcheck(t *testing.T) {
+	interval := composetypes.Duration(5 * time.Second)
+	retries := uint64(3)
+
+	tests := []struct {
+		name        string
+		healthcheck *composetypes.HealthCheckConfig
+		want        *driver.Healthcheck
+	}{
+		{
+			name: "cmd",
+			healthcheck: &composetypes.HealthCheckConfig{
+				Test:     composetypes.HealthCheckTest{"CMD", "pg_isready", "-U", "postgres"},
+				Interval: &interval,
+				Retries:  &retries,
+			},
+			want: &driver.Healthcheck{
+				Test:            []string{"pg_isready", "-U", "postgres"},
+				IntervalSeconds: 5,
+				Retries:         3,
+			},
+		},
+		{
+			name: "shell",
+			healthcheck: &composetypes.HealthCheckConfig{
+				Test: composetypes.HealthCheckTest{"CMD-SHELL", "redis-cli ping | grep PONG"},
+			},
+			want: &driver.Healthcheck{
+				Test: []string{"/bin/sh", "-c", "redis-cli ping | grep PONG"},
+			},
+		},
+		{
+			name: "disabled",
+			healthcheck: &composetypes.HealthCheckConfig{
+				Test: composetypes.HealthCheckTest{"NONE"},
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.DeepEqual(t, getComposeHealthcheck(tt.healthcheck), tt.want)
+		})
+	}
+}
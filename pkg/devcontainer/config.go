@@ -21,7 +21,12 @@ func (r *runner) getRawConfig(options provider2.CLIOptions) (*config.DevContaine
 			rawParsedConfig.Origin = path.Join(filepath.ToSlash(r.LocalWorkspaceFolder), ".devcontainer.devspace.json")
 		}
 		return rawParsedConfig, nil
-	} else if r.WorkspaceConfig.Workspace.Source.Container != "" {
+	} else if r.WorkspaceConfig.Workspace.Source.Container != "" || r.WorkspaceConfig.Workspace.Source.KubernetesWorkload != "" {
+		containerID := r.WorkspaceConfig.Workspace.Source.Container
+		if containerID == "" {
+			containerID = r.WorkspaceConfig.Workspace.Source.KubernetesWorkload
+		}
+
 		return &config.DevContainerConfig{
 			DevContainerConfigBase: config.DevContainerConfigBase{
 				// Default workspace directory for containers
@@ -29,7 +34,7 @@ func (r *runner) getRawConfig(options provider2.CLIOptions) (*config.DevContaine
 				WorkspaceFolder: "/",
 			},
 			RunningContainer: config.RunningContainer{
-				ContainerID: r.WorkspaceConfig.Workspace.Source.Container,
+				ContainerID: containerID,
 			},
 			Origin: "",
 		}, nil
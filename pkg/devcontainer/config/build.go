@@ -12,6 +12,17 @@ const (
 	DevSpaceDockerlessBuildInfoFolder = "/workspaces/.dockerless"
 
 	WorkspaceDaemonConfigExtraEnvVar = "DEVSPACE_WORKSPACE_DAEMON_CONFIG"
+
+	// WorkspacePersistentStateExtraEnvVar is set by drivers that persist the ContainerStateFolder
+	// across container restarts
+	WorkspacePersistentStateExtraEnvVar = "DEVSPACE_PERSISTENT_STATE"
+
+	// ContainerStateFolder holds the setup markers of the container
+	ContainerStateFolder = "/var/devspace"
+
+	// ContainerAgentBackupLocation is where the agent is kept if the ContainerStateFolder is persisted,
+	// so it doesn't need to be injected again when the container is recreated
+	ContainerAgentBackupLocation = ContainerStateFolder + "/bin/devspace"
 )
 
 func GetDockerLabelForID(id string) []string {
@@ -26,6 +37,21 @@ type BuildInfo struct {
 	RegistryCache string
 	Tags          []string
 
+	// PrebuildIndexHash is the platform-neutral tag of a multi-platform prebuild
+	PrebuildIndexHash string
+
+	// PrebuildManifest holds the inputs the prebuild hash was calculated from
+	PrebuildManifest *PrebuildHashManifest
+
+	// Features are the dev container features installed into a newly built image
+	Features []*FeatureSet
+
+	// Pushed is true if the image was pushed to the prebuild repository while building
+	Pushed bool
+
+	// Sources are the base image, prebuild and features the image was built from
+	Sources []ImageSource
+
 	Dockerless *BuildInfoDockerless
 }
 
@@ -43,6 +69,9 @@ type ImageBuildInfo struct {
 	User     string
 	Metadata *ImageMetadataConfig
 
+	// BaseImage is the image the dev container image is based on
+	BaseImage string
+
 	// Either on of these will be filled as will
 	Dockerfile   *dockerfile.Dockerfile
 	ImageDetails *ImageDetails
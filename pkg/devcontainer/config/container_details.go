@@ -1,8 +1,9 @@
 package config
 
 type ImageDetails struct {
-	ID     string
-	Config ImageDetailsConfig
+	ID          string
+	RepoDigests []string
+	Config      ImageDetailsConfig
 }
 
 type ImageDetailsConfig struct {
@@ -7,6 +7,10 @@ type FeatureSet struct {
 	Folder   string
 	Config   *FeatureConfig
 	Options  interface{}
+
+	// Reference and Digest are the reference and manifest digest an OCI feature was downloaded from
+	Reference string
+	Digest    string
 }
 
 type FeatureConfig struct {
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ImageSourcesLabel is the container label holding the images and features the container was built from
+const ImageSourcesLabel = "devspace.image.sources"
+
+const (
+	ImageSourceTypeImage    = "image"
+	ImageSourceTypePrebuild = "prebuild"
+	ImageSourceTypeFeature  = "feature"
+)
+
+// ImageSource is a base image, prebuild or feature a dev container was built from
+type ImageSource struct {
+	// Type is either image, prebuild or feature
+	Type string `json:"type"`
+
+	// Reference is the image or feature reference as specified in the configuration
+	Reference string `json:"reference"`
+
+	// Digest is the manifest digest the reference resolved to when the container was built
+	Digest string `json:"digest"`
+
+	// Version is the version of the feature
+	Version string `json:"version,omitempty"`
+}
+
+// ImageUpdate is a newer version of an image source within its registry
+type ImageUpdate struct {
+	ImageSource `json:",inline"`
+
+	// LatestDigest is the digest the reference currently resolves to
+	LatestDigest string `json:"latestDigest"`
+
+	// LatestVersion is the version of the latest feature
+	LatestVersion string `json:"latestVersion,omitempty"`
+}
+
+// String returns a human readable description of the update
+func (u *ImageUpdate) String() string {
+	if u.Version != "" && u.LatestVersion != "" && u.Version != u.LatestVersion {
+		return fmt.Sprintf("%s %s (%s -> %s)", u.Type, u.Reference, u.Version, u.LatestVersion)
+	}
+
+	return fmt.Sprintf("%s %s (%s -> %s)", u.Type, u.Reference, shortDigest(u.Digest), shortDigest(u.LatestDigest))
+}
+
+// ParseImageSources returns the image sources stored within the container labels
+func ParseImageSources(labels map[string]string) ([]ImageSource, error) {
+	if labels[ImageSourcesLabel] == "" {
+		return nil, nil
+	}
+
+	sources := []ImageSource{}
+	err := json.Unmarshal([]byte(labels[ImageSourcesLabel]), &sources)
+	if err != nil {
+		return nil, fmt.Errorf("parse image sources label: %w", err)
+	}
+
+	return sources, nil
+}
+
+func shortDigest(digest string) string {
+	if len(digest) > 19 {
+		return digest[:19]
+	}
+
+	return digest
+}
@@ -20,6 +20,31 @@ func CalculatePrebuildHash(
 	platform, architecture, contextPath, dockerfilePath, dockerfileContent string,
 	buildInfo *ImageBuildInfo,
 	log log.Logger) (string, error) {
+	prebuildHash, _, err := CalculatePrebuildHashes(originalConfig, platform, architecture, contextPath, dockerfilePath, dockerfileContent, buildInfo, log)
+	return prebuildHash, err
+}
+
+// CalculatePrebuildHashes returns the architecture specific prebuild hash together with the
+// platform-neutral hash that is used to tag the image index of a multi-platform prebuild.
+func CalculatePrebuildHashes(
+	originalConfig *DevContainerConfig,
+	platform, architecture, contextPath, dockerfilePath, dockerfileContent string,
+	buildInfo *ImageBuildInfo,
+	log log.Logger) (string, string, error) {
+	manifest, err := CalculatePrebuildHashManifest(originalConfig, platform, architecture, contextPath, dockerfilePath, dockerfileContent, buildInfo, log)
+	if err != nil {
+		return "", "", err
+	}
+
+	return manifest.Hash, manifest.IndexHash, nil
+}
+
+// CalculatePrebuildHashManifest calculates the prebuild hashes and returns them together with all inputs they are based on
+func CalculatePrebuildHashManifest(
+	originalConfig *DevContainerConfig,
+	platform, architecture, contextPath, dockerfilePath, dockerfileContent string,
+	buildInfo *ImageBuildInfo,
+	log log.Logger) (*PrebuildHashManifest, error) {
 	parsedConfig := CloneDevContainerConfig(originalConfig)
 
 	if platform != "" {
@@ -51,13 +76,13 @@ func CalculatePrebuildHash(
 	// marshal the config
 	configStr, err := json.Marshal(parsedConfig)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// find out excludes from dockerignore
 	excludes, err := readDockerignore(contextPath, dockerfilePath)
 	if err != nil {
-		return "", errors.Errorf("Error reading .dockerignore: %v", err)
+		return nil, errors.Errorf("Error reading .dockerignore: %v", err)
 	}
 	excludes = append(excludes, DevSpaceContextFeatureFolder+"/")
 
@@ -71,17 +96,27 @@ func CalculatePrebuildHash(
 	log.Debug("Build context files to use for hash are ", includes)
 
 	// get hash of the context directory
-	contextHash, err := util.DirectoryHash(contextPath, excludes, includes)
+	contextFiles, err := util.DirectoryFileHashes(contextPath, excludes, includes)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	contextHash := util.FileHashesHash(contextFiles)
 
 	log.Debugf("Prebuild hash from:")
 	log.Debugf("    Arch: %s", architecture)
 	log.Debugf("    Config: %s", string(configStr))
 	log.Debugf("    DockerfileContent: %s", dockerfileContent)
 	log.Debugf("    ContextHash: %s", contextHash)
-	return "devspace-" + hash.String(architecture + string(configStr) + dockerfileContent + contextHash)[:32], nil
+	return &PrebuildHashManifest{
+		Hash:         "devspace-" + hash.String(architecture + string(configStr) + dockerfileContent + contextHash)[:32],
+		IndexHash:    "devspace-index-" + hash.String(string(configStr) + dockerfileContent + contextHash)[:32],
+		Architecture: architecture,
+		Target:       originalConfig.GetTarget(),
+		Config:       string(configStr),
+		Dockerfile:   dockerfileContent,
+		ContextHash:  contextHash,
+		ContextFiles: contextFiles,
+	}, nil
 }
 
 // readDockerignore reads the .dockerignore file in the context directory and
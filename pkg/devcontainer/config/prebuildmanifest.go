@@ -0,0 +1,246 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"dev.khulnasoft.com/log/hash"
+)
+
+const (
+	// PrebuildManifestLabel is the image label the aggregate hashes of the prebuild hash manifest are stored in
+	PrebuildManifestLabel = "devspace.prebuild.manifest"
+
+	// PrebuildManifestArtifactType is the artifact type of the OCI referrer the hashed prebuild hash manifest with
+	// all context files is pushed as, it is too large for an image label
+	PrebuildManifestArtifactType = "application/vnd.devspace.prebuild.manifest.v1+json"
+
+	// PrebuildBranchLabel is the image label holding the git branch a prebuild was built from
+	PrebuildBranchLabel = "devspace.prebuild.branch"
+
+	// PrebuildHashLabel is the container label holding the prebuild hash of the image the container was created from
+	PrebuildHashLabel = "devspace.prebuild.hash"
+)
+
+// PrebuildHashManifest holds all inputs a prebuild hash is calculated from
+type PrebuildHashManifest struct {
+	Hash      string `json:"hash,omitempty"`
+	IndexHash string `json:"indexHash,omitempty"`
+
+	Architecture string `json:"architecture,omitempty"`
+	Target       string `json:"target,omitempty"`
+	Config       string `json:"config,omitempty"`
+	Dockerfile   string `json:"dockerfile,omitempty"`
+
+	// ConfigHash is the checksum of the config, it replaces Config in image labels
+	ConfigHash string `json:"configHash,omitempty"`
+
+	// ConfigHashes maps the dotted path of each config value to its checksum, it replaces Config in referrers
+	ConfigHashes map[string]string `json:"configHashes,omitempty"`
+
+	// FeaturesHash is the checksum of the features and their install order within the config
+	FeaturesHash string `json:"featuresHash,omitempty"`
+
+	// DockerfileHash is the checksum of the Dockerfile, it replaces Dockerfile in image labels
+	DockerfileHash string `json:"dockerfileHash,omitempty"`
+
+	// ContextHash is the combined hash of all context files
+	ContextHash string `json:"contextHash,omitempty"`
+
+	// ContextFiles maps the relative path of each hashed context file to its checksum
+	ContextFiles map[string]string `json:"contextFiles,omitempty"`
+}
+
+// Hashed returns a copy of the manifest with the config values and the Dockerfile replaced by their checksums, so
+// it can be pushed with the image without exposing the build inputs
+func (m *PrebuildHashManifest) Hashed() *PrebuildHashManifest {
+	hashed := *m
+	if m.Config != "" {
+		hashed.ConfigHash = hash.String(m.Config)
+		hashed.FeaturesHash = featuresHash(m.Config)
+		hashed.ConfigHashes = map[string]string{}
+		for key, value := range flattenConfig(m.Config) {
+			hashed.ConfigHashes[key] = hash.String(value)
+		}
+		hashed.Config = ""
+	}
+	if m.Dockerfile != "" {
+		hashed.DockerfileHash = hash.String(m.Dockerfile)
+		hashed.Dockerfile = ""
+	}
+
+	return &hashed
+}
+
+// Summary returns a copy of the hashed manifest with the aggregate hashes only. Its size doesn't depend on the
+// amount of context files, so it fits into an image label.
+func (m *PrebuildHashManifest) Summary() *PrebuildHashManifest {
+	hashed := m.Hashed()
+	return &PrebuildHashManifest{
+		Hash:           hashed.Hash,
+		IndexHash:      hashed.IndexHash,
+		Architecture:   hashed.Architecture,
+		Target:         hashed.Target,
+		ConfigHash:     hashed.ConfigHash,
+		FeaturesHash:   hashed.FeaturesHash,
+		DockerfileHash: hashed.DockerfileHash,
+		ContextHash:    hashed.ContextHash,
+	}
+}
+
+// Explain writes a human readable description of the prebuild hash inputs to the writer
+func (m *PrebuildHashManifest) Explain(writer io.Writer) {
+	fmt.Fprintf(writer, "Prebuild hash: %s\n", m.Hash)
+	fmt.Fprintf(writer, "Index hash: %s\n", m.IndexHash)
+	fmt.Fprintf(writer, "Architecture: %s\n", m.Architecture)
+	if m.Target != "" {
+		fmt.Fprintf(writer, "Build target: %s\n", m.Target)
+	}
+
+	fmt.Fprintf(writer, "Config:\n")
+	indented := &bytes.Buffer{}
+	if err := json.Indent(indented, []byte(m.Config), "  ", "  "); err == nil {
+		fmt.Fprintf(writer, "  %s\n", indented.String())
+	} else {
+		fmt.Fprintf(writer, "  %s\n", m.Config)
+	}
+
+	if m.Dockerfile != "" {
+		fmt.Fprintf(writer, "Dockerfile:\n")
+		for _, line := range strings.Split(strings.TrimRight(m.Dockerfile, "\n"), "\n") {
+			fmt.Fprintf(writer, "  %s\n", line)
+		}
+	}
+
+	fmt.Fprintf(writer, "Context hash: %s\n", m.ContextHash)
+	fmt.Fprintf(writer, "Context files (%d):\n", len(m.ContextFiles))
+	for _, file := range sortedKeys(m.ContextFiles) {
+		fmt.Fprintf(writer, "  %s %s\n", m.ContextFiles[file], file)
+	}
+}
+
+// DiffPrebuildHashManifests returns a human readable list of all inputs that differ between both manifests. Pushed
+// manifests only hold checksums of the config values and the Dockerfile, so only their paths are listed. If one of
+// the manifests is a summary, only the aggregate hashes are compared.
+func DiffPrebuildHashManifests(a, b *PrebuildHashManifest) []string {
+	a, b = a.Hashed(), b.Hashed()
+	differences := []string{}
+	if a.Architecture != b.Architecture {
+		differences = append(differences, fmt.Sprintf("architecture: %q != %q", a.Architecture, b.Architecture))
+	}
+	if a.Target != b.Target {
+		differences = append(differences, fmt.Sprintf("target: %q != %q", a.Target, b.Target))
+	}
+
+	// config, summaries only hold the aggregate hashes
+	if len(a.ConfigHashes) == 0 || len(b.ConfigHashes) == 0 {
+		if a.FeaturesHash != b.FeaturesHash {
+			differences = append(differences, "features changed")
+		}
+		if a.ConfigHash != b.ConfigHash {
+			differences = append(differences, "config changed")
+		}
+	} else {
+		differences = append(differences, diffChecksums("config", a.ConfigHashes, b.ConfigHashes)...)
+	}
+
+	// dockerfile
+	if a.DockerfileHash != b.DockerfileHash {
+		differences = append(differences, "dockerfile changed")
+	}
+
+	// context files
+	if len(a.ContextFiles) == 0 || len(b.ContextFiles) == 0 {
+		if a.ContextHash != b.ContextHash {
+			differences = append(differences, "context changed")
+		}
+	} else {
+		differences = append(differences, diffChecksums("context file", a.ContextFiles, b.ContextFiles)...)
+	}
+
+	return differences
+}
+
+func diffChecksums(kind string, a, b map[string]string) []string {
+	differences := []string{}
+	for _, key := range unionKeys(a, b) {
+		checksumA, okA := a[key]
+		checksumB, okB := b[key]
+		if !okA {
+			differences = append(differences, fmt.Sprintf("%s added: %s", kind, key))
+		} else if !okB {
+			differences = append(differences, fmt.Sprintf("%s removed: %s", kind, key))
+		} else if checksumA != checksumB {
+			differences = append(differences, fmt.Sprintf("%s changed: %s", kind, key))
+		}
+	}
+
+	return differences
+}
+
+// featuresHash returns the checksum of the features and their install order within the json config
+func featuresHash(config string) string {
+	parsed := struct {
+		Features                    json.RawMessage `json:"features,omitempty"`
+		OverrideFeatureInstallOrder json.RawMessage `json:"overrideFeatureInstallOrder,omitempty"`
+	}{}
+	if err := json.Unmarshal([]byte(config), &parsed); err != nil || (len(parsed.Features) == 0 && len(parsed.OverrideFeatureInstallOrder) == 0) {
+		return ""
+	}
+
+	return hash.String(string(parsed.Features) + string(parsed.OverrideFeatureInstallOrder))
+}
+
+// flattenConfig flattens the json config into a map of dotted paths to json values
+func flattenConfig(config string) map[string]string {
+	var value interface{}
+	if err := json.Unmarshal([]byte(config), &value); err != nil {
+		return map[string]string{"": config}
+	}
+
+	retMap := map[string]string{}
+	flattenValue("", value, retMap)
+	return retMap
+}
+
+func flattenValue(prefix string, value interface{}, retMap map[string]string) {
+	object, ok := value.(map[string]interface{})
+	if !ok || len(object) == 0 {
+		raw, _ := json.Marshal(value)
+		retMap[prefix] = string(raw)
+		return
+	}
+
+	for key, child := range object {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		flattenValue(key, child, retMap)
+	}
+}
+
+func unionKeys(a, b map[string]string) []string {
+	keys := map[string]string{}
+	for key := range a {
+		keys[key] = ""
+	}
+	for key := range b {
+		keys[key] = ""
+	}
+
+	return sortedKeys(keys)
+}
+
+func sortedKeys(files map[string]string) []string {
+	retFiles := make([]string, 0, len(files))
+	for file := range files {
+		retFiles = append(retFiles, file)
+	}
+	sort.Strings(retFiles)
+
+	return retFiles
+}
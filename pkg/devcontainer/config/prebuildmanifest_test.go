@@ -0,0 +1,192 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiffPrebuildHashManifests(t *testing.T) {
+	base := &PrebuildHashManifest{
+		Architecture: "amd64",
+		Config:       `{"name":"test","features":{"ghcr.io/devcontainers/features/go:1":{"version":"1.22"}},"build":{"dockerfile":"Dockerfile"}}`,
+		Dockerfile:   "FROM ubuntu\nRUN apt-get update\nCOPY . /app\n",
+		ContextFiles: map[string]string{
+			"go.mod":  "aaaa",
+			"main.go": "bbbb",
+		},
+	}
+
+	tests := []struct {
+		name string
+		a    *PrebuildHashManifest
+		b    *PrebuildHashManifest
+		want []string
+	}{
+		{
+			name: "identical",
+			a:    base,
+			b:    base,
+			want: []string{},
+		},
+		{
+			name: "architecture and target",
+			a:    base,
+			b: &PrebuildHashManifest{
+				Architecture: "arm64",
+				Target:       "dev",
+				Config:       base.Config,
+				Dockerfile:   base.Dockerfile,
+				ContextFiles: base.ContextFiles,
+			},
+			want: []string{
+				`architecture: "amd64" != "arm64"`,
+				`target: "" != "dev"`,
+			},
+		},
+		{
+			name: "config, dockerfile and context",
+			a:    base,
+			b: &PrebuildHashManifest{
+				Architecture: "amd64",
+				Config:       `{"name":"test","features":{"ghcr.io/devcontainers/features/go:1":{"version":"1.23"}},"build":{"dockerfile":"Dockerfile","target":"dev"}}`,
+				Dockerfile:   "FROM ubuntu\nRUN apt-get update && apt-get install -y git\nCOPY . /app\n",
+				ContextFiles: map[string]string{
+					"go.mod": "cccc",
+					"go.sum": "dddd",
+				},
+			},
+			want: []string{
+				"config added: build.target",
+				"config changed: features.ghcr.io/devcontainers/features/go:1.version",
+				"dockerfile changed",
+				"context file changed: go.mod",
+				"context file added: go.sum",
+				"context file removed: main.go",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" hashed", func(t *testing.T) {
+			got := DiffPrebuildHashManifests(tt.a.Hashed(), tt.b)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("DiffPrebuildHashManifests() mismatch (-want +got):\n%s", diff)
+			}
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffPrebuildHashManifests(tt.a, tt.b)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("DiffPrebuildHashManifests() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHashedPrebuildHashManifest(t *testing.T) {
+	manifest := &PrebuildHashManifest{
+		Hash:         "devspace-abc",
+		Config:       `{"name":"test","build":{"dockerfile":"Dockerfile"}}`,
+		Dockerfile:   "FROM ubuntu\n",
+		ContextFiles: map[string]string{"go.mod": "aaaa"},
+	}
+
+	hashed := manifest.Hashed()
+	if hashed.Config != "" || hashed.Dockerfile != "" {
+		t.Fatalf("expected config and dockerfile to be removed, got %q and %q", hashed.Config, hashed.Dockerfile)
+	}
+	if diff := cmp.Diff([]string{"build.dockerfile", "name"}, sortedKeys(hashed.ConfigHashes)); diff != "" {
+		t.Errorf("config hashes mismatch (-want +got):\n%s", diff)
+	}
+	if hashed.DockerfileHash == "" || hashed.Hash != manifest.Hash || hashed.ContextFiles["go.mod"] != "aaaa" {
+		t.Errorf("unexpected hashed manifest %+v", hashed)
+	}
+	if diff := cmp.Diff(hashed, hashed.Hashed()); diff != "" {
+		t.Errorf("hashing twice changed the manifest (-want +got):\n%s", diff)
+	}
+}
+
+func TestDiffPrebuildHashManifestSummaries(t *testing.T) {
+	base := &PrebuildHashManifest{
+		Architecture: "amd64",
+		Config:       `{"name":"test","features":{"ghcr.io/devcontainers/features/go:1":{"version":"1.22"}}}`,
+		Dockerfile:   "FROM ubuntu\n",
+		ContextHash:  "context-a",
+		ContextFiles: map[string]string{"go.mod": "aaaa"},
+	}
+
+	tests := []struct {
+		name string
+		b    *PrebuildHashManifest
+		want []string
+	}{
+		{
+			name: "identical",
+			b:    base,
+			want: []string{},
+		},
+		{
+			name: "features and context",
+			b: &PrebuildHashManifest{
+				Architecture: "amd64",
+				Config:       `{"name":"test","features":{"ghcr.io/devcontainers/features/go:1":{"version":"1.23"}}}`,
+				Dockerfile:   base.Dockerfile,
+				ContextHash:  "context-b",
+				ContextFiles: map[string]string{"go.mod": "bbbb"},
+			},
+			want: []string{
+				"features changed",
+				"config changed",
+				"context changed",
+			},
+		},
+		{
+			name: "config without features",
+			b: &PrebuildHashManifest{
+				Architecture: "amd64",
+				Config:       `{"name":"other","features":{"ghcr.io/devcontainers/features/go:1":{"version":"1.22"}}}`,
+				Dockerfile:   base.Dockerfile,
+				ContextHash:  base.ContextHash,
+				ContextFiles: base.ContextFiles,
+			},
+			want: []string{
+				"config changed",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffPrebuildHashManifests(base.Summary(), tt.b)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("DiffPrebuildHashManifests() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPrebuildHashManifestSummary(t *testing.T) {
+	manifest := &PrebuildHashManifest{
+		Hash:         "devspace-abc",
+		Config:       `{"name":"test","build":{"dockerfile":"Dockerfile"}}`,
+		Dockerfile:   "FROM ubuntu\n",
+		ContextHash:  "context",
+		ContextFiles: map[string]string{"go.mod": "aaaa"},
+	}
+
+	summary := manifest.Summary()
+	hashed := manifest.Hashed()
+	want := &PrebuildHashManifest{
+		Hash:           "devspace-abc",
+		ConfigHash:     hashed.ConfigHash,
+		DockerfileHash: hashed.DockerfileHash,
+		ContextHash:    "context",
+	}
+	if diff := cmp.Diff(want, summary); diff != "" {
+		t.Errorf("Summary() mismatch (-want +got):\n%s", diff)
+	}
+	if summary.ConfigHash == "" || summary.FeaturesHash != "" {
+		t.Errorf("expected a config hash and no features hash, got %q and %q", summary.ConfigHash, summary.FeaturesHash)
+	}
+}
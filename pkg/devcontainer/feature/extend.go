@@ -244,12 +244,17 @@ func fetchFeatures(devContainerConfig *config.DevContainerConfig, log log.Logger
 		}
 
 		// add to return array
-		featureSets = append(featureSets, &config.FeatureSet{
+		featureSet := &config.FeatureSet{
 			ConfigID: NormalizeFeatureID(featureID),
 			Folder:   featureFolder,
 			Config:   featureConfig,
 			Options:  featureOptions,
-		})
+		}
+		if digest := getOCIFeatureDigest(featureID); digest != "" {
+			featureSet.Reference = featureID
+			featureSet.Digest = digest
+		}
+		featureSets = append(featureSets, featureSet)
 	}
 
 	// compute order here
@@ -102,13 +102,26 @@ func ProcessFeatureID(id string, devContainerConfig *config.DevContainerConfig,
 
 	// get oci feature
 	log.Debugf("Process OCI feature")
-	return processOCIFeature(id, log)
+	return processOCIFeature(id, log, forceBuild)
 }
 
-func processOCIFeature(id string, log log.Logger) (string, error) {
+// getOCIFeatureDigest returns the manifest digest of the downloaded OCI feature or an empty string if unknown
+func getOCIFeatureDigest(id string) string {
+	digest, err := os.ReadFile(filepath.Join(getFeaturesTempFolder(id), "digest"))
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(digest))
+}
+
+func processOCIFeature(id string, log log.Logger, forceDownload bool) (string, error) {
 	// feature already exists?
 	featureFolder := getFeaturesTempFolder(id)
 	featureExtractedFolder := filepath.Join(featureFolder, "extracted")
+	if forceDownload {
+		_ = os.RemoveAll(featureFolder)
+	}
 	_, err := os.Stat(featureExtractedFolder)
 	if err == nil {
 		// make sure feature.json is there as well
@@ -137,6 +150,16 @@ func processOCIFeature(id string, log log.Logger) (string, error) {
 		return "", err
 	}
 
+	// remember the digest to check for newer versions later on
+	digest, err := img.Digest()
+	if err != nil {
+		return "", err
+	}
+	err = os.WriteFile(filepath.Join(featureFolder, "digest"), []byte(digest.String()), 0600)
+	if err != nil {
+		return "", err
+	}
+
 	file, err := os.Open(destFile)
 	if err != nil {
 		return "", err
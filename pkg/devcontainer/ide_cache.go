@@ -0,0 +1,34 @@
+package devcontainer
+
+import (
+	"context"
+
+	"dev.khulnasoft.com/pkg/driver"
+	"dev.khulnasoft.com/pkg/ide/ideparse"
+)
+
+// fillIDECache stores the ide server and extensions of the workspace in the shared ide cache of the driver. The cache
+// is read-only within the devcontainer and filled before the ide is installed. Errors are not fatal, the ide is
+// downloaded by the workspace then.
+func (r *runner) fillIDECache(ctx context.Context, image string, extensions []string) {
+	ideCacheDriver, ok := r.Driver.(driver.IDECacheDriver)
+	if !ok || !ideCacheDriver.IDECacheEnabled() || r.WorkspaceConfig.Workspace == nil {
+		return
+	}
+
+	arch, err := r.Driver.TargetArchitecture(ctx, r.ID)
+	if err != nil {
+		r.Log.Warnf("Error filling ide cache: %v", err)
+		return
+	}
+
+	downloads := ideparse.CacheDownloads(r.WorkspaceConfig.Workspace.IDE.Name, r.WorkspaceConfig.Workspace.IDE.Options, extensions, arch, r.Log)
+	if len(downloads) == 0 {
+		return
+	}
+
+	err = ideCacheDriver.FillIDECache(ctx, r.ID, image, downloads)
+	if err != nil {
+		r.Log.Warnf("Error filling ide cache: %v", err)
+	}
+}
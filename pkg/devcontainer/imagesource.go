@@ -0,0 +1,116 @@
+package devcontainer
+
+import (
+	"context"
+
+	"dev.khulnasoft.com/pkg/devcontainer/config"
+	"dev.khulnasoft.com/pkg/driver"
+	"dev.khulnasoft.com/pkg/image"
+	"dev.khulnasoft.com/pkg/provider"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// getImageSources returns the base image and OCI features the dev container is built from with
+// their current digests, so the workspace can be checked for updates later on
+func (r *runner) getImageSources(ctx context.Context, imageBuildInfo *config.ImageBuildInfo, features []*config.FeatureSet, options provider.BuildOptions) []config.ImageSource {
+	if options.Prebuild || options.Explain {
+		return nil
+	}
+
+	sources := []config.ImageSource{}
+	if imageBuildInfo != nil && imageBuildInfo.BaseImage != "" {
+		if source := r.getImageSource(ctx, config.ImageSourceTypeImage, imageBuildInfo.BaseImage); source != nil {
+			sources = append(sources, *source)
+		}
+	}
+	for _, feature := range features {
+		if feature.Digest == "" {
+			continue
+		}
+
+		source := config.ImageSource{
+			Type:      config.ImageSourceTypeFeature,
+			Reference: feature.Reference,
+			Digest:    feature.Digest,
+		}
+		if feature.Config != nil {
+			source.Version = feature.Config.Version
+		}
+		sources = append(sources, source)
+	}
+
+	return sources
+}
+
+// getImageSource resolves the digest of the given image, preferring the local image the container
+// will use over the registry
+func (r *runner) getImageSource(ctx context.Context, sourceType, imageName string) *config.ImageSource {
+	ref, err := name.ParseReference(imageName)
+	if err != nil || imageName == "scratch" {
+		return nil
+	} else if _, ok := ref.(name.Digest); ok {
+		// images pinned to a digest never change
+		return nil
+	}
+
+	source := &config.ImageSource{Type: sourceType, Reference: imageName}
+	if dockerDriver, ok := r.Driver.(driver.DockerDriver); ok {
+		imageDetails, err := dockerDriver.InspectImage(ctx, imageName)
+		if err == nil {
+			source.Digest = findRepoDigest(ref, imageDetails.RepoDigests)
+		}
+	}
+	if source.Digest == "" {
+		descriptor, err := image.GetDescriptor(ctx, imageName)
+		if err != nil {
+			r.Log.Debugf("Error resolving digest of image %s: %v", imageName, err)
+			return nil
+		}
+
+		source.Digest = descriptor.Digest.String()
+	}
+
+	return source
+}
+
+// pullImage pulls the latest version of the image into the local docker daemon
+func (r *runner) pullImage(ctx context.Context, imageName string) error {
+	dockerDriver, ok := r.Driver.(driver.DockerDriver)
+	if !ok {
+		return nil
+	}
+
+	dockerHelper, err := dockerDriver.DockerHelper()
+	if err != nil {
+		return err
+	}
+
+	r.Log.Infof("Pulling image %s", imageName)
+	writer := r.Log.Writer(logrus.DebugLevel, false)
+	defer writer.Close()
+
+	err = dockerHelper.Pull(ctx, imageName, nil, writer, writer)
+	if err != nil {
+		return errors.Wrapf(err, "pull image %s", imageName)
+	}
+
+	return nil
+}
+
+// findRepoDigest returns the digest of the repo digest matching the repository of ref
+func findRepoDigest(ref name.Reference, repoDigests []string) string {
+	for _, repoDigest := range repoDigests {
+		digestRef, err := name.NewDigest(repoDigest)
+		if err != nil {
+			continue
+		}
+
+		if digestRef.Context().Name() == ref.Context().Name() {
+			return digestRef.DigestStr()
+		}
+	}
+
+	return ""
+}
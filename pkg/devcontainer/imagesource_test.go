@@ -0,0 +1,45 @@
+package devcontainer
+
+import (
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"gotest.tools/assert"
+)
+
+func TestFindRepoDigest(t *testing.T) {
+	digest := "sha256:0000000000000000000000000000000000000000000000000000000000000001"
+	tests := []struct {
+		name        string
+		image       string
+		repoDigests []string
+		want        string
+	}{
+		{
+			name:        "docker hub short name",
+			image:       "ubuntu:24.04",
+			repoDigests: []string{"ubuntu@" + digest},
+			want:        digest,
+		},
+		{
+			name:        "other registry",
+			image:       "mcr.microsoft.com/devcontainers/go:1",
+			repoDigests: []string{"ubuntu@sha256:0000000000000000000000000000000000000000000000000000000000000002", "mcr.microsoft.com/devcontainers/go@" + digest},
+			want:        digest,
+		},
+		{
+			name:        "locally built image",
+			image:       "my-image:latest",
+			repoDigests: nil,
+			want:        "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := name.ParseReference(tt.image)
+			assert.NilError(t, err)
+			assert.Equal(t, findRepoDigest(ref, tt.repoDigests), tt.want)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package devcontainer
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,25 +13,92 @@ import (
 	"dev.khulnasoft.com/pkg/driver"
 	"dev.khulnasoft.com/pkg/image"
 	"dev.khulnasoft.com/pkg/provider"
+	"github.com/google/go-containerregistry/pkg/v1/types"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 func (r *runner) Build(ctx context.Context, options provider.BuildOptions) (string, error) {
-	dockerDriver, ok := r.Driver.(driver.DockerDriver)
-	if !ok {
-		return "", fmt.Errorf("building only supported with docker driver")
+	prebuildImage, _, err := r.buildAndPush(ctx, options)
+	return prebuildImage, err
+}
+
+// BuildPlatforms builds the image for each of the given platforms. If more than one platform
+// is built and pushed, the platform images are assembled into a single image index that is
+// pushed under a platform-neutral tag. Returns the pushed or built image names.
+func (r *runner) BuildPlatforms(ctx context.Context, options provider.BuildOptions, platforms []string) ([]string, error) {
+	if len(platforms) == 0 {
+		platforms = []string{""}
 	}
 
-	substitutedConfig, substitutionContext, err := r.getSubstitutedConfig(options.CLIOptions)
+	multiPlatform := len(platforms) > 1 && !options.SkipPush && !options.Explain
+	platformImages := []image.PlatformImage{}
+	retImages := []string{}
+	indexImage := ""
+	for _, platform := range platforms {
+		platformOptions := options
+		platformOptions.Platform = platform
+		if multiPlatform {
+			// user defined tags should point to the image index instead of a single platform
+			platformOptions.Tag = nil
+		}
+
+		// platform builds share the generated build context in the dev container folder, which each build writes
+		// and removes again, so they are built one after another
+		prebuildImage, prebuildIndexImage, err := r.buildAndPush(ctx, platformOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		indexImage = prebuildIndexImage
+		platformImages = append(platformImages, image.PlatformImage{Platform: platform, Image: prebuildImage})
+		if prebuildImage != prebuildIndexImage {
+			retImages = append(retImages, prebuildImage)
+		}
+	}
+	if options.Explain {
+		return nil, nil
+	} else if !multiPlatform {
+		return []string{platformImages[0].Image}, nil
+	} else if indexImage == "" {
+		r.Log.Warnf("Cannot create a multi-platform image index for this dev container, pushed platform images separately")
+		return retImages, nil
+	} else if len(retImages) == 0 {
+		// all platforms were found in an existing image index
+		return []string{indexImage}, nil
+	}
+
+	// push image index under the platform-neutral tag and all user defined tags
+	imageRefs := []string{indexImage}
+	imageRepo := imageRepository(indexImage)
+	for _, tag := range options.Tag {
+		imageRefs = append(imageRefs, imageRepo+":"+tag)
+	}
+
+	r.Log.Infof("Push image index %s for platforms %s...", indexImage, strings.Join(platforms, ", "))
+	err := image.PushIndex(ctx, platformImages, imageRefs...)
 	if err != nil {
-		return "", err
+		return nil, errors.Wrap(err, "push image index")
 	}
 
-	prebuildRepo := getPrebuildRepository(substitutedConfig)
+	return append(retImages, imageRefs...), nil
+}
 
-	if !options.SkipPush && options.Repository == "" && prebuildRepo == "" {
-		return "", fmt.Errorf("repository needs to be specified")
+// buildAndPush builds and pushes the image and returns the architecture specific image name
+// together with the name of the image index a multi-platform prebuild would be pushed to.
+func (r *runner) buildAndPush(ctx context.Context, options provider.BuildOptions) (string, string, error) {
+	dockerDriver, isDockerDriver := r.Driver.(driver.DockerDriver)
+	if _, ok := r.Driver.(driver.BuildDriver); !ok {
+		return "", "", fmt.Errorf("building only supported with docker or kubernetes driver")
 	}
+	options.Prebuild = true
+
+	substitutedConfig, substitutionContext, err := r.getSubstitutedConfig(options.CLIOptions)
+	if err != nil {
+		return "", "", err
+	}
+
+	prebuildRepo := getPrebuildRepository(substitutedConfig)
 
 	// remove build information
 	defer func() {
@@ -41,7 +109,12 @@ func (r *runner) Build(ctx context.Context, options provider.BuildOptions) (stri
 	// check if we need to build container
 	buildInfo, err := r.build(ctx, substitutedConfig, substitutionContext, options)
 	if err != nil {
-		return "", errors.Wrap(err, "build image")
+		return "", "", errors.Wrap(err, "build image")
+	} else if options.Explain {
+		r.explainPrebuild(buildInfo, options.Platform)
+		return buildInfo.ImageName, "", nil
+	} else if !options.SkipPush && options.Repository == "" && prebuildRepo == "" {
+		return "", "", fmt.Errorf("repository needs to be specified")
 	}
 
 	// have a fallback value for PrebuildHash
@@ -52,7 +125,7 @@ func (r *runner) Build(ctx context.Context, options provider.BuildOptions) (stri
 	}
 
 	// prebuild already exists
-	var prebuildImage string
+	var prebuildImage, prebuildIndexImage string
 	if options.Repository != "" {
 		prebuildImage = options.Repository + ":" + buildInfo.PrebuildHash
 	} else if prebuildRepo != "" {
@@ -60,25 +133,46 @@ func (r *runner) Build(ctx context.Context, options provider.BuildOptions) (stri
 	} else {
 		prebuildImage = build.GetImageName(r.LocalWorkspaceFolder, buildInfo.PrebuildHash)
 	}
+	if buildInfo.PrebuildIndexHash != "" {
+		prebuildIndexImage = imageRepository(prebuildImage) + ":" + buildInfo.PrebuildIndexHash
+	}
 
-	if buildInfo.ImageName == prebuildImage {
-		return buildInfo.ImageName, nil
+	// the driver pushed the image while building
+	if buildInfo.Pushed {
+		err = r.pushPrebuildManifest(ctx, buildInfo, prebuildImage)
+		if err != nil {
+			r.Log.Warnf("Error pushing prebuild hash manifest for image %s: %v", prebuildImage, err)
+		}
+
+		err = r.pushAttestations(ctx, options, substitutedConfig, buildInfo, prebuildImage)
+		if err != nil {
+			return "", "", err
+		}
+
+		return prebuildImage, prebuildIndexImage, nil
+	}
+
+	if buildInfo.ImageName == prebuildImage || (prebuildIndexImage != "" && buildInfo.ImageName == prebuildIndexImage) {
+		return buildInfo.ImageName, prebuildIndexImage, nil
 	}
 
 	// should we push?
 	if options.SkipPush {
-		return prebuildImage, nil
+		return prebuildImage, prebuildIndexImage, nil
+	} else if !isDockerDriver {
+		// non-docker drivers push the image while building
+		return buildInfo.ImageName, prebuildIndexImage, nil
 	}
 
 	if isDockerComposeConfig(substitutedConfig.Config) {
 		if err := dockerDriver.TagDevContainer(ctx, buildInfo.ImageName, prebuildImage); err != nil {
-			return "", errors.Wrap(err, "tag image")
+			return "", "", errors.Wrap(err, "tag image")
 		}
 	}
 
 	// check if we can push image
 	if err := image.CheckPushPermissions(prebuildImage); err != nil {
-		return "", fmt.Errorf(
+		return "", "", fmt.Errorf(
 			"cannot push to repository %s. Please make sure you are logged into the registry and credentials are available. (Error: %w)",
 			prebuildImage,
 			err,
@@ -88,28 +182,98 @@ func (r *runner) Build(ctx context.Context, options provider.BuildOptions) (stri
 	// Setup all image tags (prebuild and any user defined tags)
 	imageRefs := []string{prebuildImage}
 
-	imageRepoName := strings.Split(prebuildImage, ":")
+	imageRepo := imageRepository(prebuildImage)
 	if buildInfo.Tags != nil {
 		for _, tag := range buildInfo.Tags {
-			imageRefs = append(imageRefs, imageRepoName[0]+":"+tag)
+			imageRefs = append(imageRefs, imageRepo+":"+tag)
 		}
 	}
 
 	// tag the image
 	for _, imageRef := range imageRefs {
 		if err := dockerDriver.TagDevContainer(ctx, prebuildImage, imageRef); err != nil {
-			return "", errors.Wrap(err, "tag image")
+			return "", "", errors.Wrap(err, "tag image")
 		}
 	}
 
 	// push the image to the registry
 	for _, imageRef := range imageRefs {
 		if err := dockerDriver.PushDevContainer(ctx, imageRef); err != nil {
-			return "", errors.Wrap(err, "push image")
+			return "", "", errors.Wrap(err, "push image")
 		}
 	}
 
-	return prebuildImage, nil
+	err = r.pushPrebuildManifest(ctx, buildInfo, prebuildImage)
+	if err != nil {
+		r.Log.Warnf("Error pushing prebuild hash manifest for image %s: %v", prebuildImage, err)
+	}
+
+	err = r.pushAttestations(ctx, options, substitutedConfig, buildInfo, prebuildImage)
+	if err != nil {
+		return "", "", err
+	}
+
+	return prebuildImage, prebuildIndexImage, nil
+}
+
+// pushPrebuildManifest pushes the hashed prebuild hash manifest with the checksums of all context files as referrer
+// of the prebuild image, so build diff can compare single files. Failing to push it doesn't fail the prebuild, build
+// diff falls back to the aggregate hashes within the image label.
+func (r *runner) pushPrebuildManifest(ctx context.Context, buildInfo *config.BuildInfo, prebuildImage string) error {
+	if buildInfo.PrebuildManifest == nil {
+		return nil
+	}
+
+	rawManifest, err := json.Marshal(buildInfo.PrebuildManifest.Hashed())
+	if err != nil {
+		return err
+	}
+
+	subject, err := image.GetDescriptor(ctx, prebuildImage)
+	if err != nil {
+		return err
+	}
+
+	err = image.PushReferrer(ctx, prebuildImage, *subject, config.PrebuildManifestArtifactType, types.MediaType(config.PrebuildManifestArtifactType), rawManifest, nil)
+	if err != nil {
+		return err
+	}
+
+	r.Log.Debugf("Pushed prebuild hash manifest for image %s", prebuildImage)
+	return nil
+}
+
+func (r *runner) explainPrebuild(buildInfo *config.BuildInfo, platform string) {
+	if buildInfo.PrebuildManifest == nil {
+		r.Log.Infof("No prebuild hash is calculated for this dev container, image %s is used without building", buildInfo.ImageName)
+		return
+	}
+
+	if platform != "" {
+		r.Log.Infof("Prebuild hash inputs for platform %s:", platform)
+	} else {
+		r.Log.Infof("Prebuild hash inputs:")
+	}
+	writer := r.Log.Writer(logrus.InfoLevel, false)
+	buildInfo.PrebuildManifest.Explain(writer)
+	_ = writer.Close()
+
+	if buildInfo.ImageName != "" {
+		r.Log.Infof("Found existing prebuild image %s", buildInfo.ImageName)
+	} else {
+		r.Log.Infof("No existing prebuild image %s found, building would be required", buildInfo.PrebuildHash)
+	}
+}
+
+// imageRepository strips the tag from the image name. Only a colon after the last slash separates the tag, others
+// belong to a registry port as in localhost:5000/image.
+func imageRepository(image string) string {
+	tagIndex := strings.LastIndex(image, ":")
+	if tagIndex == -1 || tagIndex < strings.LastIndex(image, "/") {
+		return image
+	}
+
+	return image[:tagIndex]
 }
 
 func getPrebuildRepository(substitutedConfig *config.SubstitutedConfig) string {
@@ -0,0 +1,25 @@
+package devcontainer
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestImageRepository(t *testing.T) {
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{image: "ghcr.io/my-org/my-repo:devspace-abc", want: "ghcr.io/my-org/my-repo"},
+		{image: "localhost:5000/my-repo:devspace-abc", want: "localhost:5000/my-repo"},
+		{image: "localhost:5000/my-repo", want: "localhost:5000/my-repo"},
+		{image: "my-repo", want: "my-repo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			assert.Equal(t, imageRepository(tt.image), tt.want)
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package devcontainer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"dev.khulnasoft.com/pkg/devcontainer/config"
+	"dev.khulnasoft.com/pkg/git"
+	"dev.khulnasoft.com/pkg/image"
+	"dev.khulnasoft.com/pkg/provider"
+	"dev.khulnasoft.com/pkg/util/hash"
+	"dev.khulnasoft.com/pkg/version"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	inTotoStatementType     = "https://in-toto.io/Statement/v1"
+	provenancePredicateType = "https://slsa.dev/provenance/v1"
+	provenanceBuildType     = "https://dev.khulnasoft.com/devspace/devcontainer-build/v1"
+	provenanceBuilderID     = "https://dev.khulnasoft.com/devspace"
+)
+
+type inTotoStatement struct {
+	Type          string               `json:"_type"`
+	Subject       []provenanceResource `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     provenancePredicate  `json:"predicate"`
+}
+
+type provenancePredicate struct {
+	BuildDefinition provenanceBuildDefinition `json:"buildDefinition"`
+	RunDetails      provenanceRunDetails      `json:"runDetails"`
+}
+
+type provenanceBuildDefinition struct {
+	BuildType            string                   `json:"buildType"`
+	ExternalParameters   provenanceParameters     `json:"externalParameters"`
+	InternalParameters   provenanceInternalParams `json:"internalParameters"`
+	ResolvedDependencies []provenanceResource     `json:"resolvedDependencies,omitempty"`
+}
+
+type provenanceParameters struct {
+	Source       string             `json:"source,omitempty"`
+	DevContainer provenanceResource `json:"devcontainer"`
+	Platform     string             `json:"platform,omitempty"`
+}
+
+type provenanceInternalParams struct {
+	PrebuildHash string `json:"prebuildHash,omitempty"`
+}
+
+type provenanceRunDetails struct {
+	Builder provenanceBuilder `json:"builder"`
+}
+
+type provenanceBuilder struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version,omitempty"`
+}
+
+// provenanceResource is a SLSA resource descriptor
+type provenanceResource struct {
+	Name        string            `json:"name,omitempty"`
+	URI         string            `json:"uri,omitempty"`
+	Digest      map[string]string `json:"digest,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// gitSource is the git source the dev container is built from
+type gitSource struct {
+	Repository string
+	Branch     string
+	Commit     string
+}
+
+// pushAttestations pushes the SBOMs and the provenance of the given prebuild image as OCI referrers
+func (r *runner) pushAttestations(ctx context.Context, options provider.BuildOptions, substitutedConfig *config.SubstitutedConfig, buildInfo *config.BuildInfo, prebuildImage string) error {
+	if options.SBOM != "" {
+		pushed, err := image.PushSBOMReferrers(ctx, prebuildImage)
+		if err != nil {
+			return errors.Wrap(err, "push sbom")
+		} else if pushed == 0 {
+			r.Log.Warnf("No SBOM attestations found for image %s, make sure the builder supports attestations", prebuildImage)
+		} else {
+			r.Log.Infof("Pushed %d SBOM(s) for image %s", pushed, prebuildImage)
+		}
+	}
+
+	if options.Provenance {
+		subject, err := image.GetDescriptor(ctx, prebuildImage)
+		if err != nil {
+			return err
+		}
+
+		statement, err := newProvenanceStatement(
+			prebuildImage,
+			subject.Digest,
+			r.getGitSource(ctx),
+			substitutedConfig.Config.Origin,
+			r.LocalWorkspaceFolder,
+			buildInfo.Features,
+			buildInfo.PrebuildHash,
+			options.Platform,
+		)
+		if err != nil {
+			return errors.Wrap(err, "create provenance")
+		}
+
+		err = image.PushReferrer(ctx, prebuildImage, *subject, image.InTotoMediaType, image.InTotoMediaType, statement, map[string]string{
+			image.InTotoPredicateTypeAnnotation: provenancePredicateType,
+		})
+		if err != nil {
+			return errors.Wrap(err, "push provenance")
+		}
+		r.Log.Infof("Pushed provenance for image %s", prebuildImage)
+	}
+
+	return nil
+}
+
+// getGitSource returns the git source of the workspace, falling back to the local git repository
+func (r *runner) getGitSource(ctx context.Context) gitSource {
+	source := gitSource{}
+	if r.WorkspaceConfig != nil && r.WorkspaceConfig.Workspace != nil {
+		source.Repository = r.WorkspaceConfig.Workspace.Source.GitRepository
+		source.Branch = r.WorkspaceConfig.Workspace.Source.GitBranch
+		source.Commit = r.WorkspaceConfig.Workspace.Source.GitCommit
+	}
+
+	if source.Repository == "" {
+		source.Repository = r.gitOutput(ctx, "config", "--get", "remote.origin.url")
+	}
+	if source.Commit == "" {
+		source.Commit = r.gitOutput(ctx, "rev-parse", "HEAD")
+	}
+	if source.Branch == "" && source.Commit != "" {
+		// a detached HEAD has no branch
+		if branch := r.gitOutput(ctx, "rev-parse", "--abbrev-ref", "HEAD"); branch != "HEAD" {
+			source.Branch = branch
+		}
+	}
+
+	return source
+}
+
+func (r *runner) gitOutput(ctx context.Context, args ...string) string {
+	out, err := git.CommandContext(ctx, nil, append([]string{"-C", r.LocalWorkspaceFolder}, args...)...).Output()
+	if err != nil {
+		r.Log.Debugf("Error running git %s: %v", strings.Join(args, " "), err)
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+func newProvenanceStatement(
+	imageName string,
+	digest v1.Hash,
+	source gitSource,
+	devContainerPath string,
+	localWorkspaceFolder string,
+	features []*config.FeatureSet,
+	prebuildHash string,
+	platform string,
+) ([]byte, error) {
+	rawDevContainer, err := os.ReadFile(devContainerPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "read devcontainer.json")
+	}
+	devContainerHash := sha256.Sum256(rawDevContainer)
+	ref, err := name.ParseReference(imageName)
+	if err != nil {
+		return nil, err
+	}
+	if relPath, err := filepath.Rel(localWorkspaceFolder, devContainerPath); err == nil && !strings.HasPrefix(relPath, "..") {
+		devContainerPath = filepath.ToSlash(relPath)
+	}
+
+	statement := inTotoStatement{
+		Type: inTotoStatementType,
+		Subject: []provenanceResource{{
+			Name:   ref.Context().Name(),
+			Digest: map[string]string{digest.Algorithm: digest.Hex},
+		}},
+		PredicateType: provenancePredicateType,
+		Predicate: provenancePredicate{
+			BuildDefinition: provenanceBuildDefinition{
+				BuildType: provenanceBuildType,
+				ExternalParameters: provenanceParameters{
+					DevContainer: provenanceResource{
+						URI:    devContainerPath,
+						Digest: map[string]string{"sha256": hex.EncodeToString(devContainerHash[:])},
+					},
+					Platform: platform,
+				},
+				InternalParameters: provenanceInternalParams{
+					PrebuildHash: prebuildHash,
+				},
+			},
+			RunDetails: provenanceRunDetails{
+				Builder: provenanceBuilder{
+					ID:      provenanceBuilderID,
+					Version: map[string]string{"devspace": version.GetVersion()},
+				},
+			},
+		},
+	}
+
+	// source repository
+	if source.Repository != "" {
+		sourceURI := "git+" + source.Repository
+		if source.Branch != "" {
+			sourceURI += "@refs/heads/" + source.Branch
+		}
+
+		sourceResource := provenanceResource{URI: sourceURI}
+		if source.Commit != "" {
+			sourceResource.Digest = map[string]string{"gitCommit": source.Commit}
+		}
+
+		statement.Predicate.BuildDefinition.ExternalParameters.Source = sourceURI
+		statement.Predicate.BuildDefinition.ResolvedDependencies = append(statement.Predicate.BuildDefinition.ResolvedDependencies, sourceResource)
+	}
+
+	// features are recorded with the digest of their contents
+	for _, feature := range features {
+		featureHash, err := hash.DirectoryHash(feature.Folder, nil, []string{""})
+		if err != nil {
+			return nil, errors.Wrapf(err, "hash feature %s", feature.ConfigID)
+		}
+
+		featureResource := provenanceResource{
+			URI:    feature.ConfigID,
+			Digest: map[string]string{"sha256": featureHash},
+		}
+		if feature.Config != nil && feature.Config.Version != "" {
+			featureResource.Annotations = map[string]string{"version": feature.Config.Version}
+		}
+		statement.Predicate.BuildDefinition.ResolvedDependencies = append(statement.Predicate.BuildDefinition.ResolvedDependencies, featureResource)
+	}
+
+	return json.Marshal(statement)
+}
@@ -0,0 +1,62 @@
+package devcontainer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"dev.khulnasoft.com/pkg/devcontainer/config"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"gotest.tools/assert"
+)
+
+func TestNewProvenanceStatement(t *testing.T) {
+	workspaceFolder := t.TempDir()
+	devContainerPath := filepath.Join(workspaceFolder, ".devcontainer", "devcontainer.json")
+	assert.NilError(t, os.MkdirAll(filepath.Dir(devContainerPath), 0755))
+	assert.NilError(t, os.WriteFile(devContainerPath, []byte(`{"image":"ubuntu"}`), 0600))
+
+	featureFolder := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(featureFolder, "install.sh"), []byte("echo hello"), 0600))
+
+	digest := v1.Hash{Algorithm: "sha256", Hex: "abc"}
+	raw, err := newProvenanceStatement(
+		"ghcr.io/my-org/repo:devspace-abc",
+		digest,
+		gitSource{Repository: "https://github.com/my-org/repo", Branch: "main", Commit: "1234"},
+		devContainerPath,
+		workspaceFolder,
+		[]*config.FeatureSet{{
+			ConfigID: "ghcr.io/devcontainers/features/go:1",
+			Folder:   featureFolder,
+			Config:   &config.FeatureConfig{Version: "1.3.1"},
+		}},
+		"devspace-abc",
+		"linux/amd64",
+	)
+	assert.NilError(t, err)
+
+	statement := &inTotoStatement{}
+	assert.NilError(t, json.Unmarshal(raw, statement))
+	assert.Equal(t, statement.PredicateType, provenancePredicateType)
+	assert.DeepEqual(t, statement.Subject, []provenanceResource{{
+		Name:   "ghcr.io/my-org/repo",
+		Digest: map[string]string{"sha256": "abc"},
+	}})
+
+	buildDefinition := statement.Predicate.BuildDefinition
+	assert.Equal(t, buildDefinition.ExternalParameters.Source, "git+https://github.com/my-org/repo@refs/heads/main")
+	assert.Equal(t, buildDefinition.ExternalParameters.DevContainer.URI, ".devcontainer/devcontainer.json")
+	assert.Equal(t, buildDefinition.ExternalParameters.DevContainer.Digest["sha256"], "b9064358fb75a6ddf2fd6f04e2e75ce010110df0c33f609cea6d5946455b2a8a")
+	assert.Equal(t, buildDefinition.ExternalParameters.Platform, "linux/amd64")
+	assert.Equal(t, buildDefinition.InternalParameters.PrebuildHash, "devspace-abc")
+	assert.Equal(t, len(buildDefinition.ResolvedDependencies), 2)
+	assert.DeepEqual(t, buildDefinition.ResolvedDependencies[0], provenanceResource{
+		URI:    "git+https://github.com/my-org/repo@refs/heads/main",
+		Digest: map[string]string{"gitCommit": "1234"},
+	})
+	assert.Equal(t, buildDefinition.ResolvedDependencies[1].URI, "ghcr.io/devcontainers/features/go:1")
+	assert.Equal(t, buildDefinition.ResolvedDependencies[1].Annotations["version"], "1.3.1")
+	assert.Assert(t, buildDefinition.ResolvedDependencies[1].Digest["sha256"] != "")
+}
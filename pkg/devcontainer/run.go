@@ -26,6 +26,8 @@ type Runner interface {
 
 	Build(ctx context.Context, options provider2.BuildOptions) (string, error)
 
+	BuildPlatforms(ctx context.Context, options provider2.BuildOptions, platforms []string) ([]string, error)
+
 	Find(ctx context.Context) (*config.ContainerDetails, error)
 
 	Command(
@@ -87,6 +89,9 @@ type UpOptions struct {
 	NoBuild       bool
 	ForceBuild    bool
 	RegistryCache string
+
+	// Sidecars are the compose services to run next to the devcontainer
+	Sidecars []*driver.Sidecar
 }
 
 func (r *runner) Up(ctx context.Context, options UpOptions, timeout time.Duration) (*config.Result, error) {
@@ -118,6 +123,8 @@ func (r *runner) Up(ctx context.Context, options UpOptions, timeout time.Duratio
 			options,
 			timeout,
 		)
+	case isDockerComposeConfig(substitutedConfig.Config) && r.canRunComposeSidecars():
+		return r.runComposeSidecars(ctx, substitutedConfig, substitutionContext, options, timeout)
 	case isDockerComposeConfig(substitutedConfig.Config):
 		return r.runDockerCompose(ctx, substitutedConfig, substitutionContext, options, timeout)
 	default:
@@ -19,8 +19,10 @@ import (
 	"dev.khulnasoft.com/pkg/devcontainer/sshtunnel"
 	"dev.khulnasoft.com/pkg/driver"
 	"dev.khulnasoft.com/pkg/ide"
+	"dev.khulnasoft.com/pkg/ide/vscode"
 	provider2 "dev.khulnasoft.com/pkg/provider"
 	"dev.khulnasoft.com/log"
+	"github.com/alessio/shellescape"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
@@ -123,6 +125,14 @@ func (r *runner) setupContainer(
 		setupCommand += " --debug"
 	}
 
+	// allow the agent to copy the local VSIX folder into the container
+	mounts := config.GetMounts(result)
+	vsixMount := r.prepareVSIXFolder(mergedConfig)
+	if vsixMount != nil {
+		mounts = append(mounts, vsixMount)
+		setupCommand += " --stream-vsix-folder " + shellescape.Quote(vsixMount.Source)
+	}
+
 	// run setup server
 	runSetupServer := func(ctx context.Context, stdin io.WriteCloser, stdout io.Reader) (*config.Result, error) {
 		return tunnelserver.RunSetupServer(
@@ -131,7 +141,7 @@ func (r *runner) setupContainer(
 			stdin,
 			r.WorkspaceConfig.Agent.InjectGitCredentials != "false",
 			r.WorkspaceConfig.Agent.InjectDockerCredentials != "false",
-			config.GetMounts(result),
+			mounts,
 			r.Log,
 			tunnelserver.WithPlatformOptions(&r.WorkspaceConfig.CLIOptions.Platform),
 		)
@@ -161,6 +171,32 @@ func (r *runner) setupContainer(
 	)
 }
 
+// prepareVSIXFolder fills the local VSIX folder with the extensions of the devcontainer.json from the extensions
+// gallery and returns the mount the agent uses to copy it into the container
+func (r *runner) prepareVSIXFolder(mergedConfig *config.MergedDevContainerConfig) *config.Mount {
+	ideOptions := r.WorkspaceConfig.Workspace.IDE.Options
+	folder, err := vscode.GetLocalVSIXFolder(ideOptions)
+	if err != nil {
+		r.Log.Warnf("Error resolving VSIX folder: %v", err)
+		return nil
+	} else if folder == "" {
+		return nil
+	}
+
+	extensions := config.GetVSCodeConfiguration(mergedConfig).Extensions
+	err = vscode.PrepareVSIXFolder(folder, vscode.Options.GetValue(ideOptions, vscode.ExtensionsGalleryOption), extensions, r.Log)
+	if err != nil {
+		r.Log.Warnf("Error preparing VSIX folder %s: %v", folder, err)
+	}
+
+	if _, err := os.Stat(folder); err != nil {
+		r.Log.Warnf("Skip copying VSIX folder %s: %v", folder, err)
+		return nil
+	}
+
+	return vscode.VSIXMount(folder)
+}
+
 func getRelativeDevContainerJson(origin, localWorkspaceFolder string) string {
 	relativePath := strings.TrimPrefix(filepath.ToSlash(origin), filepath.ToSlash(localWorkspaceFolder))
 	return strings.TrimPrefix(relativePath, "/")
@@ -97,6 +97,9 @@ func (r *runner) runSingleContainer(
 				PrebuildRepositories: options.PrebuildRepositories,
 				ForceDockerless:      options.ForceDockerless,
 				Platform:             options.CLIOptions.Platform,
+				// a rebase skips prebuilds and cached images to build on the latest base image
+				ForceBuild: options.Rebase,
+				Rebase:     options.Rebase,
 			},
 			NoBuild:       options.NoBuild,
 			RegistryCache: options.RegistryCache,
@@ -140,7 +143,7 @@ func (r *runner) runSingleContainer(
 		}
 
 		// run dev container
-		err = r.runContainer(ctx, parsedConfig, substitutionContext, mergedConfig, buildInfo)
+		err = r.runContainer(ctx, parsedConfig, substitutionContext, mergedConfig, buildInfo, options.Sidecars)
 		if err != nil {
 			return nil, errors.Wrap(err, "start dev container")
 		}
@@ -164,6 +167,7 @@ func (r *runner) runContainer(
 	substitutionContext *config.SubstitutionContext,
 	mergedConfig *config.MergedDevContainerConfig,
 	buildInfo *config.BuildInfo,
+	sidecars []*driver.Sidecar,
 ) error {
 	var err error
 
@@ -183,11 +187,12 @@ func (r *runner) runContainer(
 	}
 
 	runOptions.Env = r.addExtraEnvVars(runOptions.Env)
+	runOptions.Sidecars = sidecars
 
 	// check if docker
 	dockerDriver, ok := r.Driver.(driver.DockerDriver)
 	if ok {
-		return dockerDriver.RunDockerDevContainer(
+		err = dockerDriver.RunDockerDevContainer(
 			ctx,
 			r.ID,
 			runOptions,
@@ -196,10 +201,16 @@ func (r *runner) runContainer(
 			r.WorkspaceConfig.Workspace.IDE.Name,
 			r.WorkspaceConfig.Workspace.IDE.Options,
 		)
+	} else {
+		// build run options for regular driver
+		err = r.Driver.RunDevContainer(ctx, r.ID, runOptions)
+	}
+	if err != nil {
+		return err
 	}
 
-	// build run options for regular driver
-	return r.Driver.RunDevContainer(ctx, r.ID, runOptions)
+	r.fillIDECache(ctx, runOptions.Image, config.GetVSCodeConfiguration(mergedConfig).Extensions)
+	return nil
 }
 
 func (r *runner) getDockerlessRunOptions(
@@ -300,6 +311,17 @@ func (r *runner) getRunOptions(
 		metadata.ImageMetadataLabel + "=" + string(marshalled),
 		config.UserLabel + "=" + buildInfo.ImageDetails.Config.User,
 	}
+	if buildInfo.PrebuildHash != "" {
+		labels = append(labels, config.PrebuildHashLabel+"="+buildInfo.PrebuildHash)
+	}
+	if len(buildInfo.Sources) > 0 {
+		rawSources, err := json.Marshal(buildInfo.Sources)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshal image sources")
+		}
+
+		labels = append(labels, config.ImageSourcesLabel+"="+string(rawSources))
+	}
 
 	user := buildInfo.ImageDetails.Config.User
 	if mergedConfig.ContainerUser != "" {
@@ -5,6 +5,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"maps"
+	"slices"
 	"strings"
 
 	"dev.khulnasoft.com/pkg/devcontainer/build"
@@ -12,7 +14,9 @@ import (
 	"dev.khulnasoft.com/pkg/devcontainer/config"
 	"dev.khulnasoft.com/pkg/devcontainer/feature"
 	"dev.khulnasoft.com/pkg/docker"
+	"dev.khulnasoft.com/pkg/image"
 	"dev.khulnasoft.com/pkg/provider"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
@@ -59,6 +63,18 @@ func (d *dockerDriver) BuildDevContainer(
 	}
 	d.Log.Debug("Using registry cache", options.RegistryCache)
 
+	// sbom attestations would be dropped when loading the image into docker, so buildx pushes the image directly
+	if buildOptions.SBOM != "" && options.Prebuild && !options.SkipPush {
+		buildOptions.PushImages = build.GetPrebuildImages(parsedConfig, options, prebuildHash)
+		if len(buildOptions.PushImages) == 0 {
+			return nil, fmt.Errorf("repository needs to be specified to push sbom attestations")
+		}
+
+		// buildx can't load and push with the same build, the pushed image is inspected within the registry instead
+		buildOptions.Load = false
+		imageName = buildOptions.PushImages[0]
+	}
+
 	// build image
 	writer := d.Log.Writer(logrus.InfoLevel, false)
 	defer writer.Close()
@@ -88,6 +104,10 @@ func (d *dockerDriver) BuildDevContainer(
 			return nil, fmt.Errorf("buildx is not available on your host. Use buildkit builder")
 		}
 	case docker.DockerBuilderBuildKit:
+		if buildOptions.SBOM != "" {
+			return nil, fmt.Errorf("sbom attestations require docker buildx")
+		}
+
 		d.Log.Info("Build with internal buildkit...")
 		err := d.internalBuild(ctx, writer, options.Platform, buildOptions)
 		if err != nil {
@@ -98,7 +118,12 @@ func (d *dockerDriver) BuildDevContainer(
 	}
 
 	// inspect image
-	imageDetails, err := d.Docker.InspectImage(ctx, imageName, false)
+	var imageDetails *config.ImageDetails
+	if len(buildOptions.PushImages) > 0 {
+		imageDetails, err = d.getPushedImageDetails(ctx, imageName, options.Platform)
+	} else {
+		imageDetails, err = d.Docker.InspectImage(ctx, imageName, false)
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "get image details")
 	}
@@ -110,6 +135,45 @@ func (d *dockerDriver) BuildDevContainer(
 		PrebuildHash:  prebuildHash,
 		RegistryCache: options.RegistryCache,
 		Tags:          options.Tag,
+		Pushed:        len(buildOptions.PushImages) > 0,
+	}, nil
+}
+
+func (d *dockerDriver) getPushedImageDetails(ctx context.Context, imageName, platform string) (*config.ImageDetails, error) {
+	arch := ""
+	if platform != "" {
+		parsedPlatform, err := v1.ParsePlatform(platform)
+		if err != nil {
+			return nil, err
+		}
+		arch = parsedPlatform.Architecture
+	} else {
+		targetArch, err := d.TargetArchitecture(ctx, "")
+		if err != nil {
+			return nil, err
+		}
+		arch = targetArch
+	}
+
+	img, err := image.GetImageForArch(ctx, imageName, arch)
+	if err != nil {
+		return nil, err
+	}
+
+	imageConfig, err := img.ConfigFile()
+	if err != nil {
+		return nil, errors.Wrap(err, "config file")
+	}
+
+	return &config.ImageDetails{
+		ID: imageName,
+		Config: config.ImageDetailsConfig{
+			User:       imageConfig.Config.User,
+			Env:        imageConfig.Config.Env,
+			Labels:     imageConfig.Config.Labels,
+			Entrypoint: imageConfig.Config.Entrypoint,
+			Cmd:        imageConfig.Config.Cmd,
+		},
 	}, nil
 }
 
@@ -142,6 +206,19 @@ func (d *dockerDriver) internalBuild(ctx context.Context, writer io.Writer, plat
 }
 
 func (d *dockerDriver) buildxBuild(ctx context.Context, writer io.Writer, platform string, options *build.BuildOptions) error {
+	args := getBuildxArgs(platform, options)
+
+	// run command
+	d.Log.Debugf("Running docker %s: docker %s", d.Docker.DockerCommand, strings.Join(args, " "))
+	err := d.Docker.Run(ctx, args, nil, writer, writer)
+	if err != nil {
+		return errors.Wrap(err, "build image")
+	}
+
+	return nil
+}
+
+func getBuildxArgs(platform string, options *build.BuildOptions) []string {
 	// build args
 	args := []string{
 		"buildx",
@@ -154,16 +231,36 @@ func (d *dockerDriver) buildxBuild(ctx context.Context, writer io.Writer, platfo
 		args = append(args, "--load")
 	}
 
+	// always pull base images
+	if options.Pull {
+		args = append(args, "--pull")
+	}
+
 	// docker images
 	for _, image := range options.Images {
 		args = append(args, "-t", image)
 	}
 
+	// push directly to keep attestations
+	if len(options.PushImages) > 0 {
+		args = append(args, "--output", fmt.Sprintf("type=image,\"name=%s\",push=true", strings.Join(options.PushImages, ",")))
+	}
+
+	// attestations
+	if options.SBOM != "" {
+		args = append(args, "--attest", "type=sbom,"+options.SBOM)
+	}
+
 	// build args
 	for k, v := range options.BuildArgs {
 		args = append(args, "--build-arg", k+"="+v)
 	}
 
+	// labels
+	for _, k := range slices.Sorted(maps.Keys(options.Labels)) {
+		args = append(args, "--label", k+"="+options.Labels[k])
+	}
+
 	// build contexts
 	for k, v := range options.Contexts {
 		args = append(args, "--build-context", k+"="+v)
@@ -191,14 +288,5 @@ func (d *dockerDriver) buildxBuild(ctx context.Context, writer io.Writer, platfo
 	args = append(args, options.CliOpts...)
 
 	// context
-	args = append(args, options.Context)
-
-	// run command
-	d.Log.Debugf("Running docker %s: docker %s", d.Docker.DockerCommand, strings.Join(args, " "))
-	err := d.Docker.Run(ctx, args, nil, writer, writer)
-	if err != nil {
-		return errors.Wrap(err, "build image")
-	}
-
-	return nil
+	return append(args, options.Context)
 }
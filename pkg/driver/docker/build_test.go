@@ -0,0 +1,97 @@
+package docker
+
+import (
+	"testing"
+
+	"dev.khulnasoft.com/pkg/devcontainer/build"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetBuildxArgs(t *testing.T) {
+	tests := []struct {
+		name         string
+		buildOptions *build.BuildOptions
+		platform     string
+		want         []string
+	}{
+		{
+			name: "load image",
+			buildOptions: &build.BuildOptions{
+				Dockerfile: "/workspace/.devcontainer/Dockerfile",
+				Context:    "/workspace/.devcontainer",
+				Images:     []string{"devspace-test:devspace-abc"},
+				Load:       true,
+			},
+			want: []string{
+				"buildx", "build",
+				"-f", "/workspace/.devcontainer/Dockerfile",
+				"--load",
+				"-t", "devspace-test:devspace-abc",
+				"/workspace/.devcontainer",
+			},
+		},
+		{
+			name: "push with sbom",
+			buildOptions: &build.BuildOptions{
+				Dockerfile: "/workspace/.devcontainer/Dockerfile",
+				Context:    "/workspace/.devcontainer",
+				Images:     []string{"devspace-test:devspace-abc"},
+				PushImages: []string{"ghcr.io/my-org/repo:devspace-abc", "ghcr.io/my-org/repo:latest"},
+				SBOM:       "generator=docker/buildkit-syft-scanner:stable-1",
+			},
+			want: []string{
+				"buildx", "build",
+				"-f", "/workspace/.devcontainer/Dockerfile",
+				"-t", "devspace-test:devspace-abc",
+				"--output", `type=image,"name=ghcr.io/my-org/repo:devspace-abc,ghcr.io/my-org/repo:latest",push=true`,
+				"--attest", "type=sbom,generator=docker/buildkit-syft-scanner:stable-1",
+				"/workspace/.devcontainer",
+			},
+		},
+		{
+			name: "labels and build options",
+			buildOptions: &build.BuildOptions{
+				Dockerfile: "/workspace/.devspace-internal/Dockerfile-with-features",
+				Context:    "/workspace",
+				Images:     []string{"devspace-test:devspace-abc"},
+				Target:     "dev",
+				BuildArgs:  map[string]string{"A": "1"},
+				Labels: map[string]string{
+					"devcontainer.metadata":   "[]",
+					"dev.khulnasoft.prebuild": "{}",
+				},
+				Contexts:  map[string]string{"features": "/tmp/features"},
+				CacheFrom: []string{"type=registry,ref=ghcr.io/my-org/cache"},
+				CliOpts:   []string{"--network", "host"},
+				Load:      true,
+				Pull:      true,
+			},
+			platform: "linux/arm64",
+			want: []string{
+				"buildx", "build",
+				"-f", "/workspace/.devspace-internal/Dockerfile-with-features",
+				"--load",
+				"--pull",
+				"-t", "devspace-test:devspace-abc",
+				"--build-arg", "A=1",
+				"--label", "dev.khulnasoft.prebuild={}",
+				"--label", "devcontainer.metadata=[]",
+				"--build-context", "features=/tmp/features",
+				"--target", "dev",
+				"--platform", "linux/arm64",
+				"--cache-from", "type=registry,ref=ghcr.io/my-org/cache",
+				"--network", "host",
+				"/workspace",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getBuildxArgs(tt.platform, tt.buildOptions)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("getBuildxArgs() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"dev.khulnasoft.com/pkg/devcontainer/config"
 	"dev.khulnasoft.com/pkg/docker"
 	"dev.khulnasoft.com/pkg/driver"
+	ide2 "dev.khulnasoft.com/pkg/ide"
 	"dev.khulnasoft.com/pkg/ide/jetbrains"
 	provider2 "dev.khulnasoft.com/pkg/provider"
 	"dev.khulnasoft.com/log"
@@ -60,7 +61,8 @@ func NewDockerDriver(workspaceInfo *provider2.AgentWorkspaceInfo, log log.Logger
 			Builder:       builder,
 			Log:           log,
 		},
-		Log: log,
+		IDECache: workspaceInfo.Agent.Docker.IDECacheEnabled(),
+		Log:      log,
 	}, nil
 }
 
@@ -68,6 +70,9 @@ type dockerDriver struct {
 	Docker  *docker.DockerHelper
 	Compose *compose.ComposeHelper
 
+	// IDECache mounts the ide cache volume shared by all workspaces
+	IDECache bool
+
 	Log log.Logger
 }
 
@@ -312,6 +317,9 @@ func (d *dockerDriver) RunDockerDevContainer(
 	}
 
 	// add ide mounts
+	if d.IDECache {
+		args = append(args, "--mount", fmt.Sprintf("type=volume,src=%s,dst=%s,readonly", ide2.CacheVolume, ide2.CacheFolder))
+	}
 	switch ide {
 	case string(config2.IDEGoland):
 		args = append(args, "--mount", jetbrains.NewGolandServer("", ideOptions, d.Log).GetVolume())
@@ -0,0 +1,68 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"dev.khulnasoft.com/pkg/ide"
+)
+
+func (d *dockerDriver) IDECacheEnabled() bool {
+	return d.IDECache
+}
+
+// FillIDECache stores the downloads that are missing in the ide cache volume. The workspace containers mount the
+// volume read-only, so the downloads are extracted by a short-lived container of the given image instead.
+func (d *dockerDriver) FillIDECache(ctx context.Context, workspaceId, image string, downloads []string) error {
+	listing := &bytes.Buffer{}
+	err := d.runIDECacheContainer(ctx, image, "ls "+ide.CacheFolder+"/urls 2>/dev/null || true", nil, listing)
+	if err != nil {
+		return fmt.Errorf("list ide cache: %w", err)
+	}
+
+	downloads = ide.UncachedDownloads(downloads, listing.String())
+	if len(downloads) == 0 {
+		return nil
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		_ = writer.CloseWithError(ide.WriteCacheArchive(writer, downloads, d.Log))
+	}()
+	defer reader.Close()
+
+	err = d.runIDECacheContainer(ctx, image, "tar xf - -C "+ide.CacheFolder, reader, io.Discard)
+	if err != nil {
+		return fmt.Errorf("fill ide cache: %w", err)
+	}
+
+	return nil
+}
+
+func (d *dockerDriver) runIDECacheContainer(ctx context.Context, image, command string, stdin io.Reader, stdout io.Writer) error {
+	stderr := &bytes.Buffer{}
+	err := d.Docker.Run(ctx, getIDECacheArgs(image, command, stdin != nil), stdin, stdout, stderr)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+func getIDECacheArgs(image, command string, stdin bool) []string {
+	args := []string{"run", "--rm"}
+	if stdin {
+		args = append(args, "-i")
+	}
+
+	return append(args,
+		"-u", "root",
+		"--entrypoint", "sh",
+		"--mount", fmt.Sprintf("type=volume,src=%s,dst=%s", ide.CacheVolume, ide.CacheFolder),
+		image,
+		"-c", command,
+	)
+}
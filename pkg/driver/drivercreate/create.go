@@ -13,6 +13,9 @@ import (
 
 func NewDriver(workspaceInfo *provider2.AgentWorkspaceInfo, log log.Logger) (driver.Driver, error) {
 	driver := workspaceInfo.Agent.Driver
+	if workspaceInfo.Workspace != nil && workspaceInfo.Workspace.Source.KubernetesWorkload != "" && driver != provider2.KubernetesDriver {
+		return nil, fmt.Errorf("workspace source %s requires a provider with the %s driver", workspaceInfo.Workspace.Source.String(), provider2.KubernetesDriver)
+	}
 	if driver == "" || driver == provider2.DockerDriver {
 		return docker.NewDockerDriver(workspaceInfo, log)
 	} else if driver == provider2.CustomDriver {
@@ -0,0 +1,529 @@
+package kubernetes
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"dev.khulnasoft.com/pkg/devcontainer/build"
+	"dev.khulnasoft.com/pkg/devcontainer/config"
+	"dev.khulnasoft.com/pkg/devcontainer/feature"
+	"dev.khulnasoft.com/pkg/driver/kubernetes/throttledlogger"
+	"dev.khulnasoft.com/pkg/extract"
+	"dev.khulnasoft.com/pkg/image"
+	provider2 "dev.khulnasoft.com/pkg/provider"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	perrors "github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const BuildContainerName = "buildkit"
+
+const DefaultBuildkitImage = "moby/buildkit:rootless"
+
+const (
+	// buildLogTailLines is how many lines of the build output are shown if the build fails
+	buildLogTailLines = 30
+
+	buildContextPath = "/devspace"
+	buildReadyFile   = buildContextPath + "/.ready"
+	buildDockerPath  = "/home/user/.docker"
+)
+
+// BuildDevContainer builds the devcontainer image within a rootless BuildKit pod and pushes it to the prebuild repository
+func (k *KubernetesDriver) BuildDevContainer(
+	ctx context.Context,
+	prebuildHash string,
+	parsedConfig *config.SubstitutedConfig,
+	extendedBuildInfo *feature.ExtendedBuildInfo,
+	dockerfilePath,
+	dockerfileContent string,
+	localWorkspaceFolder string,
+	options provider2.BuildOptions,
+) (*config.BuildInfo, error) {
+	if options.NoBuild {
+		return nil, fmt.Errorf("you cannot build in this mode. Please run 'devspace up' to rebuild the container")
+	} else if k.options.StrictSecurity == "true" {
+		// rootless buildkit needs unconfined seccomp and apparmor profiles to create its own mount namespaces
+		return nil, fmt.Errorf("building within Kubernetes is not supported with STRICT_SECURITY enabled, please build the image locally or disable STRICT_SECURITY for the build")
+	}
+
+	// the image is pushed directly from within the cluster
+	imageName := build.GetImageName(localWorkspaceFolder, prebuildHash)
+	imageNames := build.GetPrebuildImages(parsedConfig, options, prebuildHash)
+	if len(imageNames) > 0 {
+		imageName = imageNames[0]
+	} else if !options.SkipPush {
+		return nil, fmt.Errorf("repository needs to be specified to build within Kubernetes")
+	} else {
+		imageNames = []string{imageName}
+	}
+
+	if !options.SkipPush {
+		err := image.CheckPushPermissions(imageName)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot push to repository %s. Please make sure you are logged into the registry and credentials are available. (Error: %w)",
+				imageName,
+				err,
+			)
+		}
+	}
+
+	buildOptions, err := build.NewOptions(dockerfilePath, dockerfileContent, parsedConfig, extendedBuildInfo, imageName, options, prebuildHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(buildOptions.CliOpts) > 0 {
+		k.Log.Warnf("Build options %s are not supported when building within Kubernetes, will skip", strings.Join(buildOptions.CliOpts, " "))
+	}
+
+	// namespace
+	if k.namespace != "" && k.options.CreateNamespace == "true" {
+		err := k.createNamespace(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	id := getBuildID(prebuildHash)
+	defer k.cleanupBuild(ctx, id)
+
+	// ensure the build pod can push to the registry
+	registrySecretName := ""
+	if !options.SkipPush {
+		created, err := k.EnsurePullSecret(ctx, getPullSecretsName(id), imageName)
+		if err != nil {
+			return nil, err
+		} else if created {
+			registrySecretName = getPullSecretsName(id)
+		}
+	}
+
+	// service account
+	serviceAccount := ""
+	if k.options.ServiceAccount != "" {
+		serviceAccount = k.options.ServiceAccount
+
+		err = k.createServiceAccount(ctx, id, serviceAccount)
+		if err != nil {
+			return nil, fmt.Errorf("create service account: %w", err)
+		}
+	}
+
+	pod, err := k.getBuildPod(id, serviceAccount, registrySecretName, getBuildctlArgs(buildOptions, imageNames, options.Platform, !options.SkipPush))
+	if err != nil {
+		return nil, err
+	}
+
+	// remove a leftover build pod from a previous run
+	err = k.waitPodDeleted(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	k.Log.Infof("Create build pod '%s'", id)
+	_, err = k.client.Client().CoreV1().Pods(k.namespace).Create(ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("create build pod: %w", err)
+	}
+
+	k.Log.Infof("Waiting for build pod '%s' to come up...", id)
+	_, err = k.waitPodRunning(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// upload the build contexts
+	err = k.uploadBuildContext(ctx, id, buildOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	k.Log.Infof("Build %s within Kubernetes...", strings.Join(imageNames, ","))
+	err = k.streamBuildLogs(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.SkipPush {
+		return &config.BuildInfo{
+			ImageDetails:  &config.ImageDetails{ID: imageName},
+			ImageMetadata: extendedBuildInfo.MetadataConfig,
+			ImageName:     imageName,
+			PrebuildHash:  prebuildHash,
+			RegistryCache: options.RegistryCache,
+			Tags:          options.Tag,
+		}, nil
+	}
+
+	imageDetails, err := k.getImageDetails(ctx, imageName, options.Platform)
+	if err != nil {
+		return nil, perrors.Wrap(err, "get image details")
+	}
+
+	return &config.BuildInfo{
+		ImageDetails:  imageDetails,
+		ImageMetadata: extendedBuildInfo.MetadataConfig,
+		ImageName:     imageName,
+		PrebuildHash:  prebuildHash,
+		RegistryCache: options.RegistryCache,
+		Tags:          options.Tag,
+		Pushed:        true,
+	}, nil
+}
+
+func (k *KubernetesDriver) getBuildPod(id, serviceAccount, registrySecretName string, args []string) (*corev1.Pod, error) {
+	labels, err := getLabels(&corev1.Pod{}, k.options.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeSelector, err := getNodeSelector(&corev1.Pod{}, k.options.NodeSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	buildkitImage := k.options.BuildkitImage
+	if buildkitImage == "" {
+		buildkitImage = DefaultBuildkitImage
+	}
+
+	env := []corev1.EnvVar{
+		{
+			Name:  "BUILDKITD_FLAGS",
+			Value: "--oci-worker-no-process-sandbox",
+		},
+	}
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "devspace-build-context",
+			MountPath: buildContextPath,
+		},
+		{
+			Name:      "buildkitd",
+			MountPath: "/home/user/.local/share/buildkit",
+		},
+	}
+	volumes := []corev1.Volume{
+		{
+			Name:         "devspace-build-context",
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		},
+		{
+			Name:         "buildkitd",
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		},
+	}
+	if registrySecretName != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  "DOCKER_CONFIG",
+			Value: buildDockerPath,
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "devspace-registry-auth",
+			MountPath: buildDockerPath,
+		})
+		volumes = append(volumes, corev1.Volume{
+			Name: "devspace-registry-auth",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: registrySecretName,
+					Items: []corev1.KeyToPath{
+						{
+							Key:  corev1.DockerConfigJsonKey,
+							Path: "config.json",
+						},
+					},
+				},
+			},
+		})
+	}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   id,
+			Labels: labels,
+			Annotations: map[string]string{
+				// rootless buildkit needs to create its own mount namespaces
+				"container.apparmor.security.beta.kubernetes.io/" + BuildContainerName: "unconfined",
+				ClusterAutoscalerSaveToEvictAnnotation:                                 "false",
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:      corev1.RestartPolicyNever,
+			ServiceAccountName: serviceAccount,
+			NodeSelector:       nodeSelector,
+			Containers: []corev1.Container{
+				{
+					Name:  BuildContainerName,
+					Image: buildkitImage,
+					// wait until the build context is uploaded before starting the build
+					Command: []string{
+						"sh",
+						"-c",
+						fmt.Sprintf("while [ ! -f %s ]; do sleep 1; done; exec buildctl-daemonless.sh \"$@\"", buildReadyFile),
+						"--",
+					},
+					Args:         args,
+					Env:          env,
+					VolumeMounts: volumeMounts,
+					SecurityContext: &corev1.SecurityContext{
+						RunAsUser:  &[]int64{1000}[0],
+						RunAsGroup: &[]int64{1000}[0],
+						SeccompProfile: &corev1.SeccompProfile{
+							Type: corev1.SeccompProfileTypeUnconfined,
+						},
+					},
+				},
+			},
+			Volumes: volumes,
+		},
+	}, nil
+}
+
+func getBuildctlArgs(buildOptions *build.BuildOptions, imageNames []string, platform string, push bool) []string {
+	args := []string{
+		"build",
+		"--frontend", "dockerfile.v0",
+		"--local", "context=" + path.Join(buildContextPath, "context"),
+		"--local", "dockerfile=" + path.Join(buildContextPath, "dockerfile"),
+		"--opt", "filename=" + filepath.Base(buildOptions.Dockerfile),
+	}
+
+	// target stage
+	if buildOptions.Target != "" {
+		args = append(args, "--opt", "target="+buildOptions.Target)
+	}
+
+	// platform
+	if platform != "" {
+		args = append(args, "--opt", "platform="+platform)
+	}
+
+	// build args
+	for _, key := range sortedKeys(buildOptions.BuildArgs) {
+		args = append(args, "--opt", "build-arg:"+key+"="+buildOptions.BuildArgs[key])
+	}
+
+	// labels
+	for _, key := range sortedKeys(buildOptions.Labels) {
+		args = append(args, "--opt", "label:"+key+"="+buildOptions.Labels[key])
+	}
+
+	// build contexts
+	for _, key := range sortedKeys(buildOptions.Contexts) {
+		args = append(args,
+			"--local", "context-"+key+"="+path.Join(buildContextPath, "contexts", key),
+			"--opt", "context:"+key+"=local:context-"+key,
+		)
+	}
+
+	// attestations
+	if buildOptions.SBOM != "" {
+		args = append(args, "--opt", "attest:sbom="+buildOptions.SBOM)
+	}
+
+	// cache
+	for _, cacheFrom := range buildOptions.CacheFrom {
+		args = append(args, "--import-cache", cacheFrom)
+	}
+	for _, cacheTo := range buildOptions.CacheTo {
+		args = append(args, "--export-cache", cacheTo)
+	}
+
+	return append(args, "--output", fmt.Sprintf("type=image,\"name=%s\",push=%t", strings.Join(imageNames, ","), push))
+}
+
+func (k *KubernetesDriver) uploadBuildContext(ctx context.Context, id string, buildOptions *build.BuildOptions) error {
+	uploads := map[string]string{
+		"context":    buildOptions.Context,
+		"dockerfile": filepath.Dir(buildOptions.Dockerfile),
+	}
+	for key, contextPath := range buildOptions.Contexts {
+		uploads[path.Join("contexts", key)] = contextPath
+	}
+
+	for _, target := range sortedKeys(uploads) {
+		k.Log.Debugf("Upload build context %s to %s", uploads[target], target)
+		err := k.uploadFolder(ctx, id, uploads[target], path.Join(buildContextPath, target))
+		if err != nil {
+			return perrors.Wrapf(err, "upload build context %s", uploads[target])
+		}
+	}
+
+	// start the build
+	return k.execBuildPod(ctx, id, "touch "+buildReadyFile, nil)
+}
+
+func (k *KubernetesDriver) uploadFolder(ctx context.Context, id, localPath, remotePath string) error {
+	reader, writer := io.Pipe()
+	go func() {
+		_ = writer.CloseWithError(extract.WriteTar(writer, localPath, true))
+	}()
+	defer reader.Close()
+
+	return k.execBuildPod(ctx, id, fmt.Sprintf("mkdir -p '%s' && tar xzf - -C '%s'", remotePath, remotePath), reader)
+}
+
+func (k *KubernetesDriver) execBuildPod(ctx context.Context, id, command string, stdin io.Reader) error {
+	stderr := &bytes.Buffer{}
+	err := k.client.Exec(ctx, &ExecStreamOptions{
+		Pod:       id,
+		Namespace: k.namespace,
+		Container: BuildContainerName,
+		Command:   []string{"sh", "-c", command},
+		Stdin:     stdin,
+		Stdout:    io.Discard,
+		Stderr:    stderr,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+func (k *KubernetesDriver) streamBuildLogs(ctx context.Context, id string) error {
+	logs, err := k.client.Logs(ctx, k.namespace, id, BuildContainerName, true)
+	if err != nil {
+		return perrors.Wrap(err, "get build logs")
+	}
+	defer logs.Close()
+
+	// the full build output is only shown in debug mode, the last lines are printed if the build fails
+	throttledLogger := throttledlogger.NewThrottledLogger(k.Log, time.Second*5)
+	tail := []string{}
+	scanner := bufio.NewScanner(logs)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		throttledLogger.InfoOrDebugf("%s", line)
+
+		tail = append(tail, line)
+		if len(tail) > buildLogTailLines {
+			tail = tail[1:]
+		}
+	}
+	if scanner.Err() != nil {
+		return perrors.Wrap(scanner.Err(), "copy build logs")
+	}
+
+	// wait for the build container to terminate
+	err = k.waitBuildTerminated(ctx, id, throttledLogger)
+	if err != nil && k.Log.GetLevel() < logrus.DebugLevel {
+		for _, line := range tail {
+			k.Log.Error(line)
+		}
+	}
+
+	return err
+}
+
+func (k *KubernetesDriver) waitBuildTerminated(ctx context.Context, id string, throttledLogger *throttledlogger.ThrottledLogger) error {
+	return wait.PollUntilContextTimeout(ctx, time.Second, time.Minute*2, true, func(ctx context.Context) (bool, error) {
+		pod, err := k.getPod(ctx, id)
+		if err != nil {
+			return false, err
+		} else if pod == nil {
+			return false, fmt.Errorf("build pod '%s' was deleted", id)
+		}
+
+		for _, c := range pod.Status.ContainerStatuses {
+			containerStatus := &c
+			if c.Name != BuildContainerName || !IsTerminated(containerStatus) {
+				continue
+			} else if !Succeeded(containerStatus) {
+				return false, fmt.Errorf("build failed: %s (exit code %d)", c.State.Terminated.Reason, c.State.Terminated.ExitCode)
+			}
+
+			return true, nil
+		}
+
+		throttledLogger.Infof("Waiting for build pod '%s' to finish", id)
+		return false, nil
+	})
+}
+
+func (k *KubernetesDriver) getImageDetails(ctx context.Context, imageName, platform string) (*config.ImageDetails, error) {
+	arch := ""
+	if platform != "" {
+		parsedPlatform, err := v1.ParsePlatform(platform)
+		if err != nil {
+			return nil, err
+		}
+		arch = parsedPlatform.Architecture
+	} else {
+		targetArch, err := k.TargetArchitecture(ctx, "")
+		if err != nil {
+			return nil, err
+		}
+		arch = targetArch
+	}
+
+	img, err := image.GetImageForArch(ctx, imageName, arch)
+	if err != nil {
+		return nil, err
+	}
+
+	imageConfig, err := img.ConfigFile()
+	if err != nil {
+		return nil, perrors.Wrap(err, "config file")
+	}
+
+	return &config.ImageDetails{
+		ID: imageName,
+		Config: config.ImageDetailsConfig{
+			User:       imageConfig.Config.User,
+			Env:        imageConfig.Config.Env,
+			Labels:     imageConfig.Config.Labels,
+			Entrypoint: imageConfig.Config.Entrypoint,
+			Cmd:        imageConfig.Config.Cmd,
+		},
+	}, nil
+}
+
+func (k *KubernetesDriver) cleanupBuild(ctx context.Context, id string) {
+	k.Log.Debugf("Delete build pod '%s'", id)
+	err := k.waitPodDeleted(ctx, id)
+	if err != nil {
+		k.Log.Warnf("Error deleting build pod '%s': %v", id, err)
+	}
+
+	err = k.DeleteSecret(ctx, getPullSecretsName(id))
+	if err != nil {
+		k.Log.Warnf("Error deleting registry secret: %v", err)
+	}
+
+	if k.options.ServiceAccount != "" && k.options.ClusterRole != "" {
+		err = k.client.Client().RbacV1().RoleBindings(k.namespace).Delete(ctx, id, metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			k.Log.Warnf("Error deleting role binding '%s': %v", id, err)
+		}
+	}
+}
+
+func getBuildID(prebuildHash string) string {
+	return "devspace-build-" + strings.TrimPrefix(prebuildHash, "devspace-")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,78 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"dev.khulnasoft.com/pkg/devcontainer/build"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetBuildctlArgs(t *testing.T) {
+	tests := []struct {
+		name         string
+		buildOptions *build.BuildOptions
+		imageNames   []string
+		platform     string
+		push         bool
+		want         []string
+	}{
+		{
+			name: "minimal build without push",
+			buildOptions: &build.BuildOptions{
+				Dockerfile: "/workspace/.devcontainer/Dockerfile",
+			},
+			imageNames: []string{"devspace-test:devspace-abc"},
+			want: []string{
+				"build",
+				"--frontend", "dockerfile.v0",
+				"--local", "context=/devspace/context",
+				"--local", "dockerfile=/devspace/dockerfile",
+				"--opt", "filename=Dockerfile",
+				"--output", `type=image,"name=devspace-test:devspace-abc",push=false`,
+			},
+		},
+		{
+			name: "full build with push",
+			buildOptions: &build.BuildOptions{
+				Dockerfile: "/workspace/.devspace-internal/Dockerfile-with-features",
+				Target:     "dev",
+				BuildArgs:  map[string]string{"B": "2", "A": "1"},
+				Labels:     map[string]string{"devcontainer.metadata": "[]"},
+				Contexts:   map[string]string{"features": "/tmp/features"},
+				CacheFrom:  []string{"type=registry,ref=ghcr.io/my-org/cache"},
+				CacheTo:    []string{"type=registry,ref=ghcr.io/my-org/cache,mode=max,image-manifest=true"},
+				SBOM:       "generator=docker/buildkit-syft-scanner:stable-1",
+			},
+			imageNames: []string{"ghcr.io/my-org/repo:devspace-abc", "ghcr.io/my-org/repo:latest"},
+			platform:   "linux/arm64",
+			push:       true,
+			want: []string{
+				"build",
+				"--frontend", "dockerfile.v0",
+				"--local", "context=/devspace/context",
+				"--local", "dockerfile=/devspace/dockerfile",
+				"--opt", "filename=Dockerfile-with-features",
+				"--opt", "target=dev",
+				"--opt", "platform=linux/arm64",
+				"--opt", "build-arg:A=1",
+				"--opt", "build-arg:B=2",
+				"--opt", "label:devcontainer.metadata=[]",
+				"--local", "context-features=/devspace/contexts/features",
+				"--opt", "context:features=local:context-features",
+				"--opt", "attest:sbom=generator=docker/buildkit-syft-scanner:stable-1",
+				"--import-cache", "type=registry,ref=ghcr.io/my-org/cache",
+				"--export-cache", "type=registry,ref=ghcr.io/my-org/cache,mode=max,image-manifest=true",
+				"--output", `type=image,"name=ghcr.io/my-org/repo:devspace-abc,ghcr.io/my-org/repo:latest",push=true`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getBuildctlArgs(tt.buildOptions, tt.imageNames, tt.platform, tt.push)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("getBuildctlArgs() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	VolumeSnapshotKind        = "VolumeSnapshot"
+	PersistentVolumeClaimKind = "PersistentVolumeClaim"
+)
+
+var volumeSnapshotResource = schema.GroupVersionResource{
+	Group:    "snapshot.storage.k8s.io",
+	Version:  "v1",
+	Resource: "volumesnapshots",
+}
+
+var dataSourceKinds = map[string]string{
+	"snapshot":              VolumeSnapshotKind,
+	"volumesnapshot":        VolumeSnapshotKind,
+	"pvc":                   PersistentVolumeClaimKind,
+	"persistentvolumeclaim": PersistentVolumeClaimKind,
+}
+
+// parsePvcDataSource parses a data source in the form kind/name, where kind is either a volume snapshot or a
+// persistent volume claim of the workspace namespace
+func parsePvcDataSource(dataSource string) (*corev1.TypedLocalObjectReference, error) {
+	rawKind, name, ok := strings.Cut(dataSource, "/")
+	kind := dataSourceKinds[strings.ToLower(rawKind)]
+	if !ok || kind == "" || name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("invalid persistent volume claim data source '%s', expected snapshot/<name> or pvc/<name>", dataSource)
+	}
+
+	retDataSource := &corev1.TypedLocalObjectReference{
+		Kind: kind,
+		Name: name,
+	}
+	if kind == VolumeSnapshotKind {
+		retDataSource.APIGroup = ptr.To(volumeSnapshotResource.Group)
+	}
+
+	return retDataSource, nil
+}
+
+// applyPvcDataSource seeds the persistent volume claim from the configured snapshot or claim. The claim needs to be
+// at least as large as its data source.
+func (k *KubernetesDriver) applyPvcDataSource(ctx context.Context, pvc *corev1.PersistentVolumeClaim) error {
+	dataSource, err := parsePvcDataSource(k.options.PvcDataSource)
+	if err != nil {
+		return err
+	}
+
+	sourceSize, err := k.getPvcDataSourceSize(ctx, dataSource)
+	if err != nil {
+		return err
+	}
+
+	size := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	if sourceSize != nil && sourceSize.Cmp(size) > 0 {
+		k.Log.Infof("Increase persistent volume size from %s to %s to fit %s '%s'", size.String(), sourceSize.String(), dataSource.Kind, dataSource.Name)
+		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = *sourceSize
+	}
+
+	pvc.Spec.DataSource = dataSource
+	return nil
+}
+
+func (k *KubernetesDriver) getPvcDataSourceSize(ctx context.Context, dataSource *corev1.TypedLocalObjectReference) (*resource.Quantity, error) {
+	if dataSource.Kind == PersistentVolumeClaimKind {
+		sourcePvc, err := k.client.Client().CoreV1().PersistentVolumeClaims(k.namespace).Get(ctx, dataSource.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "get persistent volume claim '%s'", dataSource.Name)
+		}
+
+		size := sourcePvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		if capacity, ok := sourcePvc.Status.Capacity[corev1.ResourceStorage]; ok && capacity.Cmp(size) > 0 {
+			size = capacity
+		}
+
+		return &size, nil
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(k.client.Config())
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := dynamicClient.Resource(volumeSnapshotResource).Namespace(k.namespace).Get(ctx, dataSource.Name, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, fmt.Errorf("volume snapshot '%s' not found in namespace '%s'", dataSource.Name, k.namespace)
+		}
+
+		return nil, errors.Wrapf(err, "get volume snapshot '%s'", dataSource.Name)
+	}
+
+	if ready, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse"); !ready {
+		k.Log.Warnf("Volume snapshot '%s' is not ready to use yet, the workspace waits until it is", dataSource.Name)
+	}
+
+	rawSize, found, _ := unstructured.NestedString(snapshot.Object, "status", "restoreSize")
+	if !found || rawSize == "" {
+		return nil, nil
+	}
+
+	size, err := resource.ParseQuantity(rawSize)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse restore size of volume snapshot '%s'", dataSource.Name)
+	}
+
+	return &size, nil
+}
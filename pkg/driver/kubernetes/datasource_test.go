@@ -0,0 +1,63 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestParsePvcDataSource(t *testing.T) {
+	tests := []struct {
+		name       string
+		dataSource string
+		want       *corev1.TypedLocalObjectReference
+		wantErr    bool
+	}{
+		{
+			name:       "snapshot",
+			dataSource: "snapshot/monorepo-golden",
+			want: &corev1.TypedLocalObjectReference{
+				APIGroup: ptr.To("snapshot.storage.k8s.io"),
+				Kind:     VolumeSnapshotKind,
+				Name:     "monorepo-golden",
+			},
+		},
+		{
+			name:       "persistent volume claim",
+			dataSource: "PersistentVolumeClaim/monorepo-golden",
+			want: &corev1.TypedLocalObjectReference{
+				Kind: PersistentVolumeClaimKind,
+				Name: "monorepo-golden",
+			},
+		},
+		{
+			name:       "missing kind",
+			dataSource: "monorepo-golden",
+			wantErr:    true,
+		},
+		{
+			name:       "unknown kind",
+			dataSource: "configmap/monorepo-golden",
+			wantErr:    true,
+		},
+		{
+			name:       "other namespace",
+			dataSource: "pvc/golden/monorepo",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePvcDataSource(tt.dataSource)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePvcDataSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("parsePvcDataSource() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
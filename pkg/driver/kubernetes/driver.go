@@ -34,11 +34,24 @@ func NewKubernetesDriver(workspaceInfo *provider2.AgentWorkspaceInfo, log log.Lo
 		log.Debugf("Using Explicit Kubernetes Namespace")
 		namespace = options.KubernetesNamespace
 	}
+
+	// attach to an existing workload instead of creating a pod
+	var workload *Workload
+	if workspaceInfo.Workspace != nil && workspaceInfo.Workspace.Source.KubernetesWorkload != "" {
+		workload, err = ParseWorkload(workspaceInfo.Workspace.Source.KubernetesWorkload)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Debugf("Attach to Kubernetes workload '%s'", workload.String())
+		namespace = workload.Namespace
+	}
 	log.Debugf("Use Kubernetes Namespace '%s'", namespace)
 
 	return &KubernetesDriver{
 		client:    client,
 		namespace: namespace,
+		workload:  workload,
 
 		options: &options,
 		Log:     log,
@@ -50,12 +63,15 @@ type KubernetesDriver struct {
 
 	client *Client
 
+	// workload is the existing pod or deployment the workspace is attached to, if any
+	workload *Workload
+
 	options *provider2.ProviderKubernetesDriverConfig
 	Log     log.Logger
 }
 
 func (k *KubernetesDriver) CanReprovision() bool {
-	return true
+	return k.workload == nil
 }
 
 func (k *KubernetesDriver) getDevContainerPvc(ctx context.Context, id string) (*corev1.PersistentVolumeClaim, *DevContainerInfo, error) {
@@ -85,6 +101,11 @@ func (k *KubernetesDriver) StopDevContainer(ctx context.Context, workspaceId str
 	k.Log.Debugf("Stopping devcontainer for workspace '%s'", workspaceId)
 	defer k.Log.Debugf("Done stopping devcontainer for workspace '%s'", workspaceId)
 
+	if k.workload != nil {
+		k.Log.Infof("Skipping stop of %s, since it was not created by DevSpace", k.workload.String())
+		return nil
+	}
+
 	workspaceId = getID(workspaceId)
 
 	// delete pod
@@ -100,6 +121,11 @@ func (k *KubernetesDriver) DeleteDevContainer(ctx context.Context, workspaceId s
 	k.Log.Debugf("Deleting devcontainer for workspace '%s'", workspaceId)
 	defer k.Log.Debugf("Done deleting devcontainer for workspace '%s'", workspaceId)
 
+	if k.workload != nil {
+		k.Log.Infof("Skipping deletion of %s, since it was not created by DevSpace", k.workload.String())
+		return nil
+	}
+
 	workspaceId = getID(workspaceId)
 
 	// delete pod
@@ -149,7 +175,14 @@ func (k *KubernetesDriver) DeleteDevContainer(ctx context.Context, workspaceId s
 }
 
 func (k *KubernetesDriver) CommandDevContainer(ctx context.Context, workspaceId, user, command string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
-	workspaceId = getID(workspaceId)
+	pod, container := getID(workspaceId), DevContainerName
+	if k.workload != nil {
+		var err error
+		pod, container, err = k.getWorkloadTarget(ctx)
+		if err != nil {
+			return err
+		}
+	}
 
 	var args []string
 	if user != "" && user != "root" {
@@ -159,9 +192,9 @@ func (k *KubernetesDriver) CommandDevContainer(ctx context.Context, workspaceId,
 	}
 
 	return k.client.Exec(ctx, &ExecStreamOptions{
-		Pod:       workspaceId,
+		Pod:       pod,
 		Namespace: k.namespace,
-		Container: "devspace",
+		Container: container,
 		Command:   args,
 		Stdin:     stdin,
 		Stdout:    stdout,
@@ -170,9 +203,17 @@ func (k *KubernetesDriver) CommandDevContainer(ctx context.Context, workspaceId,
 }
 
 func (k *KubernetesDriver) GetDevContainerLogs(ctx context.Context, workspaceID string, stdout io.Writer, stderr io.Writer) error {
-	workspaceID = getID(workspaceID)
+	pod, container := getID(workspaceID), DevContainerName
+	if k.workload != nil {
+		workloadPod, workloadContainer, err := k.findRunningWorkloadContainer(ctx)
+		if err != nil {
+			return err
+		}
+
+		pod, container = workloadPod.Name, workloadContainer.Name
+	}
 
-	logs, err := k.client.Logs(ctx, k.namespace, workspaceID, "devspace", true)
+	logs, err := k.client.Logs(ctx, k.namespace, pod, container, true)
 	if err != nil {
 		return perrors.Wrap(err, "get logs")
 	}
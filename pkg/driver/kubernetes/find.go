@@ -11,6 +11,10 @@ func (k *KubernetesDriver) FindDevContainer(ctx context.Context, workspaceId str
 	k.Log.Debugf("Finding devcontainer for workspace '%s'", workspaceId)
 	defer k.Log.Debugf("Done finding devcontainer for workspace '%s'", workspaceId)
 
+	if k.workload != nil {
+		return k.findWorkloadDevContainer(ctx)
+	}
+
 	workspaceId = getID(workspaceId)
 
 	pvc, containerInfo, err := k.getDevContainerPvc(ctx, workspaceId)
@@ -56,3 +60,7 @@ func (k *KubernetesDriver) FindDevContainer(ctx context.Context, workspaceId str
 func isPodRunning(pod *corev1.Pod) bool {
 	return pod.Status.Phase == corev1.PodRunning
 }
+
+func isPodTerminated(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
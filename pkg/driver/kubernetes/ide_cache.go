@@ -0,0 +1,200 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"dev.khulnasoft.com/pkg/ide"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ideCacheVolumeName    = "devspace-ide-cache"
+	ideCacheContainerName = "ide-cache"
+)
+
+// getIDECacheVolume returns the volume that holds the ide cache shared by all workspaces. It is either an existing
+// persistent volume claim that can be mounted by multiple pods or a host path on the node.
+func (k *KubernetesDriver) getIDECacheVolume() (*corev1.Volume, error) {
+	if k.options.IDECachePvc != "" && k.options.IDECacheHostPath != "" {
+		return nil, fmt.Errorf("ideCachePvc and ideCacheHostPath cannot be used together")
+	}
+
+	if k.options.IDECachePvc != "" {
+		return &corev1.Volume{
+			Name: ideCacheVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: k.options.IDECachePvc,
+				},
+			},
+		}, nil
+	} else if k.options.IDECacheHostPath != "" {
+		hostPathType := corev1.HostPathDirectoryOrCreate
+		return &corev1.Volume{
+			Name: ideCacheVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: k.options.IDECacheHostPath,
+					Type: &hostPathType,
+				},
+			},
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// addIDECache mounts the ide cache read-only into the devcontainer if it is configured
+func (k *KubernetesDriver) addIDECache(pod *corev1.Pod) error {
+	volume, err := k.getIDECacheVolume()
+	if err != nil {
+		return err
+	} else if volume == nil {
+		return nil
+	}
+
+	pod.Spec.Volumes = append(pod.Spec.Volumes, *volume)
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name != DevContainerName {
+			continue
+		}
+
+		pod.Spec.Containers[i].VolumeMounts = append(pod.Spec.Containers[i].VolumeMounts, corev1.VolumeMount{
+			Name:      ideCacheVolumeName,
+			MountPath: ide.CacheFolder,
+			ReadOnly:  true,
+		})
+	}
+
+	return nil
+}
+
+func (k *KubernetesDriver) IDECacheEnabled() bool {
+	return k.options.IDECachePvc != "" || k.options.IDECacheHostPath != ""
+}
+
+// FillIDECache stores the downloads that are missing in the ide cache. The devcontainer mounts the cache read-only, so
+// the downloads are extracted by a short-lived pod of the given image on the node of the devcontainer instead.
+func (k *KubernetesDriver) FillIDECache(ctx context.Context, workspaceId, image string, downloads []string) error {
+	devContainerPod, err := k.getPod(ctx, workspaceId)
+	if err != nil {
+		return err
+	} else if devContainerPod == nil {
+		return fmt.Errorf("pod %s not found", workspaceId)
+	}
+
+	id := workspaceId + "-ide-cache"
+	pod, err := k.getIDECachePod(id, image, devContainerPod)
+	if err != nil {
+		return err
+	}
+
+	k.Log.Debugf("Create ide cache pod '%s'", id)
+	_, err = k.client.Client().CoreV1().Pods(k.namespace).Create(ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("create pod: %w", err)
+	}
+	defer func() {
+		err := k.waitPodDeleted(context.WithoutCancel(ctx), id)
+		if err != nil {
+			k.Log.Warnf("Error deleting ide cache pod '%s': %v", id, err)
+		}
+	}()
+
+	_, err = k.waitPodRunning(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	listing := &bytes.Buffer{}
+	err = k.execIDECachePod(ctx, id, "ls "+ide.CacheFolder+"/urls 2>/dev/null || true", nil, listing)
+	if err != nil {
+		return fmt.Errorf("list ide cache: %w", err)
+	}
+
+	downloads = ide.UncachedDownloads(downloads, listing.String())
+	if len(downloads) == 0 {
+		return nil
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		_ = writer.CloseWithError(ide.WriteCacheArchive(writer, downloads, k.Log))
+	}()
+	defer reader.Close()
+
+	err = k.execIDECachePod(ctx, id, "tar xf - -C "+ide.CacheFolder, reader, io.Discard)
+	if err != nil {
+		return fmt.Errorf("fill ide cache: %w", err)
+	}
+
+	return nil
+}
+
+// getIDECachePod returns a pod that mounts the ide cache writable. It runs on the node of the devcontainer, so a host
+// path cache is filled on the right node, and uses its pull secrets.
+func (k *KubernetesDriver) getIDECachePod(id, image string, devContainerPod *corev1.Pod) (*corev1.Pod, error) {
+	volume, err := k.getIDECacheVolume()
+	if err != nil {
+		return nil, err
+	} else if volume == nil {
+		return nil, fmt.Errorf("ide cache is not configured")
+	}
+
+	labels, err := getLabels(&corev1.Pod{}, k.options.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   id,
+			Labels: labels,
+		},
+		Spec: corev1.PodSpec{
+			NodeName:         devContainerPod.Spec.NodeName,
+			ImagePullSecrets: devContainerPod.Spec.ImagePullSecrets,
+			RestartPolicy:    corev1.RestartPolicyNever,
+			Containers: []corev1.Container{
+				{
+					Name:    ideCacheContainerName,
+					Image:   image,
+					Command: []string{"sh", "-c", "sleep 3600"},
+					SecurityContext: &corev1.SecurityContext{
+						RunAsUser: &[]int64{0}[0],
+					},
+					VolumeMounts: []corev1.VolumeMount{
+						{
+							Name:      ideCacheVolumeName,
+							MountPath: ide.CacheFolder,
+						},
+					},
+				},
+			},
+			Volumes: []corev1.Volume{*volume},
+		},
+	}, nil
+}
+
+func (k *KubernetesDriver) execIDECachePod(ctx context.Context, id, command string, stdin io.Reader, stdout io.Writer) error {
+	stderr := &bytes.Buffer{}
+	err := k.client.Exec(ctx, &ExecStreamOptions{
+		Pod:       id,
+		Namespace: k.namespace,
+		Container: ideCacheContainerName,
+		Command:   []string{"sh", "-c", command},
+		Stdin:     stdin,
+		Stdout:    stdout,
+		Stderr:    stderr,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
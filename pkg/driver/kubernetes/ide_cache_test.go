@@ -0,0 +1,102 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"dev.khulnasoft.com/pkg/ide"
+	provider2 "dev.khulnasoft.com/pkg/provider"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestAddIDECache(t *testing.T) {
+	hostPathType := corev1.HostPathDirectoryOrCreate
+	tests := []struct {
+		name        string
+		options     *provider2.ProviderKubernetesDriverConfig
+		wantVolumes []corev1.Volume
+		wantMounts  []corev1.VolumeMount
+		wantErr     bool
+	}{
+		{
+			name:    "disabled",
+			options: &provider2.ProviderKubernetesDriverConfig{},
+		},
+		{
+			name:    "persistent volume claim",
+			options: &provider2.ProviderKubernetesDriverConfig{IDECachePvc: "ide-cache"},
+			wantVolumes: []corev1.Volume{{
+				Name: ideCacheVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "ide-cache"},
+				},
+			}},
+			wantMounts: []corev1.VolumeMount{{Name: ideCacheVolumeName, MountPath: ide.CacheFolder, ReadOnly: true}},
+		},
+		{
+			name:    "host path",
+			options: &provider2.ProviderKubernetesDriverConfig{IDECacheHostPath: "/var/lib/devspace/ide-cache"},
+			wantVolumes: []corev1.Volume{{
+				Name: ideCacheVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					HostPath: &corev1.HostPathVolumeSource{Path: "/var/lib/devspace/ide-cache", Type: &hostPathType},
+				},
+			}},
+			wantMounts: []corev1.VolumeMount{{Name: ideCacheVolumeName, MountPath: ide.CacheFolder, ReadOnly: true}},
+		},
+		{
+			name:    "both",
+			options: &provider2.ProviderKubernetesDriverConfig{IDECachePvc: "ide-cache", IDECacheHostPath: "/var/lib/devspace/ide-cache"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := &KubernetesDriver{options: tt.options}
+			pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: DevContainerName}, {Name: "sidecar"}}}}
+			err := k.addIDECache(pod)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("addIDECache() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantVolumes, pod.Spec.Volumes); diff != "" {
+				t.Errorf("addIDECache() volumes mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantMounts, pod.Spec.Containers[0].VolumeMounts); diff != "" {
+				t.Errorf("addIDECache() mounts mismatch (-want +got):\n%s", diff)
+			}
+			if len(pod.Spec.Containers[1].VolumeMounts) != 0 {
+				t.Errorf("addIDECache() mounted the cache into a sidecar")
+			}
+		})
+	}
+}
+
+func TestGetIDECachePod(t *testing.T) {
+	k := &KubernetesDriver{options: &provider2.ProviderKubernetesDriverConfig{IDECacheHostPath: "/var/lib/devspace/ide-cache"}}
+	devContainerPod := &corev1.Pod{Spec: corev1.PodSpec{
+		NodeName:         "node-1",
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "devspace-pull-secret-test"}},
+	}}
+
+	pod, err := k.getIDECachePod("test-ide-cache", "ubuntu", devContainerPod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pod.Spec.NodeName != "node-1" {
+		t.Errorf("getIDECachePod() node = %q, want the node of the devcontainer", pod.Spec.NodeName)
+	}
+	if diff := cmp.Diff(devContainerPod.Spec.ImagePullSecrets, pod.Spec.ImagePullSecrets); diff != "" {
+		t.Errorf("getIDECachePod() pull secrets mismatch (-want +got):\n%s", diff)
+	}
+	wantMounts := []corev1.VolumeMount{{Name: ideCacheVolumeName, MountPath: ide.CacheFolder}}
+	if diff := cmp.Diff(wantMounts, pod.Spec.Containers[0].VolumeMounts); diff != "" {
+		t.Errorf("getIDECachePod() mounts mismatch (-want +got):\n%s", diff)
+	}
+
+	k.options.IDECacheHostPath = ""
+	_, err = k.getIDECachePod("test-ide-cache", "ubuntu", devContainerPod)
+	if err == nil {
+		t.Errorf("getIDECachePod() expected an error without ide cache")
+	}
+}
@@ -8,8 +8,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
-func (k *KubernetesDriver) getInitContainers(options *driver.RunOptions, pod *corev1.Pod, initialize bool) ([]corev1.Container, error) {
-	if !initialize {
+func (k *KubernetesDriver) getInitContainers(options *driver.RunOptions, pod *corev1.Pod, initialize, resetState bool) ([]corev1.Container, error) {
+	if !initialize && !resetState {
 		retContainers := []corev1.Container{}
 		// don't build init container and clean up existing one if defined
 		for _, container := range pod.Spec.InitContainers {
@@ -23,10 +23,19 @@ func (k *KubernetesDriver) getInitContainers(options *driver.RunOptions, pod *co
 	}
 
 	commands := []string{}
-	// find the volume type mounts
 	volumeMounts := []corev1.VolumeMount{}
+
+	// clear the state of the previous container
+	if resetState {
+		stateMount := getStateVolumeMount()
+		stateMount.MountPath = "/" + stateMount.SubPath
+		volumeMounts = append(volumeMounts, stateMount)
+		commands = append(commands, fmt.Sprintf(`find %s -mindepth 1 -delete || true`, stateMount.MountPath))
+	}
+
+	// find the volume type mounts
 	for idx, mount := range options.Mounts {
-		if mount.Type != "volume" {
+		if mount.Type != "volume" || !initialize {
 			continue
 		}
 
@@ -34,7 +43,13 @@ func (k *KubernetesDriver) getInitContainers(options *driver.RunOptions, pod *co
 		copyFrom := volumeMount.MountPath
 		volumeMount.MountPath = "/" + volumeMount.SubPath
 		volumeMounts = append(volumeMounts, volumeMount)
-		commands = append(commands, fmt.Sprintf(`cp -a %s/. %s/ || true`, strings.TrimRight(copyFrom, "/"), strings.TrimRight(volumeMount.MountPath, "/")))
+		copyTo := strings.TrimRight(volumeMount.MountPath, "/")
+		copyCommand := fmt.Sprintf(`cp -a %s/. %s/ || true`, strings.TrimRight(copyFrom, "/"), copyTo)
+		if k.options.PvcDataSource != "" {
+			// keep volumes seeded from the data source, only initialize the ones it doesn't contain
+			copyCommand = fmt.Sprintf(`[ -n "$(ls -A %s)" ] || %s`, copyTo, copyCommand)
+		}
+		commands = append(commands, copyCommand)
 	}
 
 	retContainers := []corev1.Container{}
@@ -0,0 +1,67 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"dev.khulnasoft.com/pkg/devcontainer/config"
+	"dev.khulnasoft.com/pkg/driver"
+	provider2 "dev.khulnasoft.com/pkg/provider"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGetInitContainers(t *testing.T) {
+	options := &driver.RunOptions{
+		Image: "ubuntu",
+		Mounts: []*config.Mount{
+			{Type: "volume", Source: "cache", Target: "/cache"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		initialize bool
+		resetState bool
+		dataSource string
+		wantArgs   []string
+	}{
+		{
+			name: "existing workspace",
+		},
+		{
+			name:       "new workspace",
+			initialize: true,
+			wantArgs:   []string{"-c", "cp -a /cache/. /devspace/cache/ || true\n"},
+		},
+		{
+			name:       "seeded workspace",
+			initialize: true,
+			resetState: true,
+			dataSource: "snapshot/golden",
+			wantArgs:   []string{"-c", "find /devspace/.devspace-state -mindepth 1 -delete || true\n[ -n \"$(ls -A /devspace/cache)\" ] || cp -a /cache/. /devspace/cache/ || true\n"},
+		},
+		{
+			name:       "recreated workspace",
+			resetState: true,
+			wantArgs:   []string{"-c", "find /devspace/.devspace-state -mindepth 1 -delete || true\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := &KubernetesDriver{options: &provider2.ProviderKubernetesDriverConfig{PvcDataSource: tt.dataSource}}
+			initContainers, err := k.getInitContainers(options, &corev1.Pod{}, tt.initialize, tt.resetState)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var args []string
+			if len(initContainers) > 0 {
+				args = initContainers[0].Args
+			}
+			if diff := cmp.Diff(tt.wantArgs, args); diff != "" {
+				t.Errorf("getInitContainers() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func (k *KubernetesDriver) createPersistentVolumeClaim(
@@ -22,6 +23,13 @@ func (k *KubernetesDriver) createPersistentVolumeClaim(
 		return err
 	}
 
+	if k.options.PvcDataSource != "" {
+		err = k.applyPvcDataSource(ctx, pvc)
+		if err != nil {
+			return err
+		}
+	}
+
 	k.Log.Infof("Create Persistent Volume Claim '%s'", id)
 	_, err = k.client.Client().CoreV1().PersistentVolumeClaims(k.namespace).Create(ctx, pvc, metav1.CreateOptions{})
 	if err != nil {
@@ -118,3 +126,35 @@ func (k *KubernetesDriver) getDevContainerInformation(
 
 	return string(containerInfo), nil
 }
+
+// updateDevContainerInfo stores the new run options of a recreated container and drops the manifest of the previous pod
+func (k *KubernetesDriver) updateDevContainerInfo(ctx context.Context, id string, options *driver.RunOptions) error {
+	containerInfo, err := k.getDevContainerInformation(id, options)
+	if err != nil {
+		return err
+	}
+
+	return k.patchPvcAnnotations(ctx, id, map[string]*string{
+		DevSpaceInfoAnnotation:        &containerInfo,
+		DevSpacePodManifestAnnotation: nil,
+	})
+}
+
+// patchPvcAnnotations sets the given annotations on the persistent volume claim and removes the ones without value
+func (k *KubernetesDriver) patchPvcAnnotations(ctx context.Context, id string, annotations map[string]*string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = k.client.Client().CoreV1().PersistentVolumeClaims(k.namespace).Patch(ctx, id, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("patch pvc: %w", err)
+	}
+
+	return nil
+}
@@ -46,6 +46,10 @@ func (k *KubernetesDriver) RunDevContainer(
 	options *driver.RunOptions,
 ) error {
 	k.Log.Debugf("Running devcontainer for workspace '%s'", workspaceId)
+	if k.workload != nil {
+		return fmt.Errorf("cannot run a devcontainer for %s, since it was not created by DevSpace", k.workload.String())
+	}
+
 	workspaceId = getID(workspaceId)
 
 	// namespace
@@ -58,6 +62,7 @@ func (k *KubernetesDriver) RunDevContainer(
 
 	// check if persistent volume claim already exists
 	initialize := false
+	resetState := false
 	pvc, containerInfo, err := k.getDevContainerPvc(ctx, workspaceId)
 	if err != nil {
 		return err
@@ -73,6 +78,18 @@ func (k *KubernetesDriver) RunDevContainer(
 		}
 
 		initialize = true
+		if k.options.PvcDataSource != "" {
+			// the volumes are seeded from the data source, so the state of its container is discarded
+			resetState = true
+		}
+	} else if options != nil {
+		// the container is recreated, so the state of the previous container is discarded
+		err = k.updateDevContainerInfo(ctx, workspaceId, options)
+		if err != nil {
+			return err
+		}
+
+		resetState = true
 	}
 
 	// reuse driver.RunOptions from existing workspace if none provided
@@ -81,7 +98,7 @@ func (k *KubernetesDriver) RunDevContainer(
 	}
 
 	// create dev container
-	err = k.runContainer(ctx, workspaceId, options, initialize)
+	err = k.runContainer(ctx, workspaceId, options, initialize, resetState)
 	if err != nil {
 		return err
 	}
@@ -94,6 +111,7 @@ func (k *KubernetesDriver) runContainer(
 	id string,
 	options *driver.RunOptions,
 	initialize bool,
+	resetState bool,
 ) (err error) {
 	// get workspace mount
 	mount := options.WorkspaceMount
@@ -128,13 +146,13 @@ func (k *KubernetesDriver) runContainer(
 	}
 
 	// get init containers
-	initContainers, err := k.getInitContainers(options, pod, initialize)
+	initContainers, err := k.getInitContainers(options, pod, initialize, resetState)
 	if err != nil {
 		return errors.Wrap(err, "build init container")
 	}
 
 	// loop over volume mounts
-	volumeMounts := []corev1.VolumeMount{getVolumeMount(0, mount)}
+	volumeMounts := []corev1.VolumeMount{getVolumeMount(0, mount), getStateVolumeMount()}
 	for idx, mount := range options.Mounts {
 		volumeMount := getVolumeMount(idx+1, mount)
 		if mount.Type == "bind" || mount.Type == "volume" {
@@ -154,7 +172,10 @@ func (k *KubernetesDriver) runContainer(
 	}
 
 	// env vars
-	envVars := []corev1.EnvVar{}
+	envVars := []corev1.EnvVar{{
+		Name:  config.WorkspacePersistentStateExtraEnvVar,
+		Value: "true",
+	}}
 	daemonConfig := ""
 	for k, v := range options.Env {
 		// filter out daemon config, that's going to be mounted through a secret
@@ -229,7 +250,16 @@ func (k *KubernetesDriver) runContainer(
 	pod.Spec.NodeSelector = nodeSelector
 	pod.Spec.InitContainers = initContainers
 	pod.Spec.Containers = getContainers(pod, options.Image, options.Entrypoint, options.Cmd, envVars, volumeMounts, capabilities, resources, options.Privileged, k.options.StrictSecurity, daemonConfigSecretName)
+	err = k.addSidecars(pod, options.Sidecars)
+	if err != nil {
+		return err
+	}
 	pod.Spec.Volumes = getVolumes(pod, id, daemonConfigSecretName)
+	err = k.addIDECache(pod)
+	if err != nil {
+		return err
+	}
+	pod.Spec.HostAliases = k.getHostAliases(pod, options.Sidecars)
 	// avoids a problem where attaching volumes with large repositories would cause an extremely long pod startup time
 	// because changing the ownership of all files takes longer than the kubelet expects it to
 	if pod.Spec.SecurityContext == nil {
@@ -255,13 +285,17 @@ func (k *KubernetesDriver) runContainer(
 		}
 
 		// Nothing changed, can safely return
-		if optionsEqual(existingOptions, k.options) {
+		if optionsEqual(existingOptions, k.options) && !isPodTerminated(existingPod) {
 			k.Log.Infof("Pod '%s' already exists and nothing changed, skipping update", existingPod.Name)
 			return nil
 		}
 
 		// Stop the current pod
-		k.Log.Debug("Provider options changed")
+		if isPodTerminated(existingPod) {
+			k.Log.Debug("Pod terminated")
+		} else {
+			k.Log.Debug("Provider options changed")
+		}
 		err = k.waitPodDeleted(ctx, id)
 		if err != nil {
 			return errors.Wrapf(err, "stop devcontainer: %s", id)
@@ -306,6 +340,12 @@ func (k *KubernetesDriver) runPod(ctx context.Context, id string, pod *corev1.Po
 		return fmt.Errorf("create pod: %w", err)
 	}
 
+	// remember the pod, so it can be recreated quickly after sleeping
+	err = k.savePodManifest(ctx, id, pod)
+	if err != nil {
+		k.Log.Debugf("Error saving pod manifest: %v", err)
+	}
+
 	// wait for pod running
 	k.Log.Infof("Waiting for DevContainer Pod '%s' to come up...", id)
 	_, err = k.waitPodRunning(ctx, id)
@@ -477,19 +517,32 @@ func (k *KubernetesDriver) StartDevContainer(ctx context.Context, workspaceId st
 	k.Log.Debugf("Starting devcontainer for workspace '%s'", workspaceId)
 	defer k.Log.Debugf("Done starting devcontainer for workspace '%s'", workspaceId)
 
+	if k.workload != nil {
+		return fmt.Errorf("no running pod found for %s, please start it first", k.workload.String())
+	}
+
 	workspaceId = getID(workspaceId)
-	_, containerInfo, err := k.getDevContainerPvc(ctx, workspaceId)
+	pvc, containerInfo, err := k.getDevContainerPvc(ctx, workspaceId)
 	if err != nil {
 		return err
 	} else if containerInfo == nil {
 		return fmt.Errorf("persistent volume '%s' not found", workspaceId)
 	}
 
+	// try to wake the workspace up from its last pod
+	woken, err := k.wakePod(ctx, workspaceId, pvc)
+	if err != nil {
+		return err
+	} else if woken {
+		return nil
+	}
+
 	return k.runContainer(
 		ctx,
 		workspaceId,
 		containerInfo.Options,
 		false,
+		false,
 	)
 }
 
@@ -0,0 +1,208 @@
+package kubernetes
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"dev.khulnasoft.com/pkg/driver"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/utils/ptr"
+)
+
+const SidecarContainerPrefix = "devspace-sidecar-"
+
+const (
+	defaultHealthcheckPeriodSeconds = 2
+	minHealthcheckFailureThreshold  = 30
+)
+
+var invalidContainerNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+var _ driver.SidecarDriver = (*KubernetesDriver)(nil)
+
+func (k *KubernetesDriver) CanRunSidecars() bool {
+	return true
+}
+
+// nativeSidecarsVersion is the first version that enables native sidecar containers by default. They are alpha in
+// 1.28 and need the SidecarContainers feature gate there, which can't be detected through the API.
+var nativeSidecarsVersion = utilversion.MajorMinor(1, 29)
+
+// addSidecars adds the sidecars to the pod. If the cluster supports native sidecar containers, they are added as init
+// containers that keep running. Otherwise they are added as regular containers, which Kubernetes starts together with
+// the devcontainer without waiting for their healthchecks.
+func (k *KubernetesDriver) addSidecars(pod *corev1.Pod, sidecars []*driver.Sidecar) error {
+	if len(sidecars) == 0 {
+		return nil
+	}
+
+	serverVersion, err := k.client.Client().Discovery().ServerVersion()
+	if err != nil {
+		return fmt.Errorf("get kubernetes version: %w", err)
+	}
+
+	if supportsNativeSidecars(serverVersion) {
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, k.getSidecarContainers(sidecars, true)...)
+		return nil
+	}
+
+	k.Log.Warnf("Kubernetes %s doesn't support native sidecar containers, services are started together with the devcontainer without waiting for their healthchecks", serverVersion.GitVersion)
+	pod.Spec.Containers = append(pod.Spec.Containers, k.getSidecarContainers(sidecars, false)...)
+	return nil
+}
+
+func supportsNativeSidecars(serverVersion *version.Info) bool {
+	parsedVersion, err := utilversion.ParseGeneric(serverVersion.GitVersion)
+	if err != nil {
+		return false
+	}
+
+	return parsedVersion.AtLeast(nativeSidecarsVersion)
+}
+
+// getSidecarContainers returns the sidecars as containers. Native sidecar containers are init containers that keep
+// running. Kubernetes starts them in order and waits for each startup probe, so dependencies are healthy before
+// dependent sidecars and the devcontainer start.
+func (k *KubernetesDriver) getSidecarContainers(sidecars []*driver.Sidecar, native bool) []corev1.Container {
+	retContainers := []corev1.Container{}
+	for _, sidecar := range sidecars {
+		envVars := []corev1.EnvVar{}
+		for _, name := range sortedKeys(sidecar.Env) {
+			envVars = append(envVars, corev1.EnvVar{
+				Name:  name,
+				Value: sidecar.Env[name],
+			})
+		}
+
+		volumeMounts := []corev1.VolumeMount{}
+		for _, mount := range sidecar.Mounts {
+			if mount.Type != "volume" || mount.Source == "" {
+				k.Log.Warnf("Unsupported mount '%s' in service '%s', will skip", mount.String(), sidecar.Name)
+				continue
+			}
+
+			volumeMounts = append(volumeMounts, getVolumeMount(0, mount))
+		}
+
+		container := corev1.Container{
+			Name:            getSidecarContainerName(sidecar.Name),
+			Image:           sidecar.Image,
+			Command:         sidecar.Entrypoint,
+			Args:            sidecar.Cmd,
+			Env:             envVars,
+			WorkingDir:      sidecar.WorkingDir,
+			VolumeMounts:    volumeMounts,
+			StartupProbe:    getStartupProbe(sidecar.Healthcheck),
+			SecurityContext: k.getSidecarSecurityContext(sidecar),
+		}
+		if native {
+			container.RestartPolicy = ptr.To(corev1.ContainerRestartPolicyAlways)
+		}
+
+		retContainers = append(retContainers, container)
+	}
+
+	return retContainers
+}
+
+func (k *KubernetesDriver) getSidecarSecurityContext(sidecar *driver.Sidecar) *corev1.SecurityContext {
+	if k.options.StrictSecurity == "true" {
+		return nil
+	}
+
+	securityContext := &corev1.SecurityContext{}
+	for _, cap := range sidecar.CapAdd {
+		if securityContext.Capabilities == nil {
+			securityContext.Capabilities = &corev1.Capabilities{}
+		}
+		securityContext.Capabilities.Add = append(securityContext.Capabilities.Add, corev1.Capability(cap))
+	}
+
+	// kubernetes only supports numeric users, so named users are left to the image
+	if sidecar.User != "" {
+		user, group, _ := strings.Cut(sidecar.User, ":")
+		if uid, err := strconv.ParseInt(user, 10, 64); err == nil {
+			securityContext.RunAsUser = &uid
+		} else {
+			k.Log.Warnf("Ignoring non-numeric user '%s' of service '%s'", sidecar.User, sidecar.Name)
+		}
+		if gid, err := strconv.ParseInt(group, 10, 64); err == nil {
+			securityContext.RunAsGroup = &gid
+		}
+	}
+
+	if securityContext.Capabilities == nil && securityContext.RunAsUser == nil && securityContext.RunAsGroup == nil {
+		return nil
+	}
+
+	return securityContext
+}
+
+// getHostAliases resolves the service names to the pod itself, as all sidecars share its network
+func (k *KubernetesDriver) getHostAliases(pod *corev1.Pod, sidecars []*driver.Sidecar) []corev1.HostAlias {
+	hostAliases := pod.Spec.HostAliases
+	hostnames := []string{}
+	for _, sidecar := range sidecars {
+		if errs := validation.IsDNS1123Subdomain(sidecar.Name); len(errs) > 0 {
+			k.Log.Warnf("Service '%s' is not a valid hostname and can only be reached via localhost", sidecar.Name)
+			continue
+		}
+
+		hostnames = append(hostnames, sidecar.Name)
+	}
+	if len(hostnames) == 0 {
+		return hostAliases
+	}
+
+	return append(hostAliases, corev1.HostAlias{
+		IP:        "127.0.0.1",
+		Hostnames: hostnames,
+	})
+}
+
+func getSidecarContainerName(name string) string {
+	name = invalidContainerNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	name = SidecarContainerPrefix + strings.Trim(name, "-")
+	if len(name) > validation.DNS1123LabelMaxLength {
+		name = strings.TrimRight(name[:validation.DNS1123LabelMaxLength], "-")
+	}
+
+	return name
+}
+
+func getStartupProbe(healthcheck *driver.Healthcheck) *corev1.Probe {
+	if healthcheck == nil || len(healthcheck.Test) == 0 {
+		return nil
+	}
+
+	periodSeconds := healthcheck.IntervalSeconds
+	if periodSeconds <= 0 {
+		periodSeconds = defaultHealthcheckPeriodSeconds
+	}
+
+	// failures within the start period don't count towards the retries
+	failureThreshold := healthcheck.StartPeriodSeconds/periodSeconds + healthcheck.Retries
+	if failureThreshold < minHealthcheckFailureThreshold {
+		failureThreshold = minHealthcheckFailureThreshold
+	}
+
+	probe := &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			Exec: &corev1.ExecAction{
+				Command: healthcheck.Test,
+			},
+		},
+		PeriodSeconds:    periodSeconds,
+		FailureThreshold: failureThreshold,
+	}
+	if healthcheck.TimeoutSeconds > 0 {
+		probe.TimeoutSeconds = healthcheck.TimeoutSeconds
+	}
+
+	return probe
+}
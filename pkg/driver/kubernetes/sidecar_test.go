@@ -0,0 +1,49 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/driver"
+	provider2 "dev.khulnasoft.com/pkg/provider"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+func TestSupportsNativeSidecars(t *testing.T) {
+	tests := []struct {
+		gitVersion string
+		want       bool
+	}{
+		{gitVersion: "v1.27.16", want: false},
+		{gitVersion: "v1.28.15", want: false},
+		{gitVersion: "v1.29.0", want: true},
+		{gitVersion: "v1.30.4-eks-a737599", want: true},
+		{gitVersion: "v1.31.1+k3s1", want: true},
+		{gitVersion: "unknown", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.gitVersion, func(t *testing.T) {
+			got := supportsNativeSidecars(&version.Info{GitVersion: tt.gitVersion})
+			if got != tt.want {
+				t.Errorf("supportsNativeSidecars() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSidecarContainers(t *testing.T) {
+	k := &KubernetesDriver{options: &provider2.ProviderKubernetesDriverConfig{}, Log: log.Discard}
+	sidecars := []*driver.Sidecar{{Name: "db", Image: "postgres"}}
+
+	native := k.getSidecarContainers(sidecars, true)
+	if len(native) != 1 || native[0].RestartPolicy == nil || *native[0].RestartPolicy != corev1.ContainerRestartPolicyAlways {
+		t.Errorf("getSidecarContainers() = %v, want a native sidecar container", native)
+	}
+
+	regular := k.getSidecarContainers(sidecars, false)
+	if len(regular) != 1 || regular[0].RestartPolicy != nil {
+		t.Errorf("getSidecarContainers() = %v, want a regular container", regular)
+	}
+}
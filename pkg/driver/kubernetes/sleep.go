@@ -0,0 +1,124 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"dev.khulnasoft.com/pkg/agent"
+	"dev.khulnasoft.com/pkg/devcontainer/config"
+	provider2 "dev.khulnasoft.com/pkg/provider"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+// DevSpacePodManifestAnnotation holds the last pod of the workspace on its persistent volume claim,
+// so a sleeping workspace can be woken up without building the pod again
+const DevSpacePodManifestAnnotation = "dev.khulnasoft.com/pod-manifest"
+
+// stateSubPath is where the container state folder is persisted on the workspace volume
+const stateSubPath = "devspace/.devspace-state"
+
+// getStateVolumeMount persists the setup markers and the agent, so lifecycle hooks don't run again
+// and the agent doesn't need to be injected again after the workspace wakes up
+func getStateVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      "devspace",
+		MountPath: config.ContainerStateFolder,
+		SubPath:   stateSubPath,
+	}
+}
+
+// savePodManifest stores the pod on the persistent volume claim of the workspace
+func (k *KubernetesDriver) savePodManifest(ctx context.Context, id string, pod *corev1.Pod) error {
+	manifest := pod.DeepCopy()
+
+	// volumes are only initialized once
+	initContainers := []corev1.Container{}
+	for _, container := range manifest.Spec.InitContainers {
+		if container.Name != InitContainerName {
+			initContainers = append(initContainers, container)
+		}
+	}
+	manifest.Spec.InitContainers = initContainers
+
+	rawManifest, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	return k.patchPvcAnnotations(ctx, id, map[string]*string{
+		DevSpacePodManifestAnnotation: ptr.To(string(rawManifest)),
+	})
+}
+
+// wakePod recreates the pod of a sleeping workspace from its stored manifest. It returns false if there is no
+// stored manifest or the pod needs to be rebuilt, e.g. because the provider options changed.
+func (k *KubernetesDriver) wakePod(ctx context.Context, id string, pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	rawManifest := pvc.Annotations[DevSpacePodManifestAnnotation]
+	if rawManifest == "" {
+		return false, nil
+	}
+
+	pod := &corev1.Pod{}
+	err := json.Unmarshal([]byte(rawManifest), pod)
+	if err != nil {
+		k.Log.Debugf("Error decoding pod manifest: %v", err)
+		return false, nil
+	}
+
+	lastApplied := &provider2.ProviderKubernetesDriverConfig{}
+	err = json.Unmarshal([]byte(pod.Annotations[DevSpaceLastAppliedAnnotation]), lastApplied)
+	if err != nil || !optionsEqual(lastApplied, k.options) {
+		k.Log.Debugf("Provider options changed, rebuilding pod")
+		return false, nil
+	}
+
+	existingPod, err := k.getPod(ctx, id)
+	if err != nil {
+		return false, errors.Wrapf(err, "get pod: %s", id)
+	} else if existingPod != nil {
+		if !isPodTerminated(existingPod) {
+			return false, nil
+		}
+
+		// the workspace went to sleep after it was idle
+		err = k.waitPodDeleted(ctx, id)
+		if err != nil {
+			return false, errors.Wrapf(err, "delete pod: %s", id)
+		}
+	}
+
+	// refresh the pull secret, as credentials might have expired while sleeping
+	if k.options.KubernetesPullSecretsEnabled == "true" && len(pod.Spec.ImagePullSecrets) > 0 {
+		for _, container := range pod.Spec.Containers {
+			if container.Name == DevContainerName {
+				_, err = k.EnsurePullSecret(ctx, getPullSecretsName(id), container.Image)
+				if err != nil {
+					return false, err
+				}
+			}
+		}
+	}
+
+	k.Log.Infof("Waking up workspace pod '%s'", id)
+	err = k.runPod(ctx, id, pod)
+	if err != nil {
+		return false, err
+	}
+
+	// restore the agent, the container waits for it before starting the daemon
+	command := fmt.Sprintf(`if [ -x '%[1]s' ] && [ ! -x '%[2]s' ]; then cp '%[1]s' '%[2]s'; fi`, config.ContainerAgentBackupLocation, agent.ContainerDevSpaceHelperLocation)
+	err = k.client.Exec(ctx, &ExecStreamOptions{
+		Pod:       id,
+		Namespace: k.namespace,
+		Container: DevContainerName,
+		Command:   []string{"sh", "-c", command},
+	})
+	if err != nil {
+		k.Log.Debugf("Error restoring agent: %v", err)
+	}
+
+	return true, nil
+}
@@ -16,6 +16,20 @@ func (k *KubernetesDriver) TargetArchitecture(ctx context.Context, workspaceId s
 	k.Log.Debugf("Getting target architecture for workspace '%s'", workspaceId)
 	defer k.Log.Debugf("Done getting target architecture for workspace '%s'", workspaceId)
 
+	// the architecture of an existing workload is the one of its node
+	if k.workload != nil {
+		pod, _, err := k.findRunningWorkloadContainer(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		node, err := k.client.Client().CoreV1().Nodes().Get(ctx, pod.Spec.NodeName, metav1.GetOptions{})
+		if err == nil {
+			return node.Status.NodeInfo.Architecture, nil
+		}
+		k.Log.Debugf("Error getting node of pod '%s': %v", pod.Name, err)
+	}
+
 	// get all nodes
 	nodes, err := k.client.Client().CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 	if err != nil {
@@ -28,6 +28,19 @@ func (t *ThrottledLogger) Debugf(format string, args ...interface{}) {
 	t.log(t.logger.Debugf, format, args...)
 }
 
+// InfoOrDebugf logs the message at info level if the interval passed and at debug level otherwise, so frequent
+// messages still show up in debug mode
+func (t *ThrottledLogger) InfoOrDebugf(format string, args ...interface{}) {
+	now := time.Now()
+	if t.timer.IntervalPassed(now) {
+		t.logger.Infof(format, args...)
+		t.timer.Tick(now)
+		return
+	}
+
+	t.logger.Debugf(format, args...)
+}
+
 type LoggingFunc func(string, ...interface{})
 
 func (t *ThrottledLogger) log(loggingFunc LoggingFunc, format string, args ...interface{}) {
@@ -0,0 +1,292 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"dev.khulnasoft.com/pkg/devcontainer/config"
+	perrors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	WorkloadKindPod        = "pod"
+	WorkloadKindDeployment = "deployment"
+)
+
+// DebugContainerPrefix is the name prefix of the ephemeral containers DevSpace adds to existing pods
+const DebugContainerPrefix = "devspace-debug-"
+
+// defaultContainerAnnotation selects the default container of a pod, same as for kubectl exec
+const defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+
+var workloadKinds = map[string]string{
+	"po":          WorkloadKindPod,
+	"pod":         WorkloadKindPod,
+	"pods":        WorkloadKindPod,
+	"deploy":      WorkloadKindDeployment,
+	"deployment":  WorkloadKindDeployment,
+	"deployments": WorkloadKindDeployment,
+}
+
+// Workload is an existing pod or deployment a workspace is attached to instead of creating its own pod
+type Workload struct {
+	Namespace string
+	Kind      string
+	Name      string
+
+	// Container is the container to attach to, defaults to the default container of the pod
+	Container string
+}
+
+func (w *Workload) String() string {
+	return w.Namespace + "/" + w.Kind + "/" + w.Name
+}
+
+// ParseWorkload parses a workload in the form namespace/[kind/]name[/container]. Without a kind, the name refers
+// to a deployment.
+func ParseWorkload(workload string) (*Workload, error) {
+	parts := strings.Split(workload, "/")
+	for _, part := range parts {
+		if part == "" {
+			parts = nil
+			break
+		}
+	}
+	if len(parts) < 2 || len(parts) > 4 {
+		return nil, fmt.Errorf("invalid Kubernetes workload '%s', expected namespace/[kind/]name[/container]", workload)
+	}
+
+	retWorkload := &Workload{
+		Namespace: parts[0],
+		Kind:      WorkloadKindDeployment,
+	}
+	rest := parts[1:]
+	if kind, ok := workloadKinds[strings.ToLower(rest[0])]; ok && len(rest) > 1 {
+		retWorkload.Kind = kind
+		rest = rest[1:]
+	}
+	if len(rest) > 2 {
+		return nil, fmt.Errorf("invalid Kubernetes workload '%s', expected namespace/[kind/]name[/container]", workload)
+	}
+
+	retWorkload.Name = rest[0]
+	if len(rest) == 2 {
+		retWorkload.Container = rest[1]
+	}
+
+	return retWorkload, nil
+}
+
+// findWorkloadDevContainer returns the attached container. Its working directory becomes the workspace folder.
+func (k *KubernetesDriver) findWorkloadDevContainer(ctx context.Context) (*config.ContainerDetails, error) {
+	pod, err := k.findWorkloadPod(ctx)
+	if err != nil {
+		return nil, err
+	} else if pod == nil {
+		return nil, fmt.Errorf("no pod found for %s", k.workload.String())
+	}
+
+	container, err := k.getWorkloadContainer(pod)
+	if err != nil {
+		return nil, err
+	}
+
+	status := "exited"
+	if isPodRunning(pod) {
+		status = "running"
+	}
+
+	startedAt := pod.CreationTimestamp.String()
+	if pod.Status.StartTime != nil {
+		startedAt = pod.Status.StartTime.String()
+	}
+
+	return &config.ContainerDetails{
+		ID:      pod.Name,
+		Created: pod.CreationTimestamp.String(),
+		State: config.ContainerDetailsState{
+			Status:    status,
+			StartedAt: startedAt,
+		},
+		Config: config.ContainerDetailsConfig{
+			WorkingDir: container.WorkingDir,
+		},
+	}, nil
+}
+
+// findWorkloadPod returns the pod of the workload. For deployments it prefers the newest running pod.
+func (k *KubernetesDriver) findWorkloadPod(ctx context.Context) (*corev1.Pod, error) {
+	if k.workload.Kind == WorkloadKindPod {
+		return k.getPod(ctx, k.workload.Name)
+	}
+
+	deployment, err := k.client.Client().AppsV1().Deployments(k.namespace).Get(ctx, k.workload.Name, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, perrors.Wrapf(err, "get deployment %s", k.workload.Name)
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, perrors.Wrapf(err, "parse selector of deployment %s", k.workload.Name)
+	}
+
+	pods, err := k.client.Client().CoreV1().Pods(k.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, perrors.Wrapf(err, "list pods of deployment %s", k.workload.Name)
+	}
+
+	var retPod *corev1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		if retPod == nil ||
+			(isPodRunning(pod) && !isPodRunning(retPod)) ||
+			(isPodRunning(pod) == isPodRunning(retPod) && retPod.CreationTimestamp.Before(&pod.CreationTimestamp)) {
+			retPod = pod
+		}
+	}
+
+	return retPod, nil
+}
+
+func (k *KubernetesDriver) getWorkloadContainer(pod *corev1.Pod) (*corev1.Container, error) {
+	name := k.workload.Container
+	if name == "" {
+		name = pod.Annotations[defaultContainerAnnotation]
+	}
+	if name == "" {
+		if len(pod.Spec.Containers) == 0 {
+			return nil, fmt.Errorf("pod %s has no containers", pod.Name)
+		}
+
+		return &pod.Spec.Containers[0], nil
+	}
+
+	return getContainer(pod.Spec.Containers, name)
+}
+
+func (k *KubernetesDriver) findRunningWorkloadContainer(ctx context.Context) (*corev1.Pod, *corev1.Container, error) {
+	pod, err := k.findWorkloadPod(ctx)
+	if err != nil {
+		return nil, nil, err
+	} else if pod == nil || !isPodRunning(pod) {
+		return nil, nil, fmt.Errorf("no running pod found for %s", k.workload.String())
+	}
+
+	container, err := k.getWorkloadContainer(pod)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pod, container, nil
+}
+
+// getWorkloadTarget returns the running pod and container commands are executed in. If a debug image is
+// configured, this is an ephemeral container next to the workload container instead of the container itself.
+func (k *KubernetesDriver) getWorkloadTarget(ctx context.Context) (string, string, error) {
+	pod, container, err := k.findRunningWorkloadContainer(ctx)
+	if err != nil {
+		return "", "", err
+	} else if k.options.DebugImage == "" {
+		return pod.Name, container.Name, nil
+	}
+
+	debugContainer, err := k.ensureDebugContainer(ctx, pod, container)
+	if err != nil {
+		return "", "", err
+	}
+
+	return pod.Name, debugContainer, nil
+}
+
+// ensureDebugContainer adds an ephemeral container to the pod that shares the processes and volumes of the
+// workload container. Ephemeral containers cannot be removed again, they are gone once the pod is replaced.
+func (k *KubernetesDriver) ensureDebugContainer(ctx context.Context, pod *corev1.Pod, target *corev1.Container) (string, error) {
+	// reuse a running debug container with the same image
+	images := map[string]string{}
+	for _, container := range pod.Spec.EphemeralContainers {
+		images[container.Name] = container.Image
+	}
+	for _, status := range pod.Status.EphemeralContainerStatuses {
+		if strings.HasPrefix(status.Name, DebugContainerPrefix) && IsRunning(&status) && images[status.Name] == k.options.DebugImage {
+			return status.Name, nil
+		}
+	}
+
+	// subpath mounts are not allowed for ephemeral containers
+	volumeMounts := []corev1.VolumeMount{}
+	for _, volumeMount := range target.VolumeMounts {
+		if volumeMount.SubPath == "" && volumeMount.SubPathExpr == "" {
+			volumeMounts = append(volumeMounts, volumeMount)
+		}
+	}
+
+	name := DebugContainerPrefix + utilrand.String(5)
+	k.Log.Infof("Add debug container '%s' to pod '%s'...", name, pod.Name)
+	debugPod := pod.DeepCopy()
+	debugPod.Spec.EphemeralContainers = append(debugPod.Spec.EphemeralContainers, corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:         name,
+			Image:        k.options.DebugImage,
+			Command:      []string{"sh", "-c", "while sleep 3600; do :; done"},
+			Env:          target.Env,
+			EnvFrom:      target.EnvFrom,
+			WorkingDir:   target.WorkingDir,
+			VolumeMounts: volumeMounts,
+		},
+		TargetContainerName: target.Name,
+	})
+	_, err := k.client.Client().CoreV1().Pods(k.namespace).UpdateEphemeralContainers(ctx, pod.Name, debugPod, metav1.UpdateOptions{})
+	if err != nil {
+		return "", perrors.Wrapf(err, "add debug container to pod %s", pod.Name)
+	}
+
+	timeoutDuration, err := time.ParseDuration(k.options.PodTimeout)
+	if err != nil {
+		return "", perrors.Wrap(err, "parse pod timeout")
+	}
+
+	err = wait.PollUntilContextTimeout(ctx, time.Second, timeoutDuration, true, func(ctx context.Context) (bool, error) {
+		currentPod, err := k.getPod(ctx, pod.Name)
+		if err != nil {
+			return false, err
+		} else if currentPod == nil {
+			return false, fmt.Errorf("pod was deleted")
+		}
+
+		for _, status := range currentPod.Status.EphemeralContainerStatuses {
+			if status.Name != name {
+				continue
+			}
+
+			if IsRunning(&status) {
+				return true, nil
+			} else if IsTerminated(&status) {
+				return false, fmt.Errorf("debug container terminated: %s (%s)", status.State.Terminated.Message, status.State.Terminated.Reason)
+			} else if IsWaiting(&status) && IsCritical(&status) {
+				return false, fmt.Errorf("debug container is in critical state: %s (%s)", status.State.Waiting.Message, status.State.Waiting.Reason)
+			}
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return "", perrors.Wrap(err, "wait for debug container")
+	}
+
+	return name, nil
+}
@@ -0,0 +1,64 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseWorkload(t *testing.T) {
+	tests := []struct {
+		name     string
+		workload string
+		want     *Workload
+		wantErr  bool
+	}{
+		{
+			name:     "deployment",
+			workload: "staging/api",
+			want:     &Workload{Namespace: "staging", Kind: WorkloadKindDeployment, Name: "api"},
+		},
+		{
+			name:     "deployment with container",
+			workload: "staging/api/app",
+			want:     &Workload{Namespace: "staging", Kind: WorkloadKindDeployment, Name: "api", Container: "app"},
+		},
+		{
+			name:     "explicit kind",
+			workload: "staging/deploy/api/app",
+			want:     &Workload{Namespace: "staging", Kind: WorkloadKindDeployment, Name: "api", Container: "app"},
+		},
+		{
+			name:     "pod",
+			workload: "staging/pod/api-7d9c5b-x2x4q",
+			want:     &Workload{Namespace: "staging", Kind: WorkloadKindPod, Name: "api-7d9c5b-x2x4q"},
+		},
+		{
+			name:     "missing name",
+			workload: "staging",
+			wantErr:  true,
+		},
+		{
+			name:     "empty segment",
+			workload: "staging//app",
+			wantErr:  true,
+		},
+		{
+			name:     "too many segments",
+			workload: "staging/api/app/extra",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseWorkload(tt.workload)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWorkload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseWorkload() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
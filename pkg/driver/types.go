@@ -5,6 +5,8 @@ import (
 	"io"
 
 	"dev.khulnasoft.com/pkg/devcontainer/config"
+	"dev.khulnasoft.com/pkg/devcontainer/feature"
+	"dev.khulnasoft.com/pkg/provider"
 )
 
 // Driver is the default interface for DevSpace drivers
@@ -41,6 +43,43 @@ type ReprovisioningDriver interface {
 	CanReprovision() bool
 }
 
+// SidecarDriver is a driver that is able to run additional service containers next to the devcontainer
+type SidecarDriver interface {
+	Driver
+
+	// CanRunSidecars returns true if the driver can run RunOptions.Sidecars
+	CanRunSidecars() bool
+}
+
+// IDECacheDriver is a driver that is able to mount a shared ide cache into the devcontainer
+type IDECacheDriver interface {
+	Driver
+
+	// IDECacheEnabled returns true if the shared ide cache is mounted into the devcontainer
+	IDECacheEnabled() bool
+
+	// FillIDECache stores the given downloads in the shared ide cache. The devcontainer only reads the cache, so
+	// the driver fills it with the given image.
+	FillIDECache(ctx context.Context, workspaceID, image string, downloads []string) error
+}
+
+// BuildDriver is a driver that is able to build devcontainer images itself
+type BuildDriver interface {
+	Driver
+
+	// BuildDevContainer builds a devcontainer
+	BuildDevContainer(
+		ctx context.Context,
+		prebuildHash string,
+		parsedConfig *config.SubstitutedConfig,
+		extendedBuildInfo *feature.ExtendedBuildInfo,
+		dockerfilePath,
+		dockerfileContent string,
+		localWorkspaceFolder string,
+		options provider.BuildOptions,
+	) (*config.BuildInfo, error)
+}
+
 // RunOptions are the options for running a container
 type RunOptions struct {
 	// UID is a unique identifier for this workspace
@@ -80,4 +119,60 @@ type RunOptions struct {
 	// Bind mounts are expected to get copied from local to remote once. Volume mounts are expected
 	// to be persisted for the lifetime of the container.
 	Mounts []*config.Mount `json:"mounts,omitempty"`
+
+	// Sidecars are additional service containers that run next to the devcontainer and share its network.
+	// They are ordered by their dependencies, so each sidecar only depends on sidecars before it.
+	Sidecars []*Sidecar `json:"sidecars,omitempty"`
+}
+
+// Sidecar is a service container that runs next to the devcontainer, e.g. a database
+type Sidecar struct {
+	// Name is the name of the service
+	Name string `json:"name,omitempty"`
+
+	// Image is the image to run
+	Image string `json:"image,omitempty"`
+
+	// Entrypoint overrides the entrypoint of the image
+	Entrypoint []string `json:"entrypoint,omitempty"`
+
+	// Cmd overrides the cmd of the image
+	Cmd []string `json:"cmd,omitempty"`
+
+	// Env are the environment variables of the service
+	Env map[string]string `json:"env,omitempty"`
+
+	// User is the user to run the service as
+	User string `json:"user,omitempty"`
+
+	// WorkingDir is the working directory of the service
+	WorkingDir string `json:"workingDir,omitempty"`
+
+	// CapAdd are additional capabilities for the service
+	CapAdd []string `json:"capAdd,omitempty"`
+
+	// Mounts are the volume mounts of the service. Volumes with the same source are shared with
+	// the devcontainer and other sidecars.
+	Mounts []*config.Mount `json:"mounts,omitempty"`
+
+	// Healthcheck is used to wait for the service before dependent services and the devcontainer are started
+	Healthcheck *Healthcheck `json:"healthcheck,omitempty"`
+}
+
+// Healthcheck is a command that checks if a sidecar is healthy
+type Healthcheck struct {
+	// Test is the command to run, e.g. ["pg_isready"]
+	Test []string `json:"test,omitempty"`
+
+	// IntervalSeconds is the time between two checks
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty"`
+
+	// TimeoutSeconds is the time after which a check is considered failed
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+
+	// StartPeriodSeconds is the time to wait before the first check
+	StartPeriodSeconds int32 `json:"startPeriodSeconds,omitempty"`
+
+	// Retries is the number of consecutive failures until the service is considered unhealthy
+	Retries int32 `json:"retries,omitempty"`
 }
@@ -0,0 +1,233 @@
+package ide
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"dev.khulnasoft.com/log"
+	devspacehttp "dev.khulnasoft.com/pkg/http"
+)
+
+const (
+	// CacheFolder is where the IDE cache shared by all workspaces of a machine is mounted
+	CacheFolder = "/var/devspace/ide-cache"
+
+	// CacheVolume is the name of the docker volume that holds the IDE cache
+	CacheVolume = "devspace-ide-cache"
+)
+
+// ErrNotCached is returned by CachedDownload if the download is not in the cache
+var ErrNotCached = errors.New("not cached")
+
+// CacheAvailable checks if the shared IDE cache is mounted into the container
+func CacheAvailable() bool {
+	stat, err := os.Stat(CacheFolder)
+	return err == nil && stat.IsDir()
+}
+
+// OpenDownload opens the given url. If the shared IDE cache is mounted and holds the download, it is served from the
+// cache instead. The cache is mounted read-only and filled by the driver, so downloads that are missing in it are
+// not added by the workspace.
+func OpenDownload(url string, log log.Logger) (io.ReadCloser, error) {
+	if CacheAvailable() {
+		cachedPath, err := CachedDownload(CacheFolder, url)
+		if err == nil {
+			log.Debugf("Use cached download of %s", url)
+			return os.Open(cachedPath)
+		}
+
+		log.Debugf("Error using ide cache for %s: %v", url, err)
+	}
+
+	resp, err := devspacehttp.GetHTTPClient().Get(url)
+	if err != nil {
+		return nil, err
+	} else if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("download %s: unexpected status code %d", url, resp.StatusCode)
+	}
+
+	return resp.Body, nil
+}
+
+// CachedDownload returns the path of the download of the url within the cache folder. Downloads are stored by the
+// sha256 of their content, which is verified before the path is returned, so a partially written or modified blob is
+// never used.
+func CachedDownload(folder, url string) (string, error) {
+	digest, err := os.ReadFile(filepath.Join(folder, filepath.FromSlash(CacheIndexFile(url))))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrNotCached
+		}
+
+		return "", err
+	}
+
+	blobFile := filepath.Join(folder, filepath.FromSlash(CacheBlobFile(strings.TrimSpace(string(digest)))))
+	file, err := os.Open(blobFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrNotCached
+		}
+
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	_, err = io.Copy(hasher, file)
+	if err != nil {
+		return "", err
+	} else if hex.EncodeToString(hasher.Sum(nil)) != strings.TrimSpace(string(digest)) {
+		return "", fmt.Errorf("cached download %s doesn't match its sha256", blobFile)
+	}
+
+	return blobFile, nil
+}
+
+// CacheIndexFile returns the path of the index file of the url relative to the cache folder. It holds the sha256 of
+// the download.
+func CacheIndexFile(url string) string {
+	return path.Join("urls", hash(url))
+}
+
+// CacheBlobFile returns the path of the download with the given sha256 relative to the cache folder
+func CacheBlobFile(digest string) string {
+	return path.Join("blobs", "sha256", digest)
+}
+
+// DownloadForCache downloads the url into the writer and returns the sha256 of the download
+func DownloadForCache(url string, writer io.Writer, log log.Logger) (string, error) {
+	resp, err := devspacehttp.GetHTTPClient().Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download %s: unexpected status code %d", url, resp.StatusCode)
+	}
+
+	hasher := sha256.New()
+	_, err = io.Copy(io.MultiWriter(writer, hasher), &ProgressReader{
+		Reader:    resp.Body,
+		TotalSize: resp.ContentLength,
+		Log:       log,
+	})
+	if err != nil {
+		return "", fmt.Errorf("download %s: %w", url, err)
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// UncachedDownloads returns the urls that have no index file in the listing of the urls folder of the cache
+func UncachedDownloads(urls []string, listing string) []string {
+	cached := map[string]bool{}
+	for _, name := range strings.Fields(listing) {
+		cached[name] = true
+	}
+
+	retURLs := []string{}
+	for _, url := range urls {
+		if !cached[path.Base(CacheIndexFile(url))] {
+			retURLs = append(retURLs, url)
+		}
+	}
+
+	return retURLs
+}
+
+// WriteCacheArchive downloads the urls and writes them as tar archive in the layout of the cache folder, so the
+// driver can extract it into a cache it can't write directly. The blob of a download is written before its index
+// file. Downloads that fail are skipped.
+func WriteCacheArchive(writer io.Writer, urls []string, log log.Logger) error {
+	tarWriter := tar.NewWriter(writer)
+	for _, dir := range []string{"blobs/", "blobs/sha256/", "urls/"} {
+		err := tarWriter.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeDir,
+			Name:     dir,
+			Mode:     0755,
+			ModTime:  time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, url := range urls {
+		err := writeCacheArchiveDownload(tarWriter, url, log)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tarWriter.Close()
+}
+
+func writeCacheArchiveDownload(tarWriter *tar.Writer, url string, log log.Logger) error {
+	tmpFile, err := os.CreateTemp("", "devspace-ide-cache-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+
+	log.Infof("Download %s into the ide cache", url)
+	digest, err := DownloadForCache(url, tmpFile, log)
+	if err != nil {
+		log.Warnf("Skip caching %s: %v", url, err)
+		return nil
+	}
+
+	size, err := tmpFile.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	_, err = tmpFile.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	err = tarWriter.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     CacheBlobFile(digest),
+		Mode:     0644,
+		Size:     size,
+		ModTime:  time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(tarWriter, tmpFile)
+	if err != nil {
+		return err
+	}
+
+	err = tarWriter.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     CacheIndexFile(url),
+		Mode:     0644,
+		Size:     int64(len(digest)),
+		ModTime:  time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = tarWriter.Write([]byte(digest))
+	return err
+}
+
+func hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,110 @@
+package ide
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"dev.khulnasoft.com/log"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/server.tar.gz" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write([]byte("server"))
+	}))
+	defer server.Close()
+
+	url := server.URL + "/server.tar.gz"
+	missingURL := server.URL + "/missing.tar.gz"
+
+	// the driver extracts the archive into the cache
+	archive := &bytes.Buffer{}
+	err := WriteCacheArchive(archive, []string{url, missingURL}, log.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	folder := t.TempDir()
+	names := extractArchive(t, archive, folder)
+	wantNames := []string{"blobs/", "blobs/sha256/", "urls/", CacheBlobFile(hash("server")), CacheIndexFile(url)}
+	if diff := cmp.Diff(wantNames, names); diff != "" {
+		t.Fatalf("WriteCacheArchive() entries mismatch (-want +got):\n%s", diff)
+	}
+
+	listing, err := os.ReadDir(filepath.Join(folder, "urls"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{missingURL}, UncachedDownloads([]string{url, missingURL}, listing[0].Name()+"\n")); diff != "" {
+		t.Errorf("UncachedDownloads() mismatch (-want +got):\n%s", diff)
+	}
+
+	// the workspace reads the verified blob
+	cachedPath, err := CachedDownload(folder, url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(cachedPath)
+	if err != nil {
+		t.Fatal(err)
+	} else if string(content) != "server" {
+		t.Errorf("CachedDownload() content = %q, want %q", string(content), "server")
+	}
+
+	_, err = CachedDownload(folder, missingURL)
+	if !errors.Is(err, ErrNotCached) {
+		t.Errorf("CachedDownload() error = %v, want %v", err, ErrNotCached)
+	}
+
+	// a modified blob is not used
+	err = os.WriteFile(cachedPath, []byte("modified"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = CachedDownload(folder, url)
+	if err == nil {
+		t.Errorf("CachedDownload() expected an error for a modified blob")
+	}
+}
+
+func extractArchive(t *testing.T, archive io.Reader, folder string) []string {
+	t.Helper()
+
+	names := []string{}
+	reader := tar.NewReader(archive)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return names
+		} else if err != nil {
+			t.Fatal(err)
+		}
+
+		names = append(names, header.Name)
+		target := filepath.Join(folder, filepath.FromSlash(header.Name))
+		if header.Typeflag == tar.TypeDir {
+			err = os.MkdirAll(target, 0755)
+		} else {
+			var content []byte
+			content, err = io.ReadAll(reader)
+			if err == nil {
+				err = os.WriteFile(target, content, 0644)
+			}
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
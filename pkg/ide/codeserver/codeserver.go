@@ -0,0 +1,231 @@
+package codeserver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/command"
+	"dev.khulnasoft.com/pkg/config"
+	copy2 "dev.khulnasoft.com/pkg/copy"
+	"dev.khulnasoft.com/pkg/extract"
+	"dev.khulnasoft.com/pkg/ide"
+	"dev.khulnasoft.com/pkg/ide/vscode"
+	"github.com/alessio/shellescape"
+	"github.com/pkg/errors"
+)
+
+const (
+	DownloadAmd64Template = "https://github.com/coder/code-server/releases/download/v%s/code-server-%s-linux-amd64.tar.gz"
+	DownloadArm64Template = "https://github.com/coder/code-server/releases/download/v%s/code-server-%s-linux-arm64.tar.gz"
+)
+
+const (
+	OpenOption          = ide.OpenOption
+	BindAddressOption   = ide.BindAddressOption
+	VersionOption       = "VERSION"
+	DownloadAmd64Option = "DOWNLOAD_AMD64"
+	DownloadArm64Option = "DOWNLOAD_ARM64"
+)
+
+var Options = ide.WithWebOptions(ide.Options{
+	VersionOption: {
+		Name:        VersionOption,
+		Description: "The version of code-server",
+		Default:     "4.96.4",
+	},
+	DownloadArm64Option: {
+		Name:        DownloadArm64Option,
+		Description: "The download url for the arm64 code-server release",
+	},
+	DownloadAmd64Option: {
+		Name:        DownloadAmd64Option,
+		Description: "The download url for the amd64 code-server release",
+	},
+	vscode.ExtensionsVSIXFolderOption: vscode.Options[vscode.ExtensionsVSIXFolderOption],
+	vscode.ExtensionsGalleryOption:    vscode.Options[vscode.ExtensionsGalleryOption],
+})
+
+const (
+	DefaultServerPort = 10900
+
+	installFolder = "/var/devspace/code-server"
+
+	// installedMarker holds the version code-server was installed with
+	installedMarker = ".devspace-installed"
+)
+
+func NewCodeServer(extensions []string, settings string, workspaceFolder string, userName string, values map[string]config.OptionValue, log log.Logger) *CodeServer {
+	return &CodeServer{
+		values:          values,
+		extensions:      extensions,
+		settings:        settings,
+		workspaceFolder: workspaceFolder,
+		userName:        userName,
+		webServer: &ide.WebServer{
+			Name:            "codeserver",
+			Port:            DefaultServerPort,
+			ReadinessPath:   "/healthz",
+			UserName:        userName,
+			WorkspaceFolder: workspaceFolder,
+			Log:             log,
+		},
+		log: log,
+	}
+}
+
+type CodeServer struct {
+	values          map[string]config.OptionValue
+	extensions      []string
+	settings        string
+	workspaceFolder string
+	userName        string
+	webServer       *ide.WebServer
+	log             log.Logger
+}
+
+func (c *CodeServer) Install() error {
+	version := Options.GetValue(c.values, VersionOption)
+	markerFile := filepath.Join(installFolder, installedMarker)
+	installedVersion, err := os.ReadFile(markerFile)
+	if err != nil || string(installedVersion) != version {
+		err = c.install(version)
+		if err != nil {
+			return err
+		}
+
+		err = os.WriteFile(markerFile, []byte(version), 0644)
+		if err != nil {
+			return err
+		}
+	} else {
+		c.log.Debug("code-server is already installed, skipping installation")
+	}
+
+	err = c.installSettings()
+	if err != nil {
+		return errors.Wrap(err, "install settings")
+	}
+
+	err = c.installExtensions()
+	if err != nil {
+		return errors.Wrap(err, "install extensions")
+	}
+
+	return c.Start()
+}
+
+func (c *CodeServer) install(version string) error {
+	c.log.Infof("Installing code-server %s...", version)
+	_ = os.RemoveAll(installFolder)
+	err := os.MkdirAll(installFolder, 0755)
+	if err != nil {
+		return err
+	}
+
+	body, err := ide.OpenDownload(ReleaseURL(c.values, runtime.GOARCH), c.log)
+	if err != nil {
+		return errors.Wrap(err, "download code-server")
+	}
+	defer body.Close()
+
+	err = extract.Extract(body, installFolder, extract.StripLevels(1))
+	if err != nil {
+		return errors.Wrap(err, "extract code-server")
+	}
+
+	c.log.Done("Successfully installed code-server")
+	return nil
+}
+
+// ReleaseURL returns the download url of the code-server release for the given architecture
+func ReleaseURL(values map[string]config.OptionValue, arch string) string {
+	version := strings.TrimPrefix(Options.GetValue(values, VersionOption), "v")
+	if arch == "arm64" {
+		if url := Options.GetValue(values, DownloadArm64Option); url != "" {
+			return url
+		}
+
+		return fmt.Sprintf(DownloadArm64Template, version, version)
+	}
+
+	if url := Options.GetValue(values, DownloadAmd64Option); url != "" {
+		return url
+	}
+
+	return fmt.Sprintf(DownloadAmd64Template, version, version)
+}
+
+// installSettings writes the settings of the devcontainer.json as machine settings, so the user settings stay
+// untouched
+func (c *CodeServer) installSettings() error {
+	if len(c.settings) == 0 {
+		return nil
+	}
+
+	dataDir, err := c.userDataDir()
+	if err != nil {
+		return err
+	}
+
+	settingsDir := filepath.Join(dataDir, "Machine")
+	err = os.MkdirAll(settingsDir, 0755)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(filepath.Join(settingsDir, "settings.json"), []byte(c.settings), 0600)
+	if err != nil {
+		return err
+	}
+
+	if c.userName != "" {
+		return copy2.ChownR(dataDir, c.userName)
+	}
+
+	return nil
+}
+
+func (c *CodeServer) installExtensions() error {
+	if len(c.extensions) == 0 {
+		return nil
+	}
+
+	binaryPath := filepath.Join(installFolder, "bin", "code-server")
+	gallery := Options.GetValue(c.values, vscode.ExtensionsGalleryOption)
+	for _, extension := range c.extensions {
+		c.log.Info("Install extension " + extension + "...")
+		err := vscode.InstallExtension(extension, gallery, "", c.log, func(extension string) error {
+			return c.webServer.Run(fmt.Sprintf("%s --install-extension %s", binaryPath, shellescape.Quote(extension)))
+		})
+		if err != nil {
+			c.log.Infof("Failed installing extension %s: %v", extension, err)
+		} else {
+			c.log.Info("Successfully installed extension " + extension)
+		}
+	}
+
+	return nil
+}
+
+func (c *CodeServer) Start() error {
+	c.webServer.Command = fmt.Sprintf(
+		"%s --auth none --bind-addr '0.0.0.0:%d' --disable-telemetry --disable-update-check %s",
+		filepath.Join(installFolder, "bin", "code-server"),
+		DefaultServerPort,
+		shellescape.Quote(c.workspaceFolder),
+	)
+	return c.webServer.Start()
+}
+
+func (c *CodeServer) userDataDir() (string, error) {
+	homeDir, err := command.GetHome(c.userName)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(homeDir, ".local", "share", "code-server"), nil
+}
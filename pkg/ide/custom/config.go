@@ -0,0 +1,303 @@
+package custom
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"dev.khulnasoft.com/pkg/config"
+	"dev.khulnasoft.com/pkg/ide"
+	"github.com/blang/semver"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// IDEConfigFile is the file the manifest of an added IDE is stored in
+const IDEConfigFile = "ide.json"
+
+var ideNameRegEx = regexp.MustCompile(`[^a-z0-9\-]+`)
+
+var optionNameRegEx = regexp.MustCompile(`[^A-Z0-9_]+`)
+
+// IDEConfig is the manifest of an IDE that is not built into DevSpace
+type IDEConfig struct {
+	// Name is the name of the IDE
+	Name string `json:"name,omitempty"`
+
+	// Version is the version of the IDE manifest
+	Version string `json:"version,omitempty"`
+
+	// DisplayName is the name to show to the user
+	DisplayName string `json:"displayName,omitempty"`
+
+	// Description is the IDE description
+	Description string `json:"description,omitempty"`
+
+	// Icon holds an image URL that will be displayed
+	Icon string `json:"icon,omitempty"`
+
+	// IconDark holds an image URL that will be displayed in dark mode
+	IconDark string `json:"iconDark,omitempty"`
+
+	// Group this IDE belongs to, defaults to Other
+	Group config.IDEGroup `json:"group,omitempty"`
+
+	// Options are the options of the IDE, they are available as environment variables in all commands
+	Options ide.Options `json:"options,omitempty"`
+
+	// Server describes how to install and start the IDE server inside the container
+	Server ServerConfig `json:"server,omitempty"`
+
+	// Ports are the container ports to forward locally while the IDE is open
+	Ports []string `json:"ports,omitempty"`
+
+	// Open describes how to open the IDE locally
+	Open OpenConfig `json:"open,omitempty"`
+
+	// Source is where the manifest was added from
+	Source IDESource `json:"source,omitempty"`
+}
+
+type ServerConfig struct {
+	// Download holds the server download URL per architecture, e.g. amd64 or arm64. Archives are extracted into
+	// the IDE directory, everything else is stored as executable file.
+	Download map[string]string `json:"download,omitempty"`
+
+	// StripComponents is the number of leading path elements to strip from tar archives
+	StripComponents int `json:"stripComponents,omitempty"`
+
+	// Install is executed as root inside the IDE directory after the download
+	Install string `json:"install,omitempty"`
+
+	// Start is executed in the background as remote user to start the server
+	Start string `json:"start,omitempty"`
+}
+
+type OpenConfig struct {
+	// URL is opened in the browser once the ports are forwarded
+	URL string `json:"url,omitempty"`
+
+	// Command is executed locally to open the IDE
+	Command string `json:"command,omitempty"`
+}
+
+type IDESource struct {
+	// File source for the IDE
+	File string `json:"file,omitempty"`
+
+	// URL where the IDE was downloaded from
+	URL string `json:"url,omitempty"`
+
+	// Raw is the exact string we used to load the IDE
+	Raw string `json:"raw,omitempty"`
+}
+
+// ParseIDE parses and validates an IDE manifest
+func ParseIDE(reader io.Reader) (*IDEConfig, error) {
+	payload, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedConfig := &IDEConfig{}
+	err = yaml.Unmarshal(payload, parsedConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse ide config")
+	}
+
+	err = validate(parsedConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "validate")
+	}
+
+	return parsedConfig, nil
+}
+
+// ValidateName checks that the name can be used as IDE name, it is also used as directory name
+func ValidateName(name string) error {
+	if ideNameRegEx.MatchString(name) {
+		return fmt.Errorf("ide name can only include lowercase letters, numbers or dashes")
+	} else if len(name) > 32 {
+		return fmt.Errorf("ide name cannot be longer than 32 characters")
+	}
+
+	return nil
+}
+
+func validate(ideConfig *IDEConfig) error {
+	// validate name
+	if ideConfig.Name == "" {
+		return fmt.Errorf("name is missing in ide.yaml")
+	}
+	err := ValidateName(ideConfig.Name)
+	if err != nil {
+		return err
+	}
+
+	// validate version
+	if ideConfig.Version != "" {
+		_, err := semver.Parse(strings.TrimPrefix(ideConfig.Version, "v"))
+		if err != nil {
+			return errors.Wrap(err, "parse ide version")
+		}
+	}
+
+	// validate option names
+	for optionName, option := range ideConfig.Options {
+		if optionNameRegEx.MatchString(optionName) {
+			return fmt.Errorf("ide option '%s' can only consist of upper case letters, numbers or underscores. E.g. MY_OPTION", optionName)
+		}
+		if option.ValidationPattern != "" {
+			_, err := regexp.Compile(option.ValidationPattern)
+			if err != nil {
+				return errors.Wrapf(err, "compile validation pattern of option '%s'", optionName)
+			}
+		}
+
+		option.Name = optionName
+		ideConfig.Options[optionName] = option
+	}
+
+	if ideConfig.Server.Start == "" && ideConfig.Open.Command == "" {
+		return fmt.Errorf("either server.start or open.command is required")
+	}
+	if ideConfig.Open.URL != "" && len(ideConfig.Ports) == 0 {
+		return fmt.Errorf("open.url requires at least one port to forward")
+	}
+	if ideConfig.Group == "" {
+		ideConfig.Group = config.IDEGroupOther
+	}
+
+	return nil
+}
+
+// DownloadURL returns the server download URL for the current architecture
+func (c *IDEConfig) DownloadURL() string {
+	return c.Server.Download[runtime.GOARCH]
+}
+
+// Environ returns the option values together with the given variables as environment variables
+func (c *IDEConfig) Environ(values map[string]config.OptionValue, extraVars map[string]string) map[string]string {
+	retVars := map[string]string{}
+	for optionName := range c.Options {
+		retVars[optionName] = c.Options.GetValue(values, optionName)
+	}
+	for k, v := range extraVars {
+		retVars[k] = v
+	}
+
+	return retVars
+}
+
+// Expand replaces ${VAR} and $VAR in the given string with the given variables
+func Expand(value string, vars map[string]string) string {
+	return os.Expand(value, func(name string) string {
+		return vars[name]
+	})
+}
+
+func GetIDEsDir(context string) (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "contexts", context, "ides"), nil
+}
+
+func GetIDEDir(context, ideName string) (string, error) {
+	idesDir, err := GetIDEsDir(context)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(idesDir, ideName), nil
+}
+
+func SaveIDEConfig(context string, ideConfig *IDEConfig) error {
+	ideDir, err := GetIDEDir(context, ideConfig.Name)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(ideDir, 0755)
+	if err != nil {
+		return err
+	}
+
+	ideConfigBytes, err := json.Marshal(ideConfig)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(ideDir, IDEConfigFile), ideConfigBytes, 0600)
+}
+
+// LoadIDEConfig loads an added IDE, it returns nil if the IDE does not exist
+func LoadIDEConfig(context, ideName string) (*IDEConfig, error) {
+	ideDir, err := GetIDEDir(context, ideName)
+	if err != nil {
+		return nil, err
+	}
+
+	ideConfigBytes, err := os.ReadFile(filepath.Join(ideDir, IDEConfigFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return ParseIDE(bytes.NewReader(ideConfigBytes))
+}
+
+// LoadIDEConfigs loads all IDEs added to the context
+func LoadIDEConfigs(context string) ([]*IDEConfig, error) {
+	idesDir, err := GetIDEsDir(context)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(idesDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	retIDEs := []*IDEConfig{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		ideConfig, err := LoadIDEConfig(context, entry.Name())
+		if err != nil {
+			return nil, errors.Wrapf(err, "load ide %s", entry.Name())
+		} else if ideConfig == nil {
+			continue
+		}
+
+		retIDEs = append(retIDEs, ideConfig)
+	}
+
+	return retIDEs, nil
+}
+
+func DeleteIDEConfig(context, ideName string) error {
+	ideDir, err := GetIDEDir(context, ideName)
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(ideDir)
+}
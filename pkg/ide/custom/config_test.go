@@ -0,0 +1,151 @@
+package custom
+
+import (
+	"strings"
+	"testing"
+
+	"dev.khulnasoft.com/pkg/config"
+	"dev.khulnasoft.com/pkg/ide"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseIDE(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     *IDEConfig
+		wantErr  bool
+	}{
+		{
+			name: "browser ide",
+			manifest: `
+name: code-server
+version: v0.1.0
+options:
+  PORT:
+    default: "8080"
+server:
+  download:
+    amd64: https://example.com/code-server-amd64.tar.gz
+  stripComponents: 1
+  start: ${IDE_DIR}/bin/code-server --bind-addr 0.0.0.0:${PORT} --auth none ${WORKSPACE_FOLDER}
+ports:
+  - ${PORT}
+open:
+  url: http://localhost:${LOCAL_PORT}
+`,
+			want: &IDEConfig{
+				Name:    "code-server",
+				Version: "v0.1.0",
+				Group:   config.IDEGroupOther,
+				Options: ide.Options{
+					"PORT": {Name: "PORT", Default: "8080"},
+				},
+				Server: ServerConfig{
+					Download:        map[string]string{"amd64": "https://example.com/code-server-amd64.tar.gz"},
+					StripComponents: 1,
+					Start:           "${IDE_DIR}/bin/code-server --bind-addr 0.0.0.0:${PORT} --auth none ${WORKSPACE_FOLDER}",
+				},
+				Ports: []string{"${PORT}"},
+				Open:  OpenConfig{URL: "http://localhost:${LOCAL_PORT}"},
+			},
+		},
+		{
+			name: "invalid name",
+			manifest: `
+name: Code Server
+open:
+  command: code-server-client
+`,
+			wantErr: true,
+		},
+		{
+			name: "invalid option name",
+			manifest: `
+name: code-server
+options:
+  port: {}
+open:
+  command: code-server-client
+`,
+			wantErr: true,
+		},
+		{
+			name: "nothing to start",
+			manifest: `
+name: code-server
+`,
+			wantErr: true,
+		},
+		{
+			name: "url without ports",
+			manifest: `
+name: code-server
+server:
+  start: code-server
+open:
+  url: http://localhost:8080
+`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseIDE(strings.NewReader(tt.manifest))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseIDE() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseIDE() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{name: "code-server"},
+		{name: "../../x", wantErr: true},
+		{name: "Code", wantErr: true},
+		{name: strings.Repeat("a", 33), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateName(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInstallHash(t *testing.T) {
+	server := func(install string) *CustomIDEServer {
+		return NewCustomIDEServer(&IDEConfig{
+			Name:   "my-ide",
+			Server: ServerConfig{Install: install, Start: "my-ide"},
+		}, "/workspace", "user", nil, nil)
+	}
+
+	hashA, err := server("install-a").installHash(map[string]string{"PORT": "8080"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashB, err := server("install-b").installHash(map[string]string{"PORT": "8080"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashC, err := server("install-a").installHash(map[string]string{"PORT": "9090"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hashA == hashB || hashA == hashC {
+		t.Errorf("expected the install hash to change with the manifest and options, got %s, %s and %s", hashA, hashB, hashC)
+	}
+}
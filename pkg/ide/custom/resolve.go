@@ -0,0 +1,70 @@
+package custom
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"dev.khulnasoft.com/log"
+	devspacehttp "dev.khulnasoft.com/pkg/http"
+	"github.com/pkg/errors"
+)
+
+// ResolveIDE loads an IDE manifest from a URL or a local file
+func ResolveIDE(source string, log log.Logger) (*IDEConfig, error) {
+	retSource := IDESource{
+		Raw: strings.TrimSpace(source),
+	}
+
+	var (
+		raw []byte
+		err error
+	)
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		log.Infof("Download ide %s...", source)
+		raw, err = downloadIDE(source)
+		if err != nil {
+			return nil, err
+		}
+		retSource.URL = source
+	} else {
+		raw, err = os.ReadFile(source)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("couldn't find ide manifest '%s', expected a URL or a path to an ide.yaml", source)
+			}
+
+			return nil, err
+		}
+
+		retSource.File, err = filepath.Abs(source)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ideConfig, err := ParseIDE(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	ideConfig.Source = retSource
+
+	return ideConfig, nil
+}
+
+func downloadIDE(url string) ([]byte, error) {
+	resp, err := devspacehttp.GetHTTPClient().Get(url)
+	if err != nil {
+		return nil, errors.Wrap(err, "download ide")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("download ide: unexpected status code %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
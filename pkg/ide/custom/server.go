@@ -0,0 +1,233 @@
+package custom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/log/hash"
+	"dev.khulnasoft.com/pkg/config"
+	copy2 "dev.khulnasoft.com/pkg/copy"
+	"dev.khulnasoft.com/pkg/extract"
+	"dev.khulnasoft.com/pkg/ide"
+	"dev.khulnasoft.com/pkg/single"
+	"github.com/alessio/shellescape"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	installFolder = "/var/devspace/ides"
+
+	// installedMarker holds the hash of the manifest and options the IDE was installed with
+	installedMarker = ".devspace-installed"
+)
+
+func NewCustomIDEServer(ideConfig *IDEConfig, workspaceFolder, userName string, values map[string]config.OptionValue, log log.Logger) *CustomIDEServer {
+	return &CustomIDEServer{
+		config:          ideConfig,
+		values:          values,
+		workspaceFolder: workspaceFolder,
+		userName:        userName,
+		log:             log,
+	}
+}
+
+type CustomIDEServer struct {
+	config          *IDEConfig
+	values          map[string]config.OptionValue
+	workspaceFolder string
+	userName        string
+	log             log.Logger
+}
+
+func (c *CustomIDEServer) Install() error {
+	location := filepath.Join(installFolder, c.config.Name)
+	markerFile := filepath.Join(location, installedMarker)
+	env := c.environ(location)
+	installHash, err := c.installHash(env)
+	if err != nil {
+		return err
+	}
+
+	installedHash, err := os.ReadFile(markerFile)
+	if err == nil && string(installedHash) == installHash {
+		c.log.Debugf("%s is already installed, skipping installation", c.displayName())
+		return c.Start()
+	}
+
+	c.log.Infof("Installing %s...", c.displayName())
+	err = os.MkdirAll(location, 0755)
+	if err != nil {
+		return err
+	}
+
+	downloadURL := Expand(c.config.DownloadURL(), env)
+	if downloadURL != "" {
+		err = c.download(downloadURL, location)
+		if err != nil {
+			return errors.Wrapf(err, "download %s", c.displayName())
+		}
+	} else if len(c.config.Server.Download) > 0 {
+		return fmt.Errorf("%s has no download for architecture %s", c.displayName(), runtime.GOARCH)
+	}
+
+	if c.config.Server.Install != "" {
+		err = c.run(location, env)
+		if err != nil {
+			return errors.Wrapf(err, "install %s", c.displayName())
+		}
+	}
+
+	if c.userName != "" {
+		err = copy2.ChownR(location, c.userName)
+		if err != nil {
+			return errors.Wrap(err, "chown")
+		}
+	}
+
+	err = os.WriteFile(markerFile, []byte(installHash), 0644)
+	if err != nil {
+		return err
+	}
+	c.log.Donef("Successfully installed %s", c.displayName())
+
+	return c.Start()
+}
+
+// installHash changes whenever the manifest or the options change how the IDE is installed, so manifests
+// without a version are reinstalled after an update as well
+func (c *CustomIDEServer) installHash(env map[string]string) (string, error) {
+	raw, err := json.Marshal(struct {
+		Version string            `json:"version,omitempty"`
+		Server  ServerConfig      `json:"server,omitempty"`
+		Env     map[string]string `json:"env,omitempty"`
+	}{
+		Version: c.config.Version,
+		Server:  c.config.Server,
+		Env:     env,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return hash.String(string(raw)), nil
+}
+
+func (c *CustomIDEServer) Start() error {
+	if c.config.Server.Start == "" {
+		return nil
+	}
+
+	location := filepath.Join(installFolder, c.config.Name)
+	return single.Single(c.config.Name+".pid", func() (*exec.Cmd, error) {
+		c.log.Infof("Starting %s in background...", c.displayName())
+		return c.command(location, c.environ(location), c.config.Server.Start, c.userName), nil
+	})
+}
+
+func (c *CustomIDEServer) run(location string, env map[string]string) error {
+	writer := c.log.Writer(logrus.InfoLevel, false)
+	defer writer.Close()
+
+	cmd := c.command(location, env, c.config.Server.Install, "")
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	return cmd.Run()
+}
+
+// command creates the command to run the script with the given variables. The variables are exported within the
+// script, because su does not pass through the environment.
+func (c *CustomIDEServer) command(location string, env map[string]string, script, userName string) *exec.Cmd {
+	names := []string{}
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	runCommand := ""
+	for _, name := range names {
+		runCommand += fmt.Sprintf("export %s=%s\n", name, shellescape.Quote(env[name]))
+	}
+	runCommand += script
+
+	args := []string{}
+	if userName != "" {
+		args = append(args, "su", userName, "-l", "-c", runCommand)
+	} else {
+		args = append(args, "sh", "-c", runCommand)
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = location
+	return cmd
+}
+
+func (c *CustomIDEServer) environ(location string) map[string]string {
+	return c.config.Environ(c.values, map[string]string{
+		"IDE_DIR":          location,
+		"WORKSPACE_FOLDER": c.workspaceFolder,
+		"REMOTE_USER":      c.userName,
+		"ARCH":             runtime.GOARCH,
+	})
+}
+
+// download stores the server in the IDE directory. Archives are extracted, other files are stored as executable
+// named after the last path element of the URL.
+func (c *CustomIDEServer) download(downloadURL, location string) error {
+	c.log.Infof("Download %s...", downloadURL)
+	body, err := ide.OpenDownload(downloadURL, c.log)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	fileName := "server"
+	parsedURL, err := url.Parse(downloadURL)
+	if err == nil && path.Base(parsedURL.Path) != "/" && path.Base(parsedURL.Path) != "." {
+		fileName = path.Base(parsedURL.Path)
+	}
+
+	switch {
+	case strings.HasSuffix(fileName, ".tar.gz"), strings.HasSuffix(fileName, ".tgz"), strings.HasSuffix(fileName, ".tar"):
+		return extract.Extract(body, location, extract.StripLevels(c.config.Server.StripComponents))
+	case strings.HasSuffix(fileName, ".zip"):
+		zipFile := filepath.Join(location, fileName)
+		err = writeFile(zipFile, body, 0644)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(zipFile)
+
+		return extract.UnzipFolder(zipFile, location)
+	default:
+		return writeFile(filepath.Join(location, fileName), body, 0755)
+	}
+}
+
+func (c *CustomIDEServer) displayName() string {
+	if c.config.DisplayName != "" {
+		return c.config.DisplayName
+	}
+
+	return c.config.Name
+}
+
+func writeFile(target string, reader io.Reader, perm os.FileMode) error {
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, reader)
+	return err
+}
@@ -13,7 +13,6 @@ import (
 	"dev.khulnasoft.com/pkg/command"
 	"dev.khulnasoft.com/pkg/config"
 	copy2 "dev.khulnasoft.com/pkg/copy"
-	devspacehttp "dev.khulnasoft.com/pkg/http"
 	"dev.khulnasoft.com/pkg/ide"
 	"dev.khulnasoft.com/pkg/single"
 	"dev.khulnasoft.com/pkg/util"
@@ -77,24 +76,13 @@ func (o *FleetServer) Install(projectDir string) error {
 		return o.Start(fleetBinary, location, projectDir)
 	}
 
-	// check what release we need to download
-	var url string
-	if runtime.GOARCH == "arm64" {
-		url = Options.GetValue(o.values, DownloadArm64Option)
-	} else {
-		url = Options.GetValue(o.values, DownloadAmd64Option)
-	}
-
 	// download binary
 	o.log.Infof("Downloading fleet...")
-	resp, err := devspacehttp.GetHTTPClient().Get(url)
+	body, err := ide.OpenDownload(DownloadURL(o.values, runtime.GOARCH), o.log)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("unexpected status code while trying to download fleet from %s: %d", url, resp.StatusCode)
-	}
+	defer body.Close()
 
 	f, err := os.OpenFile(fleetBinary, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
 	if err != nil {
@@ -102,7 +90,7 @@ func (o *FleetServer) Install(projectDir string) error {
 	}
 	defer f.Close()
 
-	_, err = io.Copy(f, resp.Body)
+	_, err = io.Copy(f, body)
 	if err != nil {
 		return fmt.Errorf("download fleet: %w", err)
 	}
@@ -120,6 +108,15 @@ func (o *FleetServer) Install(projectDir string) error {
 	return o.Start(fleetBinary, location, projectDir)
 }
 
+// DownloadURL returns the download url of the fleet install script for the given architecture
+func DownloadURL(values map[string]config.OptionValue, arch string) string {
+	if arch == "arm64" {
+		return Options.GetValue(values, DownloadArm64Option)
+	}
+
+	return Options.GetValue(values, DownloadAmd64Option)
+}
+
 func (o *FleetServer) Start(binaryPath, location, projectDir string) error {
 	wasStarted := false
 	var readCloser io.ReadCloser
@@ -0,0 +1,80 @@
+package ideparse
+
+import (
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/config"
+	"dev.khulnasoft.com/pkg/ide/codeserver"
+	"dev.khulnasoft.com/pkg/ide/fleet"
+	"dev.khulnasoft.com/pkg/ide/jetbrains"
+	"dev.khulnasoft.com/pkg/ide/openvscode"
+	"dev.khulnasoft.com/pkg/ide/vscode"
+)
+
+var jetBrainsServers = map[config.IDE]func(userName string, values map[string]config.OptionValue, log log.Logger) *jetbrains.GenericJetBrainsServer{
+	config.IDEGoland:    jetbrains.NewGolandServer,
+	config.IDERustRover: jetbrains.NewRustRoverServer,
+	config.IDEPyCharm:   jetbrains.NewPyCharmServer,
+	config.IDEPhpStorm:  jetbrains.NewPhpStorm,
+	config.IDEIntellij:  jetbrains.NewIntellij,
+	config.IDECLion:     jetbrains.NewCLionServer,
+	config.IDERider:     jetbrains.NewRiderServer,
+	config.IDERubyMine:  jetbrains.NewRubyMineServer,
+	config.IDEWebStorm:  jetbrains.NewWebStormServer,
+	config.IDEDataSpell: jetbrains.NewDataSpellServer,
+}
+
+var vscodeFlavors = map[config.IDE]vscode.Flavor{
+	config.IDEVSCode:         vscode.FlavorStable,
+	config.IDEVSCodeInsiders: vscode.FlavorInsiders,
+}
+
+// CacheDownloads returns the urls the workspace downloads to install the given ide and its extensions on the given
+// architecture. The driver stores them in the shared ide cache, which is read-only within the workspace.
+func CacheDownloads(ide string, values map[string]config.OptionValue, extensions []string, arch string, log log.Logger) []string {
+	downloads := []string{}
+	switch config.IDE(ide) {
+	case config.IDEOpenVSCode:
+		downloads = append(downloads, openvscode.ReleaseURL(values, arch))
+	case config.IDECodeServer:
+		downloads = append(downloads, codeserver.ReleaseURL(values, arch))
+	case config.IDEFleet:
+		if url := fleet.DownloadURL(values, arch); url != "" {
+			downloads = append(downloads, url)
+		}
+	case config.IDEVSCode, config.IDEVSCodeInsiders:
+		if url := vscode.ServerDownloadURL(values, vscodeFlavors[config.IDE(ide)], arch); url != "" {
+			downloads = append(downloads, url)
+		}
+	default:
+		if newServer, ok := jetBrainsServers[config.IDE(ide)]; ok {
+			downloads = append(downloads, newServer("", values, log).DownloadURL(arch))
+		}
+	}
+
+	// extensions that are installed from the extensions gallery or the marketplace of vscode within the workspace
+	gallery := vscode.Options.GetValue(values, vscode.ExtensionsGalleryOption)
+	marketplace := vscodeFlavors[config.IDE(ide)].Marketplace()
+	if gallery == "" && marketplace == "" {
+		return downloads
+	}
+
+	for _, extension := range extensions {
+		var (
+			downloadURL string
+			err         error
+		)
+		if gallery != "" {
+			downloadURL, err = vscode.ExtensionDownloadURL(gallery, extension)
+		} else {
+			downloadURL, err = vscode.MarketplaceDownloadURL(marketplace, extension, arch)
+		}
+		if err != nil {
+			log.Debugf("Error resolving the download of %s: %v", extension, err)
+			continue
+		}
+
+		downloads = append(downloads, downloadURL)
+	}
+
+	return downloads
+}
@@ -0,0 +1,128 @@
+package ideparse
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/config"
+	"dev.khulnasoft.com/pkg/ide/vscode"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCacheDownloads(t *testing.T) {
+	marketplace := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := &struct {
+			Filters []struct {
+				Criteria []struct {
+					Value string `json:"value"`
+				} `json:"criteria"`
+			} `json:"filters"`
+		}{}
+		if r.Method != http.MethodPost || r.URL.Path != "/_apis/public/gallery/extensionquery" || json.NewDecoder(r.Body).Decode(query) != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		versions := ""
+		switch query.Filters[0].Criteria[0].Value {
+		case "golang.go":
+			versions = `{"version":"0.42.0","properties":[{"key":"Microsoft.VisualStudio.Code.PreRelease","value":"true"}]},{"version":"0.41.2"},{"version":"0.40.0"}`
+		case "rust-lang.rust-analyzer":
+			versions = `{"version":"0.3.2","targetPlatform":"linux-arm64"},{"version":"0.3.2","targetPlatform":"linux-x64"}`
+		default:
+			_, _ = w.Write([]byte(`{"results":[{"extensions":[]}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"results":[{"extensions":[{"versions":[` + versions + `]}]}]}`))
+	}))
+	defer marketplace.Close()
+
+	gallery := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/golang/go" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"version":"0.41.2","files":{"download":"https://open-vsx.org/golang.go-0.41.2.vsix"}}`))
+	}))
+	defer gallery.Close()
+
+	marketplaceURL := vscode.MarketplaceURL
+	vscode.MarketplaceURL = marketplace.URL
+	defer func() { vscode.MarketplaceURL = marketplaceURL }()
+
+	commit := "f1a4fb101478ce6ec82fe9627c43efbf9e98c813"
+	tests := []struct {
+		name       string
+		ide        string
+		values     map[string]config.OptionValue
+		extensions []string
+		arch       string
+		want       []string
+	}{
+		{
+			name: "VS Code server of the local commit",
+			ide:  string(config.IDEVSCode),
+			values: map[string]config.OptionValue{
+				vscode.ServerCommitOption: {Value: commit},
+			},
+			arch: "arm64",
+			want: []string{"https://update.code.visualstudio.com/commit:" + commit + "/server-linux-arm64/stable"},
+		},
+		{
+			name: "VS Code Insiders server of the local commit",
+			ide:  string(config.IDEVSCodeInsiders),
+			values: map[string]config.OptionValue{
+				vscode.ServerCommitOption: {Value: commit},
+			},
+			arch: "amd64",
+			want: []string{"https://update.code.visualstudio.com/commit:" + commit + "/server-linux-x64/insider"},
+		},
+		{
+			name: "VS Code without a local commit",
+			ide:  string(config.IDEVSCode),
+			arch: "amd64",
+			want: []string{},
+		},
+		{
+			name:       "Extensions from the marketplace",
+			ide:        string(config.IDEVSCode),
+			extensions: []string{"golang.go", "golang.go@0.40.0", "rust-lang.rust-analyzer", "unknown.extension"},
+			arch:       "amd64",
+			want: []string{
+				marketplace.URL + "/_apis/public/gallery/publishers/golang/vsextensions/go/0.41.2/vspackage",
+				marketplace.URL + "/_apis/public/gallery/publishers/golang/vsextensions/go/0.40.0/vspackage",
+				marketplace.URL + "/_apis/public/gallery/publishers/rust-lang/vsextensions/rust-analyzer/0.3.2/vspackage?targetPlatform=linux-x64",
+			},
+		},
+		{
+			name: "Extensions from the extensions gallery",
+			ide:  string(config.IDEVSCode),
+			values: map[string]config.OptionValue{
+				vscode.ExtensionsGalleryOption: {Value: gallery.URL},
+			},
+			extensions: []string{"golang.go", "rust-lang.rust-analyzer"},
+			arch:       "amd64",
+			want:       []string{"https://open-vsx.org/golang.go-0.41.2.vsix"},
+		},
+		{
+			name:       "Extensions of flavors without a known marketplace",
+			ide:        string(config.IDECursor),
+			extensions: []string{"golang.go"},
+			arch:       "amd64",
+			want:       []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CacheDownloads(tt.ide, tt.values, tt.extensions, tt.arch, log.Discard)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("CacheDownloads() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
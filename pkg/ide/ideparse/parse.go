@@ -9,11 +9,14 @@ import (
 	"dev.khulnasoft.com/pkg/command"
 	"dev.khulnasoft.com/pkg/config"
 	"dev.khulnasoft.com/pkg/ide"
+	"dev.khulnasoft.com/pkg/ide/codeserver"
+	"dev.khulnasoft.com/pkg/ide/custom"
 	"dev.khulnasoft.com/pkg/ide/fleet"
 	"dev.khulnasoft.com/pkg/ide/jetbrains"
 	"dev.khulnasoft.com/pkg/ide/jupyter"
 	"dev.khulnasoft.com/pkg/ide/openvscode"
 	"dev.khulnasoft.com/pkg/ide/rstudio"
+	"dev.khulnasoft.com/pkg/ide/terminal"
 	"dev.khulnasoft.com/pkg/ide/vscode"
 	"dev.khulnasoft.com/pkg/provider"
 	"github.com/pkg/errors"
@@ -34,6 +37,8 @@ type AllowedIDE struct {
 	Experimental bool `json:"experimental,omitempty"`
 	// Group this IDE belongs to, e.g. for navigation
 	Group config.IDEGroup `json:"group,omitempty"`
+	// Custom indicates that this IDE was added with devspace ide add
+	Custom bool `json:"custom,omitempty"`
 }
 
 var AllowedIDEs = []AllowedIDE{
@@ -170,6 +175,23 @@ var AllowedIDEs = []AllowedIDE{
 		Experimental: true,
 		Group:        config.IDEGroupOther,
 	},
+	{
+		Name:         config.IDEJupyterLab,
+		DisplayName:  "JupyterLab",
+		Options:      jupyter.Options,
+		Icon:         "https://dev.khulnasoft.com/assets/jupyter.svg",
+		IconDark:     "https://dev.khulnasoft.com/assets/jupyter_dark.svg",
+		Experimental: true,
+		Group:        config.IDEGroupOther,
+	},
+	{
+		Name:         config.IDECodeServer,
+		DisplayName:  "code-server",
+		Options:      codeserver.Options,
+		Icon:         "https://dev.khulnasoft.com/assets/vscodebrowser.svg",
+		Experimental: true,
+		Group:        config.IDEGroupOther,
+	},
 	{
 		Name:         config.IDEVSCodeInsiders,
 		DisplayName:  "VSCode Insiders",
@@ -202,9 +224,37 @@ var AllowedIDEs = []AllowedIDE{
 		Experimental: true,
 		Group:        config.IDEGroupPrimary,
 	},
+	{
+		Name:         config.IDETerminal,
+		DisplayName:  "Terminal",
+		Options:      terminal.Options,
+		Icon:         "https://dev.khulnasoft.com/assets/terminal.svg",
+		Experimental: true,
+		Group:        config.IDEGroupOther,
+	},
 }
 
 func RefreshIDEOptions(devSpaceConfig *config.Config, workspace *provider.Workspace, ide string, options []string) (*provider.Workspace, error) {
+	ideConfig, err := ResolveIDE(devSpaceConfig, workspace, ide, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// check if we need to modify workspace
+	if !reflect.DeepEqual(workspace.IDE, *ideConfig) {
+		workspace.IDE = *ideConfig
+		err = provider.SaveWorkspaceConfig(workspace)
+		if err != nil {
+			return nil, errors.Wrap(err, "save workspace")
+		}
+	}
+
+	return workspace, nil
+}
+
+// ResolveIDE returns the configuration of the IDE for the workspace without changing the workspace. If ide is empty,
+// the IDE of the workspace or the default IDE is used.
+func ResolveIDE(devSpaceConfig *config.Config, workspace *provider.Workspace, ide string, options []string) (*provider.WorkspaceIDEConfig, error) {
 	ide = strings.ToLower(ide)
 	if ide == "" {
 		if workspace.IDE.Name != "" {
@@ -217,11 +267,19 @@ func RefreshIDEOptions(devSpaceConfig *config.Config, workspace *provider.Worksp
 	}
 
 	// get ide options
-	ideOptions, err := GetIDEOptions(ide)
+	ideOptions, err := GetIDEOptions(devSpaceConfig, ide)
 	if err != nil {
 		return nil, err
 	}
 
+	// get custom ide manifest
+	customIDE, err := custom.LoadIDEConfig(devSpaceConfig.DefaultContext, ide)
+	if err != nil {
+		return nil, errors.Wrap(err, "load ide")
+	} else if IsBuiltinIDE(ide) {
+		customIDE = nil
+	}
+
 	// get global options and set them as non user
 	// provided.
 	retValues := devSpaceConfig.IDEOptions(ide)
@@ -251,22 +309,55 @@ func RefreshIDEOptions(devSpaceConfig *config.Config, workspace *provider.Worksp
 		retValues[k] = v
 	}
 
-	// check if we need to modify workspace
-	if workspace.IDE.Name != ide || !reflect.DeepEqual(workspace.IDE.Options, retValues) {
-		workspace.IDE.Name = ide
-		workspace.IDE.Options = retValues
-		err = provider.SaveWorkspaceConfig(workspace)
-		if err != nil {
-			return nil, errors.Wrap(err, "save workspace")
+	// the server of vscode has to match the local vscode
+	if flavor, ok := vscodeFlavors[config.IDE(ide)]; ok && retValues[vscode.ServerCommitOption].Value == "" {
+		if commit := vscode.LocalServerCommit(flavor); commit != "" {
+			retValues[vscode.ServerCommitOption] = config.OptionValue{Value: commit}
 		}
 	}
 
-	return workspace, nil
+	return &provider.WorkspaceIDEConfig{
+		Name:    ide,
+		Options: retValues,
+		Custom:  customIDE,
+	}, nil
 }
 
-func GetIDEOptions(ide string) (ide.Options, error) {
+// GetAllowedIDEs returns the built-in IDEs together with the IDEs added to the current context
+func GetAllowedIDEs(devSpaceConfig *config.Config) ([]AllowedIDE, error) {
+	customIDEs, err := custom.LoadIDEConfigs(devSpaceConfig.DefaultContext)
+	if err != nil {
+		return nil, err
+	}
+
+	retIDEs := append([]AllowedIDE{}, AllowedIDEs...)
+	for _, customIDE := range customIDEs {
+		if IsBuiltinIDE(customIDE.Name) {
+			continue
+		}
+
+		retIDEs = append(retIDEs, AllowedIDE{
+			Name:        config.IDE(customIDE.Name),
+			DisplayName: customIDE.DisplayName,
+			Options:     customIDE.Options,
+			Icon:        customIDE.Icon,
+			IconDark:    customIDE.IconDark,
+			Group:       customIDE.Group,
+			Custom:      true,
+		})
+	}
+
+	return retIDEs, nil
+}
+
+func GetIDEOptions(devSpaceConfig *config.Config, ide string) (ide.Options, error) {
+	allowedIDEs, err := GetAllowedIDEs(devSpaceConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	var match *AllowedIDE
-	for _, m := range AllowedIDEs {
+	for _, m := range allowedIDEs {
 		m := m
 		if string(m.Name) == ide {
 			match = &m
@@ -275,7 +366,7 @@ func GetIDEOptions(ide string) (ide.Options, error) {
 	}
 	if match == nil {
 		allowedIDEArray := []string{}
-		for _, a := range AllowedIDEs {
+		for _, a := range allowedIDEs {
 			allowedIDEArray = append(allowedIDEArray, string(a.Name))
 		}
 
@@ -285,6 +376,17 @@ func GetIDEOptions(ide string) (ide.Options, error) {
 	return match.Options, nil
 }
 
+// IsBuiltinIDE checks if the IDE ships with DevSpace
+func IsBuiltinIDE(ide string) bool {
+	for _, m := range AllowedIDEs {
+		if string(m.Name) == ide {
+			return true
+		}
+	}
+
+	return false
+}
+
 func ParseOptions(options []string, ideOptions ide.Options) (map[string]config.OptionValue, error) {
 	if ideOptions == nil {
 		ideOptions = ide.Options{}
@@ -89,6 +89,15 @@ func (o *GenericJetBrainsServer) GetVolume() string {
 	return fmt.Sprintf("type=volume,src=devspace-%s,dst=%s", o.options.ID, o.getDownloadFolder())
 }
 
+// DownloadURL returns the download url of the backend for the given architecture
+func (o *GenericJetBrainsServer) DownloadURL(arch string) string {
+	if arch == "arm64" {
+		return o.options.DownloadArm64
+	}
+
+	return o.options.DownloadAmd64
+}
+
 func (o *GenericJetBrainsServer) getDownloadFolder() string {
 	return fmt.Sprintf("/var/devspace/%s", o.options.ID)
 }
@@ -162,9 +171,17 @@ func (o *GenericJetBrainsServer) download(targetFolder string, log log.Logger) (
 		return "", err
 	}
 
-	downloadURL := o.options.DownloadAmd64
-	if runtime.GOARCH == "arm64" {
-		downloadURL = o.options.DownloadArm64
+	downloadURL := o.DownloadURL(runtime.GOARCH)
+
+	// use the ide cache shared between workspaces if it is mounted
+	if ide.CacheAvailable() {
+		cachedPath, err := ide.CachedDownload(ide.CacheFolder, downloadURL)
+		if err == nil {
+			log.Infof("Use cached download of %s", o.options.DisplayName)
+			return cachedPath, nil
+		}
+
+		log.Debugf("Error using ide cache: %v", err)
 	}
 
 	targetPath := path.Join(filepath.ToSlash(targetFolder), o.options.ID+".tar.gz")
@@ -2,67 +2,110 @@ package jupyter
 
 import (
 	"fmt"
-	"os/exec"
-	"strconv"
 
+	"dev.khulnasoft.com/log"
 	"dev.khulnasoft.com/pkg/command"
 	"dev.khulnasoft.com/pkg/config"
 	"dev.khulnasoft.com/pkg/ide"
-	"dev.khulnasoft.com/pkg/single"
-	"dev.khulnasoft.com/log"
+	"github.com/alessio/shellescape"
 )
 
 const (
-	OpenOption        = "OPEN"
-	BindAddressOption = "BIND_ADDRESS"
+	OpenOption            = ide.OpenOption
+	BindAddressOption     = ide.BindAddressOption
+	RegisterKernelsOption = "REGISTER_KERNELS"
+	DefaultKernelOption   = "DEFAULT_KERNEL"
 )
 
-var Options = ide.Options{
-	BindAddressOption: {
-		Name:        BindAddressOption,
-		Description: "The address to bind the server to locally. E.g. 0.0.0.0:12345",
-		Default:     "",
-	},
-	OpenOption: {
-		Name:        OpenOption,
-		Description: "If DevSpace should automatically open the browser",
+var Options = ide.WithWebOptions(ide.Options{
+	RegisterKernelsOption: {
+		Name:        RegisterKernelsOption,
+		Description: "If DevSpace should register the conda and virtual environments of the workspace as kernels",
 		Default:     "true",
 		Enum: []string{
 			"true",
 			"false",
 		},
 	},
+	DefaultKernelOption: {
+		Name:        DefaultKernelOption,
+		Description: "The kernel new notebooks are started with. Defaults to the first environment found in the workspace",
+	},
+})
+
+const (
+	DefaultServerPort    = 10700
+	DefaultLabServerPort = 10701
+)
+
+// Flavor is the web application the jupyter server is started with
+type Flavor string
+
+const (
+	FlavorNotebook Flavor = "notebook"
+	FlavorLab      Flavor = "lab"
+)
+
+func NewJupyterNotebookServer(workspaceFolder string, userName string, values map[string]config.OptionValue, log log.Logger) *JupyterServer {
+	return newJupyterServer(FlavorNotebook, workspaceFolder, userName, values, log)
 }
 
-const DefaultServerPort = 10700
+func NewJupyterLabServer(workspaceFolder string, userName string, values map[string]config.OptionValue, log log.Logger) *JupyterServer {
+	return newJupyterServer(FlavorLab, workspaceFolder, userName, values, log)
+}
 
-func NewJupyterNotebookServer(workspaceFolder string, userName string, values map[string]config.OptionValue, log log.Logger) *JupyterNotbookServer {
-	return &JupyterNotbookServer{
+func newJupyterServer(flavor Flavor, workspaceFolder string, userName string, values map[string]config.OptionValue, log log.Logger) *JupyterServer {
+	server := &JupyterServer{
+		flavor:          flavor,
 		values:          values,
 		workspaceFolder: workspaceFolder,
 		userName:        userName,
 		log:             log,
 	}
+	server.webServer = &ide.WebServer{
+		Name:            "jupyter",
+		Port:            DefaultServerPort,
+		ReadinessPath:   "/api",
+		UserName:        userName,
+		WorkspaceFolder: workspaceFolder,
+		Log:             log,
+	}
+	if flavor == FlavorLab {
+		server.webServer.Name = "jupyterlab"
+		server.webServer.Port = DefaultLabServerPort
+	}
+
+	return server
 }
 
-type JupyterNotbookServer struct {
+type JupyterServer struct {
+	flavor          Flavor
 	values          map[string]config.OptionValue
 	workspaceFolder string
 	userName        string
+	webServer       *ide.WebServer
 	log             log.Logger
 }
 
-func (o *JupyterNotbookServer) Install() error {
-	err := o.installNotebook()
+func (o *JupyterServer) Install() error {
+	err := o.installJupyter()
 	if err != nil {
 		return err
 	}
 
-	return o.Start()
+	defaultKernel := Options.GetValue(o.values, DefaultKernelOption)
+	if Options.GetValue(o.values, RegisterKernelsOption) == "true" {
+		kernels := o.registerKernels()
+		if defaultKernel == "" && len(kernels) > 0 && kernels[0].Workspace {
+			defaultKernel = kernels[0].Name
+		}
+	}
+
+	return o.Start(defaultKernel)
 }
 
-func (o *JupyterNotbookServer) installNotebook() error {
-	if command.ExistsForUser("jupyter", o.userName) {
+func (o *JupyterServer) installJupyter() error {
+	if command.ExistsForUser("jupyter-"+string(o.flavor), o.userName) {
 		return nil
 	}
 
@@ -76,38 +119,78 @@ func (o *JupyterNotbookServer) installNotebook() error {
 		return fmt.Errorf("seems like neither pip3 nor pip exists, please make sure to install python correctly")
 	}
 
-	// install notebook command
-	runCommand := fmt.Sprintf("%s install notebook", baseCommand)
-	args := []string{}
-	if o.userName != "" {
-		args = append(args, "su", o.userName, "-c", runCommand)
-	} else {
-		args = append(args, "sh", "-c", runCommand)
-	}
-
 	// install
-	o.log.Infof("Installing jupyter notebook...")
-	out, err := exec.Command(args[0], args[1:]...).CombinedOutput()
+	o.log.Infof("Installing %s...", o.displayName())
+	err := o.webServer.Run(fmt.Sprintf("%s install %s", baseCommand, o.pipPackage()))
 	if err != nil {
-		return fmt.Errorf("error installing jupyter notebook: %w", command.WrapCommandError(out, err))
+		return fmt.Errorf("error installing %s: %w", o.displayName(), err)
 	}
 
-	o.log.Info("Successfully installed jupyter notebook")
+	o.log.Infof("Successfully installed %s", o.displayName())
 	return nil
 }
 
-func (o *JupyterNotbookServer) Start() error {
-	return single.Single("jupyter.pid", func() (*exec.Cmd, error) {
-		o.log.Infof("Starting jupyter notebook in background...")
-		runCommand := fmt.Sprintf("jupyter notebook --ip='*' --NotebookApp.notebook_dir='%s' --NotebookApp.token='' --NotebookApp.password='' --no-browser --port '%s' --allow-root", o.workspaceFolder, strconv.Itoa(DefaultServerPort))
-		args := []string{}
-		if o.userName != "" {
-			args = append(args, "su", o.userName, "-w", "SSH_AUTH_SOCK", "-l", "-c", runCommand)
-		} else {
-			args = append(args, "sh", "-l", "-c", runCommand)
+// registerKernels registers the environments of the workspace and conda as kernels of the remote user. Environments
+// without ipykernel get it installed, failures are only logged so a broken environment doesn't block the server.
+func (o *JupyterServer) registerKernels() []Kernel {
+	condaPrefixes, err := o.condaEnvironments()
+	if err != nil {
+		o.log.Debugf("Error listing conda environments: %v", err)
+	}
+
+	registered := []Kernel{}
+	for _, kernel := range FindKernels(o.workspaceFolder, condaPrefixes) {
+		python := shellescape.Quote(kernel.Python)
+		err := o.webServer.Run(python + " -m ipykernel --version")
+		if err != nil {
+			o.log.Infof("Installing ipykernel into %s...", kernel.Prefix)
+			err = o.webServer.Run(python + " -m pip install --quiet ipykernel")
+			if err != nil {
+				o.log.Warnf("Error installing ipykernel into %s: %v", kernel.Prefix, err)
+				continue
+			}
 		}
-		cmd := exec.Command(args[0], args[1:]...)
-		cmd.Dir = o.workspaceFolder
-		return cmd, nil
-	})
+
+		err = o.webServer.Run(fmt.Sprintf("%s -m ipykernel install --user --name %s --display-name %s", python, shellescape.Quote(kernel.Name), shellescape.Quote(kernel.DisplayName)))
+		if err != nil {
+			o.log.Warnf("Error registering kernel %s: %v", kernel.Name, err)
+			continue
+		}
+
+		o.log.Debugf("Registered kernel %s for %s", kernel.Name, kernel.Prefix)
+		registered = append(registered, kernel)
+	}
+
+	return registered
+}
+
+func (o *JupyterServer) Start(defaultKernel string) error {
+	runCommand := ""
+	if o.flavor == FlavorLab {
+		runCommand = fmt.Sprintf("jupyter lab --ip='*' --ServerApp.root_dir=%s --ServerApp.token='' --ServerApp.password='' --no-browser --port '%d' --allow-root", shellescape.Quote(o.workspaceFolder), o.webServer.Port)
+	} else {
+		runCommand = fmt.Sprintf("jupyter notebook --ip='*' --NotebookApp.notebook_dir=%s --NotebookApp.token='' --NotebookApp.password='' --no-browser --port '%d' --allow-root", shellescape.Quote(o.workspaceFolder), o.webServer.Port)
+	}
+	if defaultKernel != "" {
+		runCommand += " --MappingKernelManager.default_kernel_name=" + shellescape.Quote(defaultKernel)
+	}
+
+	o.webServer.Command = runCommand
+	return o.webServer.Start()
+}
+
+func (o *JupyterServer) pipPackage() string {
+	if o.flavor == FlavorLab {
+		return "jupyterlab"
+	}
+
+	return "notebook"
+}
+
+func (o *JupyterServer) displayName() string {
+	if o.flavor == FlavorLab {
+		return "JupyterLab"
+	}
+
+	return "jupyter notebook"
 }
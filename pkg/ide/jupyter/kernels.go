@@ -0,0 +1,146 @@
+package jupyter
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"dev.khulnasoft.com/pkg/command"
+)
+
+// maxKernelSearchDepth is how deep environments are searched for within the workspace, e.g. .venv or envs/ml
+const maxKernelSearchDepth = 2
+
+var invalidKernelNameRegEx = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// Kernel is a python environment that can be registered as jupyter kernel
+type Kernel struct {
+	Name        string
+	DisplayName string
+
+	// Prefix is the folder of the environment
+	Prefix string
+
+	// Python is the interpreter of the environment
+	Python string
+
+	// Workspace is true if the environment is within the workspace folder
+	Workspace bool
+}
+
+// FindKernels returns the virtual environments and conda environments within the workspace folder followed by the
+// given conda environments
+func FindKernels(workspaceFolder string, condaPrefixes []string) []Kernel {
+	kernels := []Kernel{}
+	names := map[string]bool{}
+	prefixes := map[string]bool{}
+	add := func(kernel Kernel) {
+		if prefixes[kernel.Prefix] {
+			return
+		} else if _, err := os.Stat(kernel.Python); err != nil {
+			return
+		}
+
+		name := kernel.Name
+		for i := 2; names[kernel.Name]; i++ {
+			kernel.Name = name + "-" + strconv.Itoa(i)
+		}
+
+		names[kernel.Name] = true
+		prefixes[kernel.Prefix] = true
+		kernels = append(kernels, kernel)
+	}
+
+	_ = filepath.WalkDir(workspaceFolder, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(workspaceFolder, path)
+		if err != nil {
+			return nil
+		} else if relPath == "." {
+			return nil
+		} else if entry.Name() == ".git" || entry.Name() == "node_modules" {
+			return filepath.SkipDir
+		}
+
+		isEnvironment := fileExists(filepath.Join(path, "pyvenv.cfg")) || fileExists(filepath.Join(path, "conda-meta"))
+		if isEnvironment {
+			add(Kernel{
+				Name:        kernelName(relPath),
+				DisplayName: "Python (" + filepath.ToSlash(relPath) + ")",
+				Prefix:      path,
+				Python:      filepath.Join(path, "bin", "python"),
+				Workspace:   true,
+			})
+			return filepath.SkipDir
+		} else if strings.Count(filepath.ToSlash(relPath), "/")+1 >= maxKernelSearchDepth {
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+
+	for _, prefix := range condaPrefixes {
+		name := filepath.Base(prefix)
+		if fileExists(filepath.Join(prefix, "condabin")) {
+			// the root prefix of the conda installation
+			name = "base"
+		}
+
+		add(Kernel{
+			Name:        kernelName("conda-" + name),
+			DisplayName: "Python (conda: " + name + ")",
+			Prefix:      prefix,
+			Python:      filepath.Join(prefix, "bin", "python"),
+		})
+	}
+
+	return kernels
+}
+
+// condaEnvironments lists the conda environments of the remote user
+func (o *JupyterServer) condaEnvironments() ([]string, error) {
+	if !command.ExistsForUser("conda", o.userName) {
+		return nil, nil
+	}
+
+	args := []string{"sh", "-l", "-c", "conda env list --json"}
+	if o.userName != "" {
+		args = []string{"su", o.userName, "-l", "-c", "conda env list --json"}
+	}
+	out, err := exec.Command(args[0], args[1:]...).Output()
+	if err != nil {
+		return nil, command.WrapCommandError(out, err)
+	}
+
+	environments := &struct {
+		Envs []string `json:"envs"`
+	}{}
+	err = json.Unmarshal(out, environments)
+	if err != nil {
+		return nil, err
+	}
+
+	return environments.Envs, nil
+}
+
+func kernelName(path string) string {
+	name := strings.Trim(invalidKernelNameRegEx.ReplaceAllString(filepath.ToSlash(path), "-"), ".-")
+	if name == "" {
+		return "workspace"
+	}
+
+	return strings.ToLower(name)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
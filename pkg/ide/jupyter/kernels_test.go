@@ -0,0 +1,85 @@
+package jupyter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFindKernels(t *testing.T) {
+	workspaceFolder := t.TempDir()
+	condaFolder := t.TempDir()
+	for _, file := range []string{
+		".venv/pyvenv.cfg",
+		".venv/bin/python",
+		"envs/ml/conda-meta/history",
+		"envs/ml/bin/python",
+		"node_modules/pkg/pyvenv.cfg",
+		"node_modules/pkg/bin/python",
+		"src/app/module/pyvenv.cfg",
+		"src/app/module/bin/python",
+		"broken/pyvenv.cfg",
+	} {
+		writeFile(t, filepath.Join(workspaceFolder, file))
+	}
+	for _, file := range []string{
+		"condabin/conda",
+		"bin/python",
+		"envs/torch/bin/python",
+	} {
+		writeFile(t, filepath.Join(condaFolder, file))
+	}
+
+	got := FindKernels(workspaceFolder, []string{
+		condaFolder,
+		filepath.Join(condaFolder, "envs", "torch"),
+		filepath.Join(workspaceFolder, "envs", "ml"),
+	})
+	want := []Kernel{
+		{
+			Name:        "venv",
+			DisplayName: "Python (.venv)",
+			Prefix:      filepath.Join(workspaceFolder, ".venv"),
+			Python:      filepath.Join(workspaceFolder, ".venv", "bin", "python"),
+			Workspace:   true,
+		},
+		{
+			Name:        "envs-ml",
+			DisplayName: "Python (envs/ml)",
+			Prefix:      filepath.Join(workspaceFolder, "envs", "ml"),
+			Python:      filepath.Join(workspaceFolder, "envs", "ml", "bin", "python"),
+			Workspace:   true,
+		},
+		{
+			Name:        "conda-base",
+			DisplayName: "Python (conda: base)",
+			Prefix:      condaFolder,
+			Python:      filepath.Join(condaFolder, "bin", "python"),
+		},
+		{
+			Name:        "conda-torch",
+			DisplayName: "Python (conda: torch)",
+			Prefix:      filepath.Join(condaFolder, "envs", "torch"),
+			Python:      filepath.Join(condaFolder, "envs", "torch", "bin", "python"),
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("FindKernels() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func writeFile(t *testing.T, path string) {
+	t.Helper()
+
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(path, nil, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -12,12 +12,12 @@ import (
 	"dev.khulnasoft.com/pkg/config"
 	copy2 "dev.khulnasoft.com/pkg/copy"
 	"dev.khulnasoft.com/pkg/extract"
-	devspacehttp "dev.khulnasoft.com/pkg/http"
 	"dev.khulnasoft.com/pkg/ide"
 	"dev.khulnasoft.com/pkg/ide/vscode"
 	"dev.khulnasoft.com/pkg/single"
 	"dev.khulnasoft.com/pkg/util"
 	"dev.khulnasoft.com/log"
+	"github.com/alessio/shellescape"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
@@ -73,6 +73,8 @@ var Options = ide.Options{
 		Name:        DownloadAmd64Option,
 		Description: "The download url for the amd64 vscode server binary",
 	},
+	vscode.ExtensionsVSIXFolderOption: vscode.Options[vscode.ExtensionsVSIXFolderOption],
+	vscode.ExtensionsGalleryOption:    vscode.Options[vscode.ExtensionsGalleryOption],
 }
 
 const DefaultVSCodePort = 10800
@@ -122,18 +124,18 @@ func (o *OpenVSCodeServer) Install() error {
 	}
 
 	// check what release we need to download
-	url := o.getReleaseUrl()
+	url := ReleaseURL(o.values, runtime.GOARCH)
 
 	vscode.InstallAPKRequirements(o.log)
 
 	// download tar
-	resp, err := devspacehttp.GetHTTPClient().Get(url)
+	body, err := ide.OpenDownload(url, o.log)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
+	defer body.Close()
 
-	err = extract.Extract(resp.Body, location, extract.StripLevels(1))
+	err = extract.Extract(body, location, extract.StripLevels(1))
 	if err != nil {
 		return errors.Wrap(err, "extract vscode")
 	}
@@ -155,17 +157,18 @@ func (o *OpenVSCodeServer) Install() error {
 	return nil
 }
 
-func (o *OpenVSCodeServer) getReleaseUrl() string {
+// ReleaseURL returns the download url of the openvscode server for the given architecture
+func ReleaseURL(values map[string]config.OptionValue, arch string) string {
 	var url string
-	version := Options.GetValue(o.values, VersionOption)
+	version := Options.GetValue(values, VersionOption)
 
-	if runtime.GOARCH == "arm64" {
-		url = Options.GetValue(o.values, DownloadArm64Option)
+	if arch == "arm64" {
+		url = Options.GetValue(values, DownloadArm64Option)
 		if url == "" {
 			url = fmt.Sprintf(DownloadArm64Template, version, version)
 		}
 	} else {
-		url = Options.GetValue(o.values, DownloadAmd64Option)
+		url = Options.GetValue(values, DownloadAmd64Option)
 		if url == "" {
 			url = fmt.Sprintf(DownloadAmd64Template, version, version)
 		}
@@ -188,19 +191,22 @@ func (o *OpenVSCodeServer) installExtensions() error {
 	defer out.Close()
 
 	binaryPath := filepath.Join(location, "bin", "openvscode-server")
+	gallery := Options.GetValue(o.values, vscode.ExtensionsGalleryOption)
 	for _, extension := range o.extensions {
 		o.log.Info("Install extension " + extension + "...")
-		runCommand := fmt.Sprintf("%s --install-extension '%s'", binaryPath, extension)
-		args := []string{}
-		if o.userName != "" {
-			args = append(args, "su", o.userName, "-c", runCommand)
-		} else {
-			args = append(args, "sh", "-c", runCommand)
-		}
-		cmd := exec.Command(args[0], args[1:]...)
-		cmd.Stdout = out
-		cmd.Stderr = out
-		err = cmd.Run()
+		err = vscode.InstallExtension(extension, gallery, "", o.log, func(extension string) error {
+			runCommand := fmt.Sprintf("%s --install-extension %s", binaryPath, shellescape.Quote(extension))
+			args := []string{}
+			if o.userName != "" {
+				args = append(args, "su", o.userName, "-c", runCommand)
+			} else {
+				args = append(args, "sh", "-c", runCommand)
+			}
+			cmd := exec.Command(args[0], args[1:]...)
+			cmd.Stdout = out
+			cmd.Stderr = out
+			return cmd.Run()
+		})
 		if err != nil {
 			o.log.Info("Failed installing extension " + extension)
 		} else {
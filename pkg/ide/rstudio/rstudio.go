@@ -19,31 +19,15 @@ import (
 	copypkg "dev.khulnasoft.com/pkg/copy"
 	devspacehttp "dev.khulnasoft.com/pkg/http"
 	"dev.khulnasoft.com/pkg/ide"
-	"dev.khulnasoft.com/pkg/single"
 	"dev.khulnasoft.com/log"
 )
 
 const (
-	OpenOption        = "OPEN"
-	BindAddressOption = "BIND_ADDRESS"
+	OpenOption        = ide.OpenOption
+	BindAddressOption = ide.BindAddressOption
 )
 
-var Options = ide.Options{
-	BindAddressOption: {
-		Name:        BindAddressOption,
-		Description: "The address to bind the server to locally. E.g. 0.0.0.0:12345",
-		Default:     "",
-	},
-	OpenOption: {
-		Name:        OpenOption,
-		Description: "If DevSpace should automatically open the browser",
-		Default:     "true",
-		Enum: []string{
-			"true",
-			"false",
-		},
-	},
-}
+var Options = ide.WithWebOptions(ide.Options{})
 
 const (
 	DefaultServerPort = 8787
@@ -67,7 +51,16 @@ func NewRStudioServer(workspaceFolder string, userName string, values map[string
 		values:          values,
 		workspaceFolder: workspaceFolder,
 		userName:        userName,
-		log:             log,
+		webServer: &ide.WebServer{
+			Name:            "rstudio",
+			Command:         fmt.Sprintf("rserver --server-daemonize=0 --server-pid-file=%s/rserver.pid --www-port=%d", dataFolder, DefaultServerPort),
+			Port:            DefaultServerPort,
+			ReadinessPath:   "/",
+			UserName:        userName,
+			WorkspaceFolder: workspaceFolder,
+			Log:             log,
+		},
+		log: log,
 	}
 }
 
@@ -75,6 +68,7 @@ type RStudioServer struct {
 	values          map[string]config.OptionValue
 	workspaceFolder string
 	userName        string
+	webServer       *ide.WebServer
 	log             log.Logger
 }
 
@@ -87,10 +81,15 @@ func (o *RStudioServer) Install() error {
 		return fmt.Errorf("R has to be available in image to use RStudio") //nolint:all
 	}
 
-	// Skip if already installed
+	// Skip if already installed, e.g. in rocker images, but still run it as the remote user
 	if command.ExistsForUser("rstudio-server", o.userName) {
 		o.log.Debug("RStudio is already installed, skipping installation")
-		return nil
+		err := o.configure()
+		if err != nil {
+			return err
+		}
+
+		return o.Start()
 	}
 	o.log.Info("Installing RStudio")
 
@@ -118,39 +117,34 @@ func (o *RStudioServer) Install() error {
 		return err
 	}
 
-	err = ensureConfigFolder(o.userName)
+	err = o.configure()
 	if err != nil {
 		return err
 	}
+	o.log.Done("Successfully installed RStudio")
 
-	err = setupSingleUserMode(dataFolder, o.userName)
+	return o.Start()
+}
+
+// configure sets up rserver to run as the remote user, which needs the server-user setting when rserver isn't started
+// as root
+func (o *RStudioServer) configure() error {
+	err := ensureConfigFolder(o.userName)
 	if err != nil {
 		return err
 	}
 
-	err = setupPreferences(o.workspaceFolder, o.userName)
+	err = setupSingleUserMode(dataFolder, o.userName)
 	if err != nil {
 		return err
 	}
-	o.log.Done("Successfully installed RStudio")
 
-	return o.Start()
+	return setupPreferences(o.workspaceFolder, o.userName)
 }
 
+// Start runs rserver in the foreground, so the web server runtime notices if it crashes
 func (o *RStudioServer) Start() error {
-	return single.Single("rstudio.pid", func() (*exec.Cmd, error) {
-		o.log.Info("Starting RStudio...")
-		runCommand := "rstudio-server start"
-		args := []string{}
-		if o.userName != "" {
-			args = append(args, "su", o.userName, "-w", "SSH_AUTH_SOCK", "-l", "-c", runCommand)
-		} else {
-			args = append(args, "sh", "-l", "-c", runCommand)
-		}
-		cmd := exec.Command(args[0], args[1:]...)
-		cmd.Dir = o.workspaceFolder
-		return cmd, nil
-	})
+	return o.webServer.Start()
 }
 
 func ensureGdebi(log log.Logger) error {
@@ -308,18 +302,25 @@ directory=%s`, configFolder)
 		return fmt.Errorf("save db conf: %w", err)
 	}
 
-	rServerConf := fmt.Sprintf(`# https://docs.posit.co/ide/server-pro/access_and_security/server_permissions.html#running-without-permissions
-server-user=%s
-auth-none=1
-auth-minimum-user-id=0
-
-server-data-dir=%s
-database-config-file=%s/dbconf.conf
-`, userName, configFolder, configFolder)
 	serverConfPath := filepath.Join(rstudioConfigFolder, "rserver.conf")
-	// The RStudio installer automatically creates an empty file at destConfPath, let's try to remove that first
-	_ = os.Remove(serverConfPath)
-	err = os.WriteFile(serverConfPath, []byte(rServerConf), os.ModePerm)
+	existingConf, err := os.ReadFile(serverConfPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("read rserver conf: %w", err)
+	}
+
+	// https://docs.posit.co/ide/server-pro/access_and_security/server_permissions.html#running-without-permissions
+	rServerConf := mergeServerConf(string(existingConf), [][2]string{
+		{"server-user", userName},
+		{"auth-none", "1"},
+		{"auth-minimum-user-id", "0"},
+		{"server-data-dir", configFolder},
+		{"database-config-file", configFolder + "/dbconf.conf"},
+	})
+	err = os.MkdirAll(rstudioConfigFolder, 0755)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(serverConfPath, []byte(rServerConf), 0644)
 	if err != nil {
 		return fmt.Errorf("save rserver conf: %w", err)
 	}
@@ -327,6 +328,30 @@ database-config-file=%s/dbconf.conf
 	return nil
 }
 
+// mergeServerConf sets the given settings in the rserver.conf and keeps all other settings, such as the R version
+// configured by rocker images
+func mergeServerConf(existingConf string, settings [][2]string) string {
+	keys := map[string]bool{}
+	for _, setting := range settings {
+		keys[setting[0]] = true
+	}
+
+	lines := []string{}
+	for _, line := range strings.Split(existingConf, "\n") {
+		key, _, _ := strings.Cut(line, "=")
+		if strings.TrimSpace(line) == "" || keys[strings.TrimSpace(key)] {
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+	for _, setting := range settings {
+		lines = append(lines, setting[0]+"="+setting[1])
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
 func setupPreferences(workspaceFolder, userName string) error {
 	homeDir, err := command.GetHome(userName)
 	if err != nil {
@@ -342,13 +367,18 @@ func setupPreferences(workspaceFolder, userName string) error {
 		return err
 	}
 
+	// keep the preferences the user changed since the last start
+	prefsPath := filepath.Join(prefsDir, "rstudio-prefs.json")
+	if _, err := os.Stat(prefsPath); err == nil {
+		return nil
+	}
+
 	prefs := preferences{InitialWorkingDirectory: workspaceFolder}
 	outPrefs, err := json.Marshal(prefs)
 	if err != nil {
 		return err
 	}
 
-	prefsPath := filepath.Join(prefsDir, "rstudio-prefs.json")
 	err = os.WriteFile(prefsPath, outPrefs, os.ModePerm)
 	if err != nil {
 		return fmt.Errorf("save preferences: %w", err)
@@ -0,0 +1,40 @@
+package rstudio
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMergeServerConf(t *testing.T) {
+	settings := [][2]string{{"server-user", "rstudio"}, {"auth-none", "1"}}
+	tests := []struct {
+		name         string
+		existingConf string
+		want         string
+	}{
+		{
+			name: "empty",
+			want: "server-user=rstudio\nauth-none=1\n",
+		},
+		{
+			name:         "rocker image",
+			existingConf: "rsession-which-r=/usr/local/bin/R\nauth-none=0\n",
+			want:         "rsession-which-r=/usr/local/bin/R\nserver-user=rstudio\nauth-none=1\n",
+		},
+		{
+			name:         "already configured",
+			existingConf: "server-user=rstudio\nauth-none=1\n",
+			want:         "server-user=rstudio\nauth-none=1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeServerConf(tt.existingConf, settings)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("mergeServerConf() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
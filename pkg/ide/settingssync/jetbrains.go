@@ -0,0 +1,142 @@
+package settingssync
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/config"
+)
+
+type jetBrainsProduct struct {
+	// Prefixes are the names of the local configuration folders without the version, e.g. GoLand for GoLand2024.3
+	Prefixes []string
+
+	// Code is the product code the remote development backend names its configuration folder after
+	Code string
+}
+
+var jetBrainsProducts = map[string]jetBrainsProduct{
+	string(config.IDEGoland):    {Prefixes: []string{"GoLand"}, Code: "GO"},
+	string(config.IDEIntellij):  {Prefixes: []string{"IntelliJIdea", "IdeaIC"}, Code: "IU"},
+	string(config.IDEPyCharm):   {Prefixes: []string{"PyCharm", "PyCharmCE"}, Code: "PY"},
+	string(config.IDEPhpStorm):  {Prefixes: []string{"PhpStorm"}, Code: "PS"},
+	string(config.IDECLion):     {Prefixes: []string{"CLion"}, Code: "CL"},
+	string(config.IDERubyMine):  {Prefixes: []string{"RubyMine"}, Code: "RM"},
+	string(config.IDERider):     {Prefixes: []string{"Rider"}, Code: "RD"},
+	string(config.IDEWebStorm):  {Prefixes: []string{"WebStorm"}, Code: "WS"},
+	string(config.IDEDataSpell): {Prefixes: []string{"DataSpell"}, Code: "DS"},
+	string(config.IDERustRover): {Prefixes: []string{"RustRover"}, Code: "RR"},
+}
+
+// jetBrainsFolders are the folders of the configuration that are synced with the file types they hold
+var jetBrainsFolders = map[string][]string{
+	"options":    {".xml"},
+	"codestyles": {".xml"},
+	"keymaps":    {".xml"},
+	"templates":  {".xml"},
+	"inspection": {".xml"},
+	"colors":     {".icls"},
+}
+
+// jetBrainsMachineOptions are options that hold local paths, credentials or the state of the local installation
+var jetBrainsMachineOptions = []string{
+	"applicationLibraries.xml",
+	"gateway.xml",
+	"git.xml",
+	"github.xml",
+	"gitlab.xml",
+	"jdk.table.xml",
+	"other.xml",
+	"path.macros.xml",
+	"proxy.settings.xml",
+	"recentProjects.xml",
+	"recentSolutions.xml",
+	"remote-servers.xml",
+	"security.xml",
+	"sshConfigs.xml",
+	"sshRecentConnections.xml",
+	"terminal.xml",
+	"trusted-paths.xml",
+	"updates.xml",
+	"web-browsers.xml",
+	"webServers.xml",
+	"window.state.xml",
+}
+
+var jetBrainsVersionRegEx = regexp.MustCompile(`^(\d{4})\.(\d+)$`)
+
+func collectJetBrains(configDir string, product jetBrainsProduct, log log.Logger) (*Payload, error) {
+	productDir, err := findJetBrainsConfig(filepath.Join(configDir, "JetBrains"), product)
+	if err != nil || productDir == "" {
+		return nil, err
+	}
+
+	payload := &Payload{Files: map[string]string{}}
+	for folder, extensions := range jetBrainsFolders {
+		err = readFiles(productDir, filepath.Join(productDir, folder), extensions, payload.Files)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, option := range jetBrainsMachineOptions {
+		delete(payload.Files, "options/"+option)
+	}
+
+	log.Debugf("Sync %d files from %s", len(payload.Files), productDir)
+	return payload, nil
+}
+
+// findJetBrainsConfig returns the configuration folder of the most recent local version of the product
+func findJetBrainsConfig(jetBrainsDir string, product jetBrainsProduct) (string, error) {
+	entries, err := os.ReadDir(jetBrainsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	found := ""
+	foundVersion := [2]int{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		for _, prefix := range product.Prefixes {
+			matches := jetBrainsVersionRegEx.FindStringSubmatch(strings.TrimPrefix(entry.Name(), prefix))
+			if !strings.HasPrefix(entry.Name(), prefix) || matches == nil {
+				continue
+			}
+
+			year, _ := strconv.Atoi(matches[1])
+			release, _ := strconv.Atoi(matches[2])
+			version := [2]int{year, release}
+			if found == "" || version[0] > foundVersion[0] || (version[0] == foundVersion[0] && version[1] > foundVersion[1]) {
+				found = filepath.Join(jetBrainsDir, entry.Name())
+				foundVersion = version
+			}
+		}
+	}
+
+	return found, nil
+}
+
+// applyJetBrains writes the configuration into the folder the remote development backend uses for the project. The
+// backend keeps one configuration per project below ~/.config/JetBrains/RemoteDev-<product code>, named after the
+// project path with slashes replaced by underscores.
+func applyJetBrains(payload *Payload, homeDir, userName, workspaceFolder string, product jetBrainsProduct, log log.Logger) error {
+	configDir := filepath.Join(homeDir, ".config", "JetBrains", "RemoteDev-"+product.Code, strings.ReplaceAll(workspaceFolder, "/", "_"))
+	err := writeFiles(configDir, payload.Files, userName)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Wrote %d synced files to %s", len(payload.Files), configDir)
+	return nil
+}
@@ -0,0 +1,198 @@
+package settingssync
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/command"
+	copy2 "dev.khulnasoft.com/pkg/copy"
+)
+
+// maxFileSize is the size up to which files are synced, bigger files are most likely not settings
+const maxFileSize = 1024 * 1024
+
+// Payload holds the local settings of an IDE that are applied in the workspace
+type Payload struct {
+	// IDE is the name of the IDE the settings were collected for
+	IDE string `json:"ide,omitempty"`
+
+	// Settings are the VS Code user settings without machine specific keys
+	Settings map[string]interface{} `json:"settings,omitempty"`
+
+	// Files are additional files by their path relative to the settings folder of the IDE, e.g. keybindings.json,
+	// snippets or the JetBrains options
+	Files map[string]string `json:"files,omitempty"`
+}
+
+// Empty returns true if there is nothing to apply
+func (p *Payload) Empty() bool {
+	return p == nil || (len(p.Settings) == 0 && len(p.Files) == 0)
+}
+
+// Collect reads the local settings of the given IDE. It returns nil if the IDE is not supported or no settings were
+// found.
+func Collect(ideName string, log log.Logger) (*Payload, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	var payload *Payload
+	if app, ok := vsCodeApps[ideName]; ok {
+		payload, err = collectVSCode(userConfigDir(homeDir), homeDir, app, log)
+	} else if product, ok := jetBrainsProducts[ideName]; ok {
+		payload, err = collectJetBrains(userConfigDir(homeDir), product, log)
+	} else {
+		log.Debugf("Syncing settings is not supported for %s", ideName)
+		return nil, nil
+	}
+	if err != nil || payload.Empty() {
+		return nil, err
+	}
+
+	payload.IDE = ideName
+	return payload, nil
+}
+
+// Apply writes the settings into the workspace for the given user. The devcontainer settings take precedence over
+// the synced settings, so the configuration of the project always wins.
+func Apply(payload *Payload, userName, workspaceFolder string, devContainerSettings map[string]interface{}, log log.Logger) error {
+	homeDir, err := command.GetHome(userName)
+	if err != nil {
+		return err
+	}
+
+	if app, ok := vsCodeApps[payload.IDE]; ok {
+		return applyVSCode(payload, homeDir, userName, app, devContainerSettings, log)
+	} else if product, ok := jetBrainsProducts[payload.IDE]; ok {
+		return applyJetBrains(payload, homeDir, userName, workspaceFolder, product, log)
+	}
+
+	log.Debugf("Syncing settings is not supported for %s", payload.IDE)
+	return nil
+}
+
+// userConfigDir returns the folder editors store their user configuration in on this machine
+func userConfigDir(homeDir string) string {
+	switch runtime.GOOS {
+	case "darwin":
+		return filepath.Join(homeDir, "Library", "Application Support")
+	case "windows":
+		if appData := os.Getenv("APPDATA"); appData != "" {
+			return appData
+		}
+
+		return filepath.Join(homeDir, "AppData", "Roaming")
+	default:
+		if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+			return configHome
+		}
+
+		return filepath.Join(homeDir, ".config")
+	}
+}
+
+// readFiles reads the files directly within folder that match one of the extensions. Files are keyed by their path
+// relative to base with forward slashes.
+func readFiles(base, folder string, extensions []string, files map[string]string) error {
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !hasExtension(entry.Name(), extensions) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil || info.Size() > maxFileSize {
+			continue
+		}
+
+		path := filepath.Join(folder, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(base, path)
+		if err != nil {
+			return err
+		}
+
+		files[filepath.ToSlash(relPath)] = string(content)
+	}
+
+	return nil
+}
+
+// writeFiles writes the files below the target folder and hands them over to the user
+func writeFiles(target string, files map[string]string, userName string) error {
+	for relPath, content := range files {
+		path := filepath.Join(target, filepath.FromSlash(relPath))
+		if !isWithin(target, path) {
+			continue
+		}
+
+		err := writeFile(path, []byte(content), userName)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeFile writes the file and hands it over to the user together with all folders that had to be created for it
+func writeFile(path string, content []byte, userName string) error {
+	created := ""
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(dir); err == nil || filepath.Dir(dir) == dir {
+			break
+		}
+
+		created = dir
+	}
+
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(path, content, 0600)
+	if err != nil {
+		return err
+	}
+
+	if userName == "" {
+		return nil
+	} else if created != "" {
+		return copy2.ChownR(created, userName)
+	}
+
+	return copy2.Chown(path, userName)
+}
+
+func hasExtension(name string, extensions []string) bool {
+	for _, extension := range extensions {
+		if filepath.Ext(name) == extension {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isWithin checks that a synced file doesn't escape the settings folder
+func isWithin(folder, path string) bool {
+	relPath, err := filepath.Rel(folder, path)
+	return err == nil && relPath != ".." && !strings.HasPrefix(relPath, ".."+string(filepath.Separator))
+}
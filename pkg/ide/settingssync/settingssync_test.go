@@ -0,0 +1,96 @@
+package settingssync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFilterSettings(t *testing.T) {
+	settings := map[string]interface{}{
+		"editor.fontSize":                        float64(14),
+		"editor.formatOnSave":                    true,
+		"workbench.colorTheme":                   "Default Dark Modern",
+		"breadcrumbs.filePath":                   "on",
+		"go.toolsManagement.autoUpdate":          true,
+		"python.defaultInterpreterPath":          "/opt/homebrew/bin/python3",
+		"java.home":                              `C:\Program Files\Java\jdk-21`,
+		"go.goroot":                              "~/sdk/go1.22",
+		"remote.SSH.remotePlatform":              map[string]interface{}{"host": "linux"},
+		"terminal.integrated.defaultProfile.osx": "zsh",
+		"terminal.integrated.fontSize":           float64(13),
+		"http.proxy":                             "http://proxy:8080",
+		"window.zoomLevel":                       float64(1),
+		"files.associations":                     map[string]interface{}{"*.tpl": "html"},
+		"cSpell.userWords":                       []interface{}{"devspace"},
+		"eslint.workingDirectories":              []interface{}{"/Users/alice/project"},
+	}
+	want := map[string]interface{}{
+		"editor.fontSize":               float64(14),
+		"editor.formatOnSave":           true,
+		"workbench.colorTheme":          "Default Dark Modern",
+		"breadcrumbs.filePath":          "on",
+		"go.toolsManagement.autoUpdate": true,
+		"terminal.integrated.fontSize":  float64(13),
+		"files.associations":            map[string]interface{}{"*.tpl": "html"},
+		"cSpell.userWords":              []interface{}{"devspace"},
+	}
+
+	got := FilterSettings(settings, "/Users/alice")
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("FilterSettings() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFindJetBrainsConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		folders []string
+		product jetBrainsProduct
+		want    string
+	}{
+		{
+			name:    "latest version",
+			folders: []string{"GoLand2023.3", "GoLand2024.10", "GoLand2024.3", "PyCharm2025.1"},
+			product: jetBrainsProducts["goland"],
+			want:    "GoLand2024.10",
+		},
+		{
+			name:    "community edition",
+			folders: []string{"PyCharmCE2024.1", "IdeaIC2024.2"},
+			product: jetBrainsProducts["pycharm"],
+			want:    "PyCharmCE2024.1",
+		},
+		{
+			name:    "not installed",
+			folders: []string{"GoLand2024.3", "consentOptions"},
+			product: jetBrainsProducts["rider"],
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jetBrainsDir := t.TempDir()
+			for _, folder := range tt.folders {
+				err := os.Mkdir(filepath.Join(jetBrainsDir, folder), 0755)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want := ""
+			if tt.want != "" {
+				want = filepath.Join(jetBrainsDir, tt.want)
+			}
+
+			got, err := findJetBrainsConfig(jetBrainsDir, tt.product)
+			if err != nil {
+				t.Fatal(err)
+			} else if got != want {
+				t.Errorf("findJetBrainsConfig() = %s, want %s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package settingssync
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/config"
+	"github.com/tidwall/jsonc"
+)
+
+type vsCodeApp struct {
+	// LocalFolder is the folder of the editor within the user configuration folder
+	LocalFolder string
+
+	// DataFolder is where the server in the workspace keeps its data, relative to the home of the user
+	DataFolder string
+
+	// UserData is true for browser based servers that keep their own user keybindings and snippets. Desktop editors
+	// apply the local keybindings and snippets themselves.
+	UserData bool
+}
+
+var vsCodeApps = map[string]vsCodeApp{
+	string(config.IDEVSCode):         {LocalFolder: "Code", DataFolder: ".vscode-server/data"},
+	string(config.IDEVSCodeInsiders): {LocalFolder: "Code - Insiders", DataFolder: ".vscode-server-insiders/data"},
+	string(config.IDECursor):         {LocalFolder: "Cursor", DataFolder: ".cursor-server/data"},
+	string(config.IDECodium):         {LocalFolder: "VSCodium", DataFolder: ".vscodium-server/data"},
+	string(config.IDEPositron):       {LocalFolder: "Positron", DataFolder: ".positron-server/data"},
+	string(config.IDEWindsurf):       {LocalFolder: "Windsurf", DataFolder: ".windsurf-server/data"},
+	string(config.IDEOpenVSCode):     {LocalFolder: "Code", DataFolder: ".openvscode-server/data", UserData: true},
+	string(config.IDECodeServer):     {LocalFolder: "Code", DataFolder: ".local/share/code-server", UserData: true},
+}
+
+// machineSettingPrefixes are settings that describe the local machine or the connection to the workspace
+var machineSettingPrefixes = []string{
+	"remote.",
+	"dev.containers.",
+	"docker.host",
+	"docker.environment",
+	"http.proxy",
+	"window.",
+	"update.",
+	"telemetry.",
+	"settingsSync.",
+	"sync.",
+	"security.workspace.trust.",
+	"terminal.external.",
+	"terminal.integrated.shell.",
+	"terminal.integrated.shellArgs.",
+	"terminal.integrated.profiles.",
+	"terminal.integrated.defaultProfile.",
+	"terminal.integrated.automationProfile.",
+	"terminal.integrated.env.",
+}
+
+// pathSettingRegEx matches settings that usually point to a local file, e.g. python.defaultInterpreterPath or
+// java.home. They are only removed if their value actually is a path.
+var pathSettingRegEx = regexp.MustCompile(`(?i)(path|paths|home|root|runtimes|executable)$`)
+
+var windowsPathRegEx = regexp.MustCompile(`^[a-zA-Z]:[\\/]`)
+
+func collectVSCode(configDir, homeDir string, app vsCodeApp, log log.Logger) (*Payload, error) {
+	userDir := filepath.Join(configDir, app.LocalFolder, "User")
+	payload := &Payload{Files: map[string]string{}}
+	content, err := os.ReadFile(filepath.Join(userDir, "settings.json"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	} else if err == nil {
+		settings := map[string]interface{}{}
+		err = json.Unmarshal(jsonc.ToJSON(content), &settings)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", filepath.Join(userDir, "settings.json"), err)
+		}
+
+		payload.Settings = FilterSettings(settings, homeDir)
+		log.Debugf("Sync %d of %d settings from %s", len(payload.Settings), len(settings), userDir)
+	}
+
+	if app.UserData {
+		content, err := os.ReadFile(filepath.Join(userDir, "keybindings.json"))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		} else if err == nil {
+			payload.Files["keybindings.json"] = string(content)
+		}
+
+		err = readFiles(userDir, filepath.Join(userDir, "snippets"), []string{".json", ".code-snippets"}, payload.Files)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return payload, nil
+}
+
+func applyVSCode(payload *Payload, homeDir, userName string, app vsCodeApp, devContainerSettings map[string]interface{}, log log.Logger) error {
+	dataDir := filepath.Join(homeDir, filepath.FromSlash(app.DataFolder))
+	settings := map[string]interface{}{}
+	for key, value := range payload.Settings {
+		settings[key] = value
+	}
+	for key, value := range devContainerSettings {
+		settings[key] = value
+	}
+
+	content, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	settingsFile := filepath.Join(dataDir, "Machine", "settings.json")
+	err = writeFile(settingsFile, content, userName)
+	if err != nil {
+		return err
+	}
+	log.Debugf("Wrote %d synced settings to %s", len(payload.Settings), settingsFile)
+
+	if !app.UserData {
+		return nil
+	}
+
+	return writeFiles(filepath.Join(dataDir, "User"), payload.Files, userName)
+}
+
+// FilterSettings removes the settings that only make sense on the local machine, like paths to local tools,
+// terminal profiles, proxies or the remote connection itself
+func FilterSettings(settings map[string]interface{}, homeDir string) map[string]interface{} {
+	filtered := map[string]interface{}{}
+	for key, value := range settings {
+		if isMachineSetting(key, value, homeDir) {
+			continue
+		}
+
+		filtered[key] = value
+	}
+
+	return filtered
+}
+
+func isMachineSetting(key string, value interface{}, homeDir string) bool {
+	for _, prefix := range machineSettingPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	if pathSettingRegEx.MatchString(key) && containsString(value, isPath) {
+		return true
+	}
+
+	return len(homeDir) > 1 && containsString(value, func(s string) bool {
+		return strings.Contains(s, homeDir)
+	})
+}
+
+func isPath(value string) bool {
+	return strings.HasPrefix(value, "/") ||
+		strings.HasPrefix(value, "~") ||
+		strings.HasPrefix(value, "./") ||
+		strings.HasPrefix(value, "../") ||
+		strings.HasPrefix(value, `\\`) ||
+		windowsPathRegEx.MatchString(value)
+}
+
+// containsString checks if any string within value matches
+func containsString(value interface{}, match func(string) bool) bool {
+	switch value := value.(type) {
+	case string:
+		return match(value)
+	case []interface{}:
+		for _, item := range value {
+			if containsString(item, match) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for key, item := range value {
+			if match(key) || containsString(item, match) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
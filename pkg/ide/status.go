@@ -0,0 +1,148 @@
+package ide
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"dev.khulnasoft.com/pkg/config"
+)
+
+// RunningServer is an IDE server that runs in the workspace
+type RunningServer struct {
+	// IDE is the name of the IDE the server belongs to
+	IDE string `json:"ide"`
+
+	// PID is the process id of the server
+	PID int `json:"pid"`
+}
+
+type serverSignature struct {
+	ide string
+
+	// pidFile is the file in the temp folder the server was started with
+	pidFile string
+
+	// path is part of the command line of the server process, usually its install location
+	path string
+}
+
+// serverSignatures identify the IDE servers. Servers DevSpace starts in the background are found by their pid file,
+// the servers the local IDE starts over SSH by their install location.
+var serverSignatures = []serverSignature{
+	{ide: string(config.IDEVSCode), path: "/.vscode-server/"},
+	{ide: string(config.IDEVSCodeInsiders), path: "/.vscode-server-insiders/"},
+	{ide: string(config.IDECursor), path: "/.cursor-server/"},
+	{ide: string(config.IDECodium), path: "/.vscodium-server/"},
+	{ide: string(config.IDEPositron), path: "/.positron-server/"},
+	{ide: string(config.IDEWindsurf), path: "/.windsurf-server/"},
+	{ide: string(config.IDEOpenVSCode), pidFile: "openvscode.pid"},
+	{ide: string(config.IDECodeServer), pidFile: "codeserver.pid"},
+	{ide: string(config.IDEJupyterNotebook), pidFile: "jupyter.pid"},
+	{ide: string(config.IDEJupyterLab), pidFile: "jupyterlab.pid"},
+	{ide: string(config.IDERStudio), pidFile: "rstudio.pid"},
+	{ide: string(config.IDEFleet), pidFile: "fleet.pid"},
+	{ide: string(config.IDEZed), path: "/.zed_server/"},
+	{ide: string(config.IDEGoland), path: "/JetBrains/RemoteDev/dist/goland/"},
+	{ide: string(config.IDERustRover), path: "/JetBrains/RemoteDev/dist/rustrover/"},
+	{ide: string(config.IDEPyCharm), path: "/JetBrains/RemoteDev/dist/pycharm/"},
+	{ide: string(config.IDEPhpStorm), path: "/JetBrains/RemoteDev/dist/phpstorm/"},
+	{ide: string(config.IDEIntellij), path: "/JetBrains/RemoteDev/dist/intellij/"},
+	{ide: string(config.IDECLion), path: "/JetBrains/RemoteDev/dist/clion/"},
+	{ide: string(config.IDERider), path: "/JetBrains/RemoteDev/dist/rider/"},
+	{ide: string(config.IDERubyMine), path: "/JetBrains/RemoteDev/dist/rubymine/"},
+	{ide: string(config.IDEWebStorm), path: "/JetBrains/RemoteDev/dist/webstorm/"},
+	{ide: string(config.IDEDataSpell), path: "/JetBrains/RemoteDev/dist/dataspell/"},
+}
+
+// FindRunningServers returns the IDE servers that run in the workspace. customIDEs are the names of the IDEs added
+// with devspace ide add, they are found by their pid file as well.
+func FindRunningServers(procDir, tempDir string, customIDEs []string) ([]RunningServer, error) {
+	signatures := append([]serverSignature{}, serverSignatures...)
+	for _, customIDE := range customIDEs {
+		signatures = append(signatures, serverSignature{ide: customIDE, pidFile: customIDE + ".pid"})
+	}
+
+	commandLines, err := readCommandLines(procDir)
+	if err != nil {
+		return nil, err
+	}
+
+	servers := []RunningServer{}
+	for _, signature := range signatures {
+		pid := 0
+		if signature.pidFile != "" {
+			pid = readPIDFile(filepath.Join(tempDir, signature.pidFile), commandLines)
+		} else {
+			pid = findProcess(signature.path, commandLines)
+		}
+		if pid == 0 {
+			continue
+		}
+
+		servers = append(servers, RunningServer{IDE: signature.ide, PID: pid})
+	}
+
+	return servers, nil
+}
+
+// readCommandLines returns the command lines of all processes by their process id
+func readCommandLines(procDir string) (map[int]string, error) {
+	entries, err := os.ReadDir(procDir)
+	if err != nil {
+		return nil, err
+	}
+
+	commandLines := map[int]string{}
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+
+		// processes might exit while we read them
+		out, err := os.ReadFile(filepath.Join(procDir, entry.Name(), "cmdline"))
+		if err != nil {
+			continue
+		}
+
+		commandLines[pid] = string(bytes.ReplaceAll(out, []byte{0}, []byte{' '}))
+	}
+
+	return commandLines, nil
+}
+
+func readPIDFile(pidFile string, commandLines map[int]string) int {
+	out, err := os.ReadFile(pidFile)
+	if err != nil {
+		return 0
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil {
+		return 0
+	} else if _, ok := commandLines[pid]; !ok {
+		return 0
+	}
+
+	return pid
+}
+
+// findProcess returns the lowest process id that contains path in its command line
+func findProcess(path string, commandLines map[int]string) int {
+	pids := []int{}
+	for pid, commandLine := range commandLines {
+		if strings.Contains(commandLine, path) {
+			pids = append(pids, pid)
+		}
+	}
+	if len(pids) == 0 {
+		return 0
+	}
+
+	sort.Ints(pids)
+	return pids[0]
+}
@@ -0,0 +1,59 @@
+package ide
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFindRunningServers(t *testing.T) {
+	procDir := t.TempDir()
+	tempDir := t.TempDir()
+	processes := map[string]string{
+		"1":    "/bin/sh\x00-c\x00sleep infinity\x00",
+		"88":   "sh\x00/home/vscode/.vscode-server/code-abc/bin/code-server\x00--start-server\x00",
+		"42":   "/home/vscode/.vscode-server/code-abc/node\x00out/server-main.js\x00",
+		"120":  "/home/vscode/.cache/JetBrains/RemoteDev/dist/goland/bin/remote-dev-server.sh\x00run\x00/workspaces/app\x00",
+		"300":  "sh\x00-c\x00while true; do su vscode -c 'jupyter lab'; done\x00",
+		"7000": "/home/vscode/.vscode-server-insiders/bin/code-server-insiders\x00",
+	}
+	for pid, commandLine := range processes {
+		err := os.Mkdir(filepath.Join(procDir, pid), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(procDir, pid, "cmdline"), []byte(commandLine), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// rstudio and the custom ide exited, their pid files are stale
+	pidFiles := map[string]string{
+		"jupyterlab.pid": "300",
+		"rstudio.pid":    "301",
+		"marimo.pid":     "302",
+	}
+	for name, pid := range pidFiles {
+		err := os.WriteFile(filepath.Join(tempDir, name), []byte(pid), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []RunningServer{
+		{IDE: "vscode", PID: 42},
+		{IDE: "vscode-insiders", PID: 7000},
+		{IDE: "jupyterlab", PID: 300},
+		{IDE: "goland", PID: 120},
+	}
+	got, err := FindRunningServers(procDir, tempDir, []string{"marimo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("FindRunningServers() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,270 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/command"
+	"dev.khulnasoft.com/pkg/config"
+	copy2 "dev.khulnasoft.com/pkg/copy"
+	"dev.khulnasoft.com/pkg/extract"
+	"dev.khulnasoft.com/pkg/ide"
+	"github.com/alessio/shellescape"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	EditorOption         = "EDITOR"
+	SessionManagerOption = "SESSION_MANAGER"
+	SessionNameOption    = "SESSION_NAME"
+	ConfigPathOption     = "CONFIG_PATH"
+)
+
+const (
+	EditorNeovim = "neovim"
+	EditorHelix  = "helix"
+	EditorEmacs  = "emacs"
+	EditorNone   = "none"
+
+	SessionManagerTmux   = "tmux"
+	SessionManagerZellij = "zellij"
+)
+
+var Options = ide.Options{
+	EditorOption: {
+		Name:        EditorOption,
+		Description: "The editor to install in the workspace",
+		Default:     EditorNeovim,
+		Enum: []string{
+			EditorNeovim,
+			EditorHelix,
+			EditorEmacs,
+			EditorNone,
+		},
+	},
+	SessionManagerOption: {
+		Name:        SessionManagerOption,
+		Description: "The session manager that keeps the terminal session alive between connections",
+		Default:     SessionManagerTmux,
+		Enum: []string{
+			SessionManagerTmux,
+			SessionManagerZellij,
+		},
+	},
+	SessionNameOption: {
+		Name:              SessionNameOption,
+		Description:       "The name of the session devspace ssh attaches to",
+		Default:           "devspace",
+		ValidationPattern: "^[a-zA-Z0-9_\\-]+$",
+		ValidationMessage: "Session name can only consist of letters, numbers, underscores or dashes",
+	},
+	ConfigPathOption: {
+		Name:        ConfigPathOption,
+		Description: "The editor config folder within the dotfiles repository, e.g. nvim. It is linked to the config folder of the editor",
+	},
+}
+
+type editor struct {
+	// binaries are the possible names of the editor binary, the first one is used as EDITOR
+	binaries []string
+	// packages holds the package name per package manager
+	packages map[string]string
+	// configDir is the config folder relative to the home folder
+	configDir string
+}
+
+var editors = map[string]editor{
+	EditorNeovim: {
+		binaries:  []string{"nvim"},
+		packages:  map[string]string{"apt-get": "neovim", "apk": "neovim", "dnf": "neovim", "yum": "neovim", "pacman": "neovim"},
+		configDir: ".config/nvim",
+	},
+	EditorHelix: {
+		binaries:  []string{"hx", "helix"},
+		packages:  map[string]string{"apt-get": "hx", "apk": "helix", "dnf": "helix", "yum": "helix", "pacman": "helix"},
+		configDir: ".config/helix",
+	},
+	EditorEmacs: {
+		binaries:  []string{"emacs"},
+		packages:  map[string]string{"apt-get": "emacs-nox", "apk": "emacs-nox", "dnf": "emacs-nox", "yum": "emacs-nox", "pacman": "emacs-nox"},
+		configDir: ".emacs.d",
+	},
+}
+
+// zellij is not packaged by most distributions, so we install the static release binary
+const zellijDownloadTemplate = "https://github.com/zellij-org/zellij/releases/latest/download/zellij-%s-unknown-linux-musl.tar.gz"
+
+func NewTerminalServer(userName string, values map[string]config.OptionValue, log log.Logger) *TerminalServer {
+	return &TerminalServer{
+		values:   values,
+		userName: userName,
+		log:      log,
+	}
+}
+
+type TerminalServer struct {
+	values   map[string]config.OptionValue
+	userName string
+	log      log.Logger
+}
+
+func (t *TerminalServer) Install() error {
+	editorName := Options.GetValue(t.values, EditorOption)
+	if editorName != EditorNone {
+		err := t.installEditor(editorName)
+		if err != nil {
+			return err
+		}
+	}
+
+	sessionManager := Options.GetValue(t.values, SessionManagerOption)
+	if !command.Exists(sessionManager) {
+		t.log.Infof("Installing %s...", sessionManager)
+		var err error
+		if sessionManager == SessionManagerZellij {
+			err = installZellij(t.log)
+		} else {
+			err = installPackages([]string{sessionManager}, t.log)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "install %s", sessionManager)
+		}
+	}
+
+	return nil
+}
+
+func (t *TerminalServer) installEditor(editorName string) error {
+	e := editors[editorName]
+	if findBinary(e.binaries) == "" {
+		packageManager := findPackageManager()
+		if packageManager == "" {
+			return fmt.Errorf("couldn't find a package manager to install %s", editorName)
+		}
+
+		t.log.Infof("Installing %s...", editorName)
+		err := installPackages([]string{e.packages[packageManager]}, t.log)
+		if err != nil {
+			return errors.Wrapf(err, "install %s", editorName)
+		}
+	}
+
+	// link the editor config from the dotfiles, these are cloned after the ide is installed
+	configPath := Options.GetValue(t.values, ConfigPathOption)
+	if configPath == "" {
+		return nil
+	}
+
+	homeFolder, err := command.GetHome(t.userName)
+	if err != nil {
+		return err
+	}
+
+	configDir := filepath.Join(homeFolder, e.configDir)
+	if _, err := os.Lstat(configDir); err == nil {
+		t.log.Debugf("%s already exists, skip linking editor config", configDir)
+		return nil
+	}
+
+	err = os.MkdirAll(filepath.Dir(configDir), 0755)
+	if err != nil {
+		return err
+	}
+
+	err = os.Symlink(filepath.Join(homeFolder, "dotfiles", configPath), configDir)
+	if err != nil {
+		return errors.Wrap(err, "link editor config")
+	}
+
+	if t.userName != "" {
+		return copy2.Chown(filepath.Dir(configDir), t.userName)
+	}
+
+	return nil
+}
+
+// AttachCommand returns the command that attaches to the terminal session or creates it if it doesn't exist yet.
+// If the session manager is missing it falls back to a login shell.
+func AttachCommand(values map[string]config.OptionValue) string {
+	sessionName := shellescape.Quote(Options.GetValue(values, SessionNameOption))
+	sessionManager := Options.GetValue(values, SessionManagerOption)
+
+	attachCommand := "tmux new-session -A -s " + sessionName
+	if sessionManager == SessionManagerZellij {
+		attachCommand = "zellij attach --create " + sessionName
+	}
+
+	editorName := Options.GetValue(values, EditorOption)
+	if e, ok := editors[editorName]; ok {
+		attachCommand = "EDITOR=" + e.binaries[0] + " " + attachCommand
+	}
+
+	return fmt.Sprintf("if command -v %s >/dev/null 2>&1; then %s; else exec \"${SHELL:-sh}\" -l; fi", sessionManager, attachCommand)
+}
+
+func findBinary(binaries []string) string {
+	for _, binary := range binaries {
+		if command.Exists(binary) {
+			return binary
+		}
+	}
+
+	return ""
+}
+
+func findPackageManager() string {
+	for _, packageManager := range []string{"apt-get", "apk", "dnf", "yum", "pacman"} {
+		if command.Exists(packageManager) {
+			return packageManager
+		}
+	}
+
+	return ""
+}
+
+func installPackages(packages []string, log log.Logger) error {
+	var installCommand string
+	switch findPackageManager() {
+	case "apt-get":
+		installCommand = "apt-get update && DEBIAN_FRONTEND=noninteractive apt-get install -y "
+	case "apk":
+		installCommand = "apk update && apk add "
+	case "dnf":
+		installCommand = "dnf install -y "
+	case "yum":
+		installCommand = "yum install -y "
+	case "pacman":
+		installCommand = "pacman -Sy --noconfirm "
+	default:
+		return fmt.Errorf("couldn't find a package manager to install %s", strings.Join(packages, ", "))
+	}
+
+	writer := log.Writer(logrus.DebugLevel, false)
+	defer writer.Close()
+
+	cmd := exec.Command("sh", "-c", installCommand+strings.Join(packages, " "))
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	return cmd.Run()
+}
+
+func installZellij(log log.Logger) error {
+	arch := "x86_64"
+	if runtime.GOARCH == "arm64" {
+		arch = "aarch64"
+	}
+
+	body, err := ide.OpenDownload(fmt.Sprintf(zellijDownloadTemplate, arch), log)
+	if err != nil {
+		return errors.Wrap(err, "download zellij")
+	}
+	defer body.Close()
+
+	return extract.Extract(body, "/usr/local/bin")
+}
@@ -0,0 +1,44 @@
+package terminal
+
+import (
+	"testing"
+
+	"dev.khulnasoft.com/pkg/config"
+)
+
+func TestAttachCommand(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]config.OptionValue
+		want   string
+	}{
+		{
+			name: "defaults",
+			want: `if command -v tmux >/dev/null 2>&1; then EDITOR=nvim tmux new-session -A -s devspace; else exec "${SHELL:-sh}" -l; fi`,
+		},
+		{
+			name: "zellij with helix",
+			values: map[string]config.OptionValue{
+				EditorOption:         {Value: EditorHelix},
+				SessionManagerOption: {Value: SessionManagerZellij},
+				SessionNameOption:    {Value: "work"},
+			},
+			want: `if command -v zellij >/dev/null 2>&1; then EDITOR=hx zellij attach --create work; else exec "${SHELL:-sh}" -l; fi`,
+		},
+		{
+			name: "no editor",
+			values: map[string]config.OptionValue{
+				EditorOption: {Value: EditorNone},
+			},
+			want: `if command -v tmux >/dev/null 2>&1; then tmux new-session -A -s devspace; else exec "${SHELL:-sh}" -l; fi`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AttachCommand(tt.values); got != tt.want {
+				t.Errorf("AttachCommand() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,443 @@
+package vscode
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/config"
+	devcontainerconfig "dev.khulnasoft.com/pkg/devcontainer/config"
+	devspacehttp "dev.khulnasoft.com/pkg/http"
+	"dev.khulnasoft.com/pkg/ide"
+	"github.com/blang/semver"
+)
+
+const (
+	ExtensionsVSIXFolderOption = "EXTENSIONS_VSIX_FOLDER"
+	ExtensionsGalleryOption    = "EXTENSIONS_GALLERY"
+)
+
+// VSIXFolder is where the VSIX files of the local VSIX folder are copied to within the container
+const VSIXFolder = "/var/devspace/vsix"
+
+// VSIXMount returns the mount that streams the VSIX folder into the container
+func VSIXMount(folder string) *devcontainerconfig.Mount {
+	return &devcontainerconfig.Mount{
+		Type:   "bind",
+		Source: folder,
+		Target: VSIXFolder,
+	}
+}
+
+// Extension is an extension from customizations.vscode.extensions, optionally pinned to a version
+// with publisher.name@version
+type Extension struct {
+	Publisher string
+	Name      string
+	Version   string
+}
+
+// ParseExtension parses an extension id in the form publisher.name or publisher.name@version
+func ParseExtension(id string) Extension {
+	extension := Extension{}
+	id, extension.Version, _ = strings.Cut(strings.TrimSpace(id), "@")
+	extension.Publisher, extension.Name, _ = strings.Cut(id, ".")
+	return extension
+}
+
+// ID returns the id of the extension without the version
+func (e Extension) ID() string {
+	return e.Publisher + "." + e.Name
+}
+
+func (e Extension) String() string {
+	if e.Version == "" {
+		return e.ID()
+	}
+
+	return e.ID() + "@" + e.Version
+}
+
+// InstallExtension calls install with what to pass to --install-extension for the given extension. Extensions are
+// installed from the VSIX folder copied into the container first, then from the extensions gallery and otherwise by
+// id from the marketplace configured in the IDE. If the IDE installs from the given marketplace and the ide cache is
+// mounted, the VSIX file is taken from the cache instead. VSIX files downloaded from the gallery or marketplace are
+// removed after install returned.
+func InstallExtension(id string, gallery string, marketplace string, log log.Logger, install func(extension string) error) error {
+	extension := ParseExtension(id)
+	vsixFile, err := FindVSIX(VSIXFolder, extension)
+	if err != nil {
+		log.Debugf("Error searching %s for %s: %v", VSIXFolder, extension, err)
+	} else if vsixFile != "" {
+		return install(vsixFile)
+	}
+
+	if gallery != "" {
+		tmpDir, err := os.MkdirTemp("", "devspace-vsix-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmpDir)
+
+		vsixFile, err = downloadVSIX(extension, tmpDir, log, func() (string, string, error) {
+			return galleryDownloadURL(gallery, extension)
+		})
+		if err == nil {
+			return install(vsixFile)
+		}
+
+		log.Warnf("Error downloading %s from %s: %v", extension, gallery, err)
+	} else if marketplace != "" && ide.CacheAvailable() {
+		tmpDir, err := os.MkdirTemp("", "devspace-vsix-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmpDir)
+
+		vsixFile, err = downloadVSIX(extension, tmpDir, log, func() (string, string, error) {
+			return marketplaceDownloadURL(marketplace, extension, runtime.GOARCH)
+		})
+		if err == nil {
+			return install(vsixFile)
+		}
+
+		log.Debugf("Error downloading %s from %s: %v", extension, marketplace, err)
+	}
+
+	return install(extension.String())
+}
+
+// GetLocalVSIXFolder returns the absolute path of the local VSIX folder from the ide options or an empty string if
+// it isn't configured
+func GetLocalVSIXFolder(values map[string]config.OptionValue) (string, error) {
+	folder := Options.GetValue(values, ExtensionsVSIXFolderOption)
+	if folder == "" {
+		return "", nil
+	}
+
+	if folder == "~" || strings.HasPrefix(folder, "~/") {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+
+		folder = filepath.Join(homeDir, strings.TrimPrefix(folder, "~"))
+	}
+
+	return filepath.Abs(folder)
+}
+
+// PrepareVSIXFolder downloads the extensions that are missing in the local VSIX folder from the gallery, so they can
+// be copied into containers that cannot reach the gallery themselves
+func PrepareVSIXFolder(folder string, gallery string, extensions []string, log log.Logger) error {
+	if gallery == "" {
+		return nil
+	}
+
+	err := os.MkdirAll(folder, 0755)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range extensions {
+		extension := ParseExtension(id)
+		vsixFile, err := FindVSIX(folder, extension)
+		if err != nil {
+			return err
+		} else if vsixFile != "" {
+			continue
+		}
+
+		downloadURL, version, err := galleryDownloadURL(gallery, extension)
+		if err != nil {
+			log.Warnf("Error resolving %s in %s: %v", extension, gallery, err)
+			continue
+		}
+
+		log.Infof("Download extension %s@%s...", extension.ID(), version)
+		err = downloadFile(downloadURL, filepath.Join(folder, extension.ID()+"-"+version+".vsix"), log)
+		if err != nil {
+			log.Warnf("Error downloading %s: %v", extension, err)
+		}
+	}
+
+	return nil
+}
+
+// FindVSIX searches the folder for a VSIX file of the extension. The publisher, name and version are read from the
+// manifest of the VSIX files, so the file names don't matter. If the extension is not pinned, the highest version
+// is returned.
+func FindVSIX(folder string, extension Extension) (string, error) {
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	var (
+		found        string
+		foundVersion semver.Version
+	)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".vsix") {
+			continue
+		}
+
+		vsixFile := filepath.Join(folder, entry.Name())
+		manifest, err := readVSIXManifest(vsixFile)
+		if err != nil {
+			continue
+		} else if !strings.EqualFold(manifest.Publisher, extension.Publisher) || !strings.EqualFold(manifest.Name, extension.Name) {
+			continue
+		}
+
+		if extension.Version != "" {
+			if manifest.Version == extension.Version {
+				return vsixFile, nil
+			}
+
+			continue
+		}
+
+		version, err := semver.ParseTolerant(manifest.Version)
+		if err != nil {
+			continue
+		} else if found == "" || version.GT(foundVersion) {
+			found = vsixFile
+			foundVersion = version
+		}
+	}
+
+	return found, nil
+}
+
+type vsixManifest struct {
+	Publisher string `json:"publisher"`
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+}
+
+func readVSIXManifest(vsixFile string) (*vsixManifest, error) {
+	reader, err := zip.OpenReader(vsixFile)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	file, err := reader.Open("extension/package.json")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	manifest := &vsixManifest{}
+	err = json.NewDecoder(file).Decode(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", vsixFile, err)
+	}
+
+	return manifest, nil
+}
+
+// ExtensionDownloadURL resolves the download url of the VSIX file of the extension in the extensions gallery
+func ExtensionDownloadURL(gallery string, id string) (string, error) {
+	downloadURL, _, err := galleryDownloadURL(gallery, ParseExtension(id))
+	return downloadURL, err
+}
+
+// galleryDownloadURL resolves the download url and version of the extension with the Open VSX API
+func galleryDownloadURL(gallery string, extension Extension) (string, string, error) {
+	apiURL := strings.TrimSuffix(gallery, "/") + "/api/" + url.PathEscape(extension.Publisher) + "/" + url.PathEscape(extension.Name)
+	if extension.Version != "" {
+		apiURL += "/" + url.PathEscape(extension.Version)
+	}
+
+	resp, err := devspacehttp.GetHTTPClient().Get(apiURL)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("get %s: unexpected status code %d", apiURL, resp.StatusCode)
+	}
+
+	metadata := &struct {
+		Version string `json:"version"`
+		Files   struct {
+			Download string `json:"download"`
+		} `json:"files"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(metadata)
+	if err != nil {
+		return "", "", fmt.Errorf("parse %s: %w", apiURL, err)
+	} else if metadata.Files.Download == "" {
+		return "", "", fmt.Errorf("%s has no download", extension)
+	}
+
+	return metadata.Files.Download, metadata.Version, nil
+}
+
+// MarketplaceDownloadURL resolves the download url of the VSIX file of the extension for linux on the given
+// architecture in the marketplace
+func MarketplaceDownloadURL(marketplace string, id string, arch string) (string, error) {
+	downloadURL, _, err := marketplaceDownloadURL(marketplace, ParseExtension(id), arch)
+	return downloadURL, err
+}
+
+// flags and filters of the extension query API of the marketplace
+const (
+	marketplaceFilterExtensionName      = 7
+	marketplaceIncludeVersions          = 0x1
+	marketplaceIncludeVersionProperties = 0x10
+	marketplaceIncludeLatestVersionOnly = 0x200
+	marketplacePreReleaseProperty       = "Microsoft.VisualStudio.Code.PreRelease"
+)
+
+// marketplaceDownloadURL resolves the download url and version of the extension for linux on the given architecture
+// with the extension query API of the marketplace. Pre-releases are skipped unless the extension is pinned to one.
+func marketplaceDownloadURL(marketplace string, extension Extension, arch string) (string, string, error) {
+	flags := marketplaceIncludeVersions | marketplaceIncludeVersionProperties
+	if extension.Version == "" {
+		flags |= marketplaceIncludeLatestVersionOnly
+	}
+
+	query, err := json.Marshal(map[string]any{
+		"filters": []any{
+			map[string]any{
+				"criteria": []any{
+					map[string]any{"filterType": marketplaceFilterExtensionName, "value": extension.ID()},
+				},
+			},
+		},
+		"flags": flags,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	queryURL := strings.TrimSuffix(marketplace, "/") + "/_apis/public/gallery/extensionquery"
+	req, err := http.NewRequest(http.MethodPost, queryURL, bytes.NewReader(query))
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json;api-version=3.0-preview.1")
+
+	resp, err := devspacehttp.GetHTTPClient().Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("query %s: unexpected status code %d", queryURL, resp.StatusCode)
+	}
+
+	result := &struct {
+		Results []struct {
+			Extensions []struct {
+				Versions []struct {
+					Version        string `json:"version"`
+					TargetPlatform string `json:"targetPlatform"`
+					Properties     []struct {
+						Key   string `json:"key"`
+						Value string `json:"value"`
+					} `json:"properties"`
+				} `json:"versions"`
+			} `json:"extensions"`
+		} `json:"results"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(result)
+	if err != nil {
+		return "", "", fmt.Errorf("parse %s: %w", queryURL, err)
+	}
+
+	targetPlatform := "linux-" + serverArch(arch)
+	for _, queryResult := range result.Results {
+		for _, queryExtension := range queryResult.Extensions {
+			for _, version := range queryExtension.Versions {
+				if extension.Version != "" && version.Version != extension.Version {
+					continue
+				} else if version.TargetPlatform != "" && version.TargetPlatform != targetPlatform {
+					continue
+				}
+
+				preRelease := false
+				for _, property := range version.Properties {
+					preRelease = preRelease || (property.Key == marketplacePreReleaseProperty && property.Value == "true")
+				}
+				if preRelease && extension.Version == "" {
+					continue
+				}
+
+				downloadURL := strings.TrimSuffix(marketplace, "/") + "/_apis/public/gallery/publishers/" + url.PathEscape(extension.Publisher) +
+					"/vsextensions/" + url.PathEscape(extension.Name) + "/" + url.PathEscape(version.Version) + "/vspackage"
+				if version.TargetPlatform != "" {
+					downloadURL += "?targetPlatform=" + url.QueryEscape(version.TargetPlatform)
+				}
+
+				return downloadURL, version.Version, nil
+			}
+		}
+	}
+
+	return "", "", fmt.Errorf("%s has no release for %s in %s", extension, targetPlatform, marketplace)
+}
+
+func downloadVSIX(extension Extension, tmpDir string, log log.Logger, resolve func() (string, string, error)) (string, error) {
+	downloadURL, version, err := resolve()
+	if err != nil {
+		return "", err
+	}
+
+	// the file needs to be readable by the remote user that installs the extension and needs to end with .vsix,
+	// so we don't point to the ide cache directly
+	err = os.Chmod(tmpDir, 0755)
+	if err != nil {
+		return "", err
+	}
+
+	vsixFile := filepath.Join(tmpDir, extension.ID()+"-"+version+".vsix")
+	err = downloadFile(downloadURL, vsixFile, log)
+	if err != nil {
+		return "", err
+	}
+
+	return vsixFile, nil
+}
+
+func downloadFile(downloadURL string, target string, log log.Logger) error {
+	body, err := ide.OpenDownload(downloadURL, log)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(file, body)
+	_ = file.Close()
+	if err != nil {
+		// don't leave a broken VSIX file behind
+		_ = os.Remove(target)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package vscode
+
+import (
+	"archive/zip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"dev.khulnasoft.com/log"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseExtension(t *testing.T) {
+	tests := []struct {
+		id   string
+		want Extension
+	}{
+		{id: "golang.go", want: Extension{Publisher: "golang", Name: "go"}},
+		{id: "golang.go@0.41.2", want: Extension{Publisher: "golang", Name: "go", Version: "0.41.2"}},
+		{id: " ms-python.vscode-pylance@2024.1.1 ", want: Extension{Publisher: "ms-python", Name: "vscode-pylance", Version: "2024.1.1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			got := ParseExtension(tt.id)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseExtension() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFindVSIX(t *testing.T) {
+	folder := t.TempDir()
+	writeVSIX(t, filepath.Join(folder, "go-old.vsix"), `{"publisher":"golang","name":"go","version":"0.40.0"}`)
+	writeVSIX(t, filepath.Join(folder, "go-new.vsix"), `{"publisher":"golang","name":"go","version":"0.41.2"}`)
+	writeVSIX(t, filepath.Join(folder, "python.vsix"), `{"publisher":"ms-python","name":"python","version":"2024.0.1"}`)
+	err := os.WriteFile(filepath.Join(folder, "broken.vsix"), []byte("not a zip"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{id: "golang.go", want: "go-new.vsix"},
+		{id: "golang.go@0.40.0", want: "go-old.vsix"},
+		{id: "golang.go@0.39.0", want: ""},
+		{id: "ms-python.python", want: "python.vsix"},
+		{id: "rust-lang.rust-analyzer", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			got, err := FindVSIX(folder, ParseExtension(tt.id))
+			if err != nil {
+				t.Fatalf("FindVSIX() error = %v", err)
+			}
+			if got != "" {
+				got = filepath.Base(got)
+			}
+			if got != tt.want {
+				t.Errorf("FindVSIX() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstallExtension(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/golang/go":
+			_, _ = w.Write([]byte(`{"version":"0.41.2","files":{"download":"` + server.URL + `/golang.go-0.41.2.vsix"}}`))
+		case "/golang.go-0.41.2.vsix":
+			_, _ = w.Write([]byte("vsix"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	installed := ""
+	err := InstallExtension("golang.go", server.URL, "", log.Discard, func(extension string) error {
+		installed = extension
+		content, err := os.ReadFile(extension)
+		if err != nil {
+			return err
+		} else if string(content) != "vsix" {
+			t.Errorf("InstallExtension() installed %q, want the downloaded VSIX file", string(content))
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(installed, "golang.go-0.41.2.vsix") {
+		t.Errorf("InstallExtension() installed %q, want the downloaded VSIX file", installed)
+	}
+	if _, err := os.Stat(filepath.Dir(installed)); !os.IsNotExist(err) {
+		t.Errorf("InstallExtension() didn't remove %s", filepath.Dir(installed))
+	}
+
+	// extensions that are missing in the gallery are installed by id
+	err = InstallExtension("ms-python.python@2024.0.1", server.URL, "", log.Discard, func(extension string) error {
+		installed = extension
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if installed != "ms-python.python@2024.0.1" {
+		t.Errorf("InstallExtension() installed %q, want %q", installed, "ms-python.python@2024.0.1")
+	}
+}
+
+func writeVSIX(t *testing.T, path string, packageJSON string) {
+	t.Helper()
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	writer := zip.NewWriter(file)
+	entry, err := writer.Create("extension/package.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = entry.Write([]byte(packageJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = writer.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"os/exec"
+	"regexp"
 	"runtime"
 	"strings"
+	"time"
 
 	"dev.khulnasoft.com/pkg/command"
 	"dev.khulnasoft.com/log"
@@ -122,6 +124,36 @@ func openViaCLI(ctx context.Context, workspace, folder string, newWindow bool, f
 	return nil
 }
 
+// LocalServerCommit returns the commit of the locally installed VS Code, which is the commit of the server it
+// installs in the workspace, or an empty string if it can't be found
+func LocalServerCommit(flavor Flavor) string {
+	if quality, _ := flavor.serverQuality(); quality == "" {
+		return ""
+	}
+
+	codePath := findCLI(flavor)
+	if codePath == "" {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, codePath, "--version").Output()
+	if err != nil {
+		return ""
+	}
+
+	// the output is the version, commit and architecture on separate lines
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) < 2 || !commitRegEx.MatchString(strings.TrimSpace(lines[1])) {
+		return ""
+	}
+
+	return strings.TrimSpace(lines[1])
+}
+
+var commitRegEx = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
 func findCLI(flavor Flavor) string {
 	if flavor == FlavorStable {
 		if command.Exists("code") {
@@ -6,23 +6,30 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"time"
 
 	"dev.khulnasoft.com/pkg/command"
 	"dev.khulnasoft.com/pkg/config"
 	copy2 "dev.khulnasoft.com/pkg/copy"
+	"dev.khulnasoft.com/pkg/extract"
 	"dev.khulnasoft.com/pkg/ide"
 	"dev.khulnasoft.com/pkg/util"
 	"dev.khulnasoft.com/log"
+	"github.com/alessio/shellescape"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
 const (
-	OpenNewWindow = "OPEN_NEW_WINDOW"
+	OpenNewWindow      = "OPEN_NEW_WINDOW"
+	ServerCommitOption = "SERVER_COMMIT"
 )
 
+// MarketplaceURL is the marketplace VS Code installs extensions from
+var MarketplaceURL = "https://marketplace.visualstudio.com"
+
 type Flavor string
 
 const (
@@ -53,6 +60,28 @@ func (f Flavor) DisplayName() string {
 	}
 }
 
+// Marketplace returns the marketplace the flavor installs extensions from or an empty string if it's not known
+func (f Flavor) Marketplace() string {
+	switch f {
+	case FlavorStable, FlavorInsiders:
+		return MarketplaceURL
+	default:
+		return ""
+	}
+}
+
+// serverQuality returns the quality of the flavor on the VS Code update server and the prefix of its server folder
+func (f Flavor) serverQuality() (string, string) {
+	switch f {
+	case FlavorStable:
+		return "stable", "Stable-"
+	case FlavorInsiders:
+		return "insider", "Insiders-"
+	default:
+		return "", ""
+	}
+}
+
 var Options = ide.Options{
 	OpenNewWindow: {
 		Name:        OpenNewWindow,
@@ -63,6 +92,41 @@ var Options = ide.Options{
 			"true",
 		},
 	},
+	ExtensionsVSIXFolderOption: {
+		Name:        ExtensionsVSIXFolderOption,
+		Description: "A local folder with .vsix files. Extensions found there are copied into the workspace and installed from it instead of the marketplace",
+	},
+	ExtensionsGalleryOption: {
+		Name:        ExtensionsGalleryOption,
+		Description: "An Open VSX compatible registry to install extensions from instead of the marketplace. E.g. https://open-vsx.org",
+	},
+	ServerCommitOption: {
+		Name:        ServerCommitOption,
+		Description: "The commit of the VS Code server to install from the ide cache. Defaults to the commit of the local VS Code",
+	},
+}
+
+// ServerDownloadURL returns the url of the VS Code server of the commit in SERVER_COMMIT for linux on the given
+// architecture or an empty string if the commit is not known
+func ServerDownloadURL(values map[string]config.OptionValue, flavor Flavor, arch string) string {
+	commit := Options.GetValue(values, ServerCommitOption)
+	quality, _ := flavor.serverQuality()
+	if commit == "" || quality == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("https://update.code.visualstudio.com/commit:%s/server-linux-%s/%s", commit, serverArch(arch), quality)
+}
+
+func serverArch(arch string) string {
+	switch arch {
+	case "arm64":
+		return "arm64"
+	case "arm":
+		return "armhf"
+	default:
+		return "x64"
+	}
 }
 
 func NewVSCodeServer(extensions []string, settings string, userName string, values map[string]config.OptionValue, flavor Flavor, log log.Logger) *VsCodeServer {
@@ -106,19 +170,22 @@ func (o *VsCodeServer) InstallExtensions() error {
 	defer errwriter.Close()
 
 	// download extensions
+	gallery := Options.GetValue(o.values, ExtensionsGalleryOption)
 	for _, extension := range o.extensions {
 		o.log.Info("Install extension " + extension + "...")
-		runCommand := fmt.Sprintf("%s serve-local --accept-server-license-terms --install-extension '%s'", binPath, extension)
-		args := []string{}
-		if o.userName != "" {
-			args = append(args, "su", o.userName, "-c", runCommand)
-		} else {
-			args = append(args, "sh", "-c", runCommand)
-		}
-		cmd := exec.Command(args[0], args[1:]...)
-		cmd.Stdout = writer
-		cmd.Stderr = errwriter
-		err := cmd.Run()
+		err := InstallExtension(extension, gallery, o.flavor.Marketplace(), o.log, func(extension string) error {
+			runCommand := fmt.Sprintf("%s serve-local --accept-server-license-terms --install-extension %s", binPath, shellescape.Quote(extension))
+			args := []string{}
+			if o.userName != "" {
+				args = append(args, "su", o.userName, "-c", runCommand)
+			} else {
+				args = append(args, "sh", "-c", runCommand)
+			}
+			cmd := exec.Command(args[0], args[1:]...)
+			cmd.Stdout = writer
+			cmd.Stderr = errwriter
+			return cmd.Run()
+		})
 		if err != nil {
 			o.log.Warn("Failed installing extension " + extension)
 		}
@@ -134,6 +201,11 @@ func (o *VsCodeServer) Install() error {
 		return err
 	}
 
+	err = o.installServerFromCache(location)
+	if err != nil {
+		o.log.Debugf("Error installing %s server from the ide cache: %v", o.flavor.DisplayName(), err)
+	}
+
 	settingsDir := filepath.Join(location, "data", "Machine")
 	err = os.MkdirAll(settingsDir, 0755)
 	if err != nil {
@@ -171,6 +243,66 @@ func (o *VsCodeServer) Install() error {
 	return nil
 }
 
+// installServerFromCache extracts the server of the local VS Code from the ide cache to where the VS Code cli looks for
+// it, so the VS Code client doesn't download it into the workspace
+func (o *VsCodeServer) installServerFromCache(location string) error {
+	downloadURL := ServerDownloadURL(o.values, o.flavor, runtime.GOARCH)
+	if downloadURL == "" || !ide.CacheAvailable() {
+		return nil
+	}
+
+	_, prefix := o.flavor.serverQuality()
+	serversDir := filepath.Join(location, "cli", "servers")
+	serverDir := filepath.Join(serversDir, prefix+Options.GetValue(o.values, ServerCommitOption))
+	_, err := os.Stat(serverDir)
+	if err == nil {
+		return nil
+	}
+
+	cachedPath, err := ide.CachedDownload(ide.CacheFolder, downloadURL)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(cachedPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// extract next to the server folder first, so the VS Code cli never sees a partially extracted server
+	err = os.MkdirAll(serversDir, 0755)
+	if err != nil {
+		return err
+	}
+	tmpDir, err := os.MkdirTemp(serversDir, ".devspace-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	err = os.Chmod(tmpDir, 0755)
+	if err != nil {
+		return err
+	}
+
+	o.log.Infof("Install %s server from the ide cache", o.flavor.DisplayName())
+	err = extract.Extract(file, filepath.Join(tmpDir, "server"), extract.StripLevels(1))
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tmpDir, serverDir)
+	if err != nil {
+		return err
+	}
+
+	if o.userName != "" {
+		return copy2.ChownR(filepath.Join(location, "cli"), o.userName)
+	}
+
+	return nil
+}
+
 func (o *VsCodeServer) findServerBinaryPath(location string) string {
 	binPath := ""
 	// Limit time we spend to look for code server binary.
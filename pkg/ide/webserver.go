@@ -0,0 +1,151 @@
+package ide
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/command"
+	"dev.khulnasoft.com/pkg/single"
+)
+
+const (
+	OpenOption        = "OPEN"
+	BindAddressOption = "BIND_ADDRESS"
+)
+
+// WebServerReadinessTimeout is how long to wait for a web server to answer requests after it was started
+const WebServerReadinessTimeout = 2 * time.Minute
+
+// webServerRestartDelay is how many seconds to wait before a server that exited is restarted
+var webServerRestartDelay = 3
+
+// WithWebOptions returns the given options together with the options shared by all IDEs that are opened in the
+// browser
+func WithWebOptions(options Options) Options {
+	webOptions := Options{
+		BindAddressOption: {
+			Name:        BindAddressOption,
+			Description: "The address to bind the server to locally. E.g. 0.0.0.0:12345",
+			Default:     "",
+		},
+		OpenOption: {
+			Name:        OpenOption,
+			Description: "If DevSpace should automatically open the browser",
+			Default:     "true",
+			Enum: []string{
+				"true",
+				"false",
+			},
+		},
+	}
+	for name, option := range options {
+		webOptions[name] = option
+	}
+
+	return webOptions
+}
+
+// WebServer runs the server of an IDE that is opened in the browser. The server is started in the background as the
+// remote user, restarted whenever it exits and considered started as soon as it answers requests on its port.
+type WebServer struct {
+	// Name identifies the server, its pid and log files are named after it
+	Name string
+
+	// Command starts the server in the foreground
+	Command string
+
+	// Port is the port the server listens on within the container
+	Port int
+
+	// ReadinessPath is requested to check if the server is ready
+	ReadinessPath string
+
+	UserName        string
+	WorkspaceFolder string
+	Log             log.Logger
+}
+
+// Start starts the server if it isn't running yet and waits until it is ready
+func (w *WebServer) Start() error {
+	err := single.Single(w.Name+".pid", func() (*exec.Cmd, error) {
+		w.Log.Infof("Starting %s in background...", w.Name)
+		cmd := exec.Command("sh", "-c", w.superviseScript())
+		cmd.Dir = w.WorkspaceFolder
+		return cmd, nil
+	})
+	if err != nil {
+		return fmt.Errorf("start %s: %w", w.Name, err)
+	}
+
+	return w.WaitUntilReady(WebServerReadinessTimeout)
+}
+
+// superviseScript runs the server command as the remote user in a loop, so the server comes back if it crashes
+func (w *WebServer) superviseScript() string {
+	runCommand := ""
+	if w.UserName != "" {
+		runCommand = command.Quote([]string{"su", w.UserName, "-w", "SSH_AUTH_SOCK", "-l", "-c", w.Command})
+	} else {
+		runCommand = command.Quote([]string{"sh", "-l", "-c", w.Command})
+	}
+
+	return fmt.Sprintf(`while true; do
+  %s
+  echo "%s exited with code $?, restarting in %d seconds..."
+  sleep %d
+done`, runCommand, w.Name, webServerRestartDelay, webServerRestartDelay)
+}
+
+// WaitUntilReady waits until the server answers http requests. Any response that is not a server error counts,
+// since most servers redirect or ask for authentication on their root path.
+func (w *WebServer) WaitUntilReady(timeout time.Duration) error {
+	url := fmt.Sprintf("http://127.0.0.1:%d%s", w.Port, w.ReadinessPath)
+	client := &http.Client{Timeout: 5 * time.Second}
+	deadline := time.Now().Add(timeout)
+	for {
+		resp, err := client.Get(url)
+		if err == nil {
+			_ = resp.Body.Close()
+			if resp.StatusCode < http.StatusInternalServerError {
+				w.Log.Debugf("%s is ready", w.Name)
+				return nil
+			}
+
+			err = fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s is not ready after %s (%w), please check the logs at %s", w.Name, timeout, err, w.LogFile())
+		}
+
+		time.Sleep(time.Second)
+	}
+}
+
+// LogFile returns the file the output of the server is written to
+func (w *WebServer) LogFile() string {
+	return filepath.Join(os.TempDir(), w.Name+".pid.streams")
+}
+
+// Run runs the command as the remote user in a login shell, so tools installed for the user are found
+func (w *WebServer) Run(runCommand string) error {
+	args := []string{}
+	if w.UserName != "" {
+		args = append(args, "su", w.UserName, "-l", "-c", runCommand)
+	} else {
+		args = append(args, "sh", "-l", "-c", runCommand)
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = w.WorkspaceFolder
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return command.WrapCommandError(out, err)
+	}
+
+	return nil
+}
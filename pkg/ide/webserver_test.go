@@ -0,0 +1,115 @@
+package ide
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"dev.khulnasoft.com/log"
+)
+
+func TestWaitUntilReady(t *testing.T) {
+	requests := atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/starting":
+			// the server answers after a few requests
+			if requests.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		case "/login":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	port := server.Listener.Addr().(*net.TCPAddr).Port
+
+	tests := []struct {
+		name          string
+		port          int
+		readinessPath string
+		timeout       time.Duration
+		wantErr       bool
+	}{
+		{name: "ready after a while", port: port, readinessPath: "/starting", timeout: 10 * time.Second},
+		{name: "asks for authentication", port: port, readinessPath: "/login", timeout: time.Second},
+		{name: "server error", port: port, readinessPath: "/broken", timeout: time.Second, wantErr: true},
+		{name: "not listening", port: freePort(t), readinessPath: "/", timeout: time.Second, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &WebServer{Name: "test", Port: tt.port, ReadinessPath: tt.readinessPath, Log: log.Discard}
+			err := w.WaitUntilReady(tt.timeout)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WaitUntilReady() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), w.LogFile()) {
+				t.Errorf("WaitUntilReady() error = %v, want a hint to the log file", err)
+			}
+		})
+	}
+}
+
+func TestSuperviseScript(t *testing.T) {
+	restartDelay := webServerRestartDelay
+	webServerRestartDelay = 0
+	defer func() { webServerRestartDelay = restartDelay }()
+
+	runsFile := filepath.Join(t.TempDir(), "runs")
+	w := &WebServer{
+		Name:    "test",
+		Command: "echo run >> '" + runsFile + "'; exit 1",
+		Log:     log.Discard,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", w.superviseScript())
+	err := cmd.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	// the crashing server is restarted
+	for {
+		runs, _ := os.ReadFile(runsFile)
+		if strings.Count(string(runs), "run") >= 3 {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			t.Fatalf("server was not restarted, runs: %q", string(runs))
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+func freePort(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
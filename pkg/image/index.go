@@ -0,0 +1,79 @@
+package image
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+)
+
+// PlatformImage is an already pushed image that was built for a specific platform
+type PlatformImage struct {
+	Platform string
+	Image    string
+}
+
+// PushIndex assembles the given platform images into a single OCI image index and pushes
+// it to all target references. Images that are an index themselves are resolved to the
+// manifest of their platform.
+func PushIndex(ctx context.Context, images []PlatformImage, targets ...string) error {
+	keychain, err := GetKeychain(ctx)
+	if err != nil {
+		return fmt.Errorf("create authentication keychain: %w", err)
+	}
+
+	var index v1.ImageIndex = mutate.IndexMediaType(empty.Index, types.OCIImageIndex)
+	for _, platformImage := range images {
+		platform, err := v1.ParsePlatform(platformImage.Platform)
+		if err != nil {
+			return errors.Wrapf(err, "parse platform %s", platformImage.Platform)
+		}
+
+		ref, err := name.ParseReference(platformImage.Image)
+		if err != nil {
+			return err
+		}
+
+		img, err := remote.Image(ref, remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain), remote.WithPlatform(*platform))
+		if err != nil {
+			return errors.Wrapf(err, "retrieve image %s", platformImage.Image)
+		}
+
+		// prefer the platform from the image config, as it might contain a variant
+		configFile, err := img.ConfigFile()
+		if err == nil && configFile.Architecture != "" {
+			platform = &v1.Platform{
+				OS:           configFile.OS,
+				Architecture: configFile.Architecture,
+				Variant:      configFile.Variant,
+			}
+		}
+
+		index = mutate.AppendManifests(index, mutate.IndexAddendum{
+			Add: img,
+			Descriptor: v1.Descriptor{
+				Platform: platform,
+			},
+		})
+	}
+
+	for _, target := range targets {
+		ref, err := name.ParseReference(target)
+		if err != nil {
+			return err
+		}
+
+		err = remote.WriteIndex(ref, index, remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain))
+		if err != nil {
+			return errors.Wrapf(err, "push image index %s", target)
+		}
+	}
+
+	return nil
+}
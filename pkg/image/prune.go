@@ -0,0 +1,305 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"dev.khulnasoft.com/pkg/devcontainer/config"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/pkg/errors"
+)
+
+// PrebuildTagPrefix is the prefix of all image tags DevSpace creates for prebuilds
+const PrebuildTagPrefix = "devspace-"
+
+// Prebuild is a prebuilt image within a registry or the local docker daemon
+type Prebuild struct {
+	// Repository is the image repository the prebuild is stored in
+	Repository string
+
+	// Tags are all tags pointing to the prebuild or one of its platform images
+	Tags []string
+
+	// Digests holds the manifest digest of the prebuild followed by the digests of its platform
+	// images, for local images it holds the image id
+	Digests []string
+
+	// Branch is the git branch the prebuild was built from
+	Branch string
+
+	// Created is the time the prebuild was created
+	Created time.Time
+}
+
+// PruneOptions define which prebuilds are retained
+type PruneOptions struct {
+	// KeepLast is the amount of most recent prebuilds to keep per repository and branch
+	KeepLast int
+
+	// KeepTags are prebuild tags that should be kept, e.g. because workspaces still use them
+	KeepTags map[string]bool
+}
+
+// SelectPrunable returns the prebuilds that can be deleted. Prebuilds are kept if they have tags not
+// managed by DevSpace, have a tag within options.KeepTags or belong to the options.KeepLast most
+// recent prebuilds of their repository and branch.
+func SelectPrunable(prebuilds []*Prebuild, options PruneOptions) []*Prebuild {
+	groups := map[string][]*Prebuild{}
+	for _, prebuild := range prebuilds {
+		key := prebuild.Repository + "@" + prebuild.Branch
+		groups[key] = append(groups[key], prebuild)
+	}
+
+	prunable := []*Prebuild{}
+	for _, group := range groups {
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].Created.After(group[j].Created)
+		})
+
+		for i, prebuild := range group {
+			if i < options.KeepLast || prebuild.isReferenced(options.KeepTags) {
+				continue
+			}
+
+			prunable = append(prunable, prebuild)
+		}
+	}
+
+	sort.SliceStable(prunable, func(i, j int) bool {
+		if prunable[i].Repository != prunable[j].Repository {
+			return prunable[i].Repository < prunable[j].Repository
+		} else if !prunable[i].Created.Equal(prunable[j].Created) {
+			return prunable[i].Created.Before(prunable[j].Created)
+		}
+
+		return strings.Join(prunable[i].Tags, ",") < strings.Join(prunable[j].Tags, ",")
+	})
+	return prunable
+}
+
+func (p *Prebuild) isReferenced(keepTags map[string]bool) bool {
+	for _, tag := range p.Tags {
+		if !strings.HasPrefix(tag, PrebuildTagPrefix) || keepTags[tag] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ListPrebuilds returns all prebuilds within the given registry repository. Platform images that are
+// part of a prebuild image index are returned as part of the index.
+func ListPrebuilds(ctx context.Context, repository string) ([]*Prebuild, error) {
+	repo, err := name.NewRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	keychain, err := GetKeychain(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create authentication keychain: %w", err)
+	}
+	remoteOptions := []remote.Option{remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain)}
+
+	tags, err := remote.List(repo, remoteOptions...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "list tags of %s", repository)
+	}
+
+	// group tags by the manifest they point to
+	descriptors := map[string]*remote.Descriptor{}
+	digestTags := map[string][]string{}
+	for _, tag := range tags {
+		descriptor, err := remote.Get(repo.Tag(tag), remoteOptions...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "retrieve %s:%s", repository, tag)
+		}
+
+		digest := descriptor.Digest.String()
+		descriptors[digest] = descriptor
+		digestTags[digest] = append(digestTags[digest], tag)
+	}
+
+	prebuilds := map[string]*Prebuild{}
+	for digest, descriptor := range descriptors {
+		if !hasPrebuildTag(digestTags[digest]) {
+			continue
+		}
+
+		prebuild, err := newRemotePrebuild(repository, descriptor)
+		if err != nil {
+			return nil, err
+		}
+
+		prebuilds[digest] = prebuild
+	}
+
+	// merge platform images into the image index they belong to
+	merged := map[string]bool{}
+	for digest, prebuild := range prebuilds {
+		prebuild.Tags = append(prebuild.Tags, digestTags[digest]...)
+	}
+	for digest, prebuild := range prebuilds {
+		for _, index := range prebuilds {
+			if len(index.Digests) <= len(prebuild.Digests) || !overlaps(prebuild.Digests, index.Digests[1:]) {
+				continue
+			}
+
+			index.Tags = append(index.Tags, prebuild.Tags...)
+			if !slices.Contains(index.Digests, digest) {
+				// delete the platform image before the manifests it refers to
+				index.Digests = slices.Insert(index.Digests, 1, digest)
+			}
+			merged[digest] = true
+			break
+		}
+	}
+
+	retPrebuilds := []*Prebuild{}
+	for digest, prebuild := range prebuilds {
+		if merged[digest] {
+			continue
+		}
+
+		sort.Strings(prebuild.Tags)
+		retPrebuilds = append(retPrebuilds, prebuild)
+	}
+	sort.Slice(retPrebuilds, func(i, j int) bool {
+		return retPrebuilds[i].Created.Before(retPrebuilds[j].Created)
+	})
+
+	return retPrebuilds, nil
+}
+
+// DeletePrebuild deletes the prebuild, its platform images and all artifacts referring to them from the registry
+func DeletePrebuild(ctx context.Context, prebuild *Prebuild) error {
+	repo, err := name.NewRepository(prebuild.Repository)
+	if err != nil {
+		return err
+	}
+
+	keychain, err := GetKeychain(ctx)
+	if err != nil {
+		return fmt.Errorf("create authentication keychain: %w", err)
+	}
+	remoteOptions := []remote.Option{remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain)}
+
+	for _, digest := range prebuild.Digests {
+		ref := repo.Digest(digest)
+
+		// delete sboms, provenance and signatures first
+		referrers, err := remote.Referrers(ref, remoteOptions...)
+		if err == nil {
+			referrersManifest, err := referrers.IndexManifest()
+			if err == nil {
+				for _, referrer := range referrersManifest.Manifests {
+					err = deleteManifest(repo.Digest(referrer.Digest.String()), remoteOptions)
+					if err != nil {
+						return errors.Wrapf(err, "delete referrer %s", referrer.Digest)
+					}
+				}
+			}
+		}
+
+		err = deleteManifest(ref, remoteOptions)
+		if err != nil {
+			return errors.Wrapf(err, "delete %s", ref.String())
+		}
+	}
+
+	return nil
+}
+
+func newRemotePrebuild(repository string, descriptor *remote.Descriptor) (*Prebuild, error) {
+	prebuild := &Prebuild{
+		Repository: repository,
+		Digests:    []string{descriptor.Digest.String()},
+	}
+
+	var img v1.Image
+	if descriptor.MediaType.IsIndex() {
+		index, err := descriptor.ImageIndex()
+		if err != nil {
+			return nil, err
+		}
+		indexManifest, err := index.IndexManifest()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, manifest := range indexManifest.Manifests {
+			// attestation manifests are removed together with the index
+			if manifest.Platform == nil || manifest.Platform.OS == "unknown" {
+				continue
+			}
+
+			prebuild.Digests = append(prebuild.Digests, manifest.Digest.String())
+			if img == nil {
+				img, err = index.Image(manifest.Digest)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	} else {
+		var err error
+		img, err = descriptor.Image()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if img == nil {
+		return prebuild, nil
+	}
+
+	configFile, err := img.ConfigFile()
+	if err != nil {
+		return nil, errors.Wrapf(err, "get image config of %s@%s", repository, descriptor.Digest)
+	}
+	prebuild.Created = configFile.Created.Time
+	prebuild.Branch = configFile.Config.Labels[config.PrebuildBranchLabel]
+	return prebuild, nil
+}
+
+func deleteManifest(ref name.Digest, remoteOptions []remote.Option) error {
+	err := remote.Delete(ref, remoteOptions...)
+	if err != nil {
+		// the manifest might already be gone together with its index
+		var transportErr *transport.Error
+		if errors.As(err, &transportErr) && transportErr.StatusCode == http.StatusNotFound {
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func hasPrebuildTag(tags []string) bool {
+	for _, tag := range tags {
+		if strings.HasPrefix(tag, PrebuildTagPrefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func overlaps(a, b []string) bool {
+	for _, value := range a {
+		if slices.Contains(b, value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,66 @@
+package image
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSelectPrunable(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	prebuild := func(tag, branch string, age int, tags ...string) *Prebuild {
+		return &Prebuild{
+			Repository: "ghcr.io/my-org/repo",
+			Tags:       append([]string{tag}, tags...),
+			Branch:     branch,
+			Created:    now.Add(-time.Duration(age) * time.Hour),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		options PruneOptions
+		want    []string
+	}{
+		{
+			name:    "keep last per branch",
+			options: PruneOptions{KeepLast: 1},
+			want:    []string{"devspace-main-3", "devspace-feature-2", "devspace-main-2"},
+		},
+		{
+			name: "keep referenced",
+			options: PruneOptions{
+				KeepLast: 1,
+				KeepTags: map[string]bool{"devspace-main-2": true, "devspace-arm64": true},
+			},
+			want: []string{"devspace-main-3"},
+		},
+		{
+			name:    "keep nothing",
+			options: PruneOptions{},
+			want:    []string{"devspace-main-3", "devspace-feature-2", "devspace-main-2", "devspace-feature-1", "devspace-main-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prebuilds := []*Prebuild{
+				prebuild("devspace-main-1", "main", 1),
+				prebuild("devspace-main-2", "main", 2),
+				prebuild("devspace-main-3", "main", 3),
+				prebuild("devspace-feature-1", "feature", 1),
+				prebuild("devspace-feature-2", "feature", 2, "devspace-arm64"),
+				prebuild("devspace-latest", "main", 4, "latest"),
+			}
+
+			got := []string{}
+			for _, prunable := range SelectPrunable(prebuilds, tt.options) {
+				got = append(got, prunable.Tags[0])
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("SelectPrunable() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	// InTotoMediaType is the media type of an in-toto attestation statement
+	InTotoMediaType = "application/vnd.in-toto+json"
+
+	// InTotoPredicateTypeAnnotation holds the predicate type of an attestation layer or artifact
+	InTotoPredicateTypeAnnotation = "in-toto.io/predicate-type"
+
+	// SPDXPredicateType and CycloneDXPredicateType are the in-toto predicate types of SBOM attestations
+	SPDXPredicateType      = "https://spdx.dev/Document"
+	CycloneDXPredicateType = "https://cyclonedx.org/bom"
+
+	buildkitReferenceTypeAnnotation   = "vnd.docker.reference.type"
+	buildkitReferenceDigestAnnotation = "vnd.docker.reference.digest"
+	buildkitAttestationManifest       = "attestation-manifest"
+)
+
+// sbomArtifactTypes maps the in-toto predicate type of an SBOM to the artifact type it is pushed with
+var sbomArtifactTypes = map[string]string{
+	SPDXPredicateType:      "application/spdx+json",
+	CycloneDXPredicateType: "application/vnd.cyclonedx+json",
+}
+
+// GetDescriptor returns the descriptor of the manifest or index the given image reference points to
+func GetDescriptor(ctx context.Context, image string) (*v1.Descriptor, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return nil, err
+	}
+
+	keychain, err := GetKeychain(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create authentication keychain: %w", err)
+	}
+
+	descriptor, err := remote.Head(ref, remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain))
+	if err != nil {
+		return nil, errors.Wrapf(err, "retrieve image %s", image)
+	}
+
+	return descriptor, nil
+}
+
+// PushReferrer pushes the content as a single layer OCI artifact that refers to the subject within the
+// repository of the given image, so it can be discovered through the OCI referrers API.
+func PushReferrer(ctx context.Context, image string, subject v1.Descriptor, artifactType string, mediaType types.MediaType, content []byte, annotations map[string]string) error {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return err
+	}
+
+	keychain, err := GetKeychain(ctx)
+	if err != nil {
+		return fmt.Errorf("create authentication keychain: %w", err)
+	}
+
+	artifact, err := newReferrer(subject, artifactType, mediaType, content, annotations)
+	if err != nil {
+		return err
+	}
+
+	digest, err := artifact.Digest()
+	if err != nil {
+		return err
+	}
+
+	err = remote.Write(ref.Context().Digest(digest.String()), artifact, remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain))
+	if err != nil {
+		return errors.Wrapf(err, "push %s referrer for %s", artifactType, image)
+	}
+
+	return nil
+}
+
+// GetReferrer returns the content of the first artifact with the given artifact type that refers to one of the
+// subjects of the given image. The subjects are the manifest or index the image reference points to and the image
+// for the given architecture within it. Returns nil if there is no such artifact.
+func GetReferrer(ctx context.Context, image, arch, artifactType string) ([]byte, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return nil, err
+	}
+
+	keychain, err := GetKeychain(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create authentication keychain: %w", err)
+	}
+	remoteOptions := []remote.Option{remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain)}
+
+	descriptor, err := remote.Head(ref, remoteOptions...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "retrieve image %s", image)
+	}
+	subjects := []v1.Hash{descriptor.Digest}
+	if descriptor.MediaType.IsIndex() {
+		imageOptions := remoteOptions
+		if arch != "" {
+			imageOptions = append(imageOptions, remote.WithPlatform(v1.Platform{Architecture: arch, OS: "linux"}))
+		}
+
+		img, err := remote.Image(ref, imageOptions...)
+		if err == nil {
+			if digest, err := img.Digest(); err == nil {
+				subjects = append(subjects, digest)
+			}
+		}
+	}
+
+	for _, subject := range subjects {
+		referrers, err := remote.Referrers(ref.Context().Digest(subject.String()), append(remoteOptions, remote.WithFilter("artifactType", artifactType))...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "list referrers of %s", image)
+		}
+		referrersManifest, err := referrers.IndexManifest()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, referrer := range referrersManifest.Manifests {
+			// registries may ignore the filter
+			if referrer.ArtifactType != artifactType {
+				continue
+			}
+
+			return readReferrer(ref.Context().Digest(referrer.Digest.String()), remoteOptions)
+		}
+	}
+
+	return nil, nil
+}
+
+// PushSBOMReferrers pushes the SBOM attestations BuildKit stored within the image index of the given image
+// as OCI referrers of the platform images they describe. Returns the amount of pushed SBOMs.
+func PushSBOMReferrers(ctx context.Context, image string) (int, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return 0, err
+	}
+
+	keychain, err := GetKeychain(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("create authentication keychain: %w", err)
+	}
+
+	descriptor, err := remote.Get(ref, remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain))
+	if err != nil {
+		return 0, errors.Wrapf(err, "retrieve image %s", image)
+	} else if !descriptor.MediaType.IsIndex() {
+		// BuildKit only stores attestations within an image index
+		return 0, nil
+	}
+
+	index, err := descriptor.ImageIndex()
+	if err != nil {
+		return 0, err
+	}
+	indexManifest, err := index.IndexManifest()
+	if err != nil {
+		return 0, err
+	}
+
+	pushed := 0
+	for _, manifest := range indexManifest.Manifests {
+		if manifest.Annotations[buildkitReferenceTypeAnnotation] != buildkitAttestationManifest {
+			continue
+		}
+
+		subject, err := findManifest(indexManifest, manifest.Annotations[buildkitReferenceDigestAnnotation])
+		if err != nil {
+			return pushed, err
+		}
+
+		attestationImage, err := index.Image(manifest.Digest)
+		if err != nil {
+			return pushed, err
+		}
+		attestationManifest, err := attestationImage.Manifest()
+		if err != nil {
+			return pushed, err
+		}
+
+		for _, layer := range attestationManifest.Layers {
+			predicateType := layer.Annotations[InTotoPredicateTypeAnnotation]
+			artifactType, ok := sbomArtifactTypes[predicateType]
+			if !ok {
+				continue
+			}
+
+			predicate, err := readPredicate(attestationImage, layer.Digest)
+			if err != nil {
+				return pushed, errors.Wrapf(err, "read sbom of %s", subject.Digest)
+			}
+
+			err = PushReferrer(ctx, image, *subject, artifactType, types.MediaType(artifactType), predicate, map[string]string{
+				InTotoPredicateTypeAnnotation: predicateType,
+			})
+			if err != nil {
+				return pushed, err
+			}
+			pushed++
+		}
+	}
+
+	return pushed, nil
+}
+
+func newReferrer(subject v1.Descriptor, artifactType string, mediaType types.MediaType, content []byte, annotations map[string]string) (v1.Image, error) {
+	artifact, err := mutate.Append(mutate.MediaType(empty.Image, types.OCIManifestSchema1), mutate.Addendum{
+		Layer:     static.NewLayer(content, mediaType),
+		MediaType: mediaType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// registries without an artifactType field derive it from the config media type
+	artifact = mutate.ConfigMediaType(artifact, types.MediaType(artifactType))
+	if len(annotations) > 0 {
+		artifact = mutate.Annotations(artifact, annotations).(v1.Image)
+	}
+
+	return mutate.Subject(artifact, subject).(v1.Image), nil
+}
+
+// readReferrer reads the single layer of an artifact pushed by PushReferrer
+func readReferrer(ref name.Digest, remoteOptions []remote.Option) ([]byte, error) {
+	artifact, err := remote.Image(ref, remoteOptions...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "retrieve referrer %s", ref.String())
+	}
+
+	layers, err := artifact.Layers()
+	if err != nil {
+		return nil, err
+	} else if len(layers) != 1 {
+		return nil, fmt.Errorf("referrer %s has %d layers, expected 1", ref.String(), len(layers))
+	}
+
+	reader, err := layers[0].Compressed()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+func findManifest(indexManifest *v1.IndexManifest, digest string) (*v1.Descriptor, error) {
+	for _, manifest := range indexManifest.Manifests {
+		if manifest.Digest.String() == digest {
+			return &v1.Descriptor{
+				MediaType: manifest.MediaType,
+				Size:      manifest.Size,
+				Digest:    manifest.Digest,
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("couldn't find manifest %s the attestation refers to", digest)
+}
+
+// readPredicate reads the in-toto statement within the given layer and returns its predicate
+func readPredicate(img v1.Image, digest v1.Hash) ([]byte, error) {
+	layer, err := img.LayerByDigest(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := layer.Compressed()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := struct {
+		Predicate json.RawMessage `json:"predicate"`
+	}{}
+	err = json.Unmarshal(raw, &statement)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse in-toto statement")
+	} else if len(statement.Predicate) == 0 {
+		return nil, fmt.Errorf("in-toto statement has no predicate")
+	}
+
+	return statement.Predicate, nil
+}
@@ -0,0 +1,63 @@
+package image
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+func TestGetReferrer(t *testing.T) {
+	server := httptest.NewServer(registry.New(registry.WithReferrersSupport(true)))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+	ctx := context.Background()
+
+	imageName := host + "/prebuild:devspace-abc"
+	img, err := random.Image(64, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := name.ParseReference(imageName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = remote.Write(ref, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	artifactType := "application/vnd.devspace.test+json"
+	content, err := GetReferrer(ctx, imageName, "", artifactType)
+	if err != nil {
+		t.Fatal(err)
+	} else if content != nil {
+		t.Fatalf("expected no referrer, got %s", string(content))
+	}
+
+	subject, err := GetDescriptor(ctx, imageName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = PushReferrer(ctx, imageName, *subject, "application/vnd.devspace.other+json", types.MediaType("application/vnd.devspace.other+json"), []byte(`{"other":true}`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = PushReferrer(ctx, imageName, *subject, artifactType, types.MediaType(artifactType), []byte(`{"hash":"devspace-abc"}`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err = GetReferrer(ctx, imageName, "", artifactType)
+	if err != nil {
+		t.Fatal(err)
+	} else if string(content) != `{"hash":"devspace-abc"}` {
+		t.Fatalf("expected the pushed referrer, got %q", string(content))
+	}
+}
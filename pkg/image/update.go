@@ -0,0 +1,79 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+
+	"dev.khulnasoft.com/pkg/devcontainer/config"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+)
+
+// featureMetadataAnnotation holds the devcontainer-feature.json of a published OCI feature
+const featureMetadataAnnotation = "dev.containers.metadata"
+
+// CheckUpdates compares the digests of the given image sources with the digests their references
+// currently resolve to and returns the sources that have a newer version. Sources that can't be
+// checked are skipped, their errors are returned together with the updates of all other sources.
+func CheckUpdates(ctx context.Context, sources []config.ImageSource) ([]*config.ImageUpdate, error) {
+	keychain, err := GetKeychain(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create authentication keychain: %w", err)
+	}
+	remoteOptions := []remote.Option{remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain)}
+
+	updates := []*config.ImageUpdate{}
+	var errs []error
+	for _, source := range sources {
+		ref, err := name.ParseReference(source.Reference)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "parse %s", source.Reference))
+			continue
+		}
+
+		descriptor, err := remote.Head(ref, remoteOptions...)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "retrieve %s", source.Reference))
+			continue
+		} else if descriptor.Digest.String() == source.Digest {
+			continue
+		}
+
+		update := &config.ImageUpdate{
+			ImageSource:  source,
+			LatestDigest: descriptor.Digest.String(),
+		}
+		if source.Type == config.ImageSourceTypeFeature {
+			update.LatestVersion = getFeatureVersion(ref, remoteOptions)
+		}
+		updates = append(updates, update)
+	}
+
+	return updates, stderrors.Join(errs...)
+}
+
+// getFeatureVersion returns the version of the published OCI feature or an empty string if unknown
+func getFeatureVersion(ref name.Reference, remoteOptions []remote.Option) string {
+	descriptor, err := remote.Get(ref, remoteOptions...)
+	if err != nil {
+		return ""
+	}
+
+	manifest, err := v1.ParseManifest(bytes.NewReader(descriptor.Manifest))
+	if err != nil || manifest.Annotations[featureMetadataAnnotation] == "" {
+		return ""
+	}
+
+	featureConfig := &config.FeatureConfig{}
+	err = json.Unmarshal([]byte(manifest.Annotations[featureMetadataAnnotation]), featureConfig)
+	if err != nil {
+		return ""
+	}
+
+	return featureConfig.Version
+}
@@ -0,0 +1,61 @@
+package image
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"dev.khulnasoft.com/pkg/devcontainer/config"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+func TestCheckUpdates(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	push := func(reference string) string {
+		t.Helper()
+
+		img, err := random.Image(64, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ref, err := name.ParseReference(reference)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = remote.Write(ref, img)
+		if err != nil {
+			t.Fatal(err)
+		}
+		digest, err := img.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return digest.String()
+	}
+
+	current := host + "/base:current"
+	updated := host + "/base:updated"
+	currentDigest := push(current)
+	oldDigest := push(updated)
+	latestDigest := push(updated)
+
+	updates, err := CheckUpdates(context.Background(), []config.ImageSource{
+		{Type: config.ImageSourceTypeImage, Reference: current, Digest: currentDigest},
+		{Type: config.ImageSourceTypeImage, Reference: host + "/missing:latest", Digest: currentDigest},
+		{Type: config.ImageSourceTypeImage, Reference: updated, Digest: oldDigest},
+	})
+	if err == nil || !strings.Contains(err.Error(), "missing:latest") {
+		t.Fatalf("expected an error for the missing image, got %v", err)
+	}
+	if len(updates) != 1 || updates[0].Reference != updated || updates[0].LatestDigest != latestDigest {
+		t.Fatalf("expected an update for %s despite the missing image, got %+v", updated, updates)
+	}
+}
@@ -234,6 +234,7 @@ func ResolveAgentConfig(devConfig *config.Config, provider *provider2.ProviderCo
 	agentConfig.Docker.Builder = resolver.ResolveDefaultValue(agentConfig.Docker.Builder, options)
 	agentConfig.Docker.Install = types.StrBool(resolver.ResolveDefaultValue(string(agentConfig.Docker.Install), options))
 	agentConfig.Docker.Env = resolver.ResolveDefaultValues(agentConfig.Docker.Env, options)
+	agentConfig.Docker.IDECache = types.StrBool(resolver.ResolveDefaultValue(string(agentConfig.Docker.IDECache), options))
 
 	// kubernetes driver
 	agentConfig.Kubernetes.KubernetesContext = resolver.ResolveDefaultValue(agentConfig.Kubernetes.KubernetesContext, options)
@@ -244,6 +245,7 @@ func ResolveAgentConfig(devConfig *config.Config, provider *provider2.ProviderCo
 	agentConfig.Kubernetes.StorageClass = resolver.ResolveDefaultValue(agentConfig.Kubernetes.StorageClass, options)
 	agentConfig.Kubernetes.PvcAccessMode = resolver.ResolveDefaultValue(agentConfig.Kubernetes.PvcAccessMode, options)
 	agentConfig.Kubernetes.PvcAnnotations = resolver.ResolveDefaultValue(agentConfig.Kubernetes.PvcAnnotations, options)
+	agentConfig.Kubernetes.PvcDataSource = resolver.ResolveDefaultValue(agentConfig.Kubernetes.PvcDataSource, options)
 	agentConfig.Kubernetes.NodeSelector = resolver.ResolveDefaultValue(agentConfig.Kubernetes.NodeSelector, options)
 	agentConfig.Kubernetes.Resources = resolver.ResolveDefaultValue(agentConfig.Kubernetes.Resources, options)
 	agentConfig.Kubernetes.WorkspaceVolumeMount = resolver.ResolveDefaultValue(agentConfig.Kubernetes.WorkspaceVolumeMount, options)
@@ -256,6 +258,10 @@ func ResolveAgentConfig(devConfig *config.Config, provider *provider2.ProviderCo
 	agentConfig.Kubernetes.PodTimeout = resolver.ResolveDefaultValue(agentConfig.Kubernetes.PodTimeout, options)
 	agentConfig.Kubernetes.KubernetesPullSecretsEnabled = resolver.ResolveDefaultValue(agentConfig.Kubernetes.KubernetesPullSecretsEnabled, options)
 	agentConfig.Kubernetes.DiskSize = resolver.ResolveDefaultValue(agentConfig.Kubernetes.DiskSize, options)
+	agentConfig.Kubernetes.BuildkitImage = resolver.ResolveDefaultValue(agentConfig.Kubernetes.BuildkitImage, options)
+	agentConfig.Kubernetes.DebugImage = resolver.ResolveDefaultValue(agentConfig.Kubernetes.DebugImage, options)
+	agentConfig.Kubernetes.IDECachePvc = resolver.ResolveDefaultValue(agentConfig.Kubernetes.IDECachePvc, options)
+	agentConfig.Kubernetes.IDECacheHostPath = resolver.ResolveDefaultValue(agentConfig.Kubernetes.IDECacheHostPath, options)
 
 	agentConfig.DataPath = resolver.ResolveDefaultValue(agentConfig.DataPath, options)
 	agentConfig.Path = resolver.ResolveDefaultValue(agentConfig.Path, options)
@@ -0,0 +1,306 @@
+package catalog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/config"
+	devspacehttp "dev.khulnasoft.com/pkg/http"
+	"github.com/blang/semver"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// CacheDuration is how long a downloaded catalog is used before it is refreshed
+var CacheDuration = time.Hour
+
+// Catalog is an index of providers that can be installed via DevSpace
+type Catalog struct {
+	// Providers are the providers listed in this catalog
+	Providers []*Provider `json:"providers,omitempty"`
+
+	// Origin is the location the catalog was loaded from
+	Origin string `json:"-"`
+}
+
+type Provider struct {
+	// Name is the name of the provider
+	Name string `json:"name,omitempty"`
+
+	// Description is the provider description
+	Description string `json:"description,omitempty"`
+
+	// Icon holds an image URL that will be displayed
+	Icon string `json:"icon,omitempty"`
+
+	// Home holds the provider home URL
+	Home string `json:"home,omitempty"`
+
+	// Versions are the installable versions of this provider
+	Versions []*Version `json:"versions,omitempty"`
+
+	// Options is a summary of the options the provider exposes
+	Options []*Option `json:"options,omitempty"`
+
+	// Catalog is the location of the catalog this provider was found in
+	Catalog string `json:"catalog,omitempty"`
+}
+
+type Version struct {
+	// Version is the provider version, e.g. v0.1.0
+	Version string `json:"version,omitempty"`
+
+	// Source is where to find the provider.yaml of this version. Can be
+	// a url, a local path or a github repository
+	Source string `json:"source,omitempty"`
+}
+
+type Option struct {
+	// Name is the option name
+	Name string `json:"name,omitempty"`
+
+	// Description is the option description
+	Description string `json:"description,omitempty"`
+
+	// Required signals if the option needs to be set
+	Required bool `json:"required,omitempty"`
+
+	// Default is the default value of the option
+	Default string `json:"default,omitempty"`
+}
+
+// Latest returns the newest version of the provider
+func (p *Provider) Latest() *Version {
+	if len(p.Versions) == 0 {
+		return nil
+	}
+
+	return p.sortedVersions()[0]
+}
+
+// Version returns the given version of the provider or the latest one if version is empty
+func (p *Provider) Version(version string) (*Version, error) {
+	if version == "" || version == "latest" {
+		latest := p.Latest()
+		if latest == nil {
+			return nil, fmt.Errorf("provider %s has no versions", p.Name)
+		}
+
+		return latest, nil
+	}
+
+	for _, v := range p.Versions {
+		if strings.TrimPrefix(v.Version, "v") == strings.TrimPrefix(version, "v") {
+			return v, nil
+		}
+	}
+
+	available := []string{}
+	for _, v := range p.sortedVersions() {
+		available = append(available, v.Version)
+	}
+	return nil, fmt.Errorf("version %s of provider %s not found, available versions: %s", version, p.Name, strings.Join(available, ", "))
+}
+
+// sortedVersions returns the versions sorted from newest to oldest
+func (p *Provider) sortedVersions() []*Version {
+	versions := append([]*Version{}, p.Versions...)
+	sort.SliceStable(versions, func(i, j int) bool {
+		vi, errI := semver.Parse(strings.TrimPrefix(versions[i].Version, "v"))
+		vj, errJ := semver.Parse(strings.TrimPrefix(versions[j].Version, "v"))
+		if errI != nil || errJ != nil {
+			// unparsable versions come last
+			return errI == nil
+		}
+
+		return vi.GT(vj)
+	})
+
+	return versions
+}
+
+// Matches returns true if the query is part of the name or description
+func (p *Provider) Matches(query string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	return query == "" || strings.Contains(strings.ToLower(p.Name), query) || strings.Contains(strings.ToLower(p.Description), query)
+}
+
+// Locations returns the configured catalog locations of the current context
+func Locations(devSpaceConfig *config.Config) []string {
+	retLocations := []string{}
+	for _, location := range strings.Split(devSpaceConfig.ContextOption(config.ContextOptionProviderCatalogs), ",") {
+		location = strings.TrimSpace(location)
+		if location != "" {
+			retLocations = append(retLocations, location)
+		}
+	}
+
+	return retLocations
+}
+
+// LoadAll loads all catalogs configured in the current context. Catalogs that can't be loaded are skipped with a
+// warning, an error is only returned if none of them could be loaded.
+func LoadAll(devSpaceConfig *config.Config, refresh bool, log log.Logger) ([]*Catalog, error) {
+	retCatalogs := []*Catalog{}
+	var lastErr error
+	for _, location := range Locations(devSpaceConfig) {
+		catalog, err := Load(location, refresh, log)
+		if err != nil {
+			log.Warnf("Skipping provider catalog %s: %v", location, err)
+			lastErr = err
+			continue
+		}
+
+		retCatalogs = append(retCatalogs, catalog)
+	}
+	if len(retCatalogs) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+
+	return retCatalogs, nil
+}
+
+// Find searches the configured catalogs for a provider with the given name. The first
+// catalog that contains the provider wins. Returns nil if the provider wasn't found.
+func Find(devSpaceConfig *config.Config, name string, log log.Logger) (*Provider, error) {
+	catalogs, err := LoadAll(devSpaceConfig, false, log)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, catalog := range catalogs {
+		for _, provider := range catalog.Providers {
+			if provider.Name == name {
+				return provider, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// Load loads a catalog from a url or local path. Remote catalogs are cached
+// within the DevSpace config dir and refreshed after CacheDuration.
+func Load(location string, refresh bool, log log.Logger) (*Catalog, error) {
+	if !isURL(location) {
+		out, err := os.ReadFile(location)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read catalog %s", location)
+		}
+
+		return parse(out, location)
+	}
+
+	cachePath, err := cacheFile(location)
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := os.Stat(cachePath)
+	if err == nil && !refresh && time.Since(stat.ModTime()) < CacheDuration {
+		out, err := os.ReadFile(cachePath)
+		if err == nil {
+			return parse(out, location)
+		}
+	}
+
+	log.Debugf("Download provider catalog %s...", location)
+	out, downloadErr := download(location)
+	if downloadErr != nil {
+		// fall back to the cached version if we have one
+		cached, err := os.ReadFile(cachePath)
+		if err != nil {
+			return nil, errors.Wrapf(downloadErr, "download catalog %s", location)
+		}
+
+		log.Warnf("Error downloading catalog %s, using cached version: %v", location, downloadErr)
+		return parse(cached, location)
+	}
+
+	catalog, err := parse(out, location)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(filepath.Dir(cachePath), 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.WriteFile(cachePath, out, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "cache catalog")
+	}
+
+	return catalog, nil
+}
+
+func parse(out []byte, location string) (*Catalog, error) {
+	catalog := &Catalog{}
+	err := yaml.Unmarshal(out, catalog)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse catalog %s", location)
+	}
+
+	catalog.Origin = location
+	for _, provider := range catalog.Providers {
+		if provider.Name == "" {
+			return nil, fmt.Errorf("catalog %s contains a provider without a name", location)
+		}
+
+		provider.Catalog = location
+		for _, version := range provider.Versions {
+			if version.Source == "" {
+				return nil, fmt.Errorf("version %s of provider %s in catalog %s is missing a source", version.Version, provider.Name, location)
+			}
+
+			// resolve local sources relative to the catalog
+			if !isURL(location) && !isURL(version.Source) && !filepath.IsAbs(version.Source) && isFile(version.Source) {
+				version.Source = filepath.Join(filepath.Dir(location), version.Source)
+			}
+		}
+	}
+
+	return catalog, nil
+}
+
+func download(location string) ([]byte, error) {
+	resp, err := devspacehttp.GetHTTPClient().Get(location)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+func cacheFile(location string) (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256([]byte(location))
+	return filepath.Join(configDir, "catalogs", hex.EncodeToString(hash[:])[:16]+".yaml"), nil
+}
+
+func isURL(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+func isFile(source string) bool {
+	return strings.HasSuffix(source, ".yaml") || strings.HasSuffix(source, ".yml")
+}
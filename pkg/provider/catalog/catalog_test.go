@@ -0,0 +1,307 @@
+package catalog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/config"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name     string
+		catalog  string
+		location string
+
+		expected    *Catalog
+		expectedErr bool
+	}{
+		{
+			name:     "relative sources",
+			location: "/catalogs/catalog.yaml",
+			catalog: `providers:
+- name: ssh
+  versions:
+  - version: v0.1.0
+    source: ssh/provider.yaml
+  - version: v0.2.0
+    source: https://example.com/provider.yaml
+  - version: v0.3.0
+    source: khulnasoft-lab/devspace-provider-ssh`,
+			expected: &Catalog{
+				Origin: "/catalogs/catalog.yaml",
+				Providers: []*Provider{{
+					Name:    "ssh",
+					Catalog: "/catalogs/catalog.yaml",
+					Versions: []*Version{
+						{Version: "v0.1.0", Source: "/catalogs/ssh/provider.yaml"},
+						{Version: "v0.2.0", Source: "https://example.com/provider.yaml"},
+						{Version: "v0.3.0", Source: "khulnasoft-lab/devspace-provider-ssh"},
+					},
+				}},
+			},
+		},
+		{
+			name:     "remote catalog",
+			location: "https://example.com/catalog.yaml",
+			catalog: `providers:
+- name: ssh
+  versions:
+  - version: v0.1.0
+    source: ssh/provider.yaml`,
+			expected: &Catalog{
+				Origin: "https://example.com/catalog.yaml",
+				Providers: []*Provider{{
+					Name:     "ssh",
+					Catalog:  "https://example.com/catalog.yaml",
+					Versions: []*Version{{Version: "v0.1.0", Source: "ssh/provider.yaml"}},
+				}},
+			},
+		},
+		{
+			name:     "missing name",
+			location: "catalog.yaml",
+			catalog: `providers:
+- description: test`,
+			expectedErr: true,
+		},
+		{
+			name:     "missing source",
+			location: "catalog.yaml",
+			catalog: `providers:
+- name: ssh
+  versions:
+  - version: v0.1.0`,
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			catalog, err := parse([]byte(testCase.catalog), testCase.location)
+			if testCase.expectedErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(testCase.expected, catalog); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestVersion(t *testing.T) {
+	provider := &Provider{
+		Name: "ssh",
+		Versions: []*Version{
+			{Version: "v0.2.0", Source: "0.2.0"},
+			{Version: "main", Source: "main"},
+			{Version: "v0.10.0", Source: "0.10.0"},
+			{Version: "0.3.0", Source: "0.3.0"},
+		},
+	}
+
+	testCases := []struct {
+		version string
+
+		expectedSource string
+		expectedErr    bool
+	}{
+		{version: "", expectedSource: "0.10.0"},
+		{version: "latest", expectedSource: "0.10.0"},
+		{version: "0.2.0", expectedSource: "0.2.0"},
+		{version: "v0.3.0", expectedSource: "0.3.0"},
+		{version: "main", expectedSource: "main"},
+		{version: "v1.0.0", expectedErr: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.version, func(t *testing.T) {
+			version, err := provider.Version(testCase.version)
+			if testCase.expectedErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if version.Source != testCase.expectedSource {
+				t.Fatalf("expected source %s, got %s", testCase.expectedSource, version.Source)
+			}
+		})
+	}
+
+	_, err := (&Provider{Name: "empty"}).Version("")
+	if err == nil {
+		t.Fatal("expected error for provider without versions")
+	}
+}
+
+func TestLoadAll(t *testing.T) {
+	t.Setenv("DEVSPACE_HOME", t.TempDir())
+	dir := t.TempDir()
+
+	first := filepath.Join(dir, "first.yaml")
+	writeFile(t, first, `providers:
+- name: ssh
+  versions:
+  - version: v0.1.0
+    source: ssh/provider.yaml
+- name: docker
+  versions:
+  - version: v0.1.0
+    source: khulnasoft-lab/devspace-provider-docker`)
+
+	second := filepath.Join(dir, "second.yaml")
+	writeFile(t, second, `providers:
+- name: ssh
+  versions:
+  - version: v0.2.0
+    source: khulnasoft-lab/devspace-provider-ssh
+- name: kubernetes
+  versions:
+  - version: v0.1.0
+    source: khulnasoft-lab/devspace-provider-kubernetes`)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/catalog.yaml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write([]byte(`providers:
+- name: remote
+  versions:
+  - version: v0.1.0
+    source: https://example.com/provider.yaml`))
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name      string
+		locations string
+
+		expectedOrigins []string
+		expectedFound   map[string]string
+		expectedErr     bool
+	}{
+		{
+			name:            "merge local catalogs",
+			locations:       first + "," + second,
+			expectedOrigins: []string{first, second},
+			expectedFound: map[string]string{
+				"ssh":        first,
+				"docker":     first,
+				"kubernetes": second,
+				"remote":     "",
+			},
+		},
+		{
+			name:            "precedence follows order",
+			locations:       second + ", " + first,
+			expectedOrigins: []string{second, first},
+			expectedFound: map[string]string{
+				"ssh": second,
+			},
+		},
+		{
+			name:            "remote catalog",
+			locations:       server.URL + "/catalog.yaml",
+			expectedOrigins: []string{server.URL + "/catalog.yaml"},
+			expectedFound: map[string]string{
+				"remote": server.URL + "/catalog.yaml",
+			},
+		},
+		{
+			name:            "skip unreachable catalogs",
+			locations:       filepath.Join(dir, "missing.yaml") + "," + server.URL + "/missing.yaml," + first,
+			expectedOrigins: []string{first},
+			expectedFound: map[string]string{
+				"ssh": first,
+			},
+		},
+		{
+			name:        "all catalogs unreachable",
+			locations:   filepath.Join(dir, "missing.yaml") + "," + server.URL + "/missing.yaml",
+			expectedErr: true,
+		},
+		{
+			name:            "no catalogs",
+			locations:       "",
+			expectedOrigins: []string{},
+			expectedFound: map[string]string{
+				"ssh": "",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			devSpaceConfig := &config.Config{
+				DefaultContext: "default",
+				Contexts: map[string]*config.ContextConfig{
+					"default": {
+						Options: map[string]config.OptionValue{
+							config.ContextOptionProviderCatalogs: {Value: testCase.locations},
+						},
+					},
+				},
+			}
+
+			catalogs, err := LoadAll(devSpaceConfig, false, log.Discard)
+			if testCase.expectedErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			origins := []string{}
+			for _, catalog := range catalogs {
+				origins = append(origins, catalog.Origin)
+			}
+			if diff := cmp.Diff(testCase.expectedOrigins, origins); diff != "" {
+				t.Fatal(diff)
+			}
+
+			for name, expectedCatalog := range testCase.expectedFound {
+				provider, err := Find(devSpaceConfig, name, log.Discard)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				foundCatalog := ""
+				if provider != nil {
+					foundCatalog = provider.Catalog
+				}
+				if foundCatalog != expectedCatalog {
+					t.Fatalf("expected provider %s in catalog %q, got %q", name, expectedCatalog, foundCatalog)
+				}
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	err := os.WriteFile(path, []byte(content), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
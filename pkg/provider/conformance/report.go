@@ -0,0 +1,135 @@
+package conformance
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+type ResultStatus string
+
+const (
+	StatusPassed  ResultStatus = "Passed"
+	StatusFailed  ResultStatus = "Failed"
+	StatusSkipped ResultStatus = "Skipped"
+)
+
+// Result is the outcome of a single conformance step
+type Result struct {
+	// Name is the name of the step
+	Name string `json:"name,omitempty"`
+
+	// Status is the outcome of the step
+	Status ResultStatus `json:"status,omitempty"`
+
+	// Message holds the failure or skip reason
+	Message string `json:"message,omitempty"`
+
+	// Duration is how long the step took
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+// Report holds the results of a conformance run
+type Report struct {
+	// Provider is the name of the tested provider
+	Provider string `json:"provider,omitempty"`
+
+	// Results are the step results in execution order
+	Results []Result `json:"results,omitempty"`
+
+	// Duration is how long the whole run took
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+func (r *Report) count(status ResultStatus) int {
+	count := 0
+	for _, result := range r.Results {
+		if result.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Passed returns the number of passed steps
+func (r *Report) Passed() int {
+	return r.count(StatusPassed)
+}
+
+// Failed returns the number of failed steps
+func (r *Report) Failed() int {
+	return r.count(StatusFailed)
+}
+
+// Skipped returns the number of skipped steps
+func (r *Report) Skipped() int {
+	return r.count(StatusSkipped)
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report in the JUnit XML format
+func (r *Report) WriteJUnit(w io.Writer) error {
+	className := "devspace.provider." + r.Provider
+	suite := junitTestSuite{
+		Name:     className,
+		Tests:    len(r.Results),
+		Failures: r.Failed(),
+		Skipped:  r.Skipped(),
+		Time:     junitDuration(r.Duration),
+	}
+	for _, result := range r.Results {
+		testCase := junitTestCase{
+			Name:      result.Name,
+			ClassName: className,
+			Time:      junitDuration(result.Duration),
+		}
+		switch result.Status {
+		case StatusFailed:
+			testCase.Failure = &junitMessage{Message: result.Message, Body: result.Message}
+		case StatusSkipped:
+			testCase.Skipped = &junitMessage{Message: result.Message}
+		}
+
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	out, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%s%s\n", xml.Header, out)
+	return err
+}
+
+func junitDuration(duration time.Duration) string {
+	return fmt.Sprintf("%.3f", duration.Seconds())
+}
@@ -0,0 +1,53 @@
+package conformance
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	report := &Report{
+		Provider: "docker",
+		Duration: 3 * time.Second,
+		Results: []Result{
+			{Name: StepParse, Status: StatusPassed, Duration: time.Second},
+			{Name: StepCommand, Status: StatusFailed, Message: "exit status 1", Duration: 2 * time.Second},
+			{Name: StepCreate, Status: StatusSkipped, Message: "provider is not a machine provider"},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	err := report.WriteJUnit(buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parsed := junitTestSuites{}
+	err = xml.Unmarshal(buf.Bytes(), &parsed)
+	if err != nil {
+		t.Fatalf("unexpected error parsing report: %v", err)
+	}
+
+	parsed.XMLName = xml.Name{}
+	expected := junitTestSuites{
+		Suites: []junitTestSuite{{
+			Name:     "devspace.provider.docker",
+			Tests:    3,
+			Failures: 1,
+			Skipped:  1,
+			Time:     "3.000",
+			Cases: []junitTestCase{
+				{Name: StepParse, ClassName: "devspace.provider.docker", Time: "1.000"},
+				{Name: StepCommand, ClassName: "devspace.provider.docker", Time: "2.000", Failure: &junitMessage{Message: "exit status 1", Body: "exit status 1"}},
+				{Name: StepCreate, ClassName: "devspace.provider.docker", Time: "0.000", Skipped: &junitMessage{Message: "provider is not a machine provider"}},
+			},
+		}},
+	}
+	if diff := cmp.Diff(expected, parsed); diff != "" {
+		t.Errorf("unexpected report (-want +got):\n%s", diff)
+	}
+}
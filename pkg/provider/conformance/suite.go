@@ -0,0 +1,519 @@
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"dev.khulnasoft.com/log"
+	devagent "dev.khulnasoft.com/pkg/agent"
+	"dev.khulnasoft.com/pkg/client"
+	"dev.khulnasoft.com/pkg/client/clientimplementation"
+	"dev.khulnasoft.com/pkg/config"
+	"dev.khulnasoft.com/pkg/encoding"
+	"dev.khulnasoft.com/pkg/options"
+	"dev.khulnasoft.com/pkg/provider"
+	"dev.khulnasoft.com/pkg/random"
+	"dev.khulnasoft.com/pkg/types"
+	"dev.khulnasoft.com/pkg/workspace"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	StepParse          = "parse"
+	StepInstall        = "install"
+	StepOptions        = "options"
+	StepInit           = "init"
+	StepCreate         = "create"
+	StepStatusRunning  = "status-running"
+	StepCommand        = "command"
+	StepAgentInjection = "agent-injection"
+	StepStop           = "stop"
+	StepStart          = "start"
+	StepDelete         = "delete"
+)
+
+// Steps are all conformance steps in execution order
+var Steps = []string{
+	StepParse,
+	StepInstall,
+	StepOptions,
+	StepInit,
+	StepCreate,
+	StepStatusRunning,
+	StepCommand,
+	StepAgentInjection,
+	StepStop,
+	StepStart,
+	StepDelete,
+}
+
+// SetupSteps are the steps all other steps depend on, they can't be skipped
+var SetupSteps = []string{
+	StepParse,
+	StepInstall,
+}
+
+// Options configure a conformance run
+type Options struct {
+	// Source is the provider source, e.g. a local file, url or github repository
+	Source string
+
+	// Name overrides the provider name from the provider.yaml
+	Name string
+
+	// ProviderOptions are the provider options in the form KEY=VALUE
+	ProviderOptions []string
+
+	// Skip are the steps that should not be executed
+	Skip []string
+
+	// StatusTimeout is the time to wait for a machine to reach an expected status
+	StatusTimeout time.Duration
+}
+
+type suite struct {
+	devSpaceConfig *config.Config
+	options        Options
+
+	raw             []byte
+	provider        *provider.ProviderConfig
+	workspaceClient client.WorkspaceClient
+	machine         *provider.Machine
+
+	log log.Logger
+}
+
+// Run installs the provider into the given config and runs the conformance steps
+// against it. The lifecycle steps go through a workspace client for every provider
+// type, machine providers additionally create a machine for the workspace. The
+// config should point to an isolated DevSpace home as the suite creates and deletes
+// real machines and workspaces.
+func Run(ctx context.Context, devSpaceConfig *config.Config, opts Options, log log.Logger) *Report {
+	if opts.StatusTimeout == 0 {
+		opts.StatusTimeout = time.Minute * 5
+	}
+
+	s := &suite{
+		devSpaceConfig: devSpaceConfig,
+		options:        opts,
+		log:            log,
+	}
+
+	steps := map[string]func(ctx context.Context) error{
+		StepParse:          s.parse,
+		StepInstall:        s.install,
+		StepOptions:        s.resolveOptions,
+		StepInit:           s.init,
+		StepCreate:         s.create,
+		StepStatusRunning:  s.expectStatus(client.StatusRunning),
+		StepCommand:        s.command,
+		StepAgentInjection: s.agentInjection,
+		StepStop:           s.stop,
+		StepStart:          s.start,
+		StepDelete:         s.delete,
+	}
+
+	report := &Report{Provider: opts.Name}
+	start := time.Now()
+	failed := false
+	for _, step := range Steps {
+		result := Result{Name: step}
+		if reason := s.skipReason(step, failed); reason != "" {
+			result.Status = StatusSkipped
+			result.Message = reason
+		} else {
+			log.Infof("Run conformance step %s...", step)
+			stepStart := time.Now()
+			err := steps[step](ctx)
+			result.Duration = time.Since(stepStart)
+			if err != nil {
+				result.Status = StatusFailed
+				result.Message = err.Error()
+				log.Errorf("Conformance step %s failed: %v", step, err)
+
+				// a failing setup step makes every following step meaningless
+				failed = step == StepParse || step == StepInstall || step == StepOptions || step == StepInit || step == StepCreate
+			} else {
+				result.Status = StatusPassed
+				log.Donef("Conformance step %s passed", step)
+			}
+		}
+
+		report.Results = append(report.Results, result)
+	}
+
+	// make sure we don't leave a workspace or machine behind
+	if s.workspaceClient != nil && !s.skipped(StepDelete) && !s.deleted() {
+		err := s.workspaceClient.Delete(ctx, client.DeleteOptions{Force: true})
+		if err != nil {
+			log.Warnf("Error cleaning up workspace %s: %v", s.workspaceClient.Workspace(), err)
+		}
+	}
+
+	if s.provider != nil {
+		report.Provider = s.provider.Name
+	}
+	report.Duration = time.Since(start)
+	return report
+}
+
+func (s *suite) skipReason(step string, failed bool) string {
+	if s.skipped(step) {
+		return "skipped by user"
+	} else if failed {
+		return "skipped because a previous setup step failed"
+	} else if s.workspaceClient == nil && slices.Index(Steps, step) > slices.Index(Steps, StepCreate) {
+		return "no workspace was created"
+	} else if step == StepAgentInjection && options.ResolveAgentConfig(s.devSpaceConfig, s.provider, s.workspaceConfig(), s.machineConfig()).Local == "true" {
+		return "provider runs the agent locally"
+	}
+
+	return ""
+}
+
+func (s *suite) skipped(step string) bool {
+	return !slices.Contains(SetupSteps, step) && slices.Contains(s.options.Skip, step)
+}
+
+func (s *suite) deleted() bool {
+	return s.workspaceClient == nil || !provider.WorkspaceExists(s.devSpaceConfig.DefaultContext, s.workspaceClient.Workspace())
+}
+
+func (s *suite) parse(ctx context.Context) error {
+	raw, source, err := workspace.ResolveProvider(s.devSpaceConfig, s.options.Source, s.log)
+	if err != nil {
+		return errors.Wrap(err, "resolve provider")
+	}
+
+	providerConfig, err := provider.ParseProvider(bytes.NewReader(raw))
+	if err != nil {
+		return err
+	} else if providerConfig.IsProxyProvider() || providerConfig.IsDaemonProvider() {
+		return fmt.Errorf("provider %s is a pro provider, conformance tests only support machine and non-machine providers", providerConfig.Name)
+	}
+
+	providerConfig.Source = *source
+	if s.options.Name != "" {
+		providerConfig.Name = s.options.Name
+	}
+
+	s.raw = raw
+	s.provider = providerConfig
+	return nil
+}
+
+func (s *suite) install(ctx context.Context) error {
+	providerConfig, err := workspace.AddProviderRaw(s.devSpaceConfig, s.provider.Name, &s.provider.Source, s.raw, s.log)
+	if err != nil {
+		return err
+	}
+
+	s.provider = providerConfig
+	return nil
+}
+
+func (s *suite) resolveOptions(ctx context.Context) error {
+	userOptions, err := provider.ParseOptions(s.options.ProviderOptions)
+	if err != nil {
+		return errors.Wrap(err, "parse options")
+	}
+
+	devSpaceConfig, err := options.ResolveOptions(ctx, s.devSpaceConfig, s.provider, userOptions, false, false, nil, s.log)
+	if err != nil {
+		return err
+	}
+
+	// verify resolved values, including the ones of dynamic sub options
+	resolved := devSpaceConfig.ProviderOptions(s.provider.Name)
+	allOptions := config.OptionDefinitions{}
+	for optionName, option := range s.provider.Options {
+		allOptions[optionName] = option
+	}
+	for optionName, option := range devSpaceConfig.DynamicProviderOptionDefinitions(s.provider.Name) {
+		allOptions[optionName] = option
+	}
+	for optionName, option := range allOptions {
+		if option.Local {
+			continue
+		}
+
+		value, ok := resolved[optionName]
+		if !ok || value.Value == "" {
+			if option.Required {
+				return fmt.Errorf("required option %s was not resolved", optionName)
+			}
+
+			continue
+		}
+
+		if len(option.Enum) > 0 {
+			found := false
+			for _, enumValue := range option.Enum {
+				if enumValue.Value == value.Value {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("option %s resolved to %q which is not one of its allowed values", optionName, value.Value)
+			}
+		}
+
+		if option.ValidationPattern != "" {
+			matcher, err := regexp.Compile(option.ValidationPattern)
+			if err != nil {
+				return fmt.Errorf("invalid validation pattern for option %s: %w", optionName, err)
+			} else if !matcher.MatchString(value.Value) {
+				return fmt.Errorf("option %s resolved to %q which doesn't match its validation pattern %s", optionName, value.Value, option.ValidationPattern)
+			}
+		}
+	}
+
+	devSpaceConfig.Current().DefaultProvider = s.provider.Name
+	err = config.SaveConfig(devSpaceConfig)
+	if err != nil {
+		return errors.Wrap(err, "save config")
+	}
+
+	s.devSpaceConfig = devSpaceConfig
+	return nil
+}
+
+func (s *suite) init(ctx context.Context) error {
+	writer := s.log.Writer(logrus.InfoLevel, false)
+	defer writer.Close()
+
+	err := clientimplementation.RunCommandWithBinaries(
+		ctx,
+		"init",
+		s.provider.Exec.Init,
+		s.devSpaceConfig.DefaultContext,
+		nil,
+		nil,
+		s.devSpaceConfig.ProviderOptions(s.provider.Name),
+		s.provider,
+		nil,
+		nil,
+		writer,
+		writer,
+		s.log,
+	)
+	if err != nil {
+		return err
+	}
+
+	s.devSpaceConfig.Current().Providers[s.provider.Name].Initialized = true
+	return config.SaveConfig(s.devSpaceConfig)
+}
+
+// create creates the workspace the way devspace up does before building the container. Machine providers get a
+// machine of their own that is deleted together with the workspace.
+func (s *suite) create(ctx context.Context) error {
+	workspaceID := "conformance-" + random.String(6)
+	workspaceConfig := &provider.Workspace{
+		ID:      workspaceID,
+		UID:     encoding.CreateNewUID(s.devSpaceConfig.DefaultContext, workspaceID),
+		Context: s.devSpaceConfig.DefaultContext,
+		Provider: provider.WorkspaceProviderConfig{
+			Name: s.provider.Name,
+		},
+		CreationTimestamp: types.Now(),
+		LastUsedTimestamp: types.Now(),
+	}
+
+	var machineConfig *provider.Machine
+	if s.provider.IsMachineProvider() {
+		machineClient, err := workspace.ResolveMachine(s.devSpaceConfig, []string{workspaceID}, nil, s.log)
+		if err != nil {
+			return err
+		}
+
+		machineConfig = machineClient.(client.MachineClient).MachineConfig()
+		workspaceConfig.Machine = provider.WorkspaceMachineConfig{
+			ID:         machineConfig.ID,
+			AutoDelete: true,
+		}
+	}
+
+	err := provider.SaveWorkspaceConfig(workspaceConfig)
+	if err != nil {
+		return errors.Wrap(err, "save workspace config")
+	}
+
+	workspaceClient, err := clientimplementation.NewWorkspaceClient(s.devSpaceConfig, s.provider, workspaceConfig, machineConfig, s.log)
+	if err != nil {
+		return err
+	}
+
+	s.workspaceClient = workspaceClient
+	s.machine = machineConfig
+	return s.workspaceClient.Create(ctx, client.CreateOptions{})
+}
+
+// stop stops the workspace. Without a container, the status of a workspace of a non-machine provider can't tell if
+// it's stopped, so only the status of machines is verified.
+func (s *suite) stop(ctx context.Context) error {
+	err := s.workspaceClient.Stop(ctx, client.StopOptions{})
+	if err != nil {
+		return err
+	} else if !s.provider.IsMachineProvider() {
+		return nil
+	}
+
+	return s.waitForStatus(ctx, client.StatusStopped)
+}
+
+func (s *suite) start(ctx context.Context) error {
+	err := s.workspaceClient.Start(ctx, client.StartOptions{})
+	if err != nil {
+		return err
+	}
+
+	return s.waitForStatus(ctx, client.StatusRunning)
+}
+
+func (s *suite) delete(ctx context.Context) error {
+	err := s.workspaceClient.Delete(ctx, client.DeleteOptions{})
+	if err != nil {
+		return err
+	}
+
+	// status has to be queried with a fresh client as the workspace and machine folders are gone
+	workspaceClient, err := clientimplementation.NewWorkspaceClient(s.devSpaceConfig, s.provider, s.workspaceConfig(), s.machine, s.log)
+	if err != nil {
+		return err
+	}
+
+	status, err := workspaceClient.Status(ctx, client.StatusOptions{})
+	if err != nil {
+		// an error is fine here as long as the provider reports the workspace as gone
+		s.log.Debugf("Error retrieving status of deleted workspace: %v", err)
+	}
+	if status != client.StatusNotFound {
+		return fmt.Errorf("expected status %s after delete, got %s", client.StatusNotFound, status)
+	}
+
+	return nil
+}
+
+func (s *suite) expectStatus(expected client.Status) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return s.waitForStatus(ctx, expected)
+	}
+}
+
+func (s *suite) waitForStatus(ctx context.Context, expected client.Status) error {
+	var (
+		status client.Status
+		err    error
+	)
+	deadline := time.Now().Add(s.options.StatusTimeout)
+	for time.Now().Before(deadline) {
+		status, err = s.workspaceClient.Status(ctx, client.StatusOptions{})
+		if err == nil && status == expected {
+			return nil
+		} else if err == nil && status != client.StatusBusy {
+			return fmt.Errorf("expected status %s, got %s", expected, status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second * 2):
+		}
+	}
+	if err != nil {
+		return errors.Wrapf(err, "wait for status %s", expected)
+	}
+
+	return fmt.Errorf("timed out waiting for status %s, last status was %s", expected, status)
+}
+
+func (s *suite) command(ctx context.Context) error {
+	token := "devspace-conformance-" + random.String(8)
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	err := s.exec(ctx, "echo "+token, nil, stdout, stderr)
+	if err != nil {
+		return fmt.Errorf("run command: %w: %s", err, strings.TrimSpace(stderr.String()))
+	} else if !strings.Contains(stdout.String(), token) {
+		return fmt.Errorf("expected command output to contain %s, got %q", token, stdout.String())
+	}
+
+	return nil
+}
+
+func (s *suite) agentInjection(ctx context.Context) error {
+	agentConfig := options.ResolveAgentConfig(s.devSpaceConfig, s.provider, s.workspaceConfig(), s.machineConfig())
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	err := devagent.InjectAgentAndExecute(
+		ctx,
+		s.exec,
+		false,
+		agentConfig.Path,
+		agentConfig.DownloadURL,
+		true,
+		fmt.Sprintf("'%s' version", agentConfig.Path),
+		nil,
+		stdout,
+		stderr,
+		s.log,
+		config.ParseTimeOption(s.devSpaceConfig, config.ContextOptionAgentInjectTimeout),
+	)
+	if err != nil {
+		return fmt.Errorf("inject agent: %w: %s", err, strings.TrimSpace(stderr.String()))
+	} else if strings.TrimSpace(stdout.String()) == "" {
+		return fmt.Errorf("injected agent didn't print a version")
+	}
+
+	return nil
+}
+
+func (s *suite) exec(ctx context.Context, command string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	if s.workspaceClient != nil {
+		return s.workspaceClient.Command(ctx, client.CommandOptions{
+			Command: command,
+			Stdin:   stdin,
+			Stdout:  stdout,
+			Stderr:  stderr,
+		})
+	}
+
+	return clientimplementation.RunCommandWithBinaries(
+		ctx,
+		"command",
+		s.provider.Exec.Command,
+		s.devSpaceConfig.DefaultContext,
+		nil,
+		nil,
+		s.devSpaceConfig.ProviderOptions(s.provider.Name),
+		s.provider,
+		provider.Merge(provider.GetBaseEnvironment(s.devSpaceConfig.DefaultContext, s.provider.Name), map[string]string{
+			provider.CommandEnv: command,
+		}),
+		stdin,
+		stdout,
+		stderr,
+		s.log.ErrorStreamOnly(),
+	)
+}
+
+func (s *suite) workspaceConfig() *provider.Workspace {
+	if s.workspaceClient == nil {
+		return nil
+	}
+
+	return s.workspaceClient.WorkspaceConfig()
+}
+
+func (s *suite) machineConfig() *provider.Machine {
+	return s.machine
+}
@@ -0,0 +1,185 @@
+package conformance
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/config"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake providers are shell scripts")
+	}
+
+	// the fake provider runs commands and the agent locally, the machine is a state file
+	const provider = `name: fake
+version: v0.0.1
+agent:
+  local: "true"
+  path: AGENT
+exec:
+  command: sh -c "${COMMAND}"
+`
+	const machineExec = `  create: sh -c "echo Running > STATE"
+  status: sh -c "cat STATE 2>/dev/null || echo NotFound"
+  stop: sh -c "echo Stopped > STATE"
+  start: sh -c "echo Running > STATE"
+  delete: sh -c "rm -f STATE"
+`
+
+	testCases := []struct {
+		name     string
+		provider string
+		skip     []string
+
+		expected map[string]ResultStatus
+	}{
+		{
+			name:     "non-machine provider",
+			provider: provider,
+			expected: map[string]ResultStatus{
+				StepCreate:         StatusPassed,
+				StepStatusRunning:  StatusPassed,
+				StepCommand:        StatusPassed,
+				StepAgentInjection: StatusSkipped,
+				StepStop:           StatusPassed,
+				StepStart:          StatusPassed,
+				StepDelete:         StatusPassed,
+			},
+		},
+		{
+			name:     "machine provider",
+			provider: provider + machineExec,
+			expected: map[string]ResultStatus{
+				StepCreate:         StatusPassed,
+				StepStatusRunning:  StatusPassed,
+				StepCommand:        StatusPassed,
+				StepAgentInjection: StatusSkipped,
+				StepStop:           StatusPassed,
+				StepStart:          StatusPassed,
+				StepDelete:         StatusPassed,
+			},
+		},
+		{
+			name:     "skipped by user",
+			provider: provider + machineExec,
+			skip:     []string{StepStop, StepStart},
+			expected: map[string]ResultStatus{
+				StepCreate:         StatusPassed,
+				StepStatusRunning:  StatusPassed,
+				StepCommand:        StatusPassed,
+				StepAgentInjection: StatusSkipped,
+				StepStop:           StatusSkipped,
+				StepStart:          StatusSkipped,
+				StepDelete:         StatusPassed,
+			},
+		},
+		{
+			name:     "failing command",
+			provider: strings.Replace(provider, `sh -c "${COMMAND}"`, `sh -c "exit 1"`, 1),
+			expected: map[string]ResultStatus{
+				StepCreate:         StatusPassed,
+				StepStatusRunning:  StatusPassed,
+				StepCommand:        StatusFailed,
+				StepAgentInjection: StatusSkipped,
+				StepStop:           StatusFailed,
+				StepStart:          StatusPassed,
+				StepDelete:         StatusFailed,
+			},
+		},
+		{
+			name:     "machine stays running",
+			provider: provider + strings.Replace(machineExec, `echo Stopped > STATE`, `true`, 1),
+			expected: map[string]ResultStatus{
+				StepCreate:         StatusPassed,
+				StepStatusRunning:  StatusPassed,
+				StepCommand:        StatusPassed,
+				StepAgentInjection: StatusSkipped,
+				StepStop:           StatusFailed,
+				StepStart:          StatusPassed,
+				StepDelete:         StatusPassed,
+			},
+		},
+		{
+			name:     "failing create",
+			provider: provider + strings.Replace(machineExec, `echo Running > STATE`, `exit 1`, 1),
+			expected: map[string]ResultStatus{
+				StepCreate:         StatusFailed,
+				StepStatusRunning:  StatusSkipped,
+				StepCommand:        StatusSkipped,
+				StepAgentInjection: StatusSkipped,
+				StepStop:           StatusSkipped,
+				StepStart:          StatusSkipped,
+				StepDelete:         StatusSkipped,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			devSpaceHome := t.TempDir()
+			t.Setenv(config.DEVSPACE_HOME, devSpaceHome)
+			t.Setenv(config.DEVSPACE_CONFIG, filepath.Join(devSpaceHome, config.ConfigFile))
+
+			dir := t.TempDir()
+			agentPath := filepath.Join(dir, "agent")
+			err := os.WriteFile(agentPath, []byte("#!/bin/sh\nexit 0\n"), 0o755)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			providerPath := filepath.Join(dir, "provider.yaml")
+			providerYAML := strings.ReplaceAll(testCase.provider, "AGENT", agentPath)
+			providerYAML = strings.ReplaceAll(providerYAML, "STATE", filepath.Join(dir, "state"))
+			err = os.WriteFile(providerPath, []byte(providerYAML), 0o600)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			devSpaceConfig, err := config.LoadConfig("", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			report := Run(context.Background(), devSpaceConfig, Options{
+				Source:        providerPath,
+				Skip:          testCase.skip,
+				StatusTimeout: 10 * time.Second,
+			}, log.Discard)
+
+			expected := map[string]ResultStatus{
+				StepParse:   StatusPassed,
+				StepInstall: StatusPassed,
+				StepOptions: StatusPassed,
+				StepInit:    StatusPassed,
+			}
+			for step, status := range testCase.expected {
+				expected[step] = status
+			}
+
+			got := map[string]ResultStatus{}
+			for _, result := range report.Results {
+				got[result.Name] = result.Status
+			}
+			if diff := cmp.Diff(expected, got); diff != "" {
+				t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+				for _, result := range report.Results {
+					t.Logf("%s: %s %s", result.Name, result.Status, result.Message)
+				}
+			}
+
+			_, err = os.Stat(filepath.Join(dir, "state"))
+			if !os.IsNotExist(err) {
+				t.Errorf("expected the machine to be deleted")
+			}
+		})
+	}
+}
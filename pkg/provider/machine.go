@@ -12,6 +12,9 @@ type Machine struct {
 	// Provider is the provider used to create this workspace
 	Provider MachineProviderConfig `json:"provider,omitempty"`
 
+	// Pool is the machine pool this machine belongs to
+	Pool string `json:"pool,omitempty"`
+
 	// CreationTimestamp is the timestamp when this workspace was created
 	CreationTimestamp types.Time `json:"creationTimestamp,omitempty"`
 
@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"dev.khulnasoft.com/pkg/config"
+	"dev.khulnasoft.com/pkg/types"
+)
+
+const (
+	MachinePoolConfigFile = "pool.json"
+
+	MachinePoolStateRunning = "running"
+	MachinePoolStateStopped = "stopped"
+)
+
+type MachinePool struct {
+	// Name is the name of the pool
+	Name string `json:"name,omitempty"`
+
+	// Provider is the provider used to create the machines of this pool
+	Provider MachineProviderConfig `json:"provider,omitempty"`
+
+	// ProviderOptions are the user options the pool machines are created with
+	ProviderOptions []string `json:"providerOptions,omitempty"`
+
+	// Size is the amount of unused machines the pool keeps ready
+	Size int `json:"size,omitempty"`
+
+	// State is the state idle machines are kept in, either running or stopped
+	State string `json:"state,omitempty"`
+
+	// Capacity are the resources a single pool machine offers to workspaces
+	Capacity MachineResources `json:"capacity,omitempty"`
+
+	// MaxWorkspaces is the maximum amount of workspaces that share a machine
+	MaxWorkspaces int `json:"maxWorkspaces,omitempty"`
+
+	// CreationTimestamp is the timestamp when this pool was created
+	CreationTimestamp types.Time `json:"creationTimestamp,omitempty"`
+
+	// Context is the context where this config file was loaded from
+	Context string `json:"context,omitempty"`
+
+	// Origin is the place where this config file was loaded from
+	Origin string `json:"-"`
+}
+
+type MachineResources struct {
+	// CPUs is the number of cpus
+	CPUs int `json:"cpus,omitempty"`
+
+	// Memory is the amount of memory in bytes
+	Memory int64 `json:"memory,omitempty"`
+}
+
+func GetMachinePoolsDir(context string) (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "contexts", context, "pools"), nil
+}
+
+func GetMachinePoolDir(context, poolName string) (string, error) {
+	if poolName == "" {
+		return "", fmt.Errorf("pool name is empty")
+	}
+
+	poolsDir, err := GetMachinePoolsDir(context)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(poolsDir, poolName), nil
+}
+
+func MachinePoolExists(context, poolName string) bool {
+	poolDir, err := GetMachinePoolDir(context, poolName)
+	if err != nil {
+		return false
+	}
+
+	_, err = os.Stat(poolDir)
+	return err == nil
+}
+
+func SaveMachinePoolConfig(pool *MachinePool) error {
+	poolDir, err := GetMachinePoolDir(pool.Context, pool.Name)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(poolDir, 0755)
+	if err != nil {
+		return err
+	}
+
+	poolConfigBytes, err := json.Marshal(pool)
+	if err != nil {
+		return err
+	}
+
+	poolConfigFile := filepath.Join(poolDir, MachinePoolConfigFile)
+	err = os.WriteFile(poolConfigFile, poolConfigBytes, 0600)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func LoadMachinePoolConfig(context, poolName string) (*MachinePool, error) {
+	poolDir, err := GetMachinePoolDir(context, poolName)
+	if err != nil {
+		return nil, err
+	}
+
+	poolConfigFile := filepath.Join(poolDir, MachinePoolConfigFile)
+	poolConfigBytes, err := os.ReadFile(poolConfigFile)
+	if err != nil {
+		return nil, err
+	}
+
+	poolConfig := &MachinePool{}
+	err = json.Unmarshal(poolConfigBytes, poolConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	poolConfig.Context = context
+	poolConfig.Origin = poolConfigFile
+	return poolConfig, nil
+}
+
+// Fits returns true if the requested resources fit into the capacity next to the already
+// used ones. Zero capacity values are treated as unlimited.
+func (r MachineResources) Fits(used, requested MachineResources) bool {
+	if r.CPUs > 0 && used.CPUs+requested.CPUs > r.CPUs {
+		return false
+	} else if r.Memory > 0 && used.Memory+requested.Memory > r.Memory {
+		return false
+	}
+
+	return true
+}
+
+// Sub subtracts the given resources
+func (r MachineResources) Sub(other MachineResources) MachineResources {
+	return MachineResources{
+		CPUs:   r.CPUs - other.CPUs,
+		Memory: r.Memory - other.Memory,
+	}
+}
@@ -70,6 +70,9 @@ type ProviderSource struct {
 	// URL where the provider was downloaded from
 	URL string `json:"url,omitempty"`
 
+	// Catalog is the catalog reference in the form name[@version] the provider was installed from
+	Catalog string `json:"catalog,omitempty"`
+
 	// Raw is the exact string we used to load the provider
 	Raw string `json:"raw,omitempty"`
 }
@@ -194,6 +197,14 @@ type ProviderDockerDriverConfig struct {
 
 	// Environment variables to set when running docker commands
 	Env map[string]string `json:"env,omitempty"`
+
+	// If true, DevSpace fills the IDE cache shared by all workspaces on the machine and mounts it read-only
+	IDECache types.StrBool `json:"ideCache,omitempty"`
+}
+
+// IDECacheEnabled returns if the shared IDE cache should be mounted, it is disabled unless explicitly enabled
+func (c ProviderDockerDriverConfig) IDECacheEnabled() bool {
+	return c.IDECache == "true"
 }
 
 type ProviderKubernetesDriverConfig struct {
@@ -214,6 +225,7 @@ type ProviderKubernetesDriverConfig struct {
 	DiskSize             string `json:"diskSize,omitempty"`
 	PvcAccessMode        string `json:"pvcAccessMode,omitempty"`
 	PvcAnnotations       string `json:"pvcAnnotations,omitempty"`
+	PvcDataSource        string `json:"pvcDataSource,omitempty"`
 	NodeSelector         string `json:"nodeSelector,omitempty"`
 	Resources            string `json:"resources,omitempty"`
 	WorkspaceVolumeMount string `json:"workspaceVolumeMount,omitempty"`
@@ -222,6 +234,12 @@ type ProviderKubernetesDriverConfig struct {
 	Labels              string `json:"labels,omitempty"`
 
 	StrictSecurity string `json:"strictSecurity,omitempty"`
+
+	BuildkitImage string `json:"buildkitImage,omitempty"`
+	DebugImage    string `json:"debugImage,omitempty"`
+
+	IDECachePvc      string `json:"ideCachePvc,omitempty"`
+	IDECacheHostPath string `json:"ideCacheHostPath,omitempty"`
 }
 
 type ProviderAgentConfigExec struct {
@@ -8,15 +8,17 @@ import (
 	"dev.khulnasoft.com/pkg/config"
 	devcontainerconfig "dev.khulnasoft.com/pkg/devcontainer/config"
 	"dev.khulnasoft.com/pkg/git"
+	"dev.khulnasoft.com/pkg/ide/custom"
 	"dev.khulnasoft.com/pkg/types"
 )
 
 var (
-	WorkspaceSourceGit       = "git:"
-	WorkspaceSourceLocal     = "local:"
-	WorkspaceSourceImage     = "image:"
-	WorkspaceSourceContainer = "container:"
-	WorkspaceSourceUnknown   = "unknown:"
+	WorkspaceSourceGit        = "git:"
+	WorkspaceSourceLocal      = "local:"
+	WorkspaceSourceImage      = "image:"
+	WorkspaceSourceContainer  = "container:"
+	WorkspaceSourceKubernetes = "k8s:"
+	WorkspaceSourceUnknown    = "unknown:"
 )
 
 type Workspace struct {
@@ -70,6 +72,16 @@ type Workspace struct {
 
 	// Path to the file where the SSH config to access the workspace is stored
 	SSHConfigPath string `json:"sshConfigPath,omitempty"`
+
+	// GPGAgentForwarding is true if the workspace was started with gpg agent forwarding. The wildcard ssh config
+	// entry is shared by all workspaces, so devspace ssh reads it from here.
+	GPGAgentForwarding bool `json:"gpgAgentForwarding,omitempty"`
+
+	// PrebuildHash is the prebuild hash of the image the workspace container was created from
+	PrebuildHash string `json:"prebuildHash,omitempty"`
+
+	// ImageSources are the base image, prebuild and features the workspace container was built from
+	ImageSources []devcontainerconfig.ImageSource `json:"imageSources,omitempty"`
 }
 
 type ProMetadata struct {
@@ -89,6 +101,9 @@ type WorkspaceIDEConfig struct {
 
 	// Options are the local options that override the global ones
 	Options map[string]config.OptionValue `json:"options,omitempty"`
+
+	// Custom is the manifest of an IDE added with devspace ide add
+	Custom *custom.IDEConfig `json:"custom,omitempty"`
 }
 
 type WorkspaceMachineConfig struct {
@@ -98,6 +113,10 @@ type WorkspaceMachineConfig struct {
 	// AutoDelete specifies if the machine should get destroyed when
 	// the workspace is destroyed
 	AutoDelete bool `json:"autoDelete,omitempty"`
+
+	// Resources are the resources the workspace declared when it was
+	// scheduled onto a pool machine
+	Resources *MachineResources `json:"resources,omitempty"`
 }
 
 type WorkspaceProviderConfig struct {
@@ -132,6 +151,9 @@ type WorkspaceSource struct {
 
 	// Container is the container to use
 	Container string `json:"container,omitempty"`
+
+	// KubernetesWorkload is the existing Kubernetes pod or deployment to use, e.g. namespace/deployment/container
+	KubernetesWorkload string `json:"kubernetesWorkload,omitempty"`
 }
 
 type ContainerWorkspaceInfo struct {
@@ -214,6 +236,7 @@ type CLIOptions struct {
 	InitEnv                     []string          `json:"initEnv,omitempty"`
 	Recreate                    bool              `json:"recreate,omitempty"`
 	Reset                       bool              `json:"reset,omitempty"`
+	Rebase                      bool              `json:"rebase,omitempty"`
 	DisableDaemon               bool              `json:"disableDaemon,omitempty"`
 	DaemonInterval              string            `json:"daemonInterval,omitempty"`
 	GitCloneStrategy            git.CloneStrategy `json:"gitCloneStrategy,omitempty"`
@@ -223,11 +246,20 @@ type CLIOptions struct {
 	SSHAuthSockID               string            `json:"sshAuthSockID,omitempty"` // ID to use when looking for SSH_AUTH_SOCK, defaults to a new random ID if not set (only used for browser IDEs)
 	StrictHostKeyChecking       bool              `json:"strictHostKeyChecking,omitempty"`
 
+	// VSIXFolder is the VSIX folder of the client, the agent streams it through the tunnel if it runs remotely
+	VSIXFolder string `json:"vsixFolder,omitempty"`
+
 	// build options
 	Repository string   `json:"repository,omitempty"`
 	SkipPush   bool     `json:"skipPush,omitempty"`
 	Platforms  []string `json:"platform,omitempty"`
 	Tag        []string `json:"tag,omitempty"`
+	Explain    bool     `json:"explain,omitempty"`
+
+	// SBOM is the format of the software bill of materials to attach to pushed images (spdx or cyclonedx)
+	SBOM          string `json:"sbom,omitempty"`
+	SBOMGenerator string `json:"sbomGenerator,omitempty"`
+	Provenance    bool   `json:"provenance,omitempty"`
 
 	ForceBuild            bool `json:"forceBuild,omitempty"`
 	ForceDockerless       bool `json:"forceDockerless,omitempty"`
@@ -241,6 +273,12 @@ type BuildOptions struct {
 	RegistryCache string
 	ExportCache   bool
 	NoBuild       bool
+
+	// Prebuild is true if the image is built to be pushed to a prebuild repository
+	Prebuild bool
+
+	// Labels are additional labels to add to the built image
+	Labels map[string]string
 }
 
 func (w WorkspaceSource) String() string {
@@ -260,6 +298,8 @@ func (w WorkspaceSource) String() string {
 		return WorkspaceSourceImage + w.Image
 	} else if w.Container != "" {
 		return WorkspaceSourceContainer + w.Container
+	} else if w.KubernetesWorkload != "" {
+		return WorkspaceSourceKubernetes + w.KubernetesWorkload
 	}
 
 	return ""
@@ -282,6 +322,8 @@ func (w WorkspaceSource) Type() string {
 		return WorkspaceSourceImage
 	} else if w.Container != "" {
 		return WorkspaceSourceContainer
+	} else if w.KubernetesWorkload != "" {
+		return WorkspaceSourceKubernetes
 	}
 
 	return WorkspaceSourceUnknown
@@ -309,6 +351,10 @@ func ParseWorkspaceSource(source string) *WorkspaceSource {
 		return &WorkspaceSource{
 			Container: strings.TrimPrefix(source, WorkspaceSourceContainer),
 		}
+	} else if strings.HasPrefix(source, WorkspaceSourceKubernetes) {
+		return &WorkspaceSource{
+			KubernetesWorkload: strings.TrimPrefix(source, WorkspaceSourceKubernetes),
+		}
 	}
 
 	return nil
@@ -0,0 +1,154 @@
+package signature
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// Extension is the file extension of detached minisign signatures
+	Extension = ".minisig"
+
+	// PublicKeyFile is the conventional name of a minisign public key file
+	PublicKeyFile = "minisign.pub"
+
+	trustedCommentPrefix = "trusted comment: "
+)
+
+var (
+	algorithmEd       = [2]byte{'E', 'd'}
+	algorithmHashedEd = [2]byte{'E', 'D'}
+)
+
+// PublicKey is a minisign public key
+type PublicKey struct {
+	// KeyID identifies the key the signature was created with
+	KeyID uint64
+
+	key ed25519.PublicKey
+	raw string
+}
+
+// String returns the base64 encoded key as it appears in a minisign.pub file
+func (p *PublicKey) String() string {
+	return p.raw
+}
+
+// KeyIDString returns the key id in the same format minisign prints it
+func (p *PublicKey) KeyIDString() string {
+	return fmt.Sprintf("%016X", p.KeyID)
+}
+
+// Signature is a detached minisign signature
+type Signature struct {
+	// TrustedComment is the signed comment of the signature
+	TrustedComment string
+
+	algorithm       [2]byte
+	keyID           uint64
+	signature       []byte
+	globalSignature []byte
+}
+
+// ParsePublicKey parses either the base64 encoded key or the contents of a minisign.pub file
+func ParsePublicKey(in string) (*PublicKey, error) {
+	lines := strings.Split(strings.TrimSpace(in), "\n")
+	encoded := strings.TrimSpace(lines[len(lines)-1])
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode public key: %w", err)
+	} else if len(raw) != 2+8+ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key length %d", len(raw))
+	} else if raw[0] != algorithmEd[0] || raw[1] != algorithmEd[1] {
+		return nil, fmt.Errorf("unsupported public key algorithm %q", raw[:2])
+	}
+
+	return &PublicKey{
+		KeyID: binary.LittleEndian.Uint64(raw[2:10]),
+		key:   ed25519.PublicKey(raw[10:]),
+		raw:   encoded,
+	}, nil
+}
+
+// ParseSignature parses the contents of a .minisig file
+func ParseSignature(in []byte) (*Signature, error) {
+	lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(string(in), "\r\n", "\n")), "\n")
+	if len(lines) != 4 {
+		return nil, fmt.Errorf("invalid signature, expected 4 lines but got %d", len(lines))
+	} else if !strings.HasPrefix(lines[2], trustedCommentPrefix) {
+		return nil, fmt.Errorf("invalid signature, trusted comment is missing")
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil {
+		return nil, fmt.Errorf("decode signature: %w", err)
+	} else if len(raw) != 2+8+ed25519.SignatureSize {
+		return nil, fmt.Errorf("invalid signature length %d", len(raw))
+	}
+
+	globalSignature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil {
+		return nil, fmt.Errorf("decode global signature: %w", err)
+	} else if len(globalSignature) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("invalid global signature length %d", len(globalSignature))
+	}
+
+	signature := &Signature{
+		TrustedComment:  strings.TrimPrefix(lines[2], trustedCommentPrefix),
+		keyID:           binary.LittleEndian.Uint64(raw[2:10]),
+		signature:       raw[10:],
+		globalSignature: globalSignature,
+	}
+	copy(signature.algorithm[:], raw[:2])
+	if signature.algorithm != algorithmEd && signature.algorithm != algorithmHashedEd {
+		return nil, fmt.Errorf("unsupported signature algorithm %q", raw[:2])
+	}
+
+	return signature, nil
+}
+
+// Verify checks that the signature was created by the public key over the given message
+func Verify(publicKey *PublicKey, signature *Signature, message []byte) error {
+	if publicKey.KeyID != signature.keyID {
+		return fmt.Errorf("signature was created with key %016X, but expected key %s", signature.keyID, publicKey.KeyIDString())
+	}
+
+	if signature.algorithm == algorithmHashedEd {
+		hash := blake2b.Sum512(message)
+		message = hash[:]
+	}
+	if !ed25519.Verify(publicKey.key, message, signature.signature) {
+		return fmt.Errorf("invalid signature")
+	}
+
+	globalMessage := bytes.Join([][]byte{signature.signature, []byte(signature.TrustedComment)}, nil)
+	if !ed25519.Verify(publicKey.key, globalMessage, signature.globalSignature) {
+		return fmt.Errorf("invalid signature of the trusted comment")
+	}
+
+	return nil
+}
+
+// VerifyAny verifies the signature against all given keys and returns the key that matched
+func VerifyAny(publicKeys []*PublicKey, signature *Signature, message []byte) (*PublicKey, error) {
+	for _, publicKey := range publicKeys {
+		if publicKey.KeyID != signature.keyID {
+			continue
+		}
+
+		err := Verify(publicKey, signature, message)
+		if err != nil {
+			return nil, err
+		}
+
+		return publicKey, nil
+	}
+
+	return nil, fmt.Errorf("signature was created with key %016X, which is not trusted", signature.keyID)
+}
@@ -0,0 +1,128 @@
+package signature
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+type testKey struct {
+	id      uint64
+	private ed25519.PrivateKey
+	public  string
+}
+
+func newTestKey(t *testing.T, id uint64) *testKey {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	raw := append([]byte("Ed"), binary.LittleEndian.AppendUint64(nil, id)...)
+	raw = append(raw, public...)
+	return &testKey{
+		id:      id,
+		private: private,
+		public:  "untrusted comment: minisign public key\n" + base64.StdEncoding.EncodeToString(raw) + "\n",
+	}
+}
+
+func (k *testKey) sign(message []byte, hashed bool) []byte {
+	algorithm := "Ed"
+	if hashed {
+		algorithm = "ED"
+		hash := blake2b.Sum512(message)
+		message = hash[:]
+	}
+
+	signature := ed25519.Sign(k.private, message)
+	trustedComment := "timestamp:1700000000\tfile:provider.yaml"
+	globalSignature := ed25519.Sign(k.private, append(append([]byte{}, signature...), trustedComment...))
+
+	raw := append([]byte(algorithm), binary.LittleEndian.AppendUint64(nil, k.id)...)
+	raw = append(raw, signature...)
+	return []byte(fmt.Sprintf("untrusted comment: signature from minisign secret key\n%s\ntrusted comment: %s\n%s\n",
+		base64.StdEncoding.EncodeToString(raw),
+		trustedComment,
+		base64.StdEncoding.EncodeToString(globalSignature),
+	))
+}
+
+func TestVerify(t *testing.T) {
+	message := []byte("name: my-provider\nversion: v0.0.1\n")
+	key := newTestKey(t, 1)
+	otherKey := newTestKey(t, 2)
+
+	tests := []struct {
+		name      string
+		keys      []*testKey
+		signer    *testKey
+		hashed    bool
+		message   []byte
+		expectErr bool
+	}{
+		{
+			name:    "Legacy signature",
+			keys:    []*testKey{key},
+			signer:  key,
+			message: message,
+		},
+		{
+			name:    "Prehashed signature",
+			keys:    []*testKey{otherKey, key},
+			signer:  key,
+			hashed:  true,
+			message: message,
+		},
+		{
+			name:      "Tampered message",
+			keys:      []*testKey{key},
+			signer:    key,
+			message:   []byte("name: my-provider\nversion: v0.0.2\n"),
+			expectErr: true,
+		},
+		{
+			name:      "Untrusted key",
+			keys:      []*testKey{otherKey},
+			signer:    key,
+			message:   message,
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			publicKeys := []*PublicKey{}
+			for _, k := range test.keys {
+				publicKey, err := ParsePublicKey(k.public)
+				if err != nil {
+					t.Fatalf("parse public key: %v", err)
+				}
+				publicKeys = append(publicKeys, publicKey)
+			}
+
+			signature, err := ParseSignature(test.signer.sign(message, test.hashed))
+			if err != nil {
+				t.Fatalf("parse signature: %v", err)
+			}
+
+			publicKey, err := VerifyAny(publicKeys, signature, test.message)
+			if test.expectErr {
+				if err == nil {
+					t.Errorf("expected error, got none")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if publicKey.KeyID != test.signer.id {
+				t.Errorf("expected key %d, got %d", test.signer.id, publicKey.KeyID)
+			}
+		})
+	}
+}
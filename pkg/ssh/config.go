@@ -11,8 +11,11 @@ import (
 	"strings"
 	"sync"
 
-	"dev.khulnasoft.com/pkg/util"
 	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/config"
+	config2 "dev.khulnasoft.com/pkg/devcontainer/config"
+	"dev.khulnasoft.com/pkg/provider"
+	"dev.khulnasoft.com/pkg/util"
 	"dev.khulnasoft.com/log/scanner"
 	"github.com/pkg/errors"
 )
@@ -40,6 +43,122 @@ func configureSSHConfigSameFile(sshConfigPath, context, workspace, user, workdir
 	return writeSSHConfig(sshConfigPath, newFile, log)
 }
 
+// GetWildcardConfigPath returns the file with the wildcard host entry that ~/.ssh/config includes
+func GetWildcardConfigPath() (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "ssh", "config"), nil
+}
+
+// ConfigureWildcardSSHConfig makes the workspace reachable through a single Host *.devspace entry. The entry lives in
+// its own file that the ssh config includes, so the ssh config only changes when the include is added and concurrent
+// runs don't need to rewrite it. Connections are verified against the host key of the workspace.
+func ConfigureWildcardSSHConfig(sshConfigPath, context, workspace, devSpaceHome string, log log.Logger) error {
+	configLock.Lock()
+	defer configLock.Unlock()
+
+	// get path to executable
+	execPath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	wildcardConfigPath, err := GetWildcardConfigPath()
+	if err != nil {
+		return err
+	}
+
+	knownHostsPath, err := GetKnownHostsPath()
+	if err != nil {
+		return err
+	}
+
+	wildcardConfig := wildcardHostSection(execPath, knownHostsPath, devSpaceHome)
+	existing, err := os.ReadFile(wildcardConfigPath)
+	if err != nil || string(existing) != wildcardConfig {
+		err = os.MkdirAll(filepath.Dir(wildcardConfigPath), 0755)
+		if err != nil {
+			return err
+		}
+
+		err = writeFileAtomic(wildcardConfigPath, []byte(wildcardConfig), 0600)
+		if err != nil {
+			return errors.Wrap(err, "write wildcard ssh config")
+		}
+	}
+
+	// only touch the ssh config if the include is missing or it still has a host entry for the workspace, which would
+	// take precedence over the wildcard entry
+	content, err := os.ReadFile(sshConfigPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	lines := configLines(string(content))
+	if !slices.Contains(lines, MarkerStartPrefix+includeHost(wildcardConfigPath)) || slices.Contains(lines, MarkerStartPrefix+workspace+"."+"devspace") {
+		newConfig, err := removeFromConfig(sshConfigPath, workspace+"."+"devspace")
+		if err != nil {
+			return errors.Wrap(err, "parse ssh config")
+		}
+
+		err = writeSSHConfig(sshConfigPath, addInclude(newConfig, wildcardConfigPath), log)
+		if err != nil {
+			return err
+		}
+	}
+
+	// add the host key of the workspace to the known hosts
+	_, err = GetHostKey(context, workspace)
+	return err
+}
+
+func wildcardHostSection(execPath, knownHostsPath, devSpaceHome string) string {
+	proxyCommand := fmt.Sprintf("  ProxyCommand \"%s\" ssh --stdio %%h", execPath)
+	if devSpaceHome != "" {
+		proxyCommand = fmt.Sprintf("%s --devspace-home \"%s\"", proxyCommand, devSpaceHome)
+	}
+
+	return strings.Join([]string{
+		"# This file is managed by DevSpace, changes will be overwritten",
+		"Host *.devspace",
+		"  ForwardAgent yes",
+		"  LogLevel error",
+		"  StrictHostKeyChecking yes",
+		fmt.Sprintf("  UserKnownHostsFile \"%s\"", knownHostsPath),
+		"  HostKeyAlgorithms rsa-sha2-256,rsa-sha2-512,ssh-rsa",
+		proxyCommand,
+		"",
+	}, "\n")
+}
+
+// addInclude adds the include of the wildcard config on top of the ssh config, includes below a Host line would only
+// apply to that host
+func addInclude(config, path string) string {
+	startMarker := MarkerStartPrefix + includeHost(path)
+	if slices.Contains(configLines(config), startMarker) {
+		return config
+	}
+
+	lines := []string{
+		startMarker,
+		fmt.Sprintf("Include \"%s\"", path),
+		MarkerEndPrefix + includeHost(path),
+	}
+	if config != "" {
+		lines = append(lines, config)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// includeHost returns the marker name of the include of the given wildcard config, every DevSpace home has its own
+// wildcard config and therefore its own include
+func includeHost(path string) string {
+	return "Include " + path
+}
+
 type DevSpaceSSHEntry struct {
 	Host      string
 	User      string
@@ -137,32 +256,68 @@ func addHostSection(config, execPath, host, user, context, workspace, workdir, c
 }
 
 func GetUser(workspaceID string, sshConfigPath string) (string, error) {
+	user, _, err := getUser(workspaceID, sshConfigPath)
+	return user, err
+}
+
+// GetWorkspaceUser returns the remote user of the workspace from its host entry in the ssh config. Workspaces that
+// are reached through the wildcard entry have none, their user is read from the result of the last up instead.
+func GetWorkspaceUser(workspace *provider.Workspace) (string, error) {
+	user, found, err := getUser(workspace.ID, workspace.SSHConfigPath)
+	if err != nil || found {
+		return user, err
+	}
+
+	result, err := provider.LoadWorkspaceResult(workspace.Context, workspace.ID)
+	if err == nil && result != nil {
+		return config2.GetRemoteUser(result), nil
+	}
+
+	return user, nil
+}
+
+func getUser(workspaceID string, sshConfigPath string) (string, bool, error) {
 	path, err := ResolveSSHConfigPath(sshConfigPath)
 	if err != nil {
-		return "", errors.Wrap(err, "Invalid ssh config path")
+		return "", false, errors.Wrap(err, "Invalid ssh config path")
 	}
 	sshConfigPath = path
 
 	user := "root"
+	found := false
 	_, err = transformHostSection(sshConfigPath, workspaceID+"."+"devspace", func(line string) string {
 		splitted := strings.Split(strings.ToLower(strings.TrimSpace(line)), " ")
 		if len(splitted) == 2 && splitted[0] == "user" {
 			user = strings.Trim(splitted[1], "\"")
+			found = true
 		}
 
 		return line
 	})
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
-	return user, nil
+	return user, found, nil
 }
 
 func RemoveFromConfig(workspaceID string, sshConfigPath string, log log.Logger) error {
 	configLock.Lock()
 	defer configLock.Unlock()
 
+	err := removeKnownHost(workspaceID + "." + "devspace")
+	if err != nil {
+		log.Debugf("error removing known host: %v", err)
+	}
+
+	// workspaces that use the wildcard entry have nothing to remove
+	content, err := os.ReadFile(sshConfigPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	} else if !slices.Contains(configLines(string(content)), MarkerStartPrefix+workspaceID+"."+"devspace") {
+		return nil
+	}
+
 	newFile, err := removeFromConfig(sshConfigPath, workspaceID+"."+"devspace")
 	if err != nil {
 		return errors.Wrap(err, "parse ssh config")
@@ -171,6 +326,10 @@ func RemoveFromConfig(workspaceID string, sshConfigPath string, log log.Logger)
 	return writeSSHConfig(sshConfigPath, newFile, log)
 }
 
+func configLines(content string) []string {
+	return strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+}
+
 func writeSSHConfig(path, content string, log log.Logger) error {
 	err := os.MkdirAll(filepath.Dir(path), 0755)
 	if err != nil {
@@ -2,6 +2,7 @@ package ssh
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"testing"
 
@@ -292,3 +293,121 @@ Host testhost
 		})
 	}
 }
+
+func TestAddInclude(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		expected string
+	}{
+		{
+			name:   "Empty config",
+			config: "",
+			expected: `# DevSpace Start Include /home/user/.devspace/ssh/config
+Include "/home/user/.devspace/ssh/config"
+# DevSpace End Include /home/user/.devspace/ssh/config`,
+		},
+		{
+			name:   "Existing hosts",
+			config: "Host example\n  User test",
+			expected: `# DevSpace Start Include /home/user/.devspace/ssh/config
+Include "/home/user/.devspace/ssh/config"
+# DevSpace End Include /home/user/.devspace/ssh/config
+Host example
+  User test`,
+		},
+		{
+			name: "Include already added",
+			config: `# DevSpace Start Include /home/user/.devspace/ssh/config
+Include "/home/user/.devspace/ssh/config"
+# DevSpace End Include /home/user/.devspace/ssh/config
+Host example`,
+			expected: `# DevSpace Start Include /home/user/.devspace/ssh/config
+Include "/home/user/.devspace/ssh/config"
+# DevSpace End Include /home/user/.devspace/ssh/config
+Host example`,
+		},
+		{
+			name: "Include of another home",
+			config: `# DevSpace Start Include /home/user/.other/ssh/config
+Include "/home/user/.other/ssh/config"
+# DevSpace End Include /home/user/.other/ssh/config
+Host example`,
+			expected: `# DevSpace Start Include /home/user/.devspace/ssh/config
+Include "/home/user/.devspace/ssh/config"
+# DevSpace End Include /home/user/.devspace/ssh/config
+# DevSpace Start Include /home/user/.other/ssh/config
+Include "/home/user/.other/ssh/config"
+# DevSpace End Include /home/user/.other/ssh/config
+Host example`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := addInclude(tt.config, "/home/user/.devspace/ssh/config")
+			if diff := cmp.Diff(tt.expected, result); diff != "" {
+				t.Errorf("addInclude mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestKnownHosts(t *testing.T) {
+	t.Setenv("DEVSPACE_HOME", t.TempDir())
+
+	hostKey, err := GetHostKeyBase(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherHostKey, err := GetHostKeyBase(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	knownHostsPath, err := GetKnownHostsPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	readHosts := func() []string {
+		content, err := os.ReadFile(knownHostsPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		hosts := []string{}
+		for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+			if line != "" {
+				hosts = append(hosts, strings.Fields(line)[0])
+			}
+		}
+		return hosts
+	}
+
+	for _, host := range []string{"a.devspace", "b.devspace", "a.devspace"} {
+		err = addKnownHost(host, hostKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if diff := cmp.Diff([]string{"a.devspace", "b.devspace"}, readHosts()); diff != "" {
+		t.Errorf("known hosts mismatch (-want +got):\n%s", diff)
+	}
+
+	// a recreated workspace replaces its old key
+	err = addKnownHost("a.devspace", otherHostKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"b.devspace", "a.devspace"}, readHosts()); diff != "" {
+		t.Errorf("known hosts mismatch (-want +got):\n%s", diff)
+	}
+
+	err = removeKnownHost("a.devspace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"b.devspace"}, readHosts()); diff != "" {
+		t.Errorf("known hosts mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -108,13 +108,24 @@ func GetDevSpacePrivateKeyRaw() ([]byte, error) {
 	return GetPrivateKeyRawBase(tempDir)
 }
 
+// GetHostKey returns the host key of the workspace and adds it to the known hosts of the wildcard ssh config
 func GetHostKey(context, workspaceID string) (string, error) {
 	workspaceDir, err := provider.GetWorkspaceDir(context, workspaceID)
 	if err != nil {
 		return "", err
 	}
 
-	return GetHostKeyBase(workspaceDir)
+	hostKey, err := GetHostKeyBase(workspaceDir)
+	if err != nil {
+		return "", err
+	}
+
+	err = addKnownHost(workspaceID+"."+"devspace", hostKey)
+	if err != nil {
+		return "", errors.Wrap(err, "add known host")
+	}
+
+	return hostKey, nil
 }
 
 func GetPrivateKeyRawBase(dir string) ([]byte, error) {
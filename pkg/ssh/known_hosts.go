@@ -0,0 +1,148 @@
+package ssh
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"dev.khulnasoft.com/pkg/config"
+	"github.com/gofrs/flock"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// GetKnownHostsPath returns the known_hosts file with the host keys of the workspaces that the wildcard ssh config
+// verifies connections against
+func GetKnownHostsPath() (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "ssh", "known_hosts"), nil
+}
+
+// addKnownHost adds the public part of the base64 encoded host key to the known_hosts file if it isn't in there yet
+func addKnownHost(host, hostKey string) error {
+	privateKey, err := base64.StdEncoding.DecodeString(hostKey)
+	if err != nil {
+		return err
+	}
+
+	signer, err := ssh.ParsePrivateKey(privateKey)
+	if err != nil {
+		return errors.Wrap(err, "parse host key")
+	}
+
+	line := knownhosts.Line([]string{host}, signer.PublicKey())
+	return updateKnownHosts(func(lines []string) []string {
+		newLines := []string{}
+		for _, existing := range lines {
+			if existing == line {
+				return nil
+			} else if !isKnownHostLine(existing, host) {
+				newLines = append(newLines, existing)
+			}
+		}
+
+		return append(newLines, line)
+	})
+}
+
+// removeKnownHost removes the host key of the host from the known_hosts file
+func removeKnownHost(host string) error {
+	return updateKnownHosts(func(lines []string) []string {
+		newLines := []string{}
+		for _, existing := range lines {
+			if !isKnownHostLine(existing, host) {
+				newLines = append(newLines, existing)
+			}
+		}
+		if len(newLines) == len(lines) {
+			return nil
+		}
+
+		return newLines
+	})
+}
+
+// updateKnownHosts replaces the lines of the known_hosts file with the lines update returns. If update returns nil,
+// the file is left untouched. The file is locked, because several DevSpace commands might update it at once.
+func updateKnownHosts(update func(lines []string) []string) error {
+	knownHostsPath, err := GetKnownHostsPath()
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(knownHostsPath), 0755)
+	if err != nil {
+		return err
+	}
+
+	fileLock := flock.New(knownHostsPath + ".lock")
+	err = fileLock.Lock()
+	if err != nil {
+		return errors.Wrap(err, "lock known_hosts")
+	}
+	defer func() {
+		_ = fileLock.Unlock()
+	}()
+
+	content, err := os.ReadFile(knownHostsPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	lines := []string{}
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	newLines := update(lines)
+	if newLines == nil {
+		return nil
+	}
+
+	newContent := ""
+	if len(newLines) > 0 {
+		newContent = strings.Join(newLines, "\n") + "\n"
+	}
+
+	return writeFileAtomic(knownHostsPath, []byte(newContent), 0600)
+}
+
+func isKnownHostLine(line, host string) bool {
+	fields := strings.Fields(line)
+	return len(fields) > 0 && fields[0] == knownhosts.Normalize(host)
+}
+
+// writeFileAtomic writes the file through a temporary file, so readers never see a partially written file
+func writeFileAtomic(path string, content []byte, perm os.FileMode) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+
+	_, err = tempFile.Write(content)
+	if err != nil {
+		_ = tempFile.Close()
+		return err
+	}
+
+	err = tempFile.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(tempFile.Name(), perm)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), path)
+}
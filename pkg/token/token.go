@@ -29,6 +29,17 @@ func GetDevSpaceToken() (string, error) {
 	return buildToken(hostKey, publicKey)
 }
 
+// GetWorkspaceToken returns a token that only holds the host key of the workspace, so the ssh server started
+// through a ProxyCommand presents the key that is in the DevSpace known_hosts file
+func GetWorkspaceToken(context, workspaceID string) (string, error) {
+	hostKey, err := ssh.GetHostKey(context, workspaceID)
+	if err != nil {
+		return "", errors.Wrap(err, "get host key")
+	}
+
+	return buildToken(hostKey, "")
+}
+
 func buildToken(hostKey string, publicKey string) (string, error) {
 	out, err := json.Marshal(&Token{
 		HostKey:        hostKey,
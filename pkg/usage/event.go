@@ -0,0 +1,285 @@
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/config"
+	"dev.khulnasoft.com/pkg/provider"
+	"github.com/pkg/errors"
+)
+
+const (
+	// HourlyPriceOption is the provider option providers use to declare the hourly price of a machine or workspace
+	HourlyPriceOption = "HOURLY_PRICE"
+
+	// MachineTypeOption is the provider option that holds the machine type. Options ending
+	// with _MACHINE_TYPE or _INSTANCE_TYPE are used as well.
+	MachineTypeOption = "MACHINE_TYPE"
+
+	eventsFile = "events.jsonl"
+)
+
+type EventType string
+
+const (
+	EventCreated EventType = "created"
+	EventStarted EventType = "started"
+	EventStopped EventType = "stopped"
+	EventDeleted EventType = "deleted"
+)
+
+type Kind string
+
+const (
+	KindWorkspace Kind = "workspace"
+	KindMachine   Kind = "machine"
+)
+
+type Event struct {
+	// Timestamp is the time the state transition happened
+	Timestamp time.Time `json:"timestamp"`
+
+	// Type is the state transition
+	Type EventType `json:"type"`
+
+	// Kind is either workspace or machine
+	Kind Kind `json:"kind"`
+
+	// ID is the id of the workspace or machine
+	ID string `json:"id"`
+
+	// Provider is the provider of the workspace or machine
+	Provider string `json:"provider,omitempty"`
+
+	// MachineType is the machine type from the provider options
+	MachineType string `json:"machineType,omitempty"`
+
+	// HourlyPrice is the declared hourly price at the time of the event
+	HourlyPrice float64 `json:"hourlyPrice,omitempty"`
+
+	// Machine is the machine a workspace runs on
+	Machine string `json:"machine,omitempty"`
+}
+
+// RecordMachine records a state transition of a machine. Errors are only logged, as
+// accounting should never block the actual operation.
+func RecordMachine(devSpaceConfig *config.Config, machine *provider.Machine, eventType EventType, log log.Logger) {
+	if machine == nil {
+		return
+	}
+
+	options := provider.CombineOptions(nil, machine, devSpaceConfig.ProviderOptions(machine.Provider.Name))
+	record(machine.Context, &Event{
+		Timestamp:   time.Now(),
+		Type:        eventType,
+		Kind:        KindMachine,
+		ID:          machine.ID,
+		Provider:    machine.Provider.Name,
+		MachineType: machineType(options),
+		HourlyPrice: hourlyPrice(options, log),
+	}, log)
+}
+
+// RecordWorkspace records a state transition of a workspace. Workspaces that run on a
+// machine don't carry a price themselves, the machine is accounted for instead.
+func RecordWorkspace(devSpaceConfig *config.Config, workspace *provider.Workspace, eventType EventType, log log.Logger) {
+	if workspace == nil || workspace.IsPro() {
+		return
+	}
+
+	var machine *provider.Machine
+	if workspace.Machine.ID != "" {
+		machine, _ = provider.LoadMachineConfig(workspace.Context, workspace.Machine.ID)
+	}
+
+	options := provider.CombineOptions(workspace, machine, devSpaceConfig.ProviderOptions(workspace.Provider.Name))
+	event := &Event{
+		Timestamp:   time.Now(),
+		Type:        eventType,
+		Kind:        KindWorkspace,
+		ID:          workspace.ID,
+		Provider:    workspace.Provider.Name,
+		MachineType: machineType(options),
+		Machine:     workspace.Machine.ID,
+	}
+	if workspace.Machine.ID == "" {
+		event.HourlyPrice = hourlyPrice(options, log)
+	}
+
+	record(workspace.Context, event, log)
+}
+
+// RecordWorkspaceNotRunning records a stop of the workspace if it was last recorded as started. Workspaces that are
+// stopped on their machine, e.g. after the inactivity timeout, can't record the stop themselves, so it's recorded once
+// the status shows that the workspace isn't running anymore.
+func RecordWorkspaceNotRunning(devSpaceConfig *config.Config, workspace *provider.Workspace, log log.Logger) {
+	if workspace == nil || workspace.IsPro() || !lastRecordedStarted(workspace.Context, KindWorkspace, workspace.ID, log) {
+		return
+	}
+
+	RecordWorkspace(devSpaceConfig, workspace, EventStopped, log)
+}
+
+// RecordMachineNotRunning records a stop of the machine if it was last recorded as started, e.g. because the machine
+// shut itself down after the inactivity timeout
+func RecordMachineNotRunning(devSpaceConfig *config.Config, machine *provider.Machine, log log.Logger) {
+	if machine == nil || !lastRecordedStarted(machine.Context, KindMachine, machine.ID, log) {
+		return
+	}
+
+	RecordMachine(devSpaceConfig, machine, EventStopped, log)
+}
+
+// lastRecordedStarted returns if the last recorded start or stop of the workspace or machine was a start
+func lastRecordedStarted(context string, kind Kind, id string, log log.Logger) bool {
+	if context == "" {
+		return false
+	}
+
+	events, err := LoadEvents(context)
+	if err != nil {
+		log.Debugf("Error loading usage events: %v", err)
+		return false
+	}
+
+	var last *Event
+	for _, event := range events {
+		if event.Kind != kind || event.ID != id || event.Type == EventCreated {
+			continue
+		} else if last == nil || !event.Timestamp.Before(last.Timestamp) {
+			last = event
+		}
+	}
+
+	return last != nil && last.Type == EventStarted
+}
+
+// Record appends an event to the event store of the given context
+func Record(context string, event *Event) error {
+	eventsDir, err := GetEventsDir(context)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(eventsDir, 0755)
+	if err != nil {
+		return err
+	}
+
+	out, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filepath.Join(eventsDir, eventsFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(out, '\n'))
+	return err
+}
+
+// LoadEvents loads all recorded events of the given context
+func LoadEvents(context string) ([]*Event, error) {
+	eventsDir, err := GetEventsDir(context)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filepath.Join(eventsDir, eventsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	defer f.Close()
+
+	retEvents := []*Event{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		event := &Event{}
+		err = json.Unmarshal([]byte(line), event)
+		if err != nil {
+			// skip partially written lines
+			continue
+		}
+
+		retEvents = append(retEvents, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "read events")
+	}
+
+	return retEvents, nil
+}
+
+func GetEventsDir(context string) (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "contexts", context, "usage"), nil
+}
+
+func record(context string, event *Event, log log.Logger) {
+	if context == "" {
+		return
+	}
+
+	err := Record(context, event)
+	if err != nil {
+		log.Debugf("Error recording usage event: %v", err)
+	}
+}
+
+func machineType(options map[string]config.OptionValue) string {
+	if options[MachineTypeOption].Value != "" {
+		return options[MachineTypeOption].Value
+	}
+
+	names := []string{}
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if options[name].Value != "" && (strings.HasSuffix(name, "_"+MachineTypeOption) || strings.HasSuffix(name, "_INSTANCE_TYPE")) {
+			return options[name].Value
+		}
+	}
+
+	return ""
+}
+
+func hourlyPrice(options map[string]config.OptionValue, log log.Logger) float64 {
+	value := strings.TrimSpace(options[HourlyPriceOption].Value)
+	if value == "" {
+		return 0
+	}
+
+	price, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Debugf("Error parsing %s %s: %v", HourlyPriceOption, value, err)
+		return 0
+	}
+
+	return price
+}
@@ -0,0 +1,82 @@
+package usage
+
+import (
+	"testing"
+	"time"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/config"
+	"dev.khulnasoft.com/pkg/provider"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestRecordNotRunning(t *testing.T) {
+	t.Setenv("DEVSPACE_HOME", t.TempDir())
+
+	devSpaceConfig := &config.Config{
+		DefaultContext: "default",
+		Contexts: map[string]*config.ContextConfig{
+			"default": {},
+		},
+	}
+	workspace := &provider.Workspace{
+		ID:       "ws1",
+		Context:  "default",
+		Provider: provider.WorkspaceProviderConfig{Name: "aws"},
+		Machine:  provider.WorkspaceMachineConfig{ID: "m1"},
+	}
+	machine := &provider.Machine{
+		ID:       "m1",
+		Context:  "default",
+		Provider: provider.MachineProviderConfig{Name: "aws"},
+	}
+	other := &provider.Workspace{
+		ID:       "ws2",
+		Context:  "default",
+		Provider: provider.WorkspaceProviderConfig{Name: "docker"},
+	}
+
+	// the workspace and its machine were started two hours ago and stopped themselves since then
+	started := time.Now().Add(-2 * time.Hour)
+	for _, event := range []*Event{
+		{Timestamp: started, Type: EventCreated, Kind: KindMachine, ID: "m1", Provider: "aws", HourlyPrice: 0.5},
+		{Timestamp: started, Type: EventStarted, Kind: KindMachine, ID: "m1", Provider: "aws", HourlyPrice: 0.5},
+		{Timestamp: started, Type: EventStarted, Kind: KindWorkspace, ID: "ws1", Provider: "aws", Machine: "m1"},
+		{Timestamp: started, Type: EventStarted, Kind: KindWorkspace, ID: "ws2", Provider: "docker"},
+		{Timestamp: started.Add(time.Hour), Type: EventStopped, Kind: KindWorkspace, ID: "ws2", Provider: "docker"},
+	} {
+		if err := Record("default", event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// a second status check shouldn't record another stop
+	for range 2 {
+		RecordWorkspaceNotRunning(devSpaceConfig, workspace, log.Discard)
+		RecordMachineNotRunning(devSpaceConfig, machine, log.Discard)
+		RecordWorkspaceNotRunning(devSpaceConfig, other, log.Discard)
+	}
+
+	events, err := LoadEvents("default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 7 {
+		t.Fatalf("expected 7 events, got %d", len(events))
+	}
+
+	got, err := Report(events, ReportOptions{GroupBy: []string{GroupByProvider}}, time.Now().Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []*Entry{
+		{Kind: KindMachine, Provider: "aws", Hours: 2, Cost: 1},
+		{Kind: KindWorkspace, Provider: "aws", Hours: 2},
+		{Kind: KindWorkspace, Provider: "docker", Hours: 1},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.EquateApprox(0, 0.01)); diff != "" {
+		t.Errorf("unexpected report (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,181 @@
+package usage
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	GroupByProvider    = "provider"
+	GroupByMachineType = "machine-type"
+	GroupByWorkspace   = "workspace"
+)
+
+// Entry is a single row of a usage report
+type Entry struct {
+	// Kind is either workspace or machine
+	Kind Kind `json:"kind"`
+
+	// Provider is the provider if grouped by provider
+	Provider string `json:"provider,omitempty"`
+
+	// MachineType is the machine type if grouped by machine type
+	MachineType string `json:"machineType,omitempty"`
+
+	// Workspace is the workspace if grouped by workspace. Machines are attributed
+	// to the last workspace that used them.
+	Workspace string `json:"workspace,omitempty"`
+
+	// Hours is the total running time in hours
+	Hours float64 `json:"hours"`
+
+	// Cost is the running time multiplied with the declared hourly price
+	Cost float64 `json:"cost"`
+}
+
+type ReportOptions struct {
+	// Since and Until limit the report to the given time range. Zero values are unbounded.
+	Since time.Time
+	Until time.Time
+
+	// GroupBy are the dimensions to group by
+	GroupBy []string
+}
+
+// Report sums up the running time of workspaces and machines from the given events
+func Report(events []*Event, options ReportOptions, now time.Time) ([]*Entry, error) {
+	groupBy := map[string]bool{}
+	for _, group := range options.GroupBy {
+		if group != GroupByProvider && group != GroupByMachineType && group != GroupByWorkspace {
+			return nil, fmt.Errorf("unexpected group %s, choose one of %s, %s or %s", group, GroupByProvider, GroupByMachineType, GroupByWorkspace)
+		}
+
+		groupBy[group] = true
+	}
+
+	until := options.Until
+	if until.IsZero() || until.After(now) {
+		until = now
+	}
+
+	sorted := append([]*Event{}, events...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	// remember which workspace used a machine last
+	machineWorkspaces := map[string]string{}
+	for _, event := range sorted {
+		if event.Kind == KindWorkspace && event.Machine != "" {
+			machineWorkspaces[event.Machine] = event.ID
+		}
+	}
+
+	entries := map[string]*Entry{}
+	addInterval := func(start *Event, end time.Time) {
+		from := start.Timestamp
+		if from.Before(options.Since) {
+			from = options.Since
+		}
+		to := end
+		if to.After(until) {
+			to = until
+		}
+		if !to.After(from) {
+			return
+		}
+
+		entry := &Entry{Kind: start.Kind}
+		if groupBy[GroupByProvider] {
+			entry.Provider = start.Provider
+		}
+		if groupBy[GroupByMachineType] {
+			entry.MachineType = start.MachineType
+		}
+		if groupBy[GroupByWorkspace] {
+			entry.Workspace = start.ID
+			if start.Kind == KindMachine {
+				entry.Workspace = machineWorkspaces[start.ID]
+			}
+		}
+
+		key := string(entry.Kind) + "/" + entry.Provider + "/" + entry.MachineType + "/" + entry.Workspace
+		if entries[key] == nil {
+			entries[key] = entry
+		}
+
+		hours := to.Sub(from).Hours()
+		entries[key].Hours += hours
+		entries[key].Cost += hours * start.HourlyPrice
+	}
+
+	// walk through the state transitions of every workspace and machine
+	running := map[string]*Event{}
+	for _, event := range sorted {
+		key := string(event.Kind) + "/" + event.ID
+		switch event.Type {
+		case EventStarted:
+			if running[key] == nil {
+				running[key] = event
+			}
+		case EventStopped, EventDeleted:
+			if running[key] != nil {
+				addInterval(running[key], event.Timestamp)
+				delete(running, key)
+			}
+		}
+	}
+	for _, start := range running {
+		addInterval(start, until)
+	}
+
+	keys := []string{}
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	retEntries := []*Entry{}
+	for _, key := range keys {
+		retEntries = append(retEntries, entries[key])
+	}
+	sort.SliceStable(retEntries, func(i, j int) bool {
+		if retEntries[i].Cost != retEntries[j].Cost {
+			return retEntries[i].Cost > retEntries[j].Cost
+		}
+
+		return retEntries[i].Hours > retEntries[j].Hours
+	})
+
+	return retEntries, nil
+}
+
+// WriteCSV writes the report entries as csv
+func WriteCSV(w io.Writer, entries []*Entry) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write([]string{"kind", "provider", "machine_type", "workspace", "hours", "cost"})
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		err = writer.Write([]string{
+			string(entry.Kind),
+			entry.Provider,
+			entry.MachineType,
+			entry.Workspace,
+			strconv.FormatFloat(entry.Hours, 'f', 2, 64),
+			strconv.FormatFloat(entry.Cost, 'f', 2, 64),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
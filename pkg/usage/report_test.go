@@ -0,0 +1,63 @@
+package usage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReport(t *testing.T) {
+	start := time.Date(2026, 9, 1, 8, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time {
+		return start.Add(time.Duration(hours) * time.Hour)
+	}
+
+	events := []*Event{
+		{Timestamp: at(0), Type: EventCreated, Kind: KindMachine, ID: "m1", Provider: "aws", MachineType: "t3.large", HourlyPrice: 0.5},
+		{Timestamp: at(0), Type: EventStarted, Kind: KindMachine, ID: "m1", Provider: "aws", MachineType: "t3.large", HourlyPrice: 0.5},
+		{Timestamp: at(1), Type: EventStarted, Kind: KindWorkspace, ID: "ws1", Provider: "aws", MachineType: "t3.large", Machine: "m1"},
+		{Timestamp: at(4), Type: EventStopped, Kind: KindMachine, ID: "m1", Provider: "aws", MachineType: "t3.large", HourlyPrice: 0.5},
+		{Timestamp: at(4), Type: EventStopped, Kind: KindWorkspace, ID: "ws1", Provider: "aws", Machine: "m1"},
+		{Timestamp: at(10), Type: EventStarted, Kind: KindMachine, ID: "m1", Provider: "aws", MachineType: "t3.large", HourlyPrice: 0.5},
+		{Timestamp: at(2), Type: EventStarted, Kind: KindWorkspace, ID: "ws2", Provider: "docker"},
+		{Timestamp: at(3), Type: EventDeleted, Kind: KindWorkspace, ID: "ws2", Provider: "docker"},
+	}
+
+	tests := []struct {
+		name    string
+		options ReportOptions
+		want    []*Entry
+	}{
+		{
+			name:    "Group by provider",
+			options: ReportOptions{GroupBy: []string{GroupByProvider}},
+			want: []*Entry{
+				{Kind: KindMachine, Provider: "aws", Hours: 6, Cost: 3},
+				{Kind: KindWorkspace, Provider: "aws", Hours: 3},
+				{Kind: KindWorkspace, Provider: "docker", Hours: 1},
+			},
+		},
+		{
+			name:    "Group by workspace within time range",
+			options: ReportOptions{GroupBy: []string{GroupByWorkspace}, Since: at(3), Until: at(11)},
+			want: []*Entry{
+				{Kind: KindMachine, Workspace: "ws1", Hours: 2, Cost: 1},
+				{Kind: KindWorkspace, Workspace: "ws1", Hours: 1},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Report(events, test.options, at(12))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("unexpected report (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -23,21 +23,51 @@ var (
 )
 
 func DirectoryHash(srcPath string, excludePatterns, includeFiles []string) (string, error) {
-	srcPath, err := filepath.Abs(srcPath)
+	fileHashes, err := DirectoryFileHashes(srcPath, excludePatterns, includeFiles)
 	if err != nil {
 		return "", err
 	}
 
-	// Stat dir / file
+	return FileHashesHash(fileHashes), nil
+}
+
+// FileHashesHash combines the file checksums returned by DirectoryFileHashes into a single hash
+func FileHashesHash(fileHashes map[string]string) string {
+	retFiles := []string{}
+	for relFilePath, checksum := range fileHashes {
+		retFiles = append(retFiles, relFilePath+";"+checksum)
+	}
+	sort.Strings(retFiles)
+
 	hash := sha256.New()
+	for _, f := range retFiles {
+		_, _ = hash.Write([]byte(f))
+	}
+	if len(retFiles) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// DirectoryFileHashes returns the checksums of all files within srcPath that are part
+// of the given include files and not excluded by the given patterns, keyed by their relative path.
+func DirectoryFileHashes(srcPath string, excludePatterns, includeFiles []string) (map[string]string, error) {
+	srcPath, err := filepath.Abs(srcPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stat dir / file
 	fileInfo, err := os.Stat(srcPath)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// Hash file
+	retFiles := map[string]string{}
 	if !fileInfo.IsDir() {
-		return "", nil
+		return retFiles, nil
 	}
 
 	// Fix the source path to work with long path names. This is a no-op
@@ -48,7 +78,7 @@ func DirectoryHash(srcPath string, excludePatterns, includeFiles []string) (stri
 
 	pm, err := patternmatcher.New(excludePatterns)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// In general we log errors here but ignore them because
@@ -57,17 +87,16 @@ func DirectoryHash(srcPath string, excludePatterns, includeFiles []string) (stri
 	// from this
 	stat, err := os.Lstat(srcPath)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if !stat.IsDir() {
-		return "", errors.Errorf("Path %s is not a directory", srcPath)
+		return nil, errors.Errorf("Path %s is not a directory", srcPath)
 	}
 
 	include := "."
 	seen := make(map[string]bool)
 
-	retFiles := []string{}
 	walkRoot := filepath.Join(srcPath, include)
 	err = filepath.Walk(walkRoot, func(filePath string, f os.FileInfo, err error) error {
 		if err != nil {
@@ -155,25 +184,16 @@ func DirectoryHash(srcPath string, excludePatterns, includeFiles []string) (stri
 				return nil
 			}
 
-			retFiles = append(retFiles, relFilePath+";"+checksum)
+			retFiles[relFilePath] = checksum
 		}
 
 		return nil
 	})
 	if err != nil && !errors.Is(err, errFileReadOverLimit) {
-		return "", errors.Errorf("Error hashing %s: %v", srcPath, err)
-	}
-
-	// add to hash
-	sort.Strings(retFiles)
-	for _, f := range retFiles {
-		_, _ = hash.Write([]byte(f))
-	}
-	if len(retFiles) == 0 {
-		return "", nil
+		return nil, errors.Errorf("Error hashing %s: %v", srcPath, err)
 	}
 
-	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+	return retFiles, nil
 }
 
 func hashFileCRC32(filePath string, polynomial uint32) (string, error) {
@@ -0,0 +1,523 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/client"
+	"dev.khulnasoft.com/pkg/client/clientimplementation"
+	"dev.khulnasoft.com/pkg/config"
+	devcontainerconfig "dev.khulnasoft.com/pkg/devcontainer/config"
+	"dev.khulnasoft.com/pkg/encoding"
+	providerpkg "dev.khulnasoft.com/pkg/provider"
+	"dev.khulnasoft.com/pkg/single"
+	"dev.khulnasoft.com/pkg/types"
+	"github.com/docker/go-units"
+	"github.com/gofrs/flock"
+	"github.com/pkg/errors"
+)
+
+// poolMachine is a machine of a pool together with the workspaces scheduled onto it
+type poolMachine struct {
+	Machine    *providerpkg.Machine
+	Workspaces []*providerpkg.Workspace
+	Used       providerpkg.MachineResources
+}
+
+// ListMachinePools returns all machine pools of the current context
+func ListMachinePools(devSpaceConfig *config.Config, log log.Logger) ([]*providerpkg.MachinePool, error) {
+	poolsDir, err := providerpkg.GetMachinePoolsDir(devSpaceConfig.DefaultContext)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(poolsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	retPools := []*providerpkg.MachinePool{}
+	for _, entry := range entries {
+		pool, err := providerpkg.LoadMachinePoolConfig(devSpaceConfig.DefaultContext, entry.Name())
+		if err != nil {
+			log.ErrorStreamOnly().Warnf("Couldn't load machine pool %s: %v", entry.Name(), err)
+			continue
+		}
+
+		retPools = append(retPools, pool)
+	}
+
+	sort.SliceStable(retPools, func(i, j int) bool {
+		return retPools[i].Name < retPools[j].Name
+	})
+	return retPools, nil
+}
+
+// MachinePoolUsage summarizes the machines of a pool
+type MachinePoolUsage struct {
+	Machines   int `json:"machines"`
+	Idle       int `json:"idle"`
+	Workspaces int `json:"workspaces"`
+}
+
+// GetMachinePoolUsage returns how many machines of the pool exist and how many of them are in use
+func GetMachinePoolUsage(devSpaceConfig *config.Config, pool *providerpkg.MachinePool, log log.Logger) (*MachinePoolUsage, error) {
+	machines, err := listPoolMachines(devSpaceConfig, pool, log)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &MachinePoolUsage{Machines: len(machines)}
+	for _, machine := range machines {
+		if len(machine.Workspaces) == 0 {
+			usage.Idle++
+		}
+		usage.Workspaces += len(machine.Workspaces)
+	}
+
+	return usage, nil
+}
+
+// CreateMachinePool saves a new machine pool and fills it with machines
+func CreateMachinePool(ctx context.Context, devSpaceConfig *config.Config, pool *providerpkg.MachinePool, log log.Logger) error {
+	if providerpkg.ProviderNameRegEx.MatchString(pool.Name) {
+		return fmt.Errorf("pool name can only include smaller case letters, numbers or dashes")
+	} else if providerpkg.MachinePoolExists(devSpaceConfig.DefaultContext, pool.Name) {
+		return fmt.Errorf("machine pool %s already exists", pool.Name)
+	} else if pool.Size < 0 {
+		return fmt.Errorf("pool size cannot be negative")
+	} else if pool.State != providerpkg.MachinePoolStateRunning && pool.State != providerpkg.MachinePoolStateStopped {
+		return fmt.Errorf("unexpected pool state %s, choose either %s or %s", pool.State, providerpkg.MachinePoolStateRunning, providerpkg.MachinePoolStateStopped)
+	}
+
+	providerWithOptions, err := FindProvider(devSpaceConfig, pool.Provider.Name, log)
+	if err != nil {
+		return err
+	} else if !providerWithOptions.Config.IsMachineProvider() {
+		return fmt.Errorf("provider %s doesn't create machines and cannot be used for a machine pool", pool.Provider.Name)
+	}
+
+	pool.Context = devSpaceConfig.DefaultContext
+	pool.CreationTimestamp = types.Now()
+	err = providerpkg.SaveMachinePoolConfig(pool)
+	if err != nil {
+		return errors.Wrap(err, "save machine pool")
+	}
+
+	return ReplenishMachinePool(ctx, devSpaceConfig, pool.Name, log)
+}
+
+// DeleteMachinePool deletes the unused machines of a pool and the pool itself. Machines
+// that are still used by workspaces are kept and have to be deleted manually.
+func DeleteMachinePool(ctx context.Context, devSpaceConfig *config.Config, poolName string, log log.Logger) error {
+	pool, err := providerpkg.LoadMachinePoolConfig(devSpaceConfig.DefaultContext, poolName)
+	if err != nil {
+		return fmt.Errorf("machine pool %s doesn't exist", poolName)
+	}
+
+	unlock, err := lockMachinePool(ctx, pool, "claim")
+	if err != nil {
+		return err
+	}
+	machines, err := listPoolMachines(devSpaceConfig, pool, log)
+	if err == nil {
+		// detach all machines from the pool so they can't be claimed anymore
+		for _, machine := range machines {
+			machine.Machine.Pool = ""
+			err = providerpkg.SaveMachineConfig(machine.Machine)
+			if err != nil {
+				break
+			}
+		}
+	}
+	unlock()
+	if err != nil {
+		return err
+	}
+
+	for _, machine := range machines {
+		if len(machine.Workspaces) > 0 {
+			log.Infof("Keep machine %s, because it is still used by workspace %s", machine.Machine.ID, machine.Workspaces[0].ID)
+			continue
+		}
+
+		err = deletePoolMachine(ctx, devSpaceConfig, machine.Machine, log)
+		if err != nil {
+			return err
+		}
+	}
+
+	poolDir, err := providerpkg.GetMachinePoolDir(pool.Context, pool.Name)
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(poolDir)
+}
+
+// ReplenishMachinePool makes sure the pool has exactly as many unused machines as
+// configured and that they are in the desired state.
+func ReplenishMachinePool(ctx context.Context, devSpaceConfig *config.Config, poolName string, log log.Logger) error {
+	pool, err := providerpkg.LoadMachinePoolConfig(devSpaceConfig.DefaultContext, poolName)
+	if err != nil {
+		return fmt.Errorf("machine pool %s doesn't exist", poolName)
+	}
+
+	unlockReplenish, err := lockMachinePool(ctx, pool, "replenish")
+	if err != nil {
+		return err
+	}
+	defer unlockReplenish()
+
+	// detach surplus machines while holding the claim lock
+	unlockClaim, err := lockMachinePool(ctx, pool, "claim")
+	if err != nil {
+		return err
+	}
+	idle, surplus, err := idlePoolMachines(devSpaceConfig, pool, log)
+	if err == nil {
+		for _, machine := range surplus {
+			machine.Pool = ""
+			err = providerpkg.SaveMachineConfig(machine)
+			if err != nil {
+				break
+			}
+		}
+	}
+	unlockClaim()
+	if err != nil {
+		return err
+	}
+
+	for _, machine := range surplus {
+		err = deletePoolMachine(ctx, devSpaceConfig, machine, log)
+		if err != nil {
+			return err
+		}
+	}
+
+	// make sure idle machines are in the desired state
+	for _, machine := range idle {
+		err = ensurePoolMachineState(ctx, devSpaceConfig, machine, pool.State, log)
+		if err != nil {
+			log.Warnf("Error ensuring state of pool machine %s: %v", machine.ID, err)
+		}
+	}
+
+	for i := len(idle); i < pool.Size; i++ {
+		err = createPoolMachine(ctx, devSpaceConfig, pool, log)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReplenishMachinePoolInBackground starts a detached DevSpace process that replenishes the pool
+func ReplenishMachinePoolInBackground(devSpaceConfig *config.Config, poolName string, log log.Logger) {
+	err := single.Single("devspace-pool-"+encoding.SafeConcatNameMax([]string{devSpaceConfig.DefaultContext, poolName}, 32)+".pid", func() (*exec.Cmd, error) {
+		executable, err := os.Executable()
+		if err != nil {
+			return nil, err
+		}
+
+		cmd := exec.Command(executable, "machine", "pool", "replenish", poolName, "--context", devSpaceConfig.DefaultContext)
+		cmd.Env = os.Environ()
+		return cmd, nil
+	})
+	if err != nil {
+		log.Warnf("Error replenishing machine pool %s: %v", poolName, err)
+	}
+}
+
+// claimPoolMachine schedules the workspace onto a machine of a pool of the given provider.
+// If there is no pool or no pool machine has enough capacity left, the workspace is
+// left untouched.
+func claimPoolMachine(ctx context.Context, devSpaceConfig *config.Config, providerName string, workspace *providerpkg.Workspace, log log.Logger) error {
+	pool, err := findMachinePool(devSpaceConfig, providerName, log)
+	if err != nil || pool == nil {
+		return err
+	}
+
+	requested := workspaceResources(workspace, log)
+	unlock, err := lockMachinePool(ctx, pool, "claim")
+	if err != nil {
+		return err
+	}
+	machines, err := listPoolMachines(devSpaceConfig, pool, log)
+	if err != nil {
+		unlock()
+		return err
+	}
+
+	machine := pickPoolMachine(pool, machines, requested)
+	if machine == nil {
+		unlock()
+		log.Infof("No machine of pool %s has enough capacity left, creating a new machine", pool.Name)
+		ReplenishMachinePoolInBackground(devSpaceConfig, pool.Name, log)
+		return nil
+	}
+
+	workspace.Machine.ID = machine.Machine.ID
+	workspace.Machine.Resources = &requested
+	err = providerpkg.SaveWorkspaceConfig(workspace)
+	unlock()
+	if err != nil {
+		return fmt.Errorf("save config: %w", err)
+	}
+
+	log.Infof("Use machine '%s' from pool '%s' for workspace '%s'", machine.Machine.ID, pool.Name, workspace.ID)
+	if len(machine.Workspaces) == 0 {
+		ReplenishMachinePoolInBackground(devSpaceConfig, pool.Name, log)
+	}
+
+	return ensurePoolMachineState(ctx, devSpaceConfig, machine.Machine, providerpkg.MachinePoolStateRunning, log)
+}
+
+// pickPoolMachine returns the machine the requested resources should be scheduled onto. Machines
+// that already host workspaces are preferred and among them the one with the least capacity left
+// that still fits, so idle machines stay available for the next workspace.
+func pickPoolMachine(pool *providerpkg.MachinePool, machines []*poolMachine, requested providerpkg.MachineResources) *poolMachine {
+	var best *poolMachine
+	var bestFree providerpkg.MachineResources
+	for _, machine := range machines {
+		if pool.MaxWorkspaces > 0 && len(machine.Workspaces) >= pool.MaxWorkspaces {
+			continue
+		}
+
+		if !pool.Capacity.Fits(machine.Used, requested) {
+			continue
+		}
+
+		free := pool.Capacity.Sub(machine.Used)
+		if best == nil {
+			best, bestFree = machine, free
+			continue
+		}
+
+		// prefer machines that are already in use
+		if (len(machine.Workspaces) > 0) != (len(best.Workspaces) > 0) {
+			if len(machine.Workspaces) > 0 {
+				best, bestFree = machine, free
+			}
+			continue
+		}
+
+		// prefer the tightest fit
+		if free.CPUs < bestFree.CPUs || (free.CPUs == bestFree.CPUs && free.Memory < bestFree.Memory) {
+			best, bestFree = machine, free
+		}
+	}
+
+	return best
+}
+
+// findMachinePool returns the pool new workspaces of the provider should be scheduled onto
+func findMachinePool(devSpaceConfig *config.Config, providerName string, log log.Logger) (*providerpkg.MachinePool, error) {
+	pools, err := ListMachinePools(devSpaceConfig, log)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pool := range pools {
+		if pool.Provider.Name == providerName {
+			return pool, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// listPoolMachines returns the machines of a pool together with the workspaces using them
+func listPoolMachines(devSpaceConfig *config.Config, pool *providerpkg.MachinePool, log log.Logger) ([]*poolMachine, error) {
+	machines, err := listMachines(devSpaceConfig, log)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaces, err := ListLocalWorkspaces(devSpaceConfig.DefaultContext, true, log)
+	if err != nil {
+		return nil, err
+	}
+
+	retMachines := []*poolMachine{}
+	for _, machine := range machines {
+		if machine.Pool != pool.Name {
+			continue
+		}
+
+		poolMachine := &poolMachine{Machine: machine}
+		for _, workspace := range workspaces {
+			if workspace.Machine.ID != machine.ID {
+				continue
+			}
+
+			poolMachine.Workspaces = append(poolMachine.Workspaces, workspace)
+			if workspace.Machine.Resources != nil {
+				poolMachine.Used.CPUs += workspace.Machine.Resources.CPUs
+				poolMachine.Used.Memory += workspace.Machine.Resources.Memory
+			}
+		}
+
+		retMachines = append(retMachines, poolMachine)
+	}
+
+	sort.SliceStable(retMachines, func(i, j int) bool {
+		return retMachines[i].Machine.CreationTimestamp.Before(&retMachines[j].Machine.CreationTimestamp)
+	})
+	return retMachines, nil
+}
+
+// idlePoolMachines returns the unused machines of the pool that should be kept and the ones
+// that exceed the pool size
+func idlePoolMachines(devSpaceConfig *config.Config, pool *providerpkg.MachinePool, log log.Logger) ([]*providerpkg.Machine, []*providerpkg.Machine, error) {
+	machines, err := listPoolMachines(devSpaceConfig, pool, log)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	idle := []*providerpkg.Machine{}
+	surplus := []*providerpkg.Machine{}
+	for _, machine := range machines {
+		if len(machine.Workspaces) > 0 {
+			continue
+		} else if len(idle) < pool.Size {
+			idle = append(idle, machine.Machine)
+		} else {
+			surplus = append(surplus, machine.Machine)
+		}
+	}
+
+	return idle, surplus, nil
+}
+
+func createPoolMachine(ctx context.Context, devSpaceConfig *config.Config, pool *providerpkg.MachinePool, log log.Logger) error {
+	providerWithOptions, err := FindProvider(devSpaceConfig, pool.Provider.Name, log)
+	if err != nil {
+		return err
+	}
+
+	machineConfig, err := createMachine(pool.Context, encoding.CreateNewUIDShort("pool-"+pool.Name), pool.Provider.Name)
+	if err != nil {
+		return err
+	}
+
+	machineClient, err := clientimplementation.NewMachineClient(devSpaceConfig, providerWithOptions.Config, machineConfig, log)
+	if err != nil {
+		_ = clientimplementation.DeleteMachineFolder(machineConfig.Context, machineConfig.ID)
+		return err
+	}
+
+	err = machineClient.RefreshOptions(ctx, pool.ProviderOptions, false)
+	if err != nil {
+		_ = clientimplementation.DeleteMachineFolder(machineConfig.Context, machineConfig.ID)
+		return err
+	}
+
+	err = machineClient.Create(ctx, client.CreateOptions{})
+	if err != nil {
+		_ = clientimplementation.DeleteMachineFolder(machineConfig.Context, machineConfig.ID)
+		return err
+	}
+
+	if pool.State == providerpkg.MachinePoolStateStopped {
+		err = machineClient.Stop(ctx, client.StopOptions{})
+		if err != nil {
+			log.Warnf("Error stopping pool machine %s: %v", machineConfig.ID, err)
+		}
+	}
+
+	// only add the machine to the pool once it is ready to be claimed
+	machineConfig = machineClient.MachineConfig()
+	machineConfig.Pool = pool.Name
+	return providerpkg.SaveMachineConfig(machineConfig)
+}
+
+func deletePoolMachine(ctx context.Context, devSpaceConfig *config.Config, machine *providerpkg.Machine, log log.Logger) error {
+	machineClient, err := loadExistingMachine(machine.ID, devSpaceConfig, log)
+	if err != nil {
+		return err
+	}
+
+	return machineClient.Delete(ctx, client.DeleteOptions{})
+}
+
+func ensurePoolMachineState(ctx context.Context, devSpaceConfig *config.Config, machine *providerpkg.Machine, state string, log log.Logger) error {
+	machineClient, err := loadExistingMachine(machine.ID, devSpaceConfig, log)
+	if err != nil {
+		return err
+	}
+
+	status, err := machineClient.Status(ctx, client.StatusOptions{})
+	if err != nil {
+		return err
+	}
+
+	if state == providerpkg.MachinePoolStateRunning && status == client.StatusStopped {
+		return machineClient.Start(ctx, client.StartOptions{})
+	} else if state == providerpkg.MachinePoolStateStopped && status == client.StatusRunning {
+		return machineClient.Stop(ctx, client.StopOptions{})
+	}
+
+	return nil
+}
+
+// lockMachinePool acquires one of the pool locks. The claim lock is only held for a short
+// time while machines are assigned, the replenish lock while machines are created.
+func lockMachinePool(ctx context.Context, pool *providerpkg.MachinePool, name string) (func(), error) {
+	locksDir, err := providerpkg.GetLocksDir(pool.Context)
+	if err != nil {
+		return nil, err
+	}
+	_ = os.MkdirAll(locksDir, 0777)
+
+	lock := flock.New(filepath.Join(locksDir, pool.Name+".pool-"+name+".lock"))
+	ctx, cancel := context.WithTimeout(ctx, time.Minute*5)
+	defer cancel()
+
+	locked, err := lock.TryLockContext(ctx, time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("error locking machine pool %s: %w", pool.Name, err)
+	} else if !locked {
+		return nil, fmt.Errorf("timed out waiting to lock machine pool %s", pool.Name)
+	}
+
+	return func() {
+		_ = lock.Unlock()
+	}, nil
+}
+
+// workspaceResources returns the resources declared by the dev container of a local workspace
+func workspaceResources(workspace *providerpkg.Workspace, log log.Logger) providerpkg.MachineResources {
+	resources := providerpkg.MachineResources{}
+	if workspace.Source.LocalFolder == "" {
+		return resources
+	}
+
+	devContainer, err := devcontainerconfig.ParseDevContainerJSON(workspace.Source.LocalFolder, workspace.DevContainerPath)
+	if err != nil {
+		log.Debugf("Error parsing devcontainer.json to determine resources: %v", err)
+		return resources
+	} else if devContainer == nil || devContainer.HostRequirements == nil {
+		return resources
+	}
+
+	resources.CPUs = devContainer.HostRequirements.CPUs
+	if devContainer.HostRequirements.Memory != "" {
+		memory, err := units.RAMInBytes(devContainer.HostRequirements.Memory)
+		if err != nil {
+			log.Debugf("Error parsing memory requirement %s: %v", devContainer.HostRequirements.Memory, err)
+		} else {
+			resources.Memory = memory
+		}
+	}
+
+	return resources
+}
@@ -0,0 +1,75 @@
+package workspace
+
+import (
+	"testing"
+
+	providerpkg "dev.khulnasoft.com/pkg/provider"
+)
+
+func TestPickPoolMachine(t *testing.T) {
+	newMachine := func(id string, workspaces, cpus int) *poolMachine {
+		machine := &poolMachine{
+			Machine: &providerpkg.Machine{ID: id},
+			Used:    providerpkg.MachineResources{CPUs: cpus},
+		}
+		for i := 0; i < workspaces; i++ {
+			machine.Workspaces = append(machine.Workspaces, &providerpkg.Workspace{})
+		}
+		return machine
+	}
+
+	tests := []struct {
+		name      string
+		pool      *providerpkg.MachinePool
+		machines  []*poolMachine
+		requested providerpkg.MachineResources
+		want      string
+	}{
+		{
+			name:     "Idle machine",
+			pool:     &providerpkg.MachinePool{MaxWorkspaces: 1},
+			machines: []*poolMachine{newMachine("used", 1, 0), newMachine("idle", 0, 0)},
+			want:     "idle",
+		},
+		{
+			name:      "Prefer used machine with capacity left",
+			pool:      &providerpkg.MachinePool{Capacity: providerpkg.MachineResources{CPUs: 8}},
+			machines:  []*poolMachine{newMachine("idle", 0, 0), newMachine("used", 1, 4)},
+			requested: providerpkg.MachineResources{CPUs: 4},
+			want:      "used",
+		},
+		{
+			name:      "Tightest fit",
+			pool:      &providerpkg.MachinePool{Capacity: providerpkg.MachineResources{CPUs: 8}},
+			machines:  []*poolMachine{newMachine("loose", 1, 2), newMachine("tight", 2, 6)},
+			requested: providerpkg.MachineResources{CPUs: 2},
+			want:      "tight",
+		},
+		{
+			name:      "Full machine",
+			pool:      &providerpkg.MachinePool{Capacity: providerpkg.MachineResources{CPUs: 8}},
+			machines:  []*poolMachine{newMachine("full", 2, 8), newMachine("idle", 0, 0)},
+			requested: providerpkg.MachineResources{CPUs: 2},
+			want:      "idle",
+		},
+		{
+			name:      "Nothing fits",
+			pool:      &providerpkg.MachinePool{Capacity: providerpkg.MachineResources{CPUs: 4}},
+			machines:  []*poolMachine{newMachine("idle", 0, 0)},
+			requested: providerpkg.MachineResources{CPUs: 8},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := ""
+			if machine := pickPoolMachine(test.pool, test.machines, test.requested); machine != nil {
+				got = machine.Machine.ID
+			}
+
+			if got != test.want {
+				t.Errorf("expected machine %q, got %q", test.want, got)
+			}
+		})
+	}
+}
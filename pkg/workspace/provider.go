@@ -15,6 +15,7 @@ import (
 	"dev.khulnasoft.com/providers"
 
 	"dev.khulnasoft.com/pkg/binaries"
+	"dev.khulnasoft.com/pkg/provider/catalog"
 	"dev.khulnasoft.com/pkg/config"
 	"dev.khulnasoft.com/pkg/download"
 	"dev.khulnasoft.com/log"
@@ -86,12 +87,31 @@ func AddProviderRaw(devSpaceConfig *config.Config, providerName string, provider
 }
 
 func AddProvider(devSpaceConfig *config.Config, providerName, providerSourceRaw string, log log.Logger) (*providerpkg.ProviderConfig, error) {
-	providerRaw, providerSource, err := ResolveProvider(providerSourceRaw, log)
+	providerRaw, providerSource, err := ResolveProvider(devSpaceConfig, providerSourceRaw, log)
 	if err != nil {
 		return nil, err
 	}
 
-	return AddProviderRaw(devSpaceConfig, providerName, providerSource, providerRaw, log)
+	publisherKey, err := VerifyProviderSignature(devSpaceConfig, providerName, providerRaw, providerSource, log)
+	if err != nil {
+		return nil, err
+	}
+
+	providerConfig, err := AddProviderRaw(devSpaceConfig, providerName, providerSource, providerRaw, log)
+	if err != nil {
+		return nil, err
+	}
+
+	// pin the publisher key, every update needs to be signed by the same key
+	if publisherKey != nil {
+		devSpaceConfig.Current().Providers[providerConfig.Name].PublisherKey = publisherKey.String()
+		err = config.SaveConfig(devSpaceConfig)
+		if err != nil {
+			return nil, errors.Wrap(err, "save config")
+		}
+	}
+
+	return providerConfig, nil
 }
 
 func UpdateProvider(devSpaceConfig *config.Config, providerName, providerSourceRaw string, log log.Logger) (*providerpkg.ProviderConfig, error) {
@@ -107,9 +127,16 @@ func UpdateProvider(devSpaceConfig *config.Config, providerName, providerSourceR
 		providerSourceRaw = s
 	}
 
-	providerRaw, providerSource, err := ResolveProvider(providerSourceRaw, log)
+	providerRaw, providerSource, err := ResolveProvider(devSpaceConfig, providerSourceRaw, log)
+	if err != nil {
+		return nil, err
+	}
+
+	publisherKey, err := VerifyProviderSignature(devSpaceConfig, providerName, providerRaw, providerSource, log)
 	if err != nil {
 		return nil, err
+	} else if publisherKey != nil {
+		devSpaceConfig.Current().Providers[providerName].PublisherKey = publisherKey.String()
 	}
 
 	return updateProvider(devSpaceConfig, providerName, providerRaw, providerSource, log)
@@ -123,7 +150,9 @@ func ResolveProviderSource(devSpaceConfig *config.Config, providerName string, l
 		return "", errors.Wrap(err, "find provider")
 	}
 
-	if providerConfig.Config.Source.Internal {
+	if providerConfig.Config.Source.Catalog != "" {
+		source = providerConfig.Config.Source.Catalog
+	} else if providerConfig.Config.Source.Internal {
 		// Name could also be overridden if initial name was already taken, so prefer the raw source if available
 		if providerConfig.Config.Source.Raw == "" {
 			source = providerConfig.Config.Name
@@ -143,7 +172,7 @@ func ResolveProviderSource(devSpaceConfig *config.Config, providerName string, l
 	return source, nil
 }
 
-func ResolveProvider(providerSource string, log log.Logger) ([]byte, *providerpkg.ProviderSource, error) {
+func ResolveProvider(devSpaceConfig *config.Config, providerSource string, log log.Logger) ([]byte, *providerpkg.ProviderSource, error) {
 	retSource := &providerpkg.ProviderSource{Raw: strings.TrimSpace(providerSource)}
 
 	// in-built?
@@ -182,8 +211,16 @@ func ResolveProvider(providerSource string, log log.Logger) ([]byte, *providerpk
 		}
 	}
 
+	// catalog?
+	out, source, err := resolveCatalogProvider(devSpaceConfig, providerSource, log)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "resolve catalog provider")
+	} else if len(out) > 0 {
+		return out, source, nil
+	}
+
 	// check if github
-	out, source, err := DownloadProviderGithub(providerSource, log)
+	out, source, err = DownloadProviderGithub(providerSource, log)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "download github")
 	} else if len(out) > 0 {
@@ -193,35 +230,49 @@ func ResolveProvider(providerSource string, log log.Logger) ([]byte, *providerpk
 	return nil, nil, fmt.Errorf("unrecognized provider type, please specify either a local file, url or github repository")
 }
 
-func DownloadProviderGithub(originalPath string, log log.Logger) ([]byte, *providerpkg.ProviderSource, error) {
-	path := strings.TrimPrefix(originalPath, "github.com/")
-
-	// resolve release
-	release := ""
-	index := strings.LastIndex(path, "@")
-	if index != -1 {
-		release = path[index+1:]
-		path = path[:index]
+// resolveCatalogProvider looks up a provider in the form name[@version] in the configured catalogs
+func resolveCatalogProvider(devSpaceConfig *config.Config, providerSource string, log log.Logger) ([]byte, *providerpkg.ProviderSource, error) {
+	if devSpaceConfig == nil || strings.Contains(providerSource, "/") {
+		return nil, nil, nil
 	}
 
-	// split by separator
-	splitted := strings.Split(strings.TrimSuffix(path, "/"), "/")
-	if len(splitted) == 1 {
-		path = "khulnasoftdevspace-provider-" + path
-	} else if len(splitted) != 2 {
+	// commands like provider update print json, so catalog logs must not go to stdout
+	logger := log.ErrorStreamOnly()
+	name, version, _ := strings.Cut(providerSource, "@")
+	catalogProvider, err := catalog.Find(devSpaceConfig, name, logger)
+	if err != nil {
+		// an unreachable catalog must not keep providers from resolving through github
+		logger.Warnf("Couldn't look up provider %s in the provider catalogs: %v", name, err)
+		return nil, nil, nil
+	} else if catalogProvider == nil {
 		return nil, nil, nil
 	}
 
-	// get latest release
-	requestURL := ""
-	if release == "" {
-		requestURL = fmt.Sprintf("https://github.com/%s/releases/latest/download/provider.yaml", path)
-	} else {
-		requestURL = fmt.Sprintf("https://github.com/%s/releases/download/%s/provider.yaml", path, release)
+	catalogVersion, err := catalogProvider.Version(version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// catalog sources are never resolved through a catalog again
+	logger.Infof("Found provider %s %s in catalog %s", catalogProvider.Name, catalogVersion.Version, catalogProvider.Catalog)
+	out, source, err := ResolveProvider(nil, catalogVersion.Source, log)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// keep the catalog reference, so updates are resolved through the catalog as well
+	source.Catalog = providerSource
+	return out, source, nil
+}
+
+func DownloadProviderGithub(originalPath string, log log.Logger) ([]byte, *providerpkg.ProviderSource, error) {
+	path, release, ok := parseGithubProviderSource(originalPath)
+	if !ok {
+		return nil, nil, nil
 	}
 
 	// download
-	body, err := download.File(requestURL, log)
+	body, err := download.File(githubReleaseAssetURL(path, release, "provider.yaml"), log)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "download")
 	}
@@ -239,6 +290,38 @@ func DownloadProviderGithub(originalPath string, log log.Logger) ([]byte, *provi
 	}, nil
 }
 
+// parseGithubProviderSource splits a github provider source into repository and release
+func parseGithubProviderSource(originalPath string) (string, string, bool) {
+	path := strings.TrimPrefix(originalPath, "github.com/")
+
+	// resolve release
+	release := ""
+	index := strings.LastIndex(path, "@")
+	if index != -1 {
+		release = path[index+1:]
+		path = path[:index]
+	}
+
+	// split by separator
+	splitted := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	if len(splitted) == 1 {
+		path = "khulnasoftdevspace-provider-" + path
+	} else if len(splitted) != 2 {
+		return "", "", false
+	}
+
+	return path, release, true
+}
+
+func githubReleaseAssetURL(path, release, asset string) string {
+	// get latest release
+	if release == "" {
+		return fmt.Sprintf("https://github.com/%s/releases/latest/download/%s", path, asset)
+	}
+
+	return fmt.Sprintf("https://github.com/%s/releases/download/%s/%s", path, release, asset)
+}
+
 func downloadProvider(url string) ([]byte, error) {
 	// initiate download
 	resp, err := devspacehttp.GetHTTPClient().Get(url)
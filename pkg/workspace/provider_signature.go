@@ -0,0 +1,161 @@
+package workspace
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/config"
+	devspacehttp "dev.khulnasoft.com/pkg/http"
+	providerpkg "dev.khulnasoft.com/pkg/provider"
+	"dev.khulnasoft.com/pkg/signature"
+	"github.com/pkg/errors"
+)
+
+// VerifyProviderSignature verifies the detached minisign signature of a provider manifest.
+// It returns the key the manifest was signed with or nil if the manifest is unsigned and the
+// configured signature mode allows that.
+func VerifyProviderSignature(devSpaceConfig *config.Config, providerName string, raw []byte, source *providerpkg.ProviderSource, log log.Logger) (*signature.PublicKey, error) {
+	mode := devSpaceConfig.ContextOption(config.ContextOptionProviderSignatureMode)
+	if mode == config.ProviderSignatureModeOff || source.Internal {
+		return nil, nil
+	}
+
+	pinnedKey := ""
+	if providerName != "" && devSpaceConfig.Current().Providers[providerName] != nil {
+		pinnedKey = devSpaceConfig.Current().Providers[providerName].PublisherKey
+	}
+
+	signatureLocation, keyLocation, err := signatureLocations(source)
+	if err != nil {
+		return nil, err
+	}
+
+	rawSignature, err := fetchOptional(signatureLocation)
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieve provider signature")
+	} else if rawSignature == nil {
+		if pinnedKey != "" {
+			return nil, fmt.Errorf("provider %s was signed before, but %s has no signature at %s", providerName, source.Raw, signatureLocation)
+		} else if mode == config.ProviderSignatureModeRequired {
+			return nil, fmt.Errorf("provider %s has no signature at %s, but %s is set to %s", source.Raw, signatureLocation, config.ContextOptionProviderSignatureMode, mode)
+		}
+
+		log.Warnf("Provider %s is not signed, skipping signature verification", source.Raw)
+		return nil, nil
+	}
+
+	parsedSignature, err := signature.ParseSignature(rawSignature)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse provider signature")
+	}
+
+	trustedKeys, err := trustedProviderKeys(devSpaceConfig, pinnedKey, keyLocation, log)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := signature.VerifyAny(trustedKeys, parsedSignature, raw)
+	if err != nil {
+		return nil, fmt.Errorf("verify signature of provider %s: %w", source.Raw, err)
+	}
+
+	log.Donef("Verified signature of provider %s with key %s", source.Raw, publicKey.KeyIDString())
+	return publicKey, nil
+}
+
+// trustedProviderKeys returns the keys a provider manifest may be signed with. A pinned
+// key always wins, afterwards the keys configured in the context are used and if there are
+// none, the publisher key is fetched from the provider source and trusted on first use.
+func trustedProviderKeys(devSpaceConfig *config.Config, pinnedKey, keyLocation string, log log.Logger) ([]*signature.PublicKey, error) {
+	if pinnedKey != "" {
+		publicKey, err := signature.ParsePublicKey(pinnedKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse pinned publisher key")
+		}
+
+		return []*signature.PublicKey{publicKey}, nil
+	}
+
+	retKeys := []*signature.PublicKey{}
+	for _, rawKey := range strings.Split(devSpaceConfig.ContextOption(config.ContextOptionProviderTrustedKeys), ",") {
+		rawKey = strings.TrimSpace(rawKey)
+		if rawKey == "" {
+			continue
+		}
+
+		publicKey, err := signature.ParsePublicKey(rawKey)
+		if err != nil {
+			return nil, fmt.Errorf("parse key %s from %s: %w", rawKey, config.ContextOptionProviderTrustedKeys, err)
+		}
+
+		retKeys = append(retKeys, publicKey)
+	}
+	if len(retKeys) > 0 {
+		return retKeys, nil
+	}
+
+	rawKey, err := fetchOptional(keyLocation)
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieve publisher key")
+	} else if rawKey == nil {
+		return nil, fmt.Errorf("provider is signed, but no publisher key was found at %s. Please add the key to %s", keyLocation, config.ContextOptionProviderTrustedKeys)
+	}
+
+	publicKey, err := signature.ParsePublicKey(string(rawKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "parse publisher key")
+	}
+
+	log.Infof("Trusting publisher key %s from %s on first use", publicKey.KeyIDString(), keyLocation)
+	return []*signature.PublicKey{publicKey}, nil
+}
+
+// signatureLocations returns where to find the signature and the publisher key for a provider source
+func signatureLocations(source *providerpkg.ProviderSource) (string, string, error) {
+	if source.URL != "" {
+		return source.URL + signature.Extension, source.URL[:strings.LastIndex(source.URL, "/")+1] + signature.PublicKeyFile, nil
+	} else if source.File != "" {
+		return source.File + signature.Extension, filepath.Join(filepath.Dir(source.File), signature.PublicKeyFile), nil
+	} else if source.Github != "" {
+		githubPath, release, ok := parseGithubProviderSource(source.Raw)
+		if !ok {
+			githubPath = source.Github
+		}
+
+		return githubReleaseAssetURL(githubPath, release, "provider.yaml"+signature.Extension), githubReleaseAssetURL(githubPath, release, signature.PublicKeyFile), nil
+	}
+
+	return "", "", fmt.Errorf("unable to determine signature location for provider %s", source.Raw)
+}
+
+// fetchOptional retrieves a local file or url and returns nil if it doesn't exist
+func fetchOptional(location string) ([]byte, error) {
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		out, err := os.ReadFile(location)
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return out, err
+	}
+
+	resp, err := devspacehttp.GetHTTPClient().Get(location)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d retrieving %s", resp.StatusCode, path.Base(location))
+	}
+
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,85 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"dev.khulnasoft.com/log"
+	"dev.khulnasoft.com/pkg/config"
+)
+
+func TestResolveCatalogProvider(t *testing.T) {
+	t.Setenv("DEVSPACE_HOME", t.TempDir())
+	dir := t.TempDir()
+
+	providerPath := filepath.Join(dir, "provider.yaml")
+	err := os.WriteFile(providerPath, []byte("name: ssh\nversion: v0.1.0\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	catalogPath := filepath.Join(dir, "catalog.yaml")
+	err = os.WriteFile(catalogPath, []byte(`providers:
+- name: ssh
+  versions:
+  - version: v0.1.0
+    source: provider.yaml`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name      string
+		locations string
+		source    string
+
+		expectedCatalog string
+		expectedFound   bool
+	}{
+		{
+			name:            "found in catalog",
+			locations:       catalogPath,
+			source:          "ssh",
+			expectedCatalog: "ssh",
+			expectedFound:   true,
+		},
+		{
+			name:      "not in catalog",
+			locations: catalogPath,
+			source:    "docker",
+		},
+		{
+			name:      "all catalogs unreachable",
+			locations: filepath.Join(dir, "missing.yaml"),
+			source:    "ssh",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			devSpaceConfig := &config.Config{
+				DefaultContext: "default",
+				Contexts: map[string]*config.ContextConfig{
+					"default": {
+						Options: map[string]config.OptionValue{
+							config.ContextOptionProviderCatalogs: {Value: testCase.locations},
+						},
+					},
+				},
+			}
+
+			out, source, err := resolveCatalogProvider(devSpaceConfig, testCase.source, log.Discard)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if found := out != nil; found != testCase.expectedFound {
+				t.Fatalf("expected found %t, got %t", testCase.expectedFound, found)
+			}
+			if testCase.expectedFound && source.Catalog != testCase.expectedCatalog {
+				t.Fatalf("expected catalog source %q, got %q", testCase.expectedCatalog, source.Catalog)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	devcontainerconfig "dev.khulnasoft.com/pkg/devcontainer/config"
+	"dev.khulnasoft.com/pkg/image"
+	providerpkg "dev.khulnasoft.com/pkg/provider"
+)
+
+const (
+	// DefaultUpdateCheckInterval is how long the result of an image update check is reused
+	DefaultUpdateCheckInterval = time.Hour
+
+	// failedUpdateCheckInterval is how long a failed image update check is reused. It doubles with every failure
+	// in a row up to the interval of successful checks, so unreachable registries aren't queried on every poll.
+	failedUpdateCheckInterval = 5 * time.Minute
+)
+
+// imageUpdatesFile caches the last image update check within the workspace folder
+const imageUpdatesFile = "image-updates.json"
+
+type imageUpdateCheck struct {
+	Checked time.Time                         `json:"checked"`
+	Sources []devcontainerconfig.ImageSource  `json:"sources,omitempty"`
+	Updates []*devcontainerconfig.ImageUpdate `json:"updates,omitempty"`
+
+	// Error is the error of the last check if some sources couldn't be checked
+	Error string `json:"error,omitempty"`
+
+	// Failures counts the failed checks in a row
+	Failures int `json:"failures,omitempty"`
+}
+
+// expired returns if the check needs to be repeated
+func (c *imageUpdateCheck) expired(interval time.Duration) bool {
+	ttl := interval
+	if c.Failures > 0 && c.Failures < 16 {
+		ttl = min(failedUpdateCheckInterval<<(c.Failures-1), interval)
+	}
+
+	return time.Since(c.Checked) >= ttl
+}
+
+func (c *imageUpdateCheck) err() error {
+	if c.Error == "" {
+		return nil
+	}
+
+	return errors.New(c.Error)
+}
+
+// CheckImageUpdates returns newer versions of the base image, prebuild and features the workspace container
+// was built from. The result is cached for the given interval, so polling doesn't query the registries every time.
+// If some sources can't be checked, the updates found for the others are returned together with the error. Failed
+// checks are cached as well and retried with a backoff.
+func CheckImageUpdates(ctx context.Context, workspace *providerpkg.Workspace, interval time.Duration) ([]*devcontainerconfig.ImageUpdate, error) {
+	if len(workspace.ImageSources) == 0 {
+		return nil, nil
+	}
+
+	cachePath, err := imageUpdatesPath(workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	// reuse the last check if the workspace wasn't rebuilt since
+	lastCheck := readImageUpdateCheck(cachePath, workspace)
+	if lastCheck != nil && !lastCheck.expired(interval) {
+		return lastCheck.Updates, lastCheck.err()
+	}
+
+	// updates of the sources that could be checked are returned even if others failed
+	updates, err := image.CheckUpdates(ctx, workspace.ImageSources)
+	if ctx.Err() != nil {
+		// a canceled check says nothing about the registries
+		return updates, err
+	}
+
+	check := &imageUpdateCheck{
+		Checked: time.Now(),
+		Sources: workspace.ImageSources,
+		Updates: updates,
+	}
+	if err != nil {
+		check.Error = err.Error()
+		check.Failures = 1
+		if lastCheck != nil {
+			check.Failures += lastCheck.Failures
+		}
+	}
+
+	raw, marshalErr := json.Marshal(check)
+	if marshalErr == nil {
+		_ = os.WriteFile(cachePath, raw, 0600)
+	}
+
+	return updates, err
+}
+
+// CachedImageUpdates returns the updates found by the last image update check without querying the registries,
+// no matter how old the check is. It returns nil if the workspace wasn't checked since it was built.
+func CachedImageUpdates(workspace *providerpkg.Workspace) []*devcontainerconfig.ImageUpdate {
+	if len(workspace.ImageSources) == 0 {
+		return nil
+	}
+
+	cachePath, err := imageUpdatesPath(workspace)
+	if err != nil {
+		return nil
+	}
+
+	lastCheck := readImageUpdateCheck(cachePath, workspace)
+	if lastCheck == nil {
+		return nil
+	}
+
+	return lastCheck.Updates
+}
+
+func imageUpdatesPath(workspace *providerpkg.Workspace) (string, error) {
+	workspaceDir, err := providerpkg.GetWorkspaceDir(workspace.Context, workspace.ID)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(workspaceDir, imageUpdatesFile), nil
+}
+
+// readImageUpdateCheck returns the cached check or nil if there is none for the current image sources
+func readImageUpdateCheck(cachePath string, workspace *providerpkg.Workspace) *imageUpdateCheck {
+	raw, err := os.ReadFile(cachePath)
+	if err != nil {
+		return nil
+	}
+
+	lastCheck := &imageUpdateCheck{}
+	err = json.Unmarshal(raw, lastCheck)
+	if err != nil || !slices.Equal(lastCheck.Sources, workspace.ImageSources) {
+		return nil
+	}
+
+	return lastCheck
+}
@@ -0,0 +1,133 @@
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	devcontainerconfig "dev.khulnasoft.com/pkg/devcontainer/config"
+	providerpkg "dev.khulnasoft.com/pkg/provider"
+)
+
+func TestCheckImageUpdatesCachesFailures(t *testing.T) {
+	t.Setenv("DEVSPACE_HOME", t.TempDir())
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	requests := atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	workspace := &providerpkg.Workspace{
+		ID:      "test",
+		Context: "default",
+		ImageSources: []devcontainerconfig.ImageSource{{
+			Type:      devcontainerconfig.ImageSourceTypeImage,
+			Reference: strings.TrimPrefix(server.URL, "http://") + "/base:latest",
+			Digest:    "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+		}},
+	}
+	workspaceDir, err := providerpkg.GetWorkspaceDir(workspace.Context, workspace.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.MkdirAll(workspaceDir, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := func() {
+		t.Helper()
+
+		_, err := CheckImageUpdates(context.Background(), workspace, DefaultUpdateCheckInterval)
+		if err == nil {
+			t.Fatal("expected the check against the failing registry to fail")
+		}
+	}
+
+	check()
+	queried := requests.Load()
+	if queried == 0 {
+		t.Fatal("expected the registry to be queried")
+	}
+
+	// the failure is reused within the backoff
+	check()
+	if requests.Load() != queried {
+		t.Fatalf("expected the cached failure to be reused, registry was queried again")
+	}
+
+	// the backoff doubles with every failure in a row
+	cachePath := filepath.Join(workspaceDir, imageUpdatesFile)
+	expireCheck := func(age time.Duration) {
+		t.Helper()
+
+		raw, err := os.ReadFile(cachePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lastCheck := &imageUpdateCheck{}
+		err = json.Unmarshal(raw, lastCheck)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lastCheck.Checked = time.Now().Add(-age)
+		raw, err = json.Marshal(lastCheck)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(cachePath, raw, 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expireCheck(failedUpdateCheckInterval)
+	check()
+	if requests.Load() == queried {
+		t.Fatal("expected the registry to be queried again after the backoff")
+	}
+	queried = requests.Load()
+
+	expireCheck(failedUpdateCheckInterval)
+	check()
+	if requests.Load() != queried {
+		t.Fatal("expected the backoff to double after the second failure")
+	}
+}
+
+func TestImageUpdateCheckExpired(t *testing.T) {
+	testCases := []struct {
+		name     string
+		age      time.Duration
+		failures int
+		expected bool
+	}{
+		{name: "fresh success", age: 30 * time.Minute, expected: false},
+		{name: "expired success", age: time.Hour, expected: true},
+		{name: "first failure", age: 4 * time.Minute, failures: 1, expected: false},
+		{name: "first failure expired", age: 5 * time.Minute, failures: 1, expected: true},
+		{name: "third failure", age: 15 * time.Minute, failures: 3, expected: false},
+		{name: "third failure expired", age: 20 * time.Minute, failures: 3, expected: true},
+		{name: "backoff capped at interval", age: time.Hour, failures: 10, expected: true},
+		{name: "many failures", age: 30 * time.Minute, failures: 100, expected: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			check := &imageUpdateCheck{Checked: time.Now().Add(-testCase.age), Failures: testCase.failures}
+			if expired := check.expired(time.Hour); expired != testCase.expected {
+				t.Fatalf("expected expired %t, got %t", testCase.expected, expired)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"dev.khulnasoft.com/pkg/platform"
 	providerpkg "dev.khulnasoft.com/pkg/provider"
 	"dev.khulnasoft.com/pkg/types"
+	"dev.khulnasoft.com/pkg/usage"
 	"dev.khulnasoft.com/log"
 	"dev.khulnasoft.com/log/terminal"
 )
@@ -100,7 +101,7 @@ func Resolve(
 	}
 
 	// configure dev container source
-	if workspace.Source.Container != "" {
+	if workspace.Source.Container != "" || workspace.Source.KubernetesWorkload != "" {
 		err = providerpkg.SaveWorkspaceConfig(workspace)
 		if err != nil {
 			return nil, fmt.Errorf("save workspace: %w", err)
@@ -294,6 +295,14 @@ func createWorkspace(
 		}
 	}
 
+	// try to claim a pre-warmed machine from a pool
+	if provider.Config.IsMachineProvider() && workspace.Machine.ID == "" && (provider.State == nil || !provider.State.SingleMachine) {
+		err = claimPoolMachine(ctx, devSpaceConfig, provider.Config.Name, workspace, log)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("claim pool machine: %w", err)
+		}
+	}
+
 	// create a new machine
 	var machineConfig *providerpkg.Machine
 	if provider.Config.IsMachineProvider() && workspace.Machine.ID == "" {
@@ -383,6 +392,7 @@ func createWorkspace(
 		}
 	}
 
+	usage.RecordWorkspace(devSpaceConfig, workspace, usage.EventCreated, log)
 	return provider.Config, workspace, machineConfig, nil
 }
 